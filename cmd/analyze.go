@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/stackdiff"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+	"github.com/spf13/cobra"
+)
+
+var analyzeDiff string
+var analyzeFormat string
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyze the project, or compare its stack between two git refs",
+	Long: `Print what analysis finds in the project: languages, frameworks,
+package manager, workspace packages, tooling and folders.
+
+--diff compares the analysis at two git refs instead, and lists what
+changed structurally: new or removed packages, added, removed, upgraded
+and downgraded dependencies, frameworks, shifts in the languages' share
+of the files, tooling and top-level folders. Each ref is analyzed from
+'git archive', so nothing is checked out.
+
+  A..B    from A to B (B defaults to HEAD, e.g. main..)
+  A...B   from where B branched off A to B, like a pull request diff
+  A       from A to the working tree, uncommitted changes included
+
+--format markdown prints the changes as a Markdown list for pull request
+descriptions and release notes.
+
+Examples:
+  contextpilot analyze
+  contextpilot analyze --diff main..HEAD
+  contextpilot analyze --diff v1.2.0..v1.3.0 --format markdown`,
+	Args:        cobra.NoArgs,
+	Annotations: jsonCapable,
+	Run:         runAnalyze,
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	if analyzeFormat != "text" && analyzeFormat != "markdown" {
+		output.Errorf("❌ Unknown format %q (use text or markdown)\n", analyzeFormat)
+		os.Exit(1)
+	}
+
+	if analyzeDiff == "" {
+		analysis, err := analyzer.New(cwd).Analyze()
+		if err != nil {
+			output.Errorf("❌ Error analyzing codebase: %v\n", err)
+			os.Exit(1)
+		}
+		if output.IsJSON() {
+			printJSON(map[string]interface{}{"analysis": analysis})
+			return
+		}
+		printStack(analysis)
+		return
+	}
+
+	from, to, err := diffRefs(cwd, analyzeDiff)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	spin := output.StartSpinner("🔍 Analyzing " + from + " and " + refName(to) + "...")
+	old, err := analyzeRef(cwd, from)
+	var cur *analyzer.Analysis
+	if err == nil {
+		cur, err = analyzeRef(cwd, to)
+	}
+	spin.Stop()
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	changes := stackdiff.Compare(old, cur)
+	if changes == nil {
+		changes = []stackdiff.Change{}
+	}
+
+	switch {
+	case output.IsJSON():
+		printJSON(map[string]interface{}{"from": from, "to": refName(to), "changes": changes})
+	case analyzeFormat == "markdown":
+		printStackChangesMarkdown(from, refName(to), changes)
+	default:
+		printStackChanges(from, refName(to), changes)
+	}
+}
+
+// stackCategoryTitles head each category of changes
+var stackCategoryTitles = map[string]string{
+	stackdiff.Packages:     "Packages",
+	stackdiff.Dependencies: "Dependencies",
+	stackdiff.Frameworks:   "Frameworks",
+	stackdiff.Languages:    "Languages",
+	stackdiff.Tooling:      "Tooling",
+	stackdiff.Folders:      "Folders",
+}
+
+// diffRefs splits a --diff range into the revisions to compare; to is ""
+// for the working tree
+func diffRefs(cwd, spec string) (from, to string, err error) {
+	switch {
+	case strings.Contains(spec, "..."):
+		a, b, _ := strings.Cut(spec, "...")
+		if b == "" {
+			b = "HEAD"
+		}
+		base, err := git.Output(cwd, "merge-base", a, b)
+		if err != nil {
+			return "", "", fmt.Errorf("no common ancestor of %s and %s", a, b)
+		}
+		from, _ = git.ShortSHA(cwd, base)
+		to = b
+	case strings.Contains(spec, ".."):
+		from, to, _ = strings.Cut(spec, "..")
+		if to == "" {
+			to = "HEAD"
+		}
+	default:
+		from = spec
+	}
+	for _, rev := range []string{from, to} {
+		if rev == "" {
+			continue
+		}
+		if _, err := git.ShortSHA(cwd, rev); err != nil {
+			return "", "", fmt.Errorf("unknown revision %s", rev)
+		}
+	}
+	return from, to, nil
+}
+
+// refName names a revision diffRefs returned
+func refName(rev string) string {
+	if rev == "" {
+		return "the working tree"
+	}
+	return rev
+}
+
+// analyzeRef analyzes the project at rev, or the working tree for ""
+func analyzeRef(cwd, rev string) (*analyzer.Analysis, error) {
+	if rev == "" {
+		analysis, err := analyzer.New(cwd).Analyze()
+		if err != nil {
+			return nil, fmt.Errorf("error analyzing codebase: %w", err)
+		}
+		return analysis, nil
+	}
+	data, err := git.Archive(cwd, rev)
+	if err != nil {
+		return nil, err
+	}
+	files, err := fsys.ReadTar(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	analysis, err := analyzer.NewWithOptions(analyzer.Options{Root: cwd, FS: files}).Analyze()
+	if err != nil {
+		return nil, fmt.Errorf("error analyzing %s: %w", rev, err)
+	}
+	return analysis, nil
+}
+
+// printStack summarizes an analysis
+func printStack(a *analyzer.Analysis) {
+	languages := append([]analyzer.Language(nil), a.Languages...)
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].FileCount > languages[j].FileCount
+	})
+	output.Println("🔍 Project analysis")
+	output.Println()
+	for _, l := range languages {
+		output.Printf("   %-20s %5d files  %5.1f%%\n", l.Name, l.FileCount, l.Percentage)
+	}
+	var fws []string
+	for _, f := range a.Frameworks {
+		name := strings.TrimSpace(f.Name + " " + f.Version)
+		if f.Where != "" {
+			name += " (" + f.Where + ")"
+		}
+		fws = append(fws, name)
+	}
+	line := func(label, value string) {
+		if value != "" {
+			output.Printf("   %-20s %s\n", label, value)
+		}
+	}
+	output.Println()
+	line("Frameworks", strings.Join(fws, ", "))
+	line("Package manager", a.Packages.Manager)
+	if n := len(a.Packages.Dependencies) + len(a.Packages.DevDeps); n > 0 {
+		line("Dependencies", fmt.Sprintf("%d (%d dev)", n, len(a.Packages.DevDeps)))
+	}
+	var pkgs []string
+	for _, w := range a.Packages.Workspace {
+		pkgs = append(pkgs, w.Path)
+	}
+	line("Packages", strings.Join(pkgs, ", "))
+	line("Structure", a.Structure.Type)
+	line("Folders", strings.Join(a.Structure.Folders, ", "))
+	p := a.Patterns
+	line("Tests", p.TestFramework)
+	line("Linter", p.Linter)
+	line("Formatter", p.Formatter)
+	line("ORM", p.ORM)
+	line("Styling", p.Styling)
+}
+
+// printStackChanges lists changes under a heading per category
+func printStackChanges(from, to string, changes []stackdiff.Change) {
+	if len(changes) == 0 {
+		output.Printf("✅ No structural changes from %s to %s\n", from, to)
+		return
+	}
+	output.Printf("🔀 Structural changes from %s to %s:\n", from, to)
+	for _, category := range stackdiff.Categories {
+		first := true
+		for _, c := range changes {
+			if c.Category != category {
+				continue
+			}
+			if first {
+				output.Println()
+				output.Printf("   %s\n", stackCategoryTitles[category])
+				first = false
+			}
+			output.Printf("   • %s\n", c.Message)
+		}
+	}
+}
+
+// printStackChangesMarkdown lists changes as Markdown, for pull request
+// descriptions and release notes
+func printStackChangesMarkdown(from, to string, changes []stackdiff.Change) {
+	output.Printf("### Stack changes (%s → %s)\n", from, to)
+	if len(changes) == 0 {
+		output.Println()
+		output.Println("No structural changes.")
+		return
+	}
+	for _, category := range stackdiff.Categories {
+		first := true
+		for _, c := range changes {
+			if c.Category != category {
+				continue
+			}
+			if first {
+				output.Println()
+				output.Printf("**%s**\n", stackCategoryTitles[category])
+				first = false
+			}
+			output.Printf("- %s\n", markdownChange(c))
+		}
+	}
+}
+
+// markdownChange formats c with its subject and versions as code
+func markdownChange(c stackdiff.Change) string {
+	switch {
+	case c.Category == stackdiff.Dependencies && c.Kind == stackdiff.Added:
+		return strings.TrimSpace(fmt.Sprintf("Added `%s` %s", c.Subject, c.To))
+	case c.Category == stackdiff.Dependencies && c.Kind == stackdiff.Removed:
+		return fmt.Sprintf("Removed `%s`", c.Subject)
+	case c.From != "" && c.To != "" && c.Category != stackdiff.Tooling:
+		return fmt.Sprintf("%s `%s` %s → %s", upperFirst(c.Kind), c.Subject, c.From, c.To)
+	}
+	return c.Message
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+	analyzeCmd.Flags().StringVar(&analyzeDiff, "diff", "", "Compare the analysis between two refs, e.g. main..HEAD")
+	analyzeCmd.Flags().StringVar(&analyzeFormat, "format", "text", "Output format for --diff (text, markdown)")
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var benchRuns int
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Time codebase analysis and find slow spots",
+	Long: `Run the analyzer on the current project and report how long each
+phase takes, which top-level directories dominate the file walk, and
+which directories are worth adding to the ignore list in
+.contextpilot/config.yaml.
+
+Use this to diagnose slow init/sync runs.
+
+Examples:
+  contextpilot bench
+  contextpilot bench --runs 5`,
+	Run: runBench,
+}
+
+// generatedDirs are directory names that usually hold build output, caches
+// or third-party code and are safe to ignore
+var generatedDirs = map[string]bool{
+	"target": true, "out": true, "tmp": true, "temp": true, "logs": true,
+	".cache": true, ".turbo": true, ".gradle": true, ".parcel-cache": true,
+	".svelte-kit": true, ".nuxt": true, ".output": true, ".expo": true,
+	"Pods": true, "bower_components": true, "jspm_packages": true,
+	".terraform": true, ".pytest_cache": true, ".mypy_cache": true,
+	".tox": true, "site-packages": true, "public": true, "storybook-static": true,
+}
+
+func runBench(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	if benchRuns < 1 {
+		benchRuns = 1
+	}
+
+	phaseTotals := make(map[string]time.Duration)
+	phaseOrder := []string{}
+	dirTotals := make(map[string]*analyzer.DirTiming)
+	var total time.Duration
+
+	spin := output.StartSpinner("⏱️  Benchmarking analysis...")
+	for i := 0; i < benchRuns; i++ {
+		a := analyzer.New(cwd)
+		start := time.Now()
+		if _, err := a.Analyze(); err != nil {
+			spin.Stop()
+			output.Errorf("❌ Error analyzing codebase: %v\n", err)
+			os.Exit(1)
+		}
+		total += time.Since(start)
+
+		profile := a.Profile()
+		for _, p := range profile.Phases {
+			if _, ok := phaseTotals[p.Name]; !ok {
+				phaseOrder = append(phaseOrder, p.Name)
+			}
+			phaseTotals[p.Name] += p.Duration
+		}
+		for _, d := range profile.Dirs {
+			if dirTotals[d.Path] == nil {
+				dirTotals[d.Path] = &analyzer.DirTiming{Path: d.Path}
+			}
+			dirTotals[d.Path].Duration += d.Duration
+			dirTotals[d.Path].Entries = d.Entries
+			dirTotals[d.Path].CodeFiles = d.CodeFiles
+		}
+	}
+	spin.Stop()
+
+	runs := time.Duration(benchRuns)
+	avg := total / runs
+
+	output.Printf("⏱️  Analysis: %s average over %d run(s)\n", formatDuration(avg), benchRuns)
+	output.Println()
+
+	// Phases, slowest first
+	sort.SliceStable(phaseOrder, func(i, j int) bool {
+		return phaseTotals[phaseOrder[i]] > phaseTotals[phaseOrder[j]]
+	})
+	output.Println("   ├── Phases:")
+	for i, name := range phaseOrder {
+		prefix := "│  ├──"
+		if i == len(phaseOrder)-1 {
+			prefix = "│  └──"
+		}
+		d := phaseTotals[name] / runs
+		output.Printf("   %s %-10s %10s (%.0f%%)\n", prefix, name, formatDuration(d), percentOf(d, avg))
+	}
+
+	// Directories, slowest first
+	dirs := make([]analyzer.DirTiming, 0, len(dirTotals))
+	for _, d := range dirTotals {
+		dirs = append(dirs, *d)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i].Duration > dirs[j].Duration
+	})
+	if len(dirs) > 5 {
+		dirs = dirs[:5]
+	}
+
+	if len(dirs) > 0 {
+		output.Println("   └── Slowest directories:")
+		for i, d := range dirs {
+			prefix := "      ├──"
+			if i == len(dirs)-1 {
+				prefix = "      └──"
+			}
+			output.Printf("   %s %-24s %10s  %6d entries, %d code files\n", prefix, d.Path+"/", formatDuration(d.Duration/runs), d.Entries, d.CodeFiles)
+		}
+	} else {
+		output.Println("   └── No subdirectories scanned")
+	}
+	output.Println()
+
+	// Suggestions
+	suggestions := []string{}
+	walk := phaseTotals["walk"] / runs
+	for _, d := range dirs {
+		share := percentOf(d.Duration/runs, walk)
+		if generatedDirs[d.Path] {
+			suggestions = append(suggestions, "Add '"+d.Path+"' to ignore in .contextpilot/config.yaml (looks like generated or third-party files)")
+		} else if share >= 25 && d.Entries > 1000 && d.CodeFiles*10 < d.Entries {
+			suggestions = append(suggestions, "'"+d.Path+"/' is mostly non-code files; consider adding it to ignore in .contextpilot/config.yaml")
+		}
+	}
+	if avg > 5*time.Second {
+		suggestions = append(suggestions, "Analysis is slow; in monorepos run contextpilot from the package you're working on to focus the scan")
+	}
+
+	if len(suggestions) > 0 {
+		output.Println("💡 Suggestions:")
+		for _, s := range suggestions {
+			output.Printf("   • %s\n", s)
+		}
+	} else {
+		output.Info("✅ Nothing stands out — analysis looks healthy.")
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
+
+func percentOf(part, whole time.Duration) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.Flags().IntVar(&benchRuns, "runs", 3, "Number of analysis runs to average")
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/changelog"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	changelogSince string
+	changelogTitle string
+	changelogWrite bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Draft a CHANGELOG section from decisions and sessions",
+	Long: `Assemble the decisions logged and the sessions completed since a
+release into a draft CHANGELOG section.
+
+--since takes a tag or any commit (its commit date is used) or a date
+(YYYY-MM-DD); it defaults to the latest tag, or everything when there
+are no tags. A session counts as completed when its branch has been
+merged into HEAD or deleted, including sessions already archived or
+folded into history by 'sessions gc'.
+
+Entries are grouped under one heading per tag: a decision's tags
+(decision --tag), and a session's branch prefix (feat/..., fix/...).
+Entries without one go under "Other".
+
+The section is printed to stdout; --write inserts it into CHANGELOG.md
+instead, above the newest release.
+
+Examples:
+  contextpilot changelog
+  contextpilot changelog --since v1.2.0 --title v1.3.0
+  contextpilot changelog --since 2026-01-01 --write`,
+	Args:        cobra.NoArgs,
+	Annotations: jsonCapable,
+	Run:         runChangelog,
+}
+
+func runChangelog(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	since := changelogSince
+	if since == "" {
+		since = git.LatestTag(cwd)
+	}
+	from, err := changelogStart(cwd, since)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	decs, err := decisions.New(cwd).List()
+	if err != nil {
+		output.Errorf("❌ Error reading decisions: %v\n", err)
+		os.Exit(1)
+	}
+	completed, err := session.New(cwd).Completed(from)
+	if err != nil {
+		output.Errorf("❌ Error reading sessions: %v\n", err)
+		os.Exit(1)
+	}
+
+	entries := []changelog.Entry{}
+	day := from.Format("2006-01-02")
+	for _, d := range decs {
+		if d.Date < day {
+			continue
+		}
+		text := fmt.Sprintf("%s (decision #%d)", d.Text, d.ID)
+		if len(d.Tags) == 0 {
+			entries = append(entries, changelog.Entry{Text: text})
+		}
+		for _, t := range d.Tags {
+			entries = append(entries, changelog.Entry{Tag: t, Text: text})
+		}
+	}
+	for _, s := range completed {
+		entries = append(entries, changelog.Entry{Tag: branchTag(s.Branch), Text: sessionEntry(s)})
+	}
+	policy := projectPolicy(cwd, nil)
+	for i := range entries {
+		entries[i].Text = policy.Apply(entries[i].Text)
+	}
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{"since": since, "title": changelogTitle, "entries": entries})
+		return
+	}
+	if len(entries) == 0 {
+		if since == "" {
+			output.Infof("📋 No decisions or completed sessions yet\n")
+		} else {
+			output.Infof("📋 No decisions or completed sessions since %s\n", since)
+		}
+		return
+	}
+
+	section := changelog.Section(changelogTitle, time.Now().Format("2006-01-02"), entries)
+	if !changelogWrite {
+		output.Write(section)
+		return
+	}
+	if err := changelog.Insert(cwd, section); err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	output.Printf("✅ Added %s to %s (%d entr%s)\n", changelogTitle, changelog.FileName, len(entries), pluralY(len(entries)))
+}
+
+// changelogStart resolves --since to a time: the commit date of a ref, or
+// a YYYY-MM-DD date. Empty means the beginning of time.
+func changelogStart(cwd, since string) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	if t, err := git.CommitTime(cwd, since); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", since, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("--since %q is neither a git ref nor a YYYY-MM-DD date", since)
+}
+
+// branchTag returns the prefix of a branch such as feat/login, or "" for
+// branches without one
+func branchTag(branch string) string {
+	prefix, _, ok := strings.Cut(branch, "/")
+	if !ok {
+		return ""
+	}
+	return prefix
+}
+
+// sessionEntry describes a completed session for a changelog, with its
+// issue and pull request
+func sessionEntry(s session.Session) string {
+	links := []string{}
+	for _, l := range []string{s.Issue, s.PR} {
+		if l != "" {
+			links = append(links, l)
+		}
+	}
+	if len(links) == 0 {
+		return s.Task
+	}
+	return s.Task + " (" + strings.Join(links, ", ") + ")"
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "Tag, commit or YYYY-MM-DD date to start from (default: the latest tag)")
+	changelogCmd.Flags().StringVar(&changelogTitle, "title", "Unreleased", "Heading of the drafted section, e.g. a version")
+	changelogCmd.Flags().BoolVar(&changelogWrite, "write", false, "Insert the section into CHANGELOG.md instead of printing it")
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/drift"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var checkMinScore int
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Verify context files for CI",
+	Long: `Check that context is healthy, for CI pipelines:
+
+  files   every context file exists
+  drift   context files match the current analysis ('sync' would not
+          change them), and the stack hasn't changed since the last sync
+          (new or removed dependencies, framework, tooling, folders)
+  score   the quality score is at least --min-score (or check.minScore
+          in .contextpilot/config.yaml, default 50)
+
+Exit status is 0 when every check passes, 1 when one fails and 2 when
+the checks could not run (e.g. the project isn't initialized). Use --json
+for a machine-readable report. Under GitHub Actions, failures are also
+emitted as error annotations.`,
+	Annotations: jsonCapable,
+	Args:        cobra.NoArgs,
+	Run:         runCheck,
+}
+
+// ciCheck is the outcome of one check
+type ciCheck struct {
+	Name    string       `json:"name"`
+	Passed  bool         `json:"passed"`
+	Detail  string       `json:"detail"`
+	Files   []string     `json:"files,omitempty"`
+	Changes []drift.Item `json:"changes,omitempty"`
+}
+
+func runCheck(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(2)
+	}
+	if !config.Exists(cwd) {
+		output.Errorf("❌ ContextPilot not initialized in this directory\n")
+		output.Info("Run 'contextpilot init' and commit the generated files.")
+		os.Exit(2)
+	}
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(2)
+	}
+
+	minScore := cfg.Check.MinScore
+	if minScore == 0 {
+		minScore = config.DefaultMinScore
+	}
+	if cmd.Flags().Changed("min-score") {
+		minScore = checkMinScore
+	}
+
+	var checks []ciCheck
+
+	var missing []string
+	for _, f := range generator.Outputs(cwd) {
+		if _, err := os.Stat(filepath.Join(cwd, f)); err != nil {
+			missing = append(missing, f)
+		}
+	}
+	files := ciCheck{Name: "files", Passed: len(missing) == 0, Detail: "all context files present", Files: missing}
+	if !files.Passed {
+		files.Detail = "missing " + strings.Join(missing, ", ")
+	}
+	checks = append(checks, files)
+
+	stale, d, err := contextState(cwd)
+	if err != nil {
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
+		os.Exit(2)
+	}
+	var drifted []string
+	for _, f := range stale {
+		if !slices.Contains(missing, f) {
+			drifted = append(drifted, f)
+		}
+	}
+	driftCheck := ciCheck{Name: "drift", Passed: len(drifted) == 0 && len(d.Items) == 0, Detail: "context files match the code", Files: drifted, Changes: d.Items}
+	switch {
+	case len(drifted) > 0:
+		driftCheck.Detail = strings.Join(drifted, ", ") + " out of date; run 'contextpilot sync'"
+	case len(d.Items) > 0:
+		driftCheck.Detail = fmt.Sprintf("%d change(s) since the last sync; run 'contextpilot sync'", len(d.Items))
+	}
+	checks = append(checks, driftCheck)
+
+	result := calculateScore(cwd)
+	score := ciCheck{
+		Name:   "score",
+		Passed: result.total >= minScore,
+		Detail: fmt.Sprintf("%d/100 (minimum %d)", result.total, minScore),
+	}
+	checks = append(checks, score)
+
+	passed := true
+	for _, c := range checks {
+		passed = passed && c.Passed
+	}
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{
+			"passed":   passed,
+			"score":    result.total,
+			"minScore": minScore,
+			"checks":   checks,
+		})
+	} else {
+		output.Println("🔎 ContextPilot Check")
+		output.Println()
+		for _, c := range checks {
+			icon := "✅"
+			if !c.Passed {
+				icon = "❌"
+			}
+			output.Printf("%s %-6s %s\n", icon, c.Name, c.Detail)
+			for _, item := range c.Changes {
+				output.Printf("          • %s\n", item)
+			}
+		}
+		output.Println()
+		if passed {
+			output.Println("✅ All checks passed")
+		} else {
+			output.Println("❌ Context checks failed")
+			for _, issue := range result.issues {
+				output.Info("   • " + issue)
+			}
+		}
+	}
+
+	// GitHub reads workflow commands from stdout; Write is silent in
+	// JSON mode, so annotations never corrupt the report
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		for _, c := range checks {
+			if !c.Passed {
+				output.Write(fmt.Sprintf("::error title=ContextPilot %s::%s\n", c.Name, c.Detail))
+			}
+		}
+	}
+
+	if !passed {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	checkCmd.Flags().IntVar(&checkMinScore, "min-score", config.DefaultMinScore, "Lowest passing score (overrides check.minScore)")
+}
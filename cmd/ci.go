@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/drift"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/github"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var ciPR int
+var ciBase string
+var ciDryRun bool
+
+// commentMarker identifies the sticky comment so later runs update it
+// instead of adding another
+const commentMarker = "<!-- contextpilot:ci-comment -->"
+
+// A pull request changing at least largeDiffFiles files or largeDiffLines
+// lines is an architectural change that deserves a decision
+const (
+	largeDiffFiles = 20
+	largeDiffLines = 500
+)
+
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Integrations for CI pipelines",
+}
+
+var ciCommentCmd = &cobra.Command{
+	Use:   "comment",
+	Short: "Comment the context drift a pull request introduces",
+	Long: `Post a comment on the pull request summarizing the context drift it
+introduces, and update the same comment on every later push:
+
+  - dependencies, framework, tooling and top-level folders that changed
+    since the base branch and aren't reflected in the context files
+  - context files that 'sync' would change
+  - a large diff (20+ files or 500+ lines, or a new framework) that
+    doesn't record an architectural decision
+
+It runs under GitHub Actions on pull_request events: the token comes from
+GITHUB_TOKEN, the repository from GITHUB_REPOSITORY, the pull request
+from the event payload (or --pr) and the base branch from GITHUB_BASE_REF
+(or --base). Check out with fetch-depth: 0 so the base branch is there
+to compare against. A pull request without drift gets no comment, and an
+earlier comment is updated to say it is resolved.
+
+--dry-run prints the comment instead of posting it, and needs no token.
+
+Examples:
+  contextpilot ci comment
+  contextpilot ci comment --pr 42 --base origin/main --dry-run`,
+	Args:        cobra.NoArgs,
+	Annotations: jsonCapable,
+	Run:         runCIComment,
+}
+
+// prDrift is the drift a pull request introduces
+type prDrift struct {
+	Base    string       `json:"base"`
+	Changes []drift.Item `json:"changes"`
+	Stale   []string     `json:"stale"`
+	Files   int          `json:"files"`
+	Lines   int          `json:"lines"`
+	// DecisionMissing is set when the diff is architectural and records
+	// no decision; Architectural says why it is, e.g. "changes the
+	// framework"
+	DecisionMissing bool   `json:"decisionMissing"`
+	Architectural   string `json:"architectural,omitempty"`
+}
+
+// clean reports whether there is nothing to comment on
+func (d prDrift) clean() bool {
+	return len(d.Changes) == 0 && len(d.Stale) == 0 && !d.DecisionMissing
+}
+
+func runCIComment(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	if !config.Exists(cwd) {
+		output.Errorf("❌ ContextPilot not initialized in this directory\n")
+		output.Info("Run 'contextpilot init' and commit the generated files.")
+		os.Exit(1)
+	}
+
+	pr := ciPR
+	if pr == 0 {
+		if pr, err = github.PullRequest(); err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if pr == 0 && !ciDryRun {
+		output.Errorf("❌ Not running for a pull request; pass --pr\n")
+		os.Exit(1)
+	}
+	var client *github.Client
+	if !ciDryRun {
+		if client, err = github.FromEnv(); err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	base, err := ciBaseRef(cwd)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	d, err := pullRequestDrift(cwd, base)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	body := commentBody(d)
+
+	action, url := "dry-run", ""
+	if !ciDryRun {
+		action, url, err = postComment(client, pr, d, body)
+		if err != nil {
+			output.Errorf("❌ Error commenting on #%d: %v\n", pr, err)
+			os.Exit(1)
+		}
+	}
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{
+			"pr":     pr,
+			"clean":  d.clean(),
+			"drift":  d,
+			"action": action,
+			"url":    url,
+			"body":   body,
+		})
+		return
+	}
+	switch action {
+	case "dry-run":
+		output.Print(body)
+	case "created":
+		output.Printf("💬 Commented on #%d: %s\n", pr, url)
+	case "updated":
+		output.Printf("✅ Updated the comment on #%d: %s\n", pr, url)
+	case "unchanged":
+		output.Printf("✅ The comment on #%d is up to date: %s\n", pr, url)
+	default:
+		output.Printf("✅ No context drift in #%d\n", pr)
+	}
+}
+
+// ciBaseRef returns --base, or the branch the pull request merges into
+func ciBaseRef(cwd string) (string, error) {
+	if ciBase != "" {
+		if _, err := git.ShortSHA(cwd, ciBase); err != nil {
+			return "", fmt.Errorf("unknown base %s", ciBase)
+		}
+		return ciBase, nil
+	}
+	ref := os.Getenv("GITHUB_BASE_REF")
+	if ref == "" {
+		return "", fmt.Errorf("GITHUB_BASE_REF is not set; pass --base")
+	}
+	for _, rev := range []string{"origin/" + ref, ref} {
+		if _, err := git.ShortSHA(cwd, rev); err == nil {
+			return rev, nil
+		}
+	}
+	return "", fmt.Errorf("base branch %s not found; check out with fetch-depth: 0", ref)
+}
+
+// pullRequestDrift finds what the pull request from base to HEAD left
+// out of the context: stack changes since the last sync, stale context
+// files, and a large diff without a decision
+func pullRequestDrift(cwd, base string) (prDrift, error) {
+	d := prDrift{Base: base, Changes: []drift.Item{}, Stale: []string{}}
+	analysis, err := analyzer.New(cwd).Analyze()
+	if err != nil {
+		return d, fmt.Errorf("error analyzing codebase: %w", err)
+	}
+	sort.Slice(analysis.Languages, func(i, j int) bool {
+		return analysis.Languages[i].FileCount > analysis.Languages[j].FileCount
+	})
+	if stale := generator.New(analysis, cwd).Stale(); stale != nil {
+		d.Stale = stale
+	}
+
+	// A sync in the pull request updates the snapshot, so only what
+	// changed after it is drift. Projects synced by releases that didn't
+	// write one compare with the base branch's instead.
+	old, ok, err := drift.Load(cwd)
+	if err != nil {
+		return d, err
+	}
+	if !ok {
+		if data, err := git.Show(cwd, base, drift.SnapshotFile); err == nil {
+			old, err = drift.Parse(data)
+			ok = err == nil
+		}
+	}
+	if ok {
+		d.Changes = drift.Since(cwd, old, analysis, generator.Outputs(cwd))
+	}
+
+	files, lines, err := git.DiffStat(cwd, base)
+	if err != nil {
+		return d, err
+	}
+	d.Files, d.Lines = len(files), lines
+	if d.Files >= largeDiffFiles || d.Lines >= largeDiffLines {
+		d.Architectural = fmt.Sprintf("changes %d file(s) and %d line(s)", d.Files, d.Lines)
+	}
+	for _, item := range d.Changes {
+		if item.Kind == drift.FrameworkChanged {
+			d.Architectural = "changes the framework"
+		}
+	}
+	d.DecisionMissing = d.Architectural != "" && !recordsDecision(cwd, files)
+	return d, nil
+}
+
+// recordsDecision reports whether files include a decision
+func recordsDecision(cwd string, files []string) bool {
+	dirs := []string{".contextpilot/decisions"}
+	if cfg, err := config.Load(cwd); err == nil && cfg.Decisions.Backend == "madr" {
+		dir := cfg.Decisions.Dir
+		if dir == "" {
+			dir = decisions.DefaultADRDir
+		}
+		dirs = append(dirs, strings.TrimSuffix(dir, "/")+"/")
+	}
+	for _, f := range files {
+		for _, dir := range dirs {
+			if strings.HasPrefix(f, dir) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// commentBody renders the pull request comment
+func commentBody(d prDrift) string {
+	var b strings.Builder
+	b.WriteString(commentMarker + "\n")
+	b.WriteString("## 🧭 ContextPilot\n")
+	if d.clean() {
+		b.WriteString("\n✅ The AI context files are up to date with this pull request.\n")
+		return b.String()
+	}
+
+	if len(d.Changes) > 0 || len(d.Stale) > 0 {
+		b.WriteString("\nThis pull request changes things the AI context files don't reflect yet.\n")
+	}
+	if len(d.Changes) > 0 {
+		fmt.Fprintf(&b, "\n**Stack changes since `%s`**\n", d.Base)
+		for _, item := range d.Changes {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	if len(d.Stale) > 0 {
+		b.WriteString("\n**Out of date:** ")
+		for i, f := range d.Stale {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "`%s`", f)
+		}
+		b.WriteString("\n")
+	}
+	if d.DecisionMissing {
+		fmt.Fprintf(&b, "\n**No decision recorded.** This pull request %s without recording why. Log the decision with `contextpilot decision \"...\"`.\n", d.Architectural)
+	}
+	if len(d.Changes) > 0 || len(d.Stale) > 0 {
+		b.WriteString("\nRun `contextpilot sync` and commit the result to bring the context files up to date.\n")
+	}
+	return b.String()
+}
+
+// postComment creates or updates the sticky comment on pr. A clean pull
+// request only updates a comment that is already there.
+func postComment(client *github.Client, pr int, d prDrift, body string) (action, url string, err error) {
+	existing, err := client.Find(pr, commentMarker)
+	if err != nil {
+		return "", "", err
+	}
+	switch {
+	case existing != nil && existing.Body == body:
+		return "unchanged", existing.URL, nil
+	case existing != nil:
+		c, err := client.Update(existing.ID, body)
+		if err != nil {
+			return "", "", err
+		}
+		return "updated", c.URL, nil
+	case d.clean():
+		return "skipped", "", nil
+	}
+	c, err := client.Create(pr, body)
+	if err != nil {
+		return "", "", err
+	}
+	return "created", c.URL, nil
+}
+
+func init() {
+	rootCmd.AddCommand(ciCmd)
+	ciCmd.AddCommand(ciCommentCmd)
+	ciCommentCmd.Flags().IntVar(&ciPR, "pr", 0, "Pull request number (default from GITHUB_EVENT_PATH)")
+	ciCommentCmd.Flags().StringVar(&ciBase, "base", "", "Base revision to compare with (default origin/$GITHUB_BASE_REF)")
+	ciCommentCmd.Flags().BoolVar(&ciDryRun, "dry-run", false, "Print the comment instead of posting it")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"sort"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var contextHeaderCopy bool
+
+var contextHeaderCmd = &cobra.Command{
+	Use:   "context-header",
+	Short: "Print a compact project header for ad-hoc prompts",
+	Long: `Print a three-line elevator pitch of the project (stack, package
+manager, test command, top conventions) to prepend to any prompt when
+the whole CLAUDE.md would be too much.
+
+Examples:
+  contextpilot context-header
+  contextpilot context-header --copy
+  echo "$(contextpilot context-header)
+
+Why does the checkout test fail?" | llm`,
+	Run: runContextHeader,
+}
+
+func runContextHeader(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	analysis, err := analyzer.New(cwd).Analyze()
+	if err != nil {
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
+		os.Exit(1)
+	}
+	sort.Slice(analysis.Languages, func(i, j int) bool {
+		return analysis.Languages[i].FileCount > analysis.Languages[j].FileCount
+	})
+
+	header := projectPolicy(cwd, analysis).Apply(generator.Header(analysis))
+
+	if contextHeaderCopy {
+		if err := copyToClipboard(header); err != nil {
+			output.Errorf("⚠️  Could not copy to clipboard: %v\n", err)
+		} else {
+			output.Info("✅ Header copied to clipboard!")
+			return
+		}
+	}
+	output.Write(header)
+}
+
+func init() {
+	rootCmd.AddCommand(contextHeaderCmd)
+	contextHeaderCmd.Flags().BoolVarP(&contextHeaderCopy, "copy", "c", false, "Copy to clipboard instead of printing")
+}
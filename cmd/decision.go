@@ -1,11 +1,13 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
-	"github.com/jitin-nhz/contextpilot/internal/decisions"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +15,10 @@ var (
 	listDecisions   bool
 	deleteDecision  int
 	decisionContext string
+	decisionCommit  string
+	decisionFiles   []string
+	decisionDiff    bool
+	decisionTags    []string
 )
 
 var decisionCmd = &cobra.Command{
@@ -23,18 +29,24 @@ var decisionCmd = &cobra.Command{
 Examples:
   contextpilot decision "Using Redis for sessions instead of JWT"
   contextpilot decision "Chose Prisma over Drizzle" --context "Team already knows Prisma"
+  contextpilot decision "Moved auth to middleware" --commit HEAD --files 'src/auth/*'
+  contextpilot decision --from-diff
   contextpilot decision --list
   contextpilot decision --delete 3
+  contextpilot decision edit 3 --text "Using Redis for sessions and caching"
 
-Decisions are stored in .contextpilot/decisions.md and 
+Decisions are stored in .contextpilot/decisions.json and rendered to
+.contextpilot/decisions.md (or kept as MADR files
+in docs/adr/ with decisions.backend: madr in config.yaml) and
 automatically included in generated context files.`,
-	Run: runDecision,
+	Annotations: jsonCapable,
+	Run:         runDecision,
 }
 
 func runDecision(cmd *cobra.Command, args []string) {
-	cwd, err := os.Getwd()
+	cwd, err := projectDir()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error getting current directory: %v\n", err)
+		output.Errorf("❌ Error getting current directory: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -43,10 +55,13 @@ func runDecision(cmd *cobra.Command, args []string) {
 	// Handle delete
 	if deleteDecision > 0 {
 		if err := mgr.Delete(deleteDecision); err != nil {
-			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			output.Errorf("❌ %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("✅ Deleted decision #%d\n", deleteDecision)
+		output.Printf("✅ Deleted decision #%d\n", deleteDecision)
+		if output.IsJSON() {
+			printJSON(map[string]interface{}{"deleted": deleteDecision})
+		}
 		return
 	}
 
@@ -54,26 +69,30 @@ func runDecision(cmd *cobra.Command, args []string) {
 	if listDecisions {
 		decs, err := mgr.List()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Error listing decisions: %v\n", err)
+			output.Errorf("❌ Error listing decisions: %v\n", err)
 			os.Exit(1)
 		}
+		if output.IsJSON() {
+			printJSON(map[string]interface{}{"decisions": decs})
+			return
+		}
 
 		if len(decs) == 0 {
-			fmt.Println("📋 No decisions logged yet")
-			fmt.Println()
-			fmt.Println("Add one with:")
-			fmt.Println("  contextpilot decision \"Your decision here\"")
+			output.Println("📋 No decisions logged yet")
+			output.Info()
+			output.Info("Add one with:")
+			output.Info("  contextpilot decision \"Your decision here\"")
 			return
 		}
 
-		fmt.Println("📋 Architectural Decisions")
-		fmt.Println()
-		
+		output.Println("📋 Architectural Decisions")
+		output.Println()
+
 		// Print as table
-		fmt.Println("┌─────┬────────────┬────────────────────────────────────────────────────────┐")
-		fmt.Println("│  #  │    Date    │ Decision                                               │")
-		fmt.Println("├─────┼────────────┼────────────────────────────────────────────────────────┤")
-		
+		output.Println("┌─────┬────────────┬────────────────────────────────────────────────────────┐")
+		output.Println("│  #  │    Date    │ Decision                                               │")
+		output.Println("├─────┼────────────┼────────────────────────────────────────────────────────┤")
+
 		for _, d := range decs {
 			text := d.Text
 			if len(text) > 54 {
@@ -81,28 +100,62 @@ func runDecision(cmd *cobra.Command, args []string) {
 			}
 			// Replace newlines with spaces
 			text = sanitizeForTable(text)
-			fmt.Printf("│ %3d │ %s │ %-54s │\n", d.ID, d.Date, text)
+			output.Printf("│ %3d │ %s │ %-54s │\n", d.ID, d.Date, text)
+			if links := d.Links(); links != "" {
+				links = "↳ " + sanitizeForTable(links)
+				if len([]rune(links)) > 54 {
+					links = string([]rune(links)[:51]) + "..."
+				}
+				output.Printf("│     │            │ %-54s │\n", links)
+			}
 		}
-		
-		fmt.Println("└─────┴────────────┴────────────────────────────────────────────────────────┘")
-		fmt.Println()
-		fmt.Printf("Total: %d decision(s)\n", len(decs))
+
+		output.Println("└─────┴────────────┴────────────────────────────────────────────────────────┘")
+		output.Println()
+		output.Printf("Total: %d decision(s)\n", len(decs))
 		return
 	}
 
 	// Handle add
-	if len(args) == 0 {
-		fmt.Println("❌ Please provide a decision to log")
-		fmt.Println()
-		fmt.Println("Usage:")
-		fmt.Println("  contextpilot decision \"Your decision here\"")
-		fmt.Println("  contextpilot decision --list")
-		fmt.Println("  contextpilot decision --delete <id>")
+	if len(args) == 0 && !decisionDiff {
+		output.Errorf("❌ Please provide a decision to log\n")
+		output.Info()
+		output.Info("Usage:")
+		output.Info("  contextpilot decision \"Your decision here\"")
+		output.Info("  contextpilot decision --list")
+		output.Info("  contextpilot decision --delete <id>")
 		return
 	}
 
-	text := args[0]
-	
+	files := decisionFiles
+	if cmd.Flags().Changed("files") && len(args) > 1 {
+		// An unquoted glob like --files src/auth/* is expanded by the shell,
+		// so every path after the first lands in args
+		var rest []string
+		for _, arg := range args[1:] {
+			if _, err := os.Stat(arg); err == nil {
+				files = append(files, arg)
+			} else {
+				rest = append(rest, arg)
+			}
+		}
+		args = append(args[:1], rest...)
+	}
+
+	var commit string
+	if decisionCommit != "" {
+		commit, err = git.ShortSHA(cwd, decisionCommit)
+		if err != nil {
+			output.Errorf("❌ Cannot resolve commit %s: %v\n", decisionCommit, err)
+			os.Exit(1)
+		}
+	}
+
+	var text string
+	if len(args) > 0 {
+		text = args[0]
+	}
+
 	// If multiple args, join them (allows unquoted input)
 	if len(args) > 1 {
 		text = ""
@@ -116,20 +169,42 @@ func runDecision(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	decision, err := mgr.Add(text, decisionContext)
+	draft := decisions.Decision{
+		Text:    text,
+		Context: decisionContext,
+		Commit:  commit,
+		Files:   files,
+		Tags:    decisionTags,
+	}
+	if decisionDiff {
+		draft = captureFromDiff(cwd, draft, cmd.Flags().Changed("context"))
+	}
+
+	decision, err := mgr.AddDecision(draft)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error logging decision: %v\n", err)
+		output.Errorf("❌ Error logging decision: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("✅ Decision #%d logged!\n", decision.ID)
-	fmt.Println()
-	fmt.Printf("   📝 %s\n", text)
-	if decisionContext != "" {
-		fmt.Printf("   📎 Context: %s\n", decisionContext)
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{"decision": decision})
+		return
+	}
+
+	output.Printf("✅ Decision #%d logged!\n", decision.ID)
+	output.Println()
+	output.Printf("   📝 %s\n", decision.Text)
+	if decision.Context != "" {
+		output.Printf("   📎 Context: %s\n", decision.Context)
+	}
+	if links := decision.Links(); links != "" {
+		output.Printf("   🔗 %s\n", links)
+	}
+	if len(decision.Tags) > 0 {
+		output.Printf("   🏷️  Tags: %s\n", strings.Join(decision.Tags, ", "))
 	}
-	fmt.Println()
-	fmt.Println("💡 Run 'contextpilot sync' to include in context files")
+	output.Info()
+	output.Info("💡 Run 'contextpilot sync' to include in context files")
 }
 
 func sanitizeForTable(s string) string {
@@ -149,4 +224,8 @@ func init() {
 	decisionCmd.Flags().BoolVarP(&listDecisions, "list", "l", false, "List all decisions")
 	decisionCmd.Flags().IntVarP(&deleteDecision, "delete", "d", 0, "Delete decision by ID")
 	decisionCmd.Flags().StringVarP(&decisionContext, "context", "c", "", "Add context/reasoning for the decision")
+	decisionCmd.Flags().StringVar(&decisionCommit, "commit", "", "Link the decision to a commit, e.g. HEAD")
+	decisionCmd.Flags().StringSliceVarP(&decisionTags, "tag", "t", nil, "Tag the decision, e.g. --tag security (comma-separated or repeated)")
+	decisionCmd.Flags().BoolVar(&decisionDiff, "from-diff", false, "Capture a decision for the staged changes (or the last commit)")
+	decisionCmd.Flags().StringSliceVar(&decisionFiles, "files", nil, "Link the decision to files or globs (comma-separated or repeated)")
 }
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+)
+
+// maxLinkedFiles is how many files --from-diff links before collapsing them
+// into directory globs
+const maxLinkedFiles = 8
+
+// captureFromDiff shows the staged diff (or, with nothing staged, the last
+// commit), asks what was decided and why, and links the affected files and
+// commit. Values already set by flags or arguments are kept as defaults.
+func captureFromDiff(cwd string, d decisions.Decision, contextSet bool) decisions.Decision {
+	if !git.IsRepo(cwd) {
+		output.Errorf("❌ --from-diff needs a git repository\n")
+		os.Exit(1)
+	}
+
+	files := git.StagedFiles(cwd)
+	stat := git.StagedStat(cwd)
+	source := "Staged changes"
+	if len(files) == 0 {
+		sha, err := git.ShortSHA(cwd, "HEAD")
+		if err != nil {
+			output.Errorf("❌ Nothing staged and no commits yet\n")
+			os.Exit(1)
+		}
+		files = git.CommitFiles(cwd, "HEAD")
+		stat = git.CommitStat(cwd, "HEAD")
+		source = "Last commit"
+		if d.Commit == "" {
+			d.Commit = sha
+		}
+	}
+	if len(d.Files) == 0 {
+		d.Files = collapseFiles(files)
+	}
+
+	output.Infof("📂 %s:\n", source)
+	for _, line := range strings.Split(stat, "\n") {
+		output.Infof("   %s\n", line)
+	}
+	output.Info()
+
+	// Without input the flags and detected links are used as they are
+	if noInput {
+		if d.Text == "" {
+			inputDisabled("decision --from-diff", "pass the decision as an argument")
+			os.Exit(1)
+		}
+		return d
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	if d.Text == "" {
+		output.Infof("What did you decide? ")
+		d.Text = readLine(reader)
+		if d.Text == "" {
+			output.Errorf("❌ Decision is required\n")
+			os.Exit(1)
+		}
+	}
+	if !contextSet {
+		output.Infof("Why? ")
+		d.Context = readLine(reader)
+	}
+	output.Infof("Files [%s]: ", strings.Join(d.Files, ", "))
+	if input := readLine(reader); input != "" {
+		d.Files = nil
+		for _, f := range strings.Split(input, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				d.Files = append(d.Files, f)
+			}
+		}
+	}
+	output.Info()
+	return d
+}
+
+// collapseFiles returns files unchanged when there are few of them, and one
+// "dir/*" glob per directory otherwise
+func collapseFiles(files []string) []string {
+	if len(files) <= maxLinkedFiles {
+		return files
+	}
+	seen := map[string]bool{}
+	var globs []string
+	for _, f := range files {
+		glob := path.Dir(f) + "/*"
+		if path.Dir(f) == "." {
+			glob = f
+		}
+		if !seen[glob] {
+			seen[glob] = true
+			globs = append(globs, glob)
+		}
+	}
+	sort.Strings(globs)
+	return globs
+}
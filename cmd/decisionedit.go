@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editText    string
+	editContext string
+	editEditor  bool
+)
+
+var decisionEditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edit a logged decision in place",
+	Long: `Fix or expand a decision without changing its ID or date.
+
+Examples:
+  contextpilot decision edit 3 --text "Using Redis for sessions and rate limits"
+  contextpilot decision edit 3 --context "JWT revocation was too complex"
+  contextpilot decision edit 3 --editor
+
+--editor opens $VISUAL or $EDITOR (falling back to vi) on the decision text
+and context, separated by a '---' line.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDecisionEdit,
+}
+
+func runDecisionEdit(cmd *cobra.Command, args []string) {
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		output.Errorf("❌ Invalid decision ID: %s\n", args[0])
+		os.Exit(1)
+	}
+
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	mgr := decisions.New(cwd)
+	d, err := mgr.Get(id)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	textSet := cmd.Flags().Changed("text")
+	contextSet := cmd.Flags().Changed("context")
+	if !editEditor && !textSet && !contextSet {
+		output.Errorf("❌ Nothing to change: use --text, --context or --editor\n")
+		os.Exit(1)
+	}
+
+	if textSet {
+		d.Text = editText
+	}
+	if contextSet {
+		d.Context = editContext
+	}
+	if editEditor {
+		if inputDisabled("decision edit --editor", "use --text and --context") {
+			os.Exit(1)
+		}
+		text, context, err := editInEditor(d)
+		if err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		d.Text, d.Context = text, context
+	}
+
+	if err := mgr.Update(*d); err != nil {
+		output.Errorf("❌ Error updating decision: %v\n", err)
+		os.Exit(1)
+	}
+
+	output.Printf("✅ Decision #%d updated!\n", d.ID)
+	output.Println()
+	output.Printf("   📝 %s\n", d.Text)
+	if d.Context != "" {
+		output.Printf("   📎 Context: %s\n", d.Context)
+	}
+	output.Info()
+	output.Info("💡 Run 'contextpilot sync' to include in context files")
+}
+
+// editorSeparator splits decision text from its context in the editor buffer
+const editorSeparator = "---"
+
+// editInEditor opens the user's editor on d and returns the edited text and
+// context. Lines starting with '#' are ignored.
+func editInEditor(d *decisions.Decision) (string, string, error) {
+	f, err := os.CreateTemp("", "contextpilot-decision-*.md")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	fmt.Fprintf(f, "%s\n%s\n%s\n", d.Text, editorSeparator, d.Context)
+	fmt.Fprintf(f, "# Editing decision #%d. The decision text goes above the '%s' line,\n", d.ID, editorSeparator)
+	fmt.Fprintf(f, "# its context below. Lines starting with '#' are ignored.\n")
+	if err := f.Close(); err != nil {
+		return "", "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], f.Name())...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return "", "", fmt.Errorf("editor failed: %w", err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read temp file: %w", err)
+	}
+	text, context := parseEditorBuffer(string(data))
+	if text == "" {
+		return "", "", fmt.Errorf("decision text is empty, aborting edit")
+	}
+	return text, context, nil
+}
+
+// parseEditorBuffer splits an edited buffer into decision text and context
+func parseEditorBuffer(buf string) (string, string) {
+	var text, context []string
+	inContext := false
+	for _, line := range strings.Split(buf, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !inContext && strings.TrimSpace(line) == editorSeparator {
+			inContext = true
+			continue
+		}
+		if inContext {
+			context = append(context, line)
+		} else {
+			text = append(text, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(text, "\n")), strings.TrimSpace(strings.Join(context, "\n"))
+}
+
+func init() {
+	decisionCmd.AddCommand(decisionEditCmd)
+	decisionEditCmd.Flags().StringVar(&editText, "text", "", "Replace the decision text")
+	decisionEditCmd.Flags().StringVarP(&editContext, "context", "c", "", "Replace the context/reasoning")
+	decisionEditCmd.Flags().BoolVarP(&editEditor, "editor", "e", false, "Edit text and context in $EDITOR")
+}
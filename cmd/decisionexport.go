@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+)
+
+var decisionExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export decisions as a standalone document",
+	Long: `Publish the decision log outside the repo, e.g. to Confluence or Notion.
+
+Formats:
+  html  Standalone page grouped by status and tag (default)
+  docx  Word document with the same grouping, importable by Confluence
+  json  All decisions with every field
+  csv   One row per decision, for spreadsheets and database imports
+
+Text formats go to stdout unless --output is set; docx is written to
+decisions.docx by default.
+
+Examples:
+  contextpilot decision export > decisions.html
+  contextpilot decision export --format docx -o adr-log.docx
+  contextpilot decision export --format csv -o decisions.csv`,
+	Args: cobra.NoArgs,
+	Run:  runDecisionExport,
+}
+
+func runDecisionExport(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	decs, err := decisions.New(cwd).List()
+	if err != nil {
+		output.Errorf("❌ Error listing decisions: %v\n", err)
+		os.Exit(1)
+	}
+
+	title := filepath.Base(cwd) + " — Architectural Decisions"
+	data, err := decisions.Export(decs, exportFormat, title)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	path := exportOutput
+	if path == "" && exportFormat == "docx" {
+		path = "decisions.docx"
+	}
+	if path == "" {
+		output.Write(string(data))
+		return
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		output.Errorf("❌ Error writing %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	output.Infof("📤 Exported %d decision(s) to %s\n", len(decs), path)
+}
+
+func init() {
+	decisionCmd.AddCommand(decisionExportCmd)
+	decisionExportCmd.Flags().StringVarP(&exportFormat, "format", "f", "html", "Output format: "+strings.Join(decisions.ExportFormats, ", "))
+	decisionExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to a file instead of stdout")
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/spf13/cobra"
+)
+
+var importDryRun bool
+
+var decisionImportCmd = &cobra.Command{
+	Use:   "import <path>",
+	Short: "Import decisions from existing docs",
+	Long: `Bring existing decision records into ContextPilot.
+
+<path> can be:
+  - an ADR directory such as docs/adr (MADR or Nygard layout)
+  - a DECISIONS.md style log with one "## " heading per decision
+  - a Notion or other markdown export (one page per decision)
+  - another project's .contextpilot/decisions.json or decisions.md
+
+Titles, dates and status are picked up where present. ADR numbers become
+decision IDs when they are free, and decisions that are already logged are
+skipped, so importing the same source again is safe.
+
+Examples:
+  contextpilot decision import docs/adr
+  contextpilot decision import DECISIONS.md --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDecisionImport,
+}
+
+func runDecisionImport(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	found, err := decisions.Parse(args[0])
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if len(found) == 0 {
+		output.Println("📭 No decisions found in " + args[0])
+		return
+	}
+
+	if importDryRun {
+		output.Printf("🔍 %d decision(s) found in %s\n", len(found), args[0])
+		output.Println()
+		for _, d := range found {
+			printImported(d)
+		}
+		return
+	}
+
+	mgr := decisions.New(cwd)
+	added, skipped, err := mgr.Import(found)
+	if err != nil {
+		output.Errorf("❌ Error importing decisions: %v\n", err)
+		os.Exit(1)
+	}
+
+	output.Printf("📥 Imported %d decision(s) from %s\n", len(added), args[0])
+	if len(added) > 0 {
+		output.Println()
+		for _, d := range added {
+			printImported(d)
+		}
+	}
+	if skipped > 0 {
+		output.Info()
+		output.Infof("⏭️  Skipped %d already logged\n", skipped)
+	}
+	if len(added) > 0 {
+		output.Info()
+		output.Info("💡 Run 'contextpilot sync' to include in context files")
+	}
+}
+
+func printImported(d decisions.Decision) {
+	text := sanitizeForTable(d.Text)
+	if len([]rune(text)) > 70 {
+		text = string([]rune(text)[:67]) + "..."
+	}
+	line := "   • "
+	if d.ID > 0 {
+		line += fmt.Sprintf("#%d ", d.ID)
+	}
+	line += text
+	if d.Status != "" {
+		line += fmt.Sprintf(" [%s]", d.Status)
+	}
+	output.Println(line)
+}
+
+func init() {
+	decisionCmd.AddCommand(decisionImportCmd)
+	decisionImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without writing")
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/llm"
+	"github.com/jitin-nhz/contextpilot/internal/mine"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mineSince  string
+	mineLLM    bool
+	mineYes    bool
+	mineDryRun bool
+)
+
+var decisionMineCmd = &cobra.Command{
+	Use:   "mine",
+	Short: "Propose decisions found in git history",
+	Long: `Look through recent commit messages and pull request titles for
+decisions that were made but never logged, such as "migrate tests from
+Jest to Vitest" or "replace moment with date-fns", and offer each one
+for recording. Related commits are clustered together, and the decision
+is linked to the commit that started it and the files they touched.
+Candidates a logged decision already mentions are skipped.
+
+Each candidate asks: y records it, n skips it, e lets you reword it
+first and q stops. --yes records all of them, --dry-run only lists them.
+
+With --llm the configured model (see 'contextpilot enrich --help') finds
+candidates instead; only commit subjects, dates and SHAs are sent.
+
+--since takes a count with d, w, m (months) or y, or any date git
+understands.
+
+Examples:
+  contextpilot decision mine
+  contextpilot decision mine --since 6m --dry-run
+  contextpilot decision mine --since 2026-01-01 --llm`,
+	Args:        cobra.NoArgs,
+	Annotations: jsonCapable,
+	Run:         runDecisionMine,
+}
+
+// relativeSince matches --since values such as 3m or 2w
+var relativeSince = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// gitSince turns 3m into git's 3.months.ago, leaving other dates as they are
+func gitSince(since string) string {
+	m := relativeSince.FindStringSubmatch(since)
+	if m == nil {
+		return since
+	}
+	unit := map[string]string{"d": "days", "w": "weeks", "m": "months", "y": "years"}[m[2]]
+	return m[1] + "." + unit + ".ago"
+}
+
+func runDecisionMine(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	if !git.IsRepo(cwd) {
+		output.Errorf("❌ decision mine needs a git repository\n")
+		os.Exit(1)
+	}
+
+	commits, err := git.Log(cwd, gitSince(mineSince))
+	if err != nil {
+		output.Errorf("❌ Error reading git history: %v\n", err)
+		os.Exit(1)
+	}
+	mgr := decisions.New(cwd)
+	decs, err := mgr.List()
+	if err != nil {
+		output.Errorf("❌ Error reading decisions: %v\n", err)
+		os.Exit(1)
+	}
+
+	var candidates []mine.Candidate
+	if mineLLM {
+		client, err := llm.Load(cwd)
+		if err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		spin := output.StartSpinner("✨ Asking " + client.String() + "...")
+		candidates, err = mine.WithLLM(context.Background(), client, commits, decs)
+		spin.Stop()
+		if err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		candidates = mine.Heuristic(commits, decs)
+	}
+
+	if output.IsJSON() && (mineDryRun || !mineYes) {
+		if candidates == nil {
+			candidates = []mine.Candidate{}
+		}
+		printJSON(map[string]interface{}{"commits": len(commits), "candidates": candidates})
+		return
+	}
+	if len(candidates) == 0 {
+		output.Printf("📭 No unrecorded decisions found in %d commit(s)\n", len(commits))
+		return
+	}
+
+	if mineDryRun {
+		output.Printf("🔍 %d candidate decision(s) in %d commit(s)\n", len(candidates), len(commits))
+		output.Println()
+		for _, c := range candidates {
+			output.Printf("   • %s (%d commit(s))\n", c.Summary, len(c.Commits))
+			output.Printf("     → %s\n", c.Text)
+		}
+		return
+	}
+	if !mineYes && inputDisabled("decision mine", "pass --yes to record every candidate, or --dry-run to list them") {
+		os.Exit(1)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var added []decisions.Decision
+	for _, c := range candidates {
+		text := c.Text
+		if !mineYes {
+			output.Infof("💡 %s (%d commit(s))\n", c.Summary, len(c.Commits))
+			for _, e := range c.Commits {
+				output.Infof("   %s %s\n", e.SHA, e.Subject)
+			}
+			answer := askMine(reader, text)
+			if answer == "q" {
+				break
+			}
+			if answer == "n" {
+				output.Info()
+				continue
+			}
+			if answer == "e" {
+				output.Infof("Decision [%s]: ", text)
+				if edited := readLine(reader); edited != "" {
+					text = edited
+				}
+			}
+			output.Info()
+		}
+
+		d := decisions.Decision{Text: text, Context: c.Context(), Files: collapseFiles(c.Files())}
+		if len(c.Commits) > 0 {
+			d.Commit = c.Commits[0].SHA
+		}
+		decision, err := mgr.AddDecision(d)
+		if err != nil {
+			output.Errorf("❌ Error logging decision: %v\n", err)
+			os.Exit(1)
+		}
+		added = append(added, *decision)
+	}
+
+	if output.IsJSON() {
+		if added == nil {
+			added = []decisions.Decision{}
+		}
+		printJSON(map[string]interface{}{"commits": len(commits), "decisions": added})
+		return
+	}
+	output.Printf("✅ Logged %d of %d candidate decision(s)\n", len(added), len(candidates))
+	for _, d := range added {
+		output.Printf("   • #%d %s\n", d.ID, d.Text)
+	}
+	if len(added) > 0 {
+		output.Info()
+		output.Info("💡 Run 'contextpilot sync' to include in context files")
+	}
+}
+
+// askMine asks whether to record text and returns y, n, e or q
+func askMine(reader *bufio.Reader, text string) string {
+	for {
+		output.Infof("Record \"%s\"? [y/n/e(dit)/q]: ", text)
+		line, err := reader.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		switch {
+		case answer == "y" || answer == "yes":
+			return "y"
+		case answer == "n" || answer == "no" || answer == "":
+			if err != nil && answer == "" {
+				return "q" // End of input
+			}
+			return "n"
+		case answer == "e" || answer == "edit":
+			return "e"
+		case answer == "q" || answer == "quit":
+			return "q"
+		}
+	}
+}
+
+func init() {
+	decisionCmd.AddCommand(decisionMineCmd)
+	decisionMineCmd.Flags().StringVar(&mineSince, "since", "3m", "How far back to look: 30d, 6w, 3m, 1y or a date")
+	decisionMineCmd.Flags().BoolVar(&mineLLM, "llm", false, "Have the configured LLM find candidates from commit subjects")
+	decisionMineCmd.Flags().BoolVarP(&mineYes, "yes", "y", false, "Record every candidate without asking")
+	decisionMineCmd.Flags().BoolVar(&mineDryRun, "dry-run", false, "List candidates without recording them")
+}
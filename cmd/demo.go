@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var demoKeep bool
+
+var demoCmd = &cobra.Command{
+	Use:   "demo",
+	Short: "Take a guided tour on an example project",
+	Long: `Create a throwaway example project (Next.js, TypeScript, Prisma,
+Tailwind) in a temporary directory and walk through the full loop:
+init, decision, save, resume and score, with narration at each step.
+
+Nothing in your current directory is touched. The example project is
+deleted afterwards unless --keep is given.
+
+Examples:
+  contextpilot demo
+  contextpilot demo --keep`,
+	Run: runDemo,
+}
+
+// demoFiles is the example project created by 'contextpilot demo'
+var demoFiles = map[string]string{
+	"package.json": `{
+  "name": "acme-shop",
+  "private": true,
+  "scripts": {
+    "dev": "next dev",
+    "build": "next build",
+    "test": "jest",
+    "lint": "next lint"
+  },
+  "dependencies": {
+    "next": "^14.2.0",
+    "react": "^18.3.0",
+    "@prisma/client": "^5.14.0",
+    "zustand": "^4.5.0"
+  },
+  "devDependencies": {
+    "typescript": "^5.4.0",
+    "tailwindcss": "^3.4.0",
+    "jest": "^29.7.0",
+    "eslint": "^8.57.0",
+    "prettier": "^3.2.0"
+  }
+}
+`,
+	"tsconfig.json":             "{\n  \"compilerOptions\": { \"strict\": true }\n}\n",
+	"tailwind.config.js":        "module.exports = { content: ['./src/**/*.tsx'] }\n",
+	".eslintrc.json":            "{ \"extends\": \"next/core-web-vitals\" }\n",
+	".prettierrc":               "{ \"singleQuote\": true }\n",
+	"prisma/schema.prisma":      "model Order {\n  id    Int @id @default(autoincrement())\n  total Int\n}\n",
+	"src/app/page.tsx":          "export default function Home() {\n  return <main className=\"p-4\">Shop</main>\n}\n",
+	"src/app/checkout/page.tsx": "export default function Checkout() {\n  return <main>Checkout</main>\n}\n",
+	"src/components/Cart.tsx":   "export function Cart() {\n  return <div>Cart</div>\n}\n",
+	"src/lib/db.ts":             "import { PrismaClient } from '@prisma/client'\n\nexport const db = new PrismaClient()\n",
+	"src/store/cart.ts":         "import { create } from 'zustand'\n\nexport const useCart = create(() => ({ items: [] }))\n",
+}
+
+func runDemo(cmd *cobra.Command, args []string) {
+	origDir, err := os.Getwd()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	dir, err := os.MkdirTemp("", "contextpilot-demo-")
+	if err != nil {
+		output.Errorf("❌ Error creating demo project: %v\n", err)
+		os.Exit(1)
+	}
+	for name, content := range demoFiles {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			output.Errorf("❌ Error creating demo project: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			output.Errorf("❌ Error creating demo project: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := os.Chdir(dir); err != nil {
+		output.Errorf("❌ Error entering demo project: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		os.Chdir(origDir)
+		if !demoKeep {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	output.Println("👋 Welcome to ContextPilot!")
+	output.Println()
+	output.Println("We created an example Next.js shop in a temporary directory and will")
+	output.Println("run the commands you'd use day to day against it.")
+
+	demoStep(1, "Generate context files",
+		"'contextpilot init' scans the project and writes context files that",
+		"Cursor, Claude Code and Copilot read automatically.")
+	runInit(cmd, nil)
+
+	demoStep(2, "Log an architectural decision",
+		"Decisions explain the 'why' that AI tools can't infer from code.",
+		"'contextpilot sync' folds them into every context file.")
+	decisionContext = "Team already knows Prisma; migrations are reviewed in PRs"
+	runDecision(cmd, []string{"Use Prisma for all database access"})
+	output.Println()
+	runSync(cmd, nil)
+
+	demoStep(3, "Save your work session",
+		"Before you stop for the day, save what you were doing, what you",
+		"tried and what's next. Sessions are scoped to your git branch.")
+	saveQuick = true
+	saveState = "Cart works, payment form half done"
+	saveNext = []string{"Validate card fields", "Add order confirmation email"}
+	runSave(cmd, []string{"Building the checkout page"})
+
+	demoStep(4, "Resume in a fresh chat",
+		"'contextpilot resume' turns the session into a prompt and copies it",
+		"to your clipboard. Here it is printed instead:")
+	resumeNoCopy = true
+	runResume(cmd, nil)
+
+	demoStep(5, "Check context quality",
+		"'contextpilot score' tells you how complete and fresh your context is.")
+	runScore(cmd, nil)
+
+	output.Println(repeatStr("─", 60))
+	output.Println("🎉 That's the full loop! Try it on your own project:")
+	output.Println()
+	output.Println("   cd your-project")
+	output.Println("   contextpilot init")
+	output.Println()
+	if demoKeep {
+		output.Printf("📁 The example project was kept at %s\n", dir)
+	} else {
+		output.Info("🧹 Cleaned up the example project")
+	}
+}
+
+// demoStep prints a numbered step heading with its explanation
+func demoStep(n int, title string, lines ...string) {
+	output.Println()
+	output.Println(repeatStr("─", 60))
+	output.Printf("Step %d/5: %s\n", n, title)
+	for _, line := range lines {
+		output.Printf("   %s\n", line)
+	}
+	output.Println(repeatStr("─", 60))
+	output.Println()
+}
+
+func init() {
+	rootCmd.AddCommand(demoCmd)
+	demoCmd.Flags().BoolVar(&demoKeep, "keep", false, "Keep the example project instead of deleting it")
+}
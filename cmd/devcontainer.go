@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/jitin-nhz/contextpilot/internal/devcontainer"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var devcontainerDryRun bool
+
+var devcontainerCmd = &cobra.Command{
+	Use:   "devcontainer",
+	Short: "Wire ContextPilot into devcontainer.json",
+	Long: `Add ContextPilot to the project's devcontainer.json so Codespaces and
+dev containers come up ready:
+
+  - the MCP server under customizations.vscode.mcp (VS Code, Cursor)
+  - Copilot instruction files pointing at the generated context files
+  - a postCreateCommand that installs contextpilot, unless the file
+    already has one
+
+.devcontainer/devcontainer.json (or .devcontainer.json) is created if it
+doesn't exist. Other settings are kept; comments are not, so review the
+diff before committing.
+
+Examples:
+  contextpilot devcontainer --dry-run
+  contextpilot devcontainer`,
+	Args: cobra.NoArgs,
+	Run:  runDevcontainer,
+}
+
+func runDevcontainer(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	var instructions []string
+	for _, f := range generator.Outputs(cwd) {
+		// Copilot reads its own instructions files without being told
+		if f != ".github/copilot-instructions.md" && f != generator.InstructionsDir {
+			instructions = append(instructions, f)
+		}
+	}
+	opts := devcontainer.Options{
+		Server: map[string]interface{}{
+			"type":    "stdio",
+			"command": "contextpilot",
+			"args":    []string{"mcp"},
+		},
+		Instructions: instructions,
+	}
+
+	path := devcontainer.Path(cwd)
+	res, err := devcontainer.Inject(path, opts, devcontainerDryRun)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	display := displayPath(cwd, path)
+
+	switch {
+	case len(res.Changed) == 0:
+		output.Printf("✅ %s already has ContextPilot\n", display)
+	case devcontainerDryRun:
+		output.Printf("🔍 Would update %s:\n", display)
+	case res.Created:
+		output.Printf("✅ Created %s\n", display)
+	default:
+		output.Printf("✅ Updated %s\n", display)
+	}
+	for _, c := range res.Changed {
+		output.Printf("   • %s\n", c)
+	}
+
+	if res.OwnPostCreate {
+		output.Info()
+		output.Info("💡 postCreateCommand is already set; make it install contextpilot too:")
+		output.Infof("   %s\n", devcontainer.InstallCommand)
+	}
+	if res.HadComments && len(res.Changed) > 0 && !devcontainerDryRun {
+		output.Errorf("⚠️  Comments in %s were not kept\n", display)
+	}
+	if len(res.Changed) > 0 && !devcontainerDryRun {
+		output.Info()
+		output.Info("💡 Commit it and rebuild the container to pick up the changes")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(devcontainerCmd)
+	devcontainerCmd.Flags().BoolVar(&devcontainerDryRun, "dry-run", false, "Show what would change without writing anything")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/consistency"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+	"github.com/spf13/cobra"
+)
+
+var diffContextCmd = &cobra.Command{
+	Use:   "diff-context",
+	Short: "Check that the context files for each tool say the same thing",
+	Long: `Compare .cursorrules, CLAUDE.md, GEMINI.md, AGENTS.md and
+.github/copilot-instructions.md and report any that no longer agree with
+the others on the stack, conventions or decisions, usually because
+someone edited it by hand.
+
+Each file is compared with what 'sync' would generate for it:
+
+  missing  a framework, tool, convention or decision its generated
+           version states, and the other files still do
+  added    a line only this file has, e.g. a rule written into CLAUDE.md
+           that .cursorrules never got
+
+Differences every file shares, like a stack that changed since the last
+sync, are left to 'contextpilot check'. Rules meant for one tool belong
+under instructions in .contextpilot/config.yaml, which sync keeps.
+
+Exits 1 when a file has drifted. Use --json for a machine-readable
+report.`,
+	Annotations: jsonCapable,
+	Args:        cobra.NoArgs,
+	Run:         runDiffContext,
+}
+
+func runDiffContext(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	spin := output.StartSpinner("🔍 Comparing context files...")
+	analysis, err := analyzer.New(cwd).Analyze()
+	spin.Stop()
+	if err != nil {
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
+		os.Exit(1)
+	}
+	sort.Slice(analysis.Languages, func(i, j int) bool {
+		return analysis.Languages[i].FileCount > analysis.Languages[j].FileCount
+	})
+	project, err := consistency.Load(cwd, analysis)
+	if err != nil {
+		output.Errorf("❌ Error reading decisions: %v\n", err)
+		os.Exit(1)
+	}
+	r := consistency.Compare(fsys.OS(cwd), project)
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{
+			"consistent": r.Consistent,
+			"files":      r.Files,
+			"drifted":    r.Drifted(),
+		})
+		if !r.Consistent {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(r.Files) < 2 {
+		output.Println("🔀 Fewer than two context files to compare")
+		output.Info()
+		output.Info("Add outputs to .contextpilot/config.yaml and run 'contextpilot sync'.")
+		return
+	}
+
+	output.Println("🔀 Context consistency")
+	output.Println()
+	for _, f := range r.Files {
+		switch {
+		case !f.Drifted:
+			output.Printf("✅ %s\n", f.Path)
+			continue
+		case !f.Generated:
+			output.Printf("❌ %s (written by hand)\n", f.Path)
+		default:
+			output.Printf("❌ %s\n", f.Path)
+		}
+		for _, m := range f.Missing {
+			output.Printf("   - %s missing: %s (in %s)\n", m.Kind, m.Text, strings.Join(m.In, ", "))
+		}
+		for _, l := range f.Added {
+			output.Printf("   + line %d (%s) only here: %s\n", l.Line, l.Kind, l.Text)
+		}
+	}
+	output.Println()
+
+	if r.Consistent {
+		output.Println("✅ Context files agree")
+		return
+	}
+	drifted := r.Drifted()
+	output.Printf("❌ %s drifted from the other context files\n", strings.Join(drifted, ", "))
+	output.Info("→ Log decisions with 'contextpilot decision' and put tool-specific rules under instructions in .contextpilot/config.yaml,")
+	output.Info("  then run 'contextpilot sync' to regenerate every file")
+	os.Exit(1)
+}
+
+func init() {
+	rootCmd.AddCommand(diffContextCmd)
+}
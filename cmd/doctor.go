@@ -0,0 +1,441 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/mcpconfig"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose environment and configuration problems",
+	Long: `Check that ContextPilot can do its job here and explain how to fix
+anything that's off:
+
+  - git is installed and this is a repository
+  - a clipboard tool is available for 'resume'
+  - .contextpilot/ and the context files are writable
+  - .contextpilot/config.yaml parses and has valid settings
+  - no hand-written .cursorrules / CLAUDE.md that 'sync' would overwrite
+  - MCP client configs (Claude, Cursor, Windsurf, VS Code) point at a
+    contextpilot binary that exists
+
+Exits with status 1 when a problem is found.`,
+	Annotations: jsonCapable,
+	Run:         runDoctor,
+}
+
+// doctorStatus is the outcome of one check
+type doctorStatus int
+
+const (
+	doctorOK doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+// doctorCheck is one diagnostic line, with a fix when it isn't OK
+type doctorCheck struct {
+	name   string
+	status doctorStatus
+	detail string
+	fix    string
+}
+
+func runDoctor(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	var checks []doctorCheck
+	checks = append(checks, checkGit(cwd)...)
+	checks = append(checks, checkClipboard())
+	checks = append(checks, checkWritable(cwd))
+	checks = append(checks, checkConfig(cwd)...)
+	checks = append(checks, checkHandWritten(cwd)...)
+	checks = append(checks, checkMCPConfigs(cwd)...)
+
+	if output.IsJSON() {
+		printDoctorJSON(checks)
+		return
+	}
+
+	output.Println("🩺 ContextPilot Doctor")
+	output.Println()
+
+	var warnings, problems int
+	for _, c := range checks {
+		icon := "✅"
+		switch c.status {
+		case doctorWarn:
+			icon = "⚠️ "
+			warnings++
+		case doctorFail:
+			icon = "❌"
+			problems++
+		}
+		output.Printf("%s %s: %s\n", icon, c.name, c.detail)
+		if c.fix != "" && c.status != doctorOK {
+			output.Printf("   → %s\n", c.fix)
+		}
+	}
+
+	output.Println()
+	output.Printf("%d check(s), %d warning(s), %d problem(s)\n", len(checks), warnings, problems)
+	if problems > 0 {
+		os.Exit(1)
+	}
+}
+
+// doctorStatusNames are the JSON names of doctor statuses
+var doctorStatusNames = map[doctorStatus]string{doctorOK: "ok", doctorWarn: "warning", doctorFail: "problem"}
+
+func printDoctorJSON(checks []doctorCheck) {
+	type jsonCheck struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Detail string `json:"detail"`
+		Fix    string `json:"fix,omitempty"`
+	}
+	list := []jsonCheck{}
+	problems := 0
+	for _, c := range checks {
+		jc := jsonCheck{Name: c.name, Status: doctorStatusNames[c.status], Detail: c.detail}
+		if c.status != doctorOK {
+			jc.Fix = c.fix
+		}
+		if c.status == doctorFail {
+			problems++
+		}
+		list = append(list, jc)
+	}
+	printJSON(map[string]interface{}{"checks": list, "ok": problems == 0})
+	if problems > 0 {
+		os.Exit(1)
+	}
+}
+
+func checkGit(cwd string) []doctorCheck {
+	if _, err := exec.LookPath("git"); err != nil {
+		return []doctorCheck{{
+			name: "git", status: doctorFail, detail: "git not found on PATH",
+			fix: "Install git (https://git-scm.com); sessions, sync and suggest rely on it",
+		}}
+	}
+	version, _ := git.Output(cwd, "--version")
+	checks := []doctorCheck{{name: "git", detail: strings.TrimSpace(version)}}
+	if !git.IsRepo(cwd) {
+		checks = append(checks, doctorCheck{
+			name: "repository", status: doctorWarn, detail: "not inside a git repository",
+			fix: "Run 'git init' so sessions can follow branches",
+		})
+		return checks
+	}
+	if tracked := git.TrackedFiles(cwd, localEntries...); len(tracked) > 0 {
+		checks = append(checks, doctorCheck{
+			name: "local files", status: doctorWarn, detail: fmt.Sprintf("%d local file(s) tracked by git, e.g. %s", len(tracked), tracked[0]),
+			fix: "Run 'git rm -r --cached " + strings.Join(trackedDirs(tracked), " ") + "'; sessions and logs are per machine",
+		})
+	}
+	return checks
+}
+
+func checkClipboard() doctorCheck {
+	cmd, err := clipboardCommand()
+	if err == nil {
+		err = cmd.Err
+	}
+	if err != nil {
+		return doctorCheck{
+			name: "clipboard", status: doctorWarn, detail: err.Error(),
+			fix: "Install a clipboard tool, or use 'contextpilot resume --no-copy'",
+		}
+	}
+	return doctorCheck{name: "clipboard", detail: filepath.Base(cmd.Path)}
+}
+
+func checkWritable(cwd string) doctorCheck {
+	dir := filepath.Join(cwd, ".contextpilot")
+	if _, err := os.Stat(dir); err != nil {
+		dir = cwd
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return doctorCheck{
+			name: "permissions", status: doctorFail, detail: fmt.Sprintf("cannot write to %s", dir),
+			fix: "Fix the directory's ownership or permissions",
+		}
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	for _, name := range generator.ContextFiles {
+		path := filepath.Join(cwd, name)
+		if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0200 == 0 {
+			return doctorCheck{
+				name: "permissions", status: doctorFail, detail: name + " is read-only",
+				fix: "Run 'chmod u+w " + name + "' so sync can update it",
+			}
+		}
+	}
+	return doctorCheck{name: "permissions", detail: "project is writable"}
+}
+
+func checkConfig(cwd string) []doctorCheck {
+	if !config.Exists(cwd) {
+		return []doctorCheck{{
+			name: "config", status: doctorWarn, detail: "not initialized",
+			fix: "Run 'contextpilot init'",
+		}}
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return []doctorCheck{{
+			name: "config", status: doctorFail, detail: err.Error(),
+			fix: "Fix the YAML in .contextpilot/config.yaml (or delete it and re-run 'contextpilot init')",
+		}}
+	}
+
+	var checks []doctorCheck
+	if data, err := os.ReadFile(config.Path(cwd)); err == nil {
+		dec := yaml.NewDecoder(strings.NewReader(string(data)))
+		dec.KnownFields(true)
+		var strict config.Config
+		if err := dec.Decode(&strict); err != nil {
+			checks = append(checks, doctorCheck{
+				name: "config", status: doctorWarn, detail: "unknown setting: " + yamlErrorDetail(err),
+				fix: "Check the key's spelling; unknown settings are ignored",
+			})
+		}
+	}
+	if cfg.History.MaxAge != "" {
+		if _, err := config.ParseAge(cfg.History.MaxAge); err != nil {
+			checks = append(checks, doctorCheck{
+				name: "config", status: doctorFail, detail: "history.maxAge: " + err.Error(),
+				fix: "Use a duration such as 90d or 720h",
+			})
+		}
+	}
+	if cfg.MCP.Timeout != "" {
+		if d, err := time.ParseDuration(strings.TrimSpace(cfg.MCP.Timeout)); err != nil || d < 0 {
+			checks = append(checks, doctorCheck{
+				name: "config", status: doctorFail, detail: fmt.Sprintf("mcp.timeout: invalid duration %q", cfg.MCP.Timeout),
+				fix: "Use a duration such as 30s or 5m, or 0 for none",
+			})
+		}
+	}
+	for _, rule := range cfg.Score.Rules {
+		if err := rule.Validate(); err != nil {
+			checks = append(checks, doctorCheck{
+				name: "config", status: doctorFail, detail: err.Error(),
+				fix: "Fix the rule under score.rules; invalid rules always fail",
+			})
+		}
+	}
+	if err := cfg.Redact.Validate(); err != nil {
+		checks = append(checks, doctorCheck{
+			name: "config", status: doctorFail, detail: err.Error(),
+			fix: "Fix the redact section; generated files show the error instead of content until then",
+		})
+	}
+	tools := make([]string, 0, len(cfg.Instructions))
+	for tool := range cfg.Instructions {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		if !slices.Contains(config.InstructionTools, tool) {
+			checks = append(checks, doctorCheck{
+				name: "config", status: doctorWarn, detail: fmt.Sprintf("instructions.%s is not a known tool", tool),
+				fix: "Use " + strings.Join(config.InstructionTools, ", ") + "; other keys are ignored",
+			})
+		}
+	}
+	switch cfg.Decisions.Backend {
+	case "", "markdown", "madr", "sqlite":
+	default:
+		checks = append(checks, doctorCheck{
+			name: "config", status: doctorFail, detail: fmt.Sprintf("decisions.backend %q is not supported", cfg.Decisions.Backend),
+			fix: "Set decisions.backend to markdown, madr or sqlite",
+		})
+	}
+	switch cfg.Storage {
+	case "", config.StorageFiles, config.StorageSQLite:
+	default:
+		checks = append(checks, doctorCheck{
+			name: "config", status: doctorFail, detail: fmt.Sprintf("storage %q is not supported", cfg.Storage),
+			fix: "Set storage to files or sqlite",
+		})
+	}
+	if cfg.Decisions.Template != "" {
+		path := cfg.Decisions.Template
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(cwd, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			checks = append(checks, doctorCheck{
+				name: "config", status: doctorFail, detail: "decisions.template " + cfg.Decisions.Template + " not found",
+				fix: "Create the template or remove the setting",
+			})
+		}
+	}
+
+	if len(checks) == 0 {
+		detail := ".contextpilot/config.yaml is valid"
+		if parent := config.Parent(cwd); parent != "" {
+			if rel, err := filepath.Rel(cwd, config.Path(parent)); err == nil {
+				detail += ", on top of " + filepath.ToSlash(rel)
+			}
+		}
+		checks = append(checks, doctorCheck{name: "config", detail: detail})
+	}
+	return checks
+}
+
+// unknownFieldPattern matches yaml.v3's error for a key with no matching field
+var unknownFieldPattern = regexp.MustCompile(`line (\d+): field (\S+) not found`)
+
+// yamlErrorDetail turns yaml.v3's strict-decoding error into "key (line N)"
+func yamlErrorDetail(err error) string {
+	if m := unknownFieldPattern.FindStringSubmatch(err.Error()); m != nil {
+		return fmt.Sprintf("%s (line %s)", m[2], m[1])
+	}
+	return strings.TrimPrefix(err.Error(), "yaml: ")
+}
+
+// checkHandWritten flags context files that exist but weren't generated by
+// ContextPilot, which sync would overwrite
+func checkHandWritten(cwd string) []doctorCheck {
+	var checks []doctorCheck
+	for _, name := range generator.ContextFiles {
+		data, err := os.ReadFile(filepath.Join(cwd, name))
+		if err != nil || strings.Contains(string(data), "Generated by ContextPilot") {
+			continue
+		}
+		checks = append(checks, doctorCheck{
+			name: "rule files", status: doctorWarn, detail: name + " was written by hand; 'sync' will overwrite it",
+			fix: "Move its rules into decisions ('contextpilot decision ...') or back it up before syncing",
+		})
+	}
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{name: "rule files", detail: "no conflicting hand-written files"})
+	}
+	return checks
+}
+
+// mcpConfigPaths returns the MCP client config files to inspect, with the
+// key holding their server map
+func mcpConfigPaths(cwd string) map[string]string {
+	paths := map[string]string{
+		filepath.Join(cwd, ".mcp.json"):           "mcpServers",
+		filepath.Join(cwd, ".cursor", "mcp.json"): "mcpServers",
+		filepath.Join(cwd, ".vscode", "mcp.json"): "servers",
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return paths
+	}
+	paths[filepath.Join(home, ".claude.json")] = "mcpServers"
+	paths[filepath.Join(home, ".cursor", "mcp.json")] = "mcpServers"
+	paths[filepath.Join(home, ".codeium", "windsurf", "mcp_config.json")] = "mcpServers"
+	paths[mcpconfig.ClaudeDesktopPath(home)] = "mcpServers"
+	return paths
+}
+
+// checkMCPConfigs verifies every contextpilot server entry in known MCP
+// client configs launches a binary that exists
+func checkMCPConfigs(cwd string) []doctorCheck {
+	var checks []doctorCheck
+	for path, key := range mcpConfigPaths(cwd) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		display := path
+		if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, home) {
+			display = "~" + strings.TrimPrefix(path, home)
+		} else if rel, err := filepath.Rel(cwd, path); err == nil {
+			display = rel
+		}
+
+		var cfg map[string]json.RawMessage
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			checks = append(checks, doctorCheck{
+				name: "mcp", status: doctorFail, detail: display + " is not valid JSON",
+				fix: "Fix the file so your editor can load its MCP servers",
+			})
+			continue
+		}
+		var servers map[string]struct {
+			Command string   `json:"command"`
+			Args    []string `json:"args"`
+		}
+		json.Unmarshal(cfg[key], &servers)
+
+		for name, srv := range servers {
+			if !strings.Contains(srv.Command+" "+strings.Join(srv.Args, " "), "contextpilot") {
+				continue
+			}
+			checks = append(checks, checkMCPCommand(display, name, srv.Command))
+		}
+	}
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{
+			name: "mcp", status: doctorWarn, detail: "no MCP client is configured to run contextpilot",
+			fix: "Add \"contextpilot\": {\"command\": \"contextpilot\", \"args\": [\"mcp\"]} to your client's MCP config",
+		})
+	}
+	// Map iteration order is random; keep the output stable
+	sort.Slice(checks, func(i, j int) bool { return checks[i].detail < checks[j].detail })
+	return checks
+}
+
+func checkMCPCommand(file, server, command string) doctorCheck {
+	label := fmt.Sprintf("%s (%s)", file, server)
+	resolved, err := exec.LookPath(command)
+	if err != nil {
+		fix := "Use the full path to the contextpilot binary"
+		if exe, err := os.Executable(); err == nil {
+			fix += ", e.g. " + exe
+		}
+		return doctorCheck{name: "mcp", status: doctorFail, detail: label + ": command " + command + " not found", fix: fix}
+	}
+
+	if filepath.Base(command) == "contextpilot" || filepath.Base(command) == "contextpilot.exe" {
+		if exe, err := os.Executable(); err == nil && !sameFile(exe, resolved) {
+			return doctorCheck{
+				name: "mcp", status: doctorWarn, detail: label + ": runs " + resolved + ", not this binary (" + exe + ")",
+				fix: "Point the config at the contextpilot you upgrade, or remove the stale copy",
+			}
+		}
+	}
+	return doctorCheck{name: "mcp", detail: label + " → " + resolved}
+}
+
+func sameFile(a, b string) bool {
+	ia, errA := os.Stat(a)
+	ib, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(ia, ib)
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
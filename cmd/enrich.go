@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/enrich"
+	"github.com/jitin-nhz/contextpilot/internal/ignore"
+	"github.com/jitin-nhz/contextpilot/internal/llm"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	enrichInclude []string
+	enrichDryRun  bool
+)
+
+var enrichCmd = &cobra.Command{
+	Use:   "enrich",
+	Short: "Draft architecture and conventions prose with an LLM",
+	Long: `Send the project analysis to a language model and have it draft an
+"Architecture Overview" and a "Conventions in Detail" section for the
+context files. Opt-in: nothing is sent until an LLM is configured, in
+.contextpilot/config.yaml or ~/.config/contextpilot/config.yaml:
+
+  llm:
+    provider: openai      # openai (or any compatible server), anthropic, ollama
+    model: gpt-4o-mini    # default per provider
+    url: https://api.openai.com/v1       # default per provider
+    apiKeyEnv: OPENAI_API_KEY            # default per provider
+
+Only the structured analysis (languages, framework, folders, dependency
+names, detected conventions) and the active decisions are sent, never
+source code. --include adds the named files, unless .contextpilotignore
+excludes them. The redaction policy in config.yaml applies to all of it.
+
+The draft is written to .contextpilot/enrichment.md for review. Edit or
+trim it, then run 'contextpilot sync': it is copied into each context
+file between contextpilot:enrichment markers. Delete the file to drop
+the sections again.
+
+Examples:
+  contextpilot enrich --dry-run
+  contextpilot enrich
+  contextpilot enrich --include README.md --include docs/architecture.md`,
+	Args: cobra.NoArgs,
+	Run:  runEnrich,
+}
+
+func runEnrich(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	if !config.Exists(cwd) {
+		output.Errorf("❌ ContextPilot not initialized in this directory\n")
+		output.Info()
+		output.Info("Run 'contextpilot init' first to generate context files.")
+		os.Exit(1)
+	}
+
+	client, err := llm.Load(cwd)
+	if err != nil && !(enrichDryRun && errors.Is(err, llm.ErrNotConfigured)) {
+		output.Errorf("❌ %v\n", err)
+		if errors.Is(err, llm.ErrNotConfigured) {
+			output.Info()
+			output.Info("💡 See: contextpilot enrich --help")
+		}
+		os.Exit(1)
+	}
+
+	analysis, err := analyzer.New(cwd).Analyze()
+	if err != nil {
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
+		os.Exit(1)
+	}
+	decs, err := decisions.New(cwd).List()
+	if err != nil {
+		output.Errorf("❌ Error reading decisions: %v\n", err)
+		os.Exit(1)
+	}
+
+	files := map[string]string{}
+	if len(enrichInclude) > 0 {
+		matcher, err := ignore.Load(cwd)
+		if err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		for _, p := range enrichInclude {
+			rel := filepath.ToSlash(filepath.Clean(p))
+			data, err := matcher.ReadFile(cwd, rel)
+			if err != nil {
+				output.Errorf("❌ Cannot include %s: %v\n", p, err)
+				os.Exit(1)
+			}
+			files[rel] = string(data)
+		}
+	}
+
+	system, prompt, err := enrich.Prompt(enrich.Input{Analysis: analysis, Decisions: decs, Files: files})
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	// The request leaves the machine, included files and all
+	policy := projectPolicy(cwd, analysis)
+	system, prompt = policy.Apply(system), policy.Apply(prompt)
+	if enrichDryRun {
+		output.Info("🔍 Would send this request (nothing was sent):")
+		output.Info()
+		output.Write("System:\n\n" + system + "\n\nUser:\n\n" + prompt)
+		return
+	}
+
+	spin := output.StartSpinner("✨ Drafting with " + client.String() + "...")
+	reply, err := client.Complete(context.Background(), system, prompt, enrich.MaxTokens)
+	spin.Stop()
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	body, err := enrich.Parse(reply)
+	if err != nil {
+		output.Errorf("❌ Unexpected reply from %s: %v\n", client, err)
+		os.Exit(1)
+	}
+
+	path := filepath.Join(cwd, filepath.FromSlash(generator.EnrichmentFile))
+	if err := os.WriteFile(path, []byte(enrich.Document(body, client.String(), time.Now())), 0644); err != nil {
+		output.Errorf("❌ Error writing %s: %v\n", generator.EnrichmentFile, err)
+		os.Exit(1)
+	}
+	output.Printf("✅ Drafted %s with %s\n", generator.EnrichmentFile, client)
+	output.Info()
+	output.Info("💡 Review and edit it, then run 'contextpilot sync' to add it to the context files")
+}
+
+func init() {
+	rootCmd.AddCommand(enrichCmd)
+	enrichCmd.Flags().StringArrayVar(&enrichInclude, "include", nil, "Also send this file's contents (repeatable; .contextpilotignore applies)")
+	enrichCmd.Flags().BoolVar(&enrichDryRun, "dry-run", false, "Print the request instead of sending it")
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/api"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var envExportFormat string
+
+var envExportCmd = &cobra.Command{
+	Use:   "env-export",
+	Short: "Export essential context as environment variables or JSON",
+	Long: `Print the essential project context (stack, commands, conventions,
+recent decisions) in a form that can be injected into Codespaces, Gitpod,
+CI runners or agent sandboxes where .contextpilot/ may not be present.
+
+Formats:
+  env      export KEY='value' lines for eval in a POSIX shell (default)
+  dotenv   KEY=value lines for .env files and docker --env-file
+  github   KEY<<EOF blocks for appending to $GITHUB_ENV
+  json     a single JSON object
+
+Every format includes CONTEXTPILOT_CONTEXT, the full context as one
+JSON blob.
+
+Examples:
+  eval "$(contextpilot env-export)"
+  contextpilot env-export --format github >> "$GITHUB_ENV"
+  contextpilot env-export --format json > context.json`,
+	Run: runEnvExport,
+}
+
+// envContext is the essential context exported to ephemeral environments
+type envContext struct {
+	APIVersion     int                 `json:"apiVersion"`
+	Stack          string              `json:"stack"`
+	Framework      string              `json:"framework,omitempty"`
+	Languages      []string            `json:"languages"`
+	PackageManager string              `json:"packageManager,omitempty"`
+	Commands       []generator.Command `json:"commands,omitempty"`
+	Conventions    []string            `json:"conventions,omitempty"`
+	Decisions      []string            `json:"decisions,omitempty"`
+}
+
+func runEnvExport(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	a := analyzer.New(cwd)
+	analysis, err := a.Analyze()
+	if err != nil {
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
+		os.Exit(1)
+	}
+	sort.Slice(analysis.Languages, func(i, j int) bool {
+		return analysis.Languages[i].FileCount > analysis.Languages[j].FileCount
+	})
+
+	ctx := buildEnvContext(cwd, analysis)
+
+	switch envExportFormat {
+	case "json":
+		data, _ := json.MarshalIndent(ctx, "", "  ")
+		output.Write(string(data) + "\n")
+	case "env", "dotenv", "github":
+		for _, kv := range envVars(ctx) {
+			output.Write(formatEnvVar(envExportFormat, kv[0], kv[1]))
+		}
+	default:
+		output.Errorf("❌ Unknown format %q (use env, dotenv, github or json)\n", envExportFormat)
+		os.Exit(1)
+	}
+}
+
+func buildEnvContext(cwd string, analysis *analyzer.Analysis) envContext {
+	ctx := envContext{
+		APIVersion:     api.Selected(),
+		Stack:          generator.Stack(analysis),
+		Languages:      []string{},
+		PackageManager: analysis.Packages.Manager,
+		Commands:       generator.Commands(analysis),
+		Conventions:    generator.Conventions(analysis),
+	}
+	if analysis.Framework != nil {
+		ctx.Framework = analysis.Framework.Name
+	}
+	for _, lang := range analysis.Languages {
+		ctx.Languages = append(ctx.Languages, lang.Name)
+	}
+
+	// Most recent decisions first, capped to keep variables small
+	decs, _ := decisions.New(cwd).List()
+	for i := len(decs) - 1; i >= 0 && len(ctx.Decisions) < 5; i-- {
+		ctx.Decisions = append(ctx.Decisions, decs[i].Text)
+	}
+
+	// Redact field by field, so the JSON stays valid whatever the
+	// replacement
+	policy := projectPolicy(cwd, analysis)
+	ctx.Stack = policy.Apply(ctx.Stack)
+	ctx.Framework = policy.Apply(ctx.Framework)
+	for i := range ctx.Commands {
+		ctx.Commands[i].Run = policy.Apply(ctx.Commands[i].Run)
+		ctx.Commands[i].Comment = policy.Apply(ctx.Commands[i].Comment)
+	}
+	for i := range ctx.Conventions {
+		ctx.Conventions[i] = policy.Apply(ctx.Conventions[i])
+	}
+	for i := range ctx.Decisions {
+		ctx.Decisions[i] = policy.Apply(ctx.Decisions[i])
+	}
+	return ctx
+}
+
+// envVars flattens ctx into ordered name/value pairs
+func envVars(ctx envContext) [][2]string {
+	vars := [][2]string{
+		{"CONTEXTPILOT_STACK", ctx.Stack},
+		{"CONTEXTPILOT_FRAMEWORK", ctx.Framework},
+		{"CONTEXTPILOT_LANGUAGES", strings.Join(ctx.Languages, ", ")},
+		{"CONTEXTPILOT_PACKAGE_MANAGER", ctx.PackageManager},
+	}
+	used := map[string]bool{}
+	for _, c := range ctx.Commands {
+		// dev:watch and dev-watch both become DEV_WATCH
+		base := envName("CONTEXTPILOT_CMD_" + c.Name)
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[name] = true
+		vars = append(vars, [2]string{name, c.Run})
+	}
+	vars = append(vars,
+		[2]string{"CONTEXTPILOT_CONVENTIONS", strings.Join(ctx.Conventions, "; ")},
+		[2]string{"CONTEXTPILOT_DECISIONS", strings.Join(ctx.Decisions, "\n")},
+	)
+
+	blob, _ := json.Marshal(ctx)
+	vars = append(vars, [2]string{"CONTEXTPILOT_CONTEXT", string(blob)})
+	return vars
+}
+
+// envName upper-cases name and replaces what a shell variable name can't
+// hold, such as the : of Deno's dev:watch, with _
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		}
+		return '_'
+	}, name)
+}
+
+// heredocDelimiter returns a random delimiter for a $GITHUB_ENV block, so
+// no value can end the block early and set variables of its own
+func heredocDelimiter() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "CONTEXTPILOT_EOF_" + hex.EncodeToString(b)
+}
+
+func formatEnvVar(format, name, value string) string {
+	switch format {
+	case "github":
+		delim := heredocDelimiter()
+		return fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delim, value, delim)
+	case "dotenv":
+		if strings.ContainsAny(value, " \"'#\n$") {
+			value = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`).Replace(value) + `"`
+		}
+		return fmt.Sprintf("%s=%s\n", name, value)
+	default:
+		return fmt.Sprintf("export %s='%s'\n", name, strings.ReplaceAll(value, "'", `'\''`))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(envExportCmd)
+	envExportCmd.Flags().StringVarP(&envExportFormat, "format", "f", "env", "Output format (env, dotenv, github, json)")
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/explain"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/redact"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/spf13/cobra"
+)
+
+var explainDays int
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <path>",
+	Short: "Show what ContextPilot knows about a file or folder",
+	Long: `Print the context that applies to one file or directory: the monorepo
+package it belongs to, the frameworks and ORMs of that package, the
+detected conventions and your instructions, the decisions that cover it
+and how often it changed recently.
+
+A decision covers a path when its --files match the path, lie inside it
+or contain it, or when one of its tags names the path's package or area.
+
+MCP clients get the same through the contextpilot_explain tool.
+
+Examples:
+  contextpilot explain src/payments
+  contextpilot explain apps/api/src/server.ts --days 30
+  contextpilot explain src/auth --json`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: jsonCapable,
+	Run:         runExplain,
+}
+
+func runExplain(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	rel, dir, err := projectPath(cwd, args[0])
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	analysis, err := analyzer.New(cwd).Analyze()
+	if err != nil {
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
+		os.Exit(1)
+	}
+	decs, _ := decisions.New(cwd).List()
+	cfg, _ := config.Load(cwd)
+	e, err := explain.Explain(cwd, rel, dir, analysis, decs, cfg, explainDays)
+	if err != nil {
+		output.Errorf("❌ Error reading git history: %v\n", err)
+		os.Exit(1)
+	}
+	redactExplanation(projectPolicy(cwd, analysis), e)
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{"explanation": e})
+		return
+	}
+	printExplanation(e)
+}
+
+// projectPath turns arg into a slash-separated path relative to root,
+// reporting whether it is a directory
+func projectPath(root, arg string) (string, bool, error) {
+	abs, err := filepath.Abs(arg)
+	if err != nil {
+		return "", false, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", false, err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false, fmt.Errorf("%s is outside the project", arg)
+	}
+	return filepath.ToSlash(rel), info.IsDir(), nil
+}
+
+// redactExplanation applies policy to the text of e field by field, so
+// the JSON stays valid whatever the replacement
+func redactExplanation(policy *redact.Policy, e *explain.Explanation) {
+	if e.Workspace != nil {
+		ws := *e.Workspace
+		ws.Name = policy.Apply(ws.Name)
+		e.Workspace = &ws
+	}
+	for i := range e.Conventions {
+		e.Conventions[i] = policy.Apply(e.Conventions[i])
+	}
+	for i := range e.Instructions {
+		e.Instructions[i] = policy.Apply(e.Instructions[i])
+	}
+	for i := range e.Churn.Recent {
+		e.Churn.Recent[i].Subject = policy.Apply(e.Churn.Recent[i].Subject)
+	}
+	redactDecisions(policy, e.Decisions)
+}
+
+func printExplanation(e *explain.Explanation) {
+	icon := "📄"
+	if e.Dir {
+		icon = "📁"
+	}
+	output.Printf("%s %s\n", icon, e.Path)
+	if len(e.Languages) > 0 {
+		output.Printf("   ├── Language: %s\n", strings.Join(e.Languages, ", "))
+	}
+	if e.Dir {
+		output.Printf("   ├── Files: %d tracked\n", e.Files)
+	}
+	if e.Test {
+		output.Println("   ├── Test file")
+	}
+	if e.Workspace != nil {
+		output.Printf("   ├── Package: %s (%s)\n", e.Workspace.Name, e.Workspace.Path)
+	}
+	if e.Area != "" {
+		output.Printf("   ├── Area: %s\n", e.Area)
+	}
+	var stack []string
+	for _, f := range e.Frameworks {
+		stack = append(stack, strings.TrimSpace(f.Name+" "+f.Version))
+	}
+	for _, o := range e.ORMs {
+		stack = append(stack, o.Name)
+	}
+	if len(stack) > 0 {
+		output.Printf("   ├── Stack: %s\n", strings.Join(stack, ", "))
+	}
+	output.Printf("   └── Churn: %d commit(s) in the last %d days\n", e.Churn.Commits, e.Churn.Days)
+	for _, c := range e.Churn.Recent {
+		output.Printf("       %s %s %s\n", c.SHA, c.Time.Format("2006-01-02"), c.Subject)
+	}
+
+	if len(e.Conventions) > 0 || len(e.Instructions) > 0 {
+		output.Println()
+		output.Println("🧭 Conventions:")
+		for _, c := range append(e.Conventions, e.Instructions...) {
+			output.Printf("   • %s\n", c)
+		}
+	}
+
+	output.Println()
+	if len(e.Decisions) == 0 {
+		output.Println("📋 No decisions cover this path")
+		output.Info()
+		output.Infof("💡 Record one with: contextpilot decision \"...\" --files '%s'\n", decisionGlob(e))
+		return
+	}
+	output.Println("📋 Decisions:")
+	for _, d := range e.Decisions {
+		output.Printf("   #%d %s\n", d.ID, d.Text)
+		if links := d.Links(); links != "" {
+			output.Printf("      %s\n", links)
+		}
+	}
+}
+
+// decisionGlob is the --files pattern that links a decision to e's path
+func decisionGlob(e *explain.Explanation) string {
+	if e.Dir && e.Path != "." {
+		return e.Path + "/*"
+	}
+	return e.Path
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+	explainCmd.Flags().IntVar(&explainDays, "days", explain.DefaultDays, "How far back to count changes")
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+	"github.com/spf13/cobra"
+)
+
+var hookCmd = &cobra.Command{
+	Use:    "hook",
+	Short:  "Entry points called from git hooks",
+	Hidden: true,
+}
+
+var hookPostCheckoutCmd = &cobra.Command{
+	Use:   "post-checkout <prev-head> <new-head> <branch-flag>",
+	Short: "Show the saved session after switching branches",
+	Long: `Called by git's post-checkout hook. When a branch checkout lands on
+a branch with saved sessions, prints their summary as an automatic
+resume reminder. File checkouts (branch-flag 0) are ignored.
+
+Installed by 'contextpilot hooks install'.`,
+	Args: cobra.MaximumNArgs(3),
+	Run:  runHookPostCheckout,
+}
+
+var hookPreCommitCmd = &cobra.Command{
+	Use:   "pre-commit",
+	Short: "Remind about stale context files before committing",
+	Long: `Called by git's pre-commit hook. Warns when the context files are out
+of date; it never blocks the commit (use 'hooks install --strict' for
+that).`,
+	Args: cobra.NoArgs,
+	Run:  runHookStaleReminder,
+}
+
+var hookPrePushCmd = &cobra.Command{
+	Use:   "pre-push [remote] [url]",
+	Short: "Stop a push while the context lags behind the stack",
+	Long: `Called by git's pre-push hook. When dependencies, the framework,
+tooling or top-level folders changed since the last sync, it lists what
+changed and fails the push so stale context never lands on the main
+branch. With hooks.prePush: warn in config.yaml it only warns.
+
+Installed by 'contextpilot hooks install --pre-push'; 'git push
+--no-verify' skips it.`,
+	Args: cobra.MaximumNArgs(2),
+	Run:  runHookPrePush,
+}
+
+var hookPostMergeCmd = &cobra.Command{
+	Use:   "post-merge [squash-flag]",
+	Short: "Remind about stale context files after a pull or merge",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runHookStaleReminder,
+}
+
+// runHookStaleReminder prints a reminder when sync would change the
+// context files
+func runHookStaleReminder(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil || !config.Exists(cwd) {
+		return
+	}
+
+	stale, err := staleContextFiles(cwd)
+	if err != nil || len(stale) == 0 {
+		return
+	}
+	output.Infof("⚠️  ContextPilot: %s out of date. Run 'contextpilot sync' to refresh.\n", strings.Join(stale, ", "))
+}
+
+func runHookPrePush(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil || !config.Exists(cwd) {
+		return
+	}
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return
+	}
+
+	// A broken analysis mustn't hold up the push
+	_, d, err := contextState(cwd)
+	if err != nil || len(d.Items) == 0 {
+		return
+	}
+	block := cfg.Hooks.BlockPush()
+	if block {
+		output.Errorf("❌ ContextPilot: the stack changed since the last sync:\n")
+	} else {
+		output.Errorf("⚠️  ContextPilot: the stack changed since the last sync:\n")
+	}
+	for _, item := range d.Items {
+		output.Errorf("   • %s\n", item)
+	}
+	output.Info("💡 Run 'contextpilot sync' and commit the context files before pushing")
+	if block {
+		output.Info("   (or push with --no-verify to skip this check)")
+		os.Exit(1)
+	}
+}
+
+func runHookPostCheckout(cmd *cobra.Command, args []string) {
+	if len(args) == 3 && args[2] != "1" {
+		return
+	}
+
+	cwd, err := projectDir()
+	if err != nil {
+		return
+	}
+
+	// Hooks must never fail the checkout, so errors are silently ignored
+	sessions, err := session.New(cwd).List()
+	if err != nil || len(sessions) == 0 {
+		return
+	}
+
+	s := sessions[0]
+	output.Println()
+	output.Printf("📋 Welcome back to %s (session saved %s)\n", s.Branch, formatAge(s.UpdatedAt))
+	output.Printf("   📝 Task: %s\n", s.Task)
+	if s.State != "" {
+		output.Printf("   📍 State: %s\n", s.State)
+	}
+	if len(s.NextSteps) > 0 {
+		output.Printf("   ➡️  Next: %s\n", s.NextSteps[0])
+	}
+	if len(sessions) > 1 {
+		output.Printf("   🏷️  +%d named session(s): contextpilot sessions list\n", len(sessions)-1)
+	}
+	output.Println("💡 Run 'contextpilot resume' to copy the full context")
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+	hookCmd.AddCommand(hookPostCheckoutCmd)
+	hookCmd.AddCommand(hookPreCommitCmd)
+	hookCmd.AddCommand(hookPostMergeCmd)
+	hookCmd.AddCommand(hookPrePushCmd)
+}
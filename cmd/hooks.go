@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/hooks"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hooksStrict    bool
+	hooksPreCommit bool
+	hooksPrePush   bool
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Install or remove ContextPilot's git hooks",
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install git hooks that keep context fresh",
+	Long: `Install git hooks that run ContextPilot at the right moments:
+
+  pre-commit     warn when context files are out of date
+                 (--strict: block the commit with 'sync --check')
+  post-merge     warn when a pull or merge left context out of date
+  post-checkout  show the saved session of the branch you switched to
+  pre-push       with --pre-push: stop a push when dependencies or
+                 top-level folders changed since the last sync
+                 (hooks.prePush: warn in config.yaml only warns)
+
+Hooks go where git runs them, honoring core.hooksPath. Existing hook
+scripts are kept: ContextPilot adds a marked block that 'hooks uninstall'
+removes again. When the project uses the pre-commit framework
+(.pre-commit-config.yaml), the pre-commit check is added there instead.`,
+	Args: cobra.NoArgs,
+	Run:  runHooksInstall,
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove ContextPilot's git hooks",
+	Args:  cobra.NoArgs,
+	Run:   runHooksUninstall,
+}
+
+// gitHooks are the hooks ContextPilot installs; pre-commit depends on
+// --strict, and pre-push is only added with --pre-push
+func gitHooks(strict, prePush bool) []hooks.Hook {
+	preCommit := "contextpilot hook pre-commit"
+	if strict {
+		preCommit = "contextpilot sync --check || exit 1"
+	}
+	list := []hooks.Hook{
+		{Name: "pre-commit", Command: preCommit},
+		{Name: "post-merge", Command: `contextpilot hook post-merge "$@"`},
+		{Name: "post-checkout", Command: `contextpilot hook post-checkout "$@"`},
+	}
+	if prePush {
+		list = append(list, hooks.Hook{Name: "pre-push", Command: `contextpilot hook pre-push "$@" || exit 1`})
+	}
+	return list
+}
+
+func hooksDir() (cwd, dir string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	if !git.IsRepo(cwd) {
+		output.Errorf("❌ Not a git repository\n")
+		os.Exit(1)
+	}
+	dir, err = git.HooksDir(cwd)
+	if err != nil {
+		output.Errorf("❌ Cannot find the hooks directory: %v\n", err)
+		os.Exit(1)
+	}
+	return cwd, dir
+}
+
+func runHooksInstall(cmd *cobra.Command, args []string) {
+	cwd, dir := hooksDir()
+	usePreCommit := hooksPreCommit || hooks.UsesPreCommit(cwd)
+
+	output.Printf("🪝 Installing hooks in %s\n", displayPath(cwd, dir))
+	for _, h := range gitHooks(hooksStrict, hooksPrePush) {
+		var status string
+		var err error
+		if h.Name == "pre-commit" && usePreCommit {
+			// The framework rewrites .git/hooks/pre-commit, so the check
+			// lives in its config; it only blocks with --strict
+			entry := "contextpilot hook pre-commit"
+			if hooksStrict {
+				entry = "contextpilot sync --check"
+			}
+			status, err = hooks.AddPreCommitHook(cwd, entry)
+			h.Name += " (" + hooks.PreCommitConfig + ")"
+		} else {
+			status, err = hooks.Install(dir, h)
+		}
+		if err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		output.Printf("   ✅ %-14s %s\n", h.Name, status)
+	}
+	if usePreCommit {
+		output.Info()
+		output.Info("💡 Run 'pre-commit install' if you haven't already")
+	}
+}
+
+func runHooksUninstall(cmd *cobra.Command, args []string) {
+	cwd, dir := hooksDir()
+
+	output.Printf("🪝 Removing hooks from %s\n", displayPath(cwd, dir))
+	for _, h := range gitHooks(false, true) {
+		status, err := hooks.Uninstall(dir, h.Name)
+		if err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		output.Printf("   • %-14s %s\n", h.Name, status)
+	}
+	if status, err := hooks.RemovePreCommitHook(cwd); err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	} else if status == hooks.Removed {
+		output.Printf("   • %-14s %s\n", hooks.PreCommitConfig, status)
+	}
+}
+
+// displayPath shows path relative to cwd when it lies inside it
+func displayPath(cwd, path string) string {
+	if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+func init() {
+	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksUninstallCmd)
+	hooksInstallCmd.Flags().BoolVar(&hooksStrict, "strict", false, "Block commits while context files are out of date")
+	hooksInstallCmd.Flags().BoolVar(&hooksPrePush, "pre-push", false, "Also add a pre-push hook that stops pushes while the stack drifted since the last sync")
+	hooksInstallCmd.Flags().BoolVar(&hooksPreCommit, "pre-commit-framework", false, "Add the pre-commit check to .pre-commit-config.yaml (default when the file exists)")
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/jitin-nhz/contextpilot/internal/ignore"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var ignoreCmd = &cobra.Command{
+	Use:   "ignore",
+	Short: "Control which files content-based features may read",
+	Long: `.contextpilotignore controls which files content-based features
+(prompt file excerpts and the files enrich sends) may read.
+It is independent from analysis: ignored files are still counted in
+language and structure statistics, but their contents are never
+sampled, embedded, or sent anywhere.
+
+The syntax follows .gitignore (globs, ** , trailing / for directories,
+! to re-allow). Secrets such as .env files, private keys and
+.contextpilot/sessions/ are excluded by default.
+
+Examples:
+  contextpilot ignore init
+  contextpilot ignore check src/payments/keys.ts .env`,
+}
+
+var ignoreCheckCmd = &cobra.Command{
+	Use:   "check <path>...",
+	Short: "Show whether content features may read the given paths",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runIgnoreCheck,
+}
+
+var ignoreInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a starter .contextpilotignore",
+	Run:   runIgnoreInit,
+}
+
+func runIgnoreCheck(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	m, err := ignore.Load(cwd)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, path := range args {
+		// Paths are relative to where the command runs, which may be
+		// below the project root
+		rel := path
+		if abs, err := filepath.Abs(path); err == nil {
+			if r, err := filepath.Rel(cwd, abs); err == nil {
+				rel = r
+			}
+		}
+		isDir := false
+		if info, err := os.Stat(filepath.Join(cwd, rel)); err == nil {
+			isDir = info.IsDir()
+		}
+
+		rule := m.Match(rel, isDir)
+		switch {
+		case rule == nil:
+			output.Printf("✅ %s  allowed\n", rel)
+		case rule.Negated():
+			output.Printf("✅ %s  allowed (%s: %s)\n", rel, rule.Source, rule.Pattern)
+		default:
+			output.Printf("🚫 %s  excluded (%s: %s)\n", rel, rule.Source, rule.Pattern)
+		}
+	}
+}
+
+func runIgnoreInit(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	path := filepath.Join(cwd, ignore.FileName)
+	if _, err := os.Stat(path); err == nil {
+		output.Infof("📋 %s already exists\n", ignore.FileName)
+		return
+	}
+
+	content := `# .contextpilotignore — files ContextPilot may count but never read.
+# Applies to content-based features (prompt excerpts, enrich).
+# Syntax follows .gitignore. Secrets (.env*, *.pem, *.key,
+# id_rsa*, secrets/) are always excluded unless re-allowed with "!".
+
+# Examples:
+# internal/billing/
+# **/*.sql
+# config/production.*
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		output.Errorf("❌ Error writing %s: %v\n", ignore.FileName, err)
+		os.Exit(1)
+	}
+	output.Printf("✅ Created %s\n", ignore.FileName)
+}
+
+func init() {
+	rootCmd.AddCommand(ignoreCmd)
+	ignoreCmd.AddCommand(ignoreCheckCmd)
+	ignoreCmd.AddCommand(ignoreInitCmd)
+}
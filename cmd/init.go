@@ -3,15 +3,24 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path"
+	"slices"
 	"sort"
+	"strings"
 
-	"github.com/jitin-nhz/contextpilot/internal/analyzer"
-	"github.com/jitin-nhz/contextpilot/internal/generator"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/gitignore"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
 	"github.com/spf13/cobra"
 )
 
 var initTemplate string
 var dryRun bool
+var initInteractive bool
+var initLocalOnly bool
+var initNoGitignore bool
 
 var initCmd = &cobra.Command{
 	Use:   "init",
@@ -21,26 +30,53 @@ var initCmd = &cobra.Command{
   - CLAUDE.md (Claude Code, OpenClaw)
   - .github/copilot-instructions.md (GitHub Copilot)
 
+GEMINI.md (Gemini CLI), .gemini/styleguide.md (Gemini Code Assist
+reviews) and AGENTS.md (Codex CLI, Amp) are generated when listed under
+outputs in .contextpilot/config.yaml; --interactive adds GEMINI.md when
+told you use Gemini. With agents.nested set, every monorepo package also
+gets an AGENTS.md with its own stack and decisions.
+
+Repos that mix stacks also get Cursor rules in .cursor/rules scoped to
+each stack's files. Listing .github/instructions under outputs in
+.contextpilot/config.yaml adds the same scopes as Copilot instructions
+files with applyTo globs.
+
 The generated files help AI tools understand your project's
-tech stack, coding conventions, and architectural decisions.`,
-	Run: runInit,
+tech stack, coding conventions, and architectural decisions.
+
+--interactive asks which AI tools you use, whether this is a monorepo
+and whether the generated files are committed, pre-filling each answer
+from what it detects. It then generates only the files your tools read,
+adds .gitignore entries, and registers the MCP server in each tool's
+project config (.mcp.json, .cursor/mcp.json, .vscode/mcp.json). With
+--no-input the detected answers are used as they are.
+
+Decisions and config.yaml are meant to be committed so the team shares
+them; sessions, logs, backups, locks and caches stay on this machine, and
+init adds them to .gitignore (asked with --interactive, skipped with
+--no-gitignore). It warns when any of them are already tracked by git. With --local-only nothing ContextPilot writes is committed:
+the context files and .contextpilot/ go to .git/info/exclude instead,
+which git honors but never shares.`,
+	Annotations: jsonCapable,
+	Run:         runInit,
 }
 
 func runInit(cmd *cobra.Command, args []string) {
 	// Get current directory
 	cwd, err := os.Getwd()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error getting current directory: %v\n", err)
+		output.Errorf("❌ Error getting current directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Println("🔍 Analyzing codebase...")
+	spin := output.StartSpinner("🔍 Analyzing codebase...")
 
 	// Create analyzer and run analysis
 	a := analyzer.New(cwd)
 	analysis, err := a.Analyze()
+	spin.Stop()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error analyzing codebase: %v\n", err)
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -50,35 +86,50 @@ func runInit(cmd *cobra.Command, args []string) {
 	})
 
 	// Display results
+	scanned, skipped := describeTotals(analysis.Totals)
+	output.Printf("   ├── %s\n", scanned)
+	if skipped != "" {
+		output.Printf("   ├── %s\n", skipped)
+	}
 	if len(analysis.Languages) > 0 {
-		fmt.Println("   ├── Languages detected:")
+		output.Println("   ├── Languages detected:")
 		for i, lang := range analysis.Languages {
 			prefix := "│  ├──"
 			if i == len(analysis.Languages)-1 {
 				prefix = "│  └──"
 			}
-			fmt.Printf("   %s %s (%d files, %.1f%%)\n", prefix, lang.Name, lang.FileCount, lang.Percentage)
+			output.Printf("   %s %s (%d files, %.1f%%)\n", prefix, lang.Name, lang.FileCount, lang.Percentage)
 		}
 	}
 
 	if analysis.Framework != nil {
-		fmt.Printf("   ├── Framework: %s", analysis.Framework.Name)
+		output.Printf("   ├── Framework: %s", analysis.Framework.Name)
 		if analysis.Framework.Version != "" {
-			fmt.Printf(" %s", analysis.Framework.Version)
+			output.Printf(" %s", analysis.Framework.Version)
+		}
+		output.Println()
+		for _, f := range analysis.Frameworks[min(1, len(analysis.Frameworks)):] {
+			output.Printf("   ├── Also: %s", f.Name)
+			if f.Version != "" {
+				output.Printf(" %s", f.Version)
+			}
+			if f.Where != "" {
+				output.Printf(" (%s)", f.Where)
+			}
+			output.Println()
 		}
-		fmt.Println()
 	}
 
 	if analysis.Structure.Type != "" {
-		fmt.Printf("   ├── Structure: %s", analysis.Structure.Type)
+		output.Printf("   ├── Structure: %s", analysis.Structure.Type)
 		if analysis.Structure.SrcDir != "" {
-			fmt.Printf(" (src: %s)", analysis.Structure.SrcDir)
+			output.Printf(" (src: %s)", analysis.Structure.SrcDir)
 		}
-		fmt.Println()
+		output.Println()
 	}
 
 	if len(analysis.Structure.Folders) > 0 {
-		fmt.Printf("   ├── Folders: %v\n", analysis.Structure.Folders)
+		output.Printf("   ├── Folders: %v\n", analysis.Structure.Folders)
 	}
 
 	// Show detected patterns
@@ -103,56 +154,208 @@ func runInit(cmd *cobra.Command, args []string) {
 	}
 
 	if len(patterns) > 0 {
-		fmt.Println("   └── Patterns:")
+		output.Println("   └── Patterns:")
 		for i, p := range patterns {
 			prefix := "      ├──"
 			if i == len(patterns)-1 {
 				prefix = "      └──"
 			}
-			fmt.Printf("   %s %s\n", prefix, p)
+			output.Printf("   %s %s\n", prefix, p)
 		}
 	} else {
-		fmt.Println("   └── Analysis complete")
+		output.Println("   └── Analysis complete")
 	}
 
-	fmt.Println()
+	output.Println()
+
+	gen := generator.New(analysis, cwd)
+	var wizard *initAnswers
+	if initInteractive {
+		wizard = askInitQuestions(cwd, analysis)
+		gen.SetOutputs(wizard.outputs())
+	}
 
 	if dryRun {
-		fmt.Println("🔍 Dry run - no files written")
-		fmt.Println()
-		fmt.Println("Would generate:")
-		fmt.Println("   ├── .cursorrules")
-		fmt.Println("   ├── CLAUDE.md")
-		fmt.Println("   ├── .github/copilot-instructions.md")
-		fmt.Println("   └── .contextpilot/config.yaml")
+		output.Info("🔍 Dry run - no files written")
+		output.Info()
+		output.Println("Would generate:")
+		printGeneratedFiles(gen.Files(), false)
+		if output.IsJSON() {
+			printJSON(map[string]interface{}{"analysis": analysis, "files": generatedFiles(gen.Files()), "dryRun": true})
+		}
 		return
 	}
 
 	// Generate context files
-	fmt.Println("📝 Generating context files...")
-	gen := generator.New(analysis, cwd)
+	output.Info("📝 Generating context files...")
 	if err := gen.GenerateAll(); err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error generating files: %v\n", err)
+		output.Errorf("❌ Error generating files: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Println("   ├── .cursorrules (Cursor)")
-	fmt.Println("   ├── CLAUDE.md (Claude Code, OpenClaw)")
-	fmt.Println("   ├── .github/copilot-instructions.md (GitHub Copilot)")
-	fmt.Println("   └── .contextpilot/config.yaml (ContextPilot config)")
-	fmt.Println()
-	fmt.Println("✅ Done! Your AI tools now understand your codebase.")
-	fmt.Println()
-	fmt.Println("💡 Tips:")
-	fmt.Println("   • Review and customize the generated files")
-	fmt.Println("   • Run 'contextpilot sync' after major code changes")
-	fmt.Println("   • Log decisions with 'contextpilot decision \"...\"'")
-	fmt.Println()
-	fmt.Println("Star us: github.com/contextpilot-dev/contextpilot")
+	printGeneratedFiles(gen.Files(), true)
+	var ignored []string
+	if wizard != nil {
+		applyInitAnswers(cwd, analysis, wizard)
+		if !wizard.commit {
+			ignored = wizard.outputs()
+		}
+	}
+	updateIgnores(cwd, gen.Files(), ignored, wizard)
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{"analysis": analysis, "files": generatedFiles(gen.Files()), "dryRun": false})
+		return
+	}
+	output.Info()
+	output.Info("✅ Done! Your AI tools now understand your codebase.")
+	output.Info()
+	output.Info("💡 Tips:")
+	output.Info("   • Review and customize the generated files")
+	output.Info("   • Run 'contextpilot sync' after major code changes")
+	output.Info("   • Log decisions with 'contextpilot decision \"...\"'")
+	output.Info()
+	output.Info("Star us: github.com/contextpilot-dev/contextpilot")
+}
+
+// updateIgnores keeps local files out of git: localEntries (unless
+// declined) and ignored go to .gitignore, or with --local-only everything
+// init wrote goes to .git/info/exclude
+func updateIgnores(cwd string, outputs, ignored []string, wizard *initAnswers) {
+	if !initLocalOnly {
+		entries := append([]string{}, ignored...)
+		if (wizard == nil && !initNoGitignore) || (wizard != nil && wizard.ignore) {
+			entries = append(append([]string{}, localEntries...), entries...)
+		}
+		added, err := gitignore.Add(cwd, entries)
+		if err != nil {
+			output.Errorf("⚠️  %v\n", err)
+		} else if len(added) > 0 {
+			output.Printf("   🙈 .gitignore: %s\n", strings.Join(added, ", "))
+		}
+		warnTrackedLocal(cwd)
+		return
+	}
+
+	entries := append(append([]string{}, outputs...), ".contextpilot/")
+	if wizard != nil && wizard.mcp {
+		for _, t := range wizard.tools {
+			entries = append(entries, t.mcpPath)
+		}
+	}
+	added, err := gitignore.Exclude(cwd, entries)
+	if err != nil {
+		output.Errorf("⚠️  --local-only: %v; nothing was excluded\n", err)
+	} else if len(added) > 0 {
+		output.Printf("   🙈 .git/info/exclude: %s\n", strings.Join(added, ", "))
+	}
+}
+
+// warnTrackedLocal warns about local files committed before they were
+// ignored, which .gitignore can't hide
+func warnTrackedLocal(cwd string) {
+	tracked := git.TrackedFiles(cwd, localEntries...)
+	if len(tracked) == 0 {
+		return
+	}
+	output.Errorf("⚠️  Already tracked by git, so .gitignore won't hide them: %s\n", strings.Join(tracked, ", "))
+	output.Infof("   Untrack them (keeping your copies) with: git rm -r --cached %s\n", strings.Join(trackedDirs(tracked), " "))
+}
+
+// trackedDirs returns the localEntries holding any of files
+func trackedDirs(files []string) []string {
+	var dirs []string
+	for _, e := range localEntries {
+		for _, f := range files {
+			if strings.HasPrefix(f, strings.TrimSuffix(e, "*")) {
+				dirs = append(dirs, e)
+				break
+			}
+		}
+	}
+	return dirs
+}
+
+// outputTools names the AI tools that read each context file
+var outputTools = map[string]string{
+	".cursorrules":                    "Cursor",
+	"CLAUDE.md":                       "Claude Code, OpenClaw",
+	".github/copilot-instructions.md": "GitHub Copilot",
+	"GEMINI.md":                       "Gemini CLI",
+	generator.AgentsFile:              "Codex CLI, Amp",
+	generator.GeminiStyleguide:        "Gemini Code Assist",
+}
+
+// fileTools names the AI tools that read a generated file
+func fileTools(f string) string {
+	switch {
+	case strings.HasPrefix(f, generator.ScopedRulesDir+"/"):
+		return "Cursor, scoped"
+	case strings.HasPrefix(f, generator.InstructionsDir+"/"):
+		return "GitHub Copilot, scoped"
+	case path.Base(f) == generator.AgentsFile:
+		return outputTools[generator.AgentsFile]
+	}
+	return outputTools[f]
+}
+
+// maxIgnoredShown caps the ignored directories describeTotals names
+const maxIgnoredShown = 5
+
+// describeTotals summarizes what the analysis walked, and what it skipped
+// ("" if nothing), directories ignored by the config first
+func describeTotals(t analyzer.Totals) (scanned, ignored string) {
+	scanned = fmt.Sprintf("Scanned %d files (%d code) in %dms", t.FilesScanned, t.CodeFiles, t.WalkMillis)
+	if len(t.Ignored) == 0 {
+		return scanned, ""
+	}
+	dirs := slices.Clone(t.Ignored)
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return dirs[i].Reason != analyzer.IgnoreDefault && dirs[j].Reason == analyzer.IgnoreDefault
+	})
+	var names []string
+	for _, d := range dirs[:min(len(dirs), maxIgnoredShown)] {
+		name := d.Path + "/"
+		switch d.Reason {
+		case analyzer.IgnoreConfig:
+			name += fmt.Sprintf(" (config.yaml, %d files)", d.Files)
+		case analyzer.IgnoreOption:
+			name += fmt.Sprintf(" (%d files)", d.Files)
+		}
+		names = append(names, name)
+	}
+	if len(dirs) > maxIgnoredShown {
+		names = append(names, fmt.Sprintf("and %d more", len(dirs)-maxIgnoredShown))
+	}
+	return scanned, "Ignored: " + strings.Join(names, ", ")
+}
+
+// printGeneratedFiles lists outputs and config.yaml as a tree, optionally
+// with the tools that read each file
+func printGeneratedFiles(outputs []string, withTools bool) {
+	for _, f := range outputs {
+		if withTools {
+			output.Printf("   ├── %s (%s)\n", f, fileTools(f))
+		} else {
+			output.Printf("   ├── %s\n", f)
+		}
+	}
+	if withTools {
+		output.Println("   └── .contextpilot/config.yaml (ContextPilot config)")
+	} else {
+		output.Println("   └── .contextpilot/config.yaml")
+	}
+}
+
+// generatedFiles lists every file init and sync write
+func generatedFiles(outputs []string) []string {
+	return append(append([]string{}, outputs...), ".contextpilot/config.yaml")
 }
 
 func init() {
 	rootCmd.AddCommand(initCmd)
 	initCmd.Flags().StringVarP(&initTemplate, "template", "t", "", "Use a specific template (e.g., nextjs-prisma)")
 	initCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview analysis without generating files")
+	initCmd.Flags().BoolVarP(&initInteractive, "interactive", "i", false, "Ask which tools you use and configure targets, .gitignore and MCP")
+	initCmd.Flags().BoolVar(&initNoGitignore, "no-gitignore", false, "Don't add sessions, logs and other local files to .gitignore")
+	initCmd.Flags().BoolVar(&initLocalOnly, "local-only", false, "Keep everything ContextPilot writes out of git via .git/info/exclude")
 }
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/gitignore"
+	"github.com/jitin-nhz/contextpilot/internal/mcpconfig"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// aiTool is an assistant init --interactive can configure
+type aiTool struct {
+	id      string
+	name    string
+	output  string   // context file the tool reads
+	mcpPath string   // project-level MCP config, relative to the root
+	mcpKey  string   // key holding the server list in mcpPath
+	markers []string // paths whose presence suggests the tool is in use
+}
+
+var aiTools = []aiTool{
+	{"cursor", "Cursor", ".cursorrules", ".cursor/mcp.json", "mcpServers", []string{".cursor", ".cursorrules"}},
+	{"claude", "Claude Code", "CLAUDE.md", ".mcp.json", "mcpServers", []string{".claude", "CLAUDE.md", ".mcp.json"}},
+	{"copilot", "GitHub Copilot", ".github/copilot-instructions.md", ".vscode/mcp.json", "servers", []string{".github/copilot-instructions.md", ".vscode"}},
+	{"gemini", "Gemini CLI", "GEMINI.md", ".gemini/settings.json", "mcpServers", []string{".gemini", "GEMINI.md"}},
+}
+
+// localEntries are the parts of .contextpilot that stay on this machine;
+// config, decisions and the analysis snapshot are shared through git
+var localEntries = []string{
+	".contextpilot/sessions/",
+	".contextpilot/backups/",
+	".contextpilot/logs/",
+	".contextpilot/locks/",
+	".contextpilot/cache/",
+	".contextpilot/contextpilot.db*",
+}
+
+// initAnswers are the choices made in init --interactive
+type initAnswers struct {
+	tools    []aiTool
+	monorepo bool
+	commit   bool
+	ignore   bool // add localEntries to .gitignore
+	mcp      bool
+}
+
+func (a *initAnswers) outputs() []string {
+	outputs := []string{}
+	for _, t := range a.tools {
+		outputs = append(outputs, t.output)
+	}
+	return outputs
+}
+
+// detectInitAnswers guesses the answers from the files in the project
+func detectInitAnswers(cwd string, analysis *analyzer.Analysis) *initAnswers {
+	a := &initAnswers{
+		monorepo: analysis.Structure.Type == "monorepo",
+		commit:   true,
+		ignore:   !initNoGitignore,
+		mcp:      true,
+	}
+	for _, t := range aiTools {
+		for _, m := range t.markers {
+			if _, err := os.Stat(filepath.Join(cwd, m)); err == nil {
+				a.tools = append(a.tools, t)
+				break
+			}
+		}
+		if gitignore.Contains(cwd, t.output) {
+			a.commit = false
+		}
+	}
+	if len(a.tools) == 0 {
+		a.tools = aiTools
+	}
+	return a
+}
+
+// askInitQuestions runs the init wizard on stdin, offering the detected
+// answers as defaults. With --no-input the defaults are taken as they are.
+func askInitQuestions(cwd string, analysis *analyzer.Analysis) *initAnswers {
+	a := detectInitAnswers(cwd, analysis)
+	if noInput {
+		output.Info("🧭 Using detected answers (--no-input)")
+		output.Info()
+		return a
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	output.Info("🧭 A few questions (press Enter to accept the [default])")
+	output.Info()
+
+	ids := []string{}
+	for _, t := range a.tools {
+		ids = append(ids, t.id)
+	}
+	all := []string{}
+	for _, t := range aiTools {
+		all = append(all, t.id)
+	}
+	for {
+		output.Infof("Which AI tools do you use? (%s) [%s]: ", strings.Join(all, ", "), strings.Join(ids, ", "))
+		input := readLine(reader)
+		if input == "" {
+			break
+		}
+		if tools, ok := parseTools(input); ok {
+			a.tools = tools
+			break
+		}
+		output.Errorf("⚠️  Pick one or more of: %s\n", strings.Join(all, ", "))
+	}
+
+	a.monorepo = askYesNo(reader, "Is this a monorepo?", a.monorepo)
+	if !initLocalOnly {
+		a.commit = askYesNo(reader, "Commit the generated context files to git?", a.commit)
+		a.ignore = askYesNo(reader, "Add ContextPilot's local files (sessions, logs, cache) to .gitignore?", a.ignore)
+	}
+	a.mcp = askYesNo(reader, "Register the ContextPilot MCP server for these tools?", a.mcp)
+	output.Info()
+	return a
+}
+
+// parseTools turns a comma- or space-separated list of tool IDs into
+// tools, in aiTools order
+func parseTools(input string) ([]aiTool, bool) {
+	chosen := map[string]bool{}
+	for _, id := range strings.FieldsFunc(strings.ToLower(input), func(r rune) bool { return r == ',' || r == ' ' }) {
+		chosen[id] = true
+	}
+	var tools []aiTool
+	for _, t := range aiTools {
+		if chosen[t.id] {
+			tools = append(tools, t)
+			delete(chosen, t.id)
+		}
+	}
+	return tools, len(tools) > 0 && len(chosen) == 0
+}
+
+// askYesNo asks question and returns the answer, or def on an empty line
+func askYesNo(reader *bufio.Reader, question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		output.Infof("%s [%s]: ", question, hint)
+		switch strings.ToLower(readLine(reader)) {
+		case "":
+			return def
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+	}
+}
+
+// applyInitAnswers records the outputs and repository layout and registers
+// the MCP server, printing what changed
+func applyInitAnswers(cwd string, analysis *analyzer.Analysis, a *initAnswers) {
+	if cfg, err := config.Load(cwd); err == nil && !slices.Equal(cfg.Outputs, a.outputs()) {
+		if err := config.SetOutputs(cwd, a.outputs()); err != nil {
+			output.Errorf("⚠️  Could not record the outputs: %v\n", err)
+		}
+	}
+
+	if detected := analysis.Structure.Type == "monorepo"; a.monorepo != detected {
+		structure := "single"
+		if a.monorepo {
+			structure = "monorepo"
+		}
+		if err := config.SetStructure(cwd, structure); err != nil {
+			output.Errorf("⚠️  Could not record the repository layout: %v\n", err)
+		} else {
+			output.Printf("   📐 config.yaml: structure: %s\n", structure)
+		}
+	}
+
+	if !a.mcp {
+		return
+	}
+	// Project files are shared, so they name the command rather than this
+	// machine's path to it, unless contextpilot only runs through npx
+	srv := mcpconfig.Server{Command: "contextpilot", Args: []string{"mcp"}}
+	if exe, err := binaryPath(); err == nil {
+		if launch := mcpconfig.LaunchFor(exe); launch.Runner() {
+			srv = mcpconfig.Server{Command: launch.Command, Args: append(launch.Args, "mcp")}
+		}
+	}
+	for _, t := range a.tools {
+		status, err := mcpconfig.Add(filepath.Join(cwd, t.mcpPath), t.mcpKey, mcpconfig.ServerName, srv)
+		if err != nil {
+			output.Errorf("⚠️  Could not register the MCP server for %s: %v\n", t.name, err)
+			continue
+		}
+		output.Printf("   🔌 %s: MCP server %s (%s)\n", t.mcpPath, status, t.name)
+	}
+}
@@ -2,12 +2,17 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"time"
 
 	"github.com/jitin-nhz/contextpilot/internal/mcp"
+	"github.com/jitin-nhz/contextpilot/internal/output"
 	"github.com/spf13/cobra"
 )
 
+var mcpTimeout time.Duration
+
 var mcpCmd = &cobra.Command{
 	Use:   "mcp",
 	Short: "Start MCP server for AI tool integration",
@@ -16,7 +21,11 @@ with AI coding tools like Claude Code and Windsurf.
 
 The server communicates via JSON-RPC over stdio.
 
-Add to your MCP config (claude_desktop_config.json or similar):
+Register it with a client in one step:
+
+  contextpilot mcp install --client claude|claude-code|cursor|windsurf|vscode
+
+or add it to your MCP config (claude_desktop_config.json or similar):
 
 {
   "mcpServers": {
@@ -45,6 +54,21 @@ Available tools:
   - contextpilot_decision Log architectural decision
   - contextpilot_score   Get context quality score
 
+Sampling tools (opt-in with "mcp: {sampling: true}" in config.yaml,
+and only when the client supports sampling):
+  - contextpilot_summarize_session  Condense a session via the client's model
+  - contextpilot_draft_decision     Draft a decision record from conversation text
+  - contextpilot_improve            Draft a better CLAUDE.md section and write it
+                                    once the user confirms (needs elicitation)
+
+Each tool call gets 2 minutes to analyze and write files. A call that
+runs out of time (e.g. on a slow network filesystem) answers with how far
+it got and the partial analysis. Change the limit with --timeout or in
+config.yaml ("0" for none):
+
+  mcp:
+    timeout: 30s
+
 Available resources:
   - contextpilot://context  Project context (CLAUDE.md/.cursorrules)
   - contextpilot://session  Current work session`,
@@ -52,13 +76,21 @@ Available resources:
 }
 
 func runMCP(cmd *cobra.Command, args []string) {
-	cwd, err := os.Getwd()
+	cwd, err := projectDir()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
 		os.Exit(1)
 	}
 
 	server := mcp.NewServer(cwd, Version)
+	server.SetTimeout(mcpTimeout)
+
+	// stdout now belongs to the JSON-RPC stream; anything else that tries
+	// to print (warnings from analysis, stray logging) goes to stderr
+	os.Stdout = os.Stderr
+	output.Stdout = os.Stderr
+	log.SetOutput(os.Stderr)
+
 	if err := server.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "MCP server error: %v\n", err)
 		os.Exit(1)
@@ -66,5 +98,6 @@ func runMCP(cmd *cobra.Command, args []string) {
 }
 
 func init() {
+	mcpCmd.Flags().DurationVar(&mcpTimeout, "timeout", 0, "Time limit for each tool call, e.g. 30s (default: mcp.timeout in config.yaml, or 2m)")
 	rootCmd.AddCommand(mcpCmd)
 }
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/mcpconfig"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mcpInstallClient   string
+	mcpInstallNoVerify bool
+	mcpInstallDryRun   bool
+)
+
+// mcpVerifyTimeout bounds the handshake with the registered server;
+// through a package runner it may have to download contextpilot first
+const (
+	mcpVerifyTimeout       = 10 * time.Second
+	mcpRunnerVerifyTimeout = 2 * time.Minute
+)
+
+var mcpInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Register the MCP server with an AI client",
+	Long: `Add ContextPilot to an MCP client's config file, pointing at this
+binary (absolute path) and this project (cwd), then start the server the
+way the client will and check it answers the MCP handshake.
+
+The entry follows how contextpilot was installed, so it survives
+upgrades: run through npx (or pnpm dlx, bunx) it registers
+"npx -y contextpilot mcp", and from Homebrew or Scoop it uses their
+stable link rather than the current version's directory.
+
+Clients:
+  claude       Claude Desktop (claude_desktop_config.json)
+  claude-code  Claude Code (.mcp.json in the project)
+  cursor       Cursor (~/.cursor/mcp.json)
+  windsurf     Windsurf (~/.codeium/windsurf/mcp_config.json)
+  vscode       VS Code (.vscode/mcp.json in the project)
+
+Other servers and settings in the file are kept; an existing contextpilot
+entry is replaced.
+
+Examples:
+  contextpilot mcp install --client claude
+  contextpilot mcp install --client cursor --dry-run`,
+	Args: cobra.NoArgs,
+	Run:  runMCPInstall,
+}
+
+func runMCPInstall(cmd *cobra.Command, args []string) {
+	client, ok := mcpconfig.Lookup(mcpInstallClient)
+	if !ok {
+		output.Errorf("❌ Unknown client %q (want %s)\n", mcpInstallClient, strings.Join(mcpconfig.IDs(), ", "))
+		os.Exit(1)
+	}
+
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	path, err := client.Path(cwd)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	exe, err := binaryPath()
+	if err != nil {
+		output.Errorf("❌ Could not locate the contextpilot binary: %v\n", err)
+		os.Exit(1)
+	}
+	launch := mcpconfig.LaunchFor(exe)
+	srv := client.Entry(launch, cwd)
+	display := displayPath(cwd, path)
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, home+string(filepath.Separator)) {
+		display = "~" + strings.TrimPrefix(path, home)
+	}
+
+	if mcpInstallDryRun {
+		output.Printf("🔍 Would register ContextPilot with %s in %s:\n", client.Name, display)
+		output.Printf("   ▶️  %s %s\n", srv.Command, strings.Join(srv.Args, " "))
+		output.Printf("   📂 cwd: %s\n", srv.Cwd)
+		if launch.Via != "binary" {
+			output.Printf("   📦 via %s\n", launch.Via)
+		}
+		return
+	}
+
+	status, err := mcpconfig.Add(path, client.Key, mcpconfig.ServerName, srv)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	output.Printf("✅ Registered ContextPilot with %s\n", client.Name)
+	output.Printf("   📄 %s (%s)\n", display, status)
+	output.Printf("   ▶️  %s %s\n", srv.Command, strings.Join(srv.Args, " "))
+	output.Printf("   📂 cwd: %s\n", srv.Cwd)
+	if launch.Via != "binary" {
+		output.Printf("   📦 via %s\n", launch.Via)
+	}
+
+	if !mcpInstallNoVerify {
+		// Check what was written, not what we meant to write
+		written, found, err := mcpconfig.Get(path, client.Key, mcpconfig.ServerName)
+		if err == nil && !found {
+			err = os.ErrNotExist
+		}
+		if err == nil {
+			var info string
+			timeout := mcpVerifyTimeout
+			if launch.Runner() {
+				timeout = mcpRunnerVerifyTimeout
+			}
+			info, err = mcpconfig.Verify(written, timeout)
+			if err == nil {
+				output.Printf("   🤝 Handshake OK: %s\n", info)
+			}
+		}
+		if err != nil {
+			output.Errorf("❌ Handshake failed: %v\n", err)
+			output.Info("💡 Run 'contextpilot doctor' to check the installation")
+			os.Exit(1)
+		}
+	}
+
+	output.Info()
+	output.Infof("💡 Restart %s to load the server\n", client.Name)
+}
+
+// binaryPath returns an absolute path that runs this contextpilot. The path
+// it was started by is preferred over the resolved executable, so package
+// manager symlinks (e.g. Homebrew's bin/contextpilot) survive upgrades.
+func binaryPath() (string, error) {
+	if strings.ContainsRune(os.Args[0], filepath.Separator) {
+		return filepath.Abs(os.Args[0])
+	}
+	if path, err := exec.LookPath(os.Args[0]); err == nil {
+		return filepath.Abs(path)
+	}
+	return os.Executable()
+}
+
+func init() {
+	mcpCmd.AddCommand(mcpInstallCmd)
+	mcpInstallCmd.Flags().StringVar(&mcpInstallClient, "client", "", "Client to configure: "+strings.Join(mcpconfig.IDs(), ", "))
+	mcpInstallCmd.Flags().BoolVar(&mcpInstallNoVerify, "no-verify", false, "Skip the handshake with the registered server")
+	mcpInstallCmd.Flags().BoolVar(&mcpInstallDryRun, "dry-run", false, "Show the entry without writing it")
+	mcpInstallCmd.MarkFlagRequired("client")
+}
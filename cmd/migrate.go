@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/jitin-nhz/contextpilot/internal/migrate"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var migrateDryRun bool
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade project files written by older releases",
+	Long: `Upgrade .contextpilot files (config.yaml, session history, the decision
+index) written by older ContextPilot releases to the current formats.
+
+Commands that write to .contextpilot (init, sync, save, decision,
+sessions done/gc/prune/pull, watch and the MCP servers) do this
+automatically when they find old files. Other commands, and previews
+and checks such as sync --dry-run or sync --check, leave the files alone
+and point here instead. Run migrate to do it explicitly, or with
+--dry-run to see what would change.
+Files are copied to .contextpilot/backups/<timestamp>/ before they are
+rewritten.
+
+Examples:
+  contextpilot migrate --dry-run
+  contextpilot migrate`,
+	Annotations: jsonCapable,
+	Run:         runMigrate,
+}
+
+func runMigrate(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	steps, err := migrate.Pending(cwd)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	backup := ""
+	if !migrateDryRun {
+		backup, err = migrate.Apply(cwd, steps)
+		if err != nil {
+			output.Errorf("❌ %v\n", err)
+			if backup != "" {
+				output.Infof("💾 Originals are in %s\n", backup)
+			}
+			os.Exit(1)
+		}
+	}
+
+	if output.IsJSON() {
+		migrations := []map[string]string{}
+		for _, s := range steps {
+			migrations = append(migrations, map[string]string{"name": s.Name, "change": s.Change})
+		}
+		printJSON(map[string]interface{}{"dryRun": migrateDryRun, "migrations": migrations, "backup": backup})
+		return
+	}
+
+	if len(steps) == 0 {
+		output.Println("✅ Everything is up to date")
+		return
+	}
+	if migrateDryRun {
+		output.Println("🔍 Would migrate:")
+	} else {
+		output.Println("🔧 Migrated:")
+	}
+	for _, s := range steps {
+		output.Printf("   • %s: %s\n", s.Name, s.Change)
+	}
+	if migrateDryRun {
+		output.Info()
+		output.Info("Run 'contextpilot migrate' to apply")
+	} else {
+		output.Infof("💾 Backup: %s\n", backup)
+	}
+}
+
+// autoMigrate upgrades old project files before a command that writes
+// them runs. Other commands leave the disk alone and only point to
+// 'contextpilot migrate'. Failures are reported but don't stop the
+// command; 'contextpilot migrate' shows the details.
+func autoMigrate(cmd *cobra.Command) {
+	cwd, err := projectDir()
+	if err != nil {
+		return
+	}
+	steps, err := migrate.Pending(cwd)
+	if err != nil {
+		output.Errorf("⚠️  %v\n", err)
+		return
+	}
+	if len(steps) == 0 {
+		return
+	}
+	if !writesProject(cmd) {
+		output.Errorf("⚠️  Project files are from an older ContextPilot; run 'contextpilot migrate' to upgrade them\n")
+		return
+	}
+	backup, err := migrate.Apply(cwd, steps)
+	if err != nil {
+		output.Errorf("⚠️  Could not upgrade project files: %v (run 'contextpilot migrate')\n", err)
+		return
+	}
+	for _, s := range steps {
+		output.Infof("🔧 Upgraded: %s\n", s.Change)
+	}
+	output.Infof("💾 Backup: %s\n", backup)
+}
+
+// writesProject reports whether cmd, as invoked, writes to .contextpilot.
+// Previews and checks (--dry-run, --check, --list) don't.
+func writesProject(cmd *cobra.Command) bool {
+	for _, name := range []string{"dry-run", "check", "list"} {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Value.String() == "true" {
+			return false
+		}
+	}
+	switch cmd {
+	case initCmd, syncCmd, saveCmd, watchCmd, mcpCmd, serveCmd,
+		decisionCmd, decisionEditCmd, decisionImportCmd, decisionMineCmd,
+		sessionsDoneCmd, sessionsGCCmd, sessionsPruneCmd, sessionsPullCmd:
+		return true
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show what would change without writing anything")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"sort"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var onboardOutput string
+
+var onboardCmd = &cobra.Command{
+	Use:   "onboard",
+	Short: "Write a single onboarding document for a new team member or agent",
+	Long: `Generate one document with everything needed on a first day in the
+project: an overview, the stack, common commands, the architecture (key
+folders, workspace packages and the prose from 'contextpilot enrich'),
+conventions, key decisions, and where to start reading: the entry point
+and the areas with the most commits in the last 90 days.
+
+Hand it to a new hire, or paste it into an AI agent starting with an empty
+context window. It is printed to stdout unless --output is set, and isn't
+kept up to date by sync; run onboard again to refresh it. It is rendered
+from ONBOARDING.md.tmpl, which can be overridden like the other templates
+(see 'contextpilot templates').
+
+Examples:
+  contextpilot onboard
+  contextpilot onboard -o ONBOARDING.md
+  contextpilot onboard | pbcopy`,
+	Args:        cobra.NoArgs,
+	Annotations: jsonCapable,
+	Run:         runOnboard,
+}
+
+func runOnboard(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	spin := output.StartSpinner("🔍 Analyzing codebase...")
+	analysis, err := analyzer.New(cwd).Analyze()
+	spin.Stop()
+	if err != nil {
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
+		os.Exit(1)
+	}
+	sort.Slice(analysis.Languages, func(i, j int) bool {
+		return analysis.Languages[i].FileCount > analysis.Languages[j].FileCount
+	})
+	doc := generator.New(analysis, cwd).RenderOnboarding()
+
+	if onboardOutput != "" {
+		if err := os.WriteFile(onboardOutput, []byte(doc), 0644); err != nil {
+			output.Errorf("❌ Error writing %s: %v\n", onboardOutput, err)
+			os.Exit(1)
+		}
+	}
+	switch {
+	case output.IsJSON():
+		printJSON(map[string]interface{}{"file": onboardOutput, "content": doc})
+	case onboardOutput != "":
+		output.Printf("📘 Wrote the onboarding document to %s\n", onboardOutput)
+	default:
+		output.Write(doc)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(onboardCmd)
+	onboardCmd.Flags().StringVarP(&onboardOutput, "output", "o", "", "Write to a file instead of stdout")
+}
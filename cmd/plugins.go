@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/plugin"
+	"github.com/spf13/cobra"
+)
+
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "List plugins and check they run",
+	Long: `List the plugins enabled in .contextpilot/config.yaml and ask each one
+to describe itself, then the plugins installed on PATH but not enabled.
+
+Plugins are executables named contextpilot-plugin-<name> that add
+private framework detectors and extra output files. Enable them with:
+
+  plugins:
+    - acme              # contextpilot-plugin-acme on PATH
+    - /opt/bin/detector # or an absolute path
+
+They run during analysis (the detect hook) and sync (the generate hook),
+reading a JSON request on stdin and writing a JSON response to stdout.
+See the README for the protocol.
+
+Exits with status 1 if an enabled plugin can't be run.
+
+Examples:
+  contextpilot plugins
+  contextpilot plugins --json`,
+	Args:        cobra.NoArgs,
+	Annotations: jsonCapable,
+	Run:         runPlugins,
+}
+
+// pluginStatus is one plugin as 'plugins' reports it
+type pluginStatus struct {
+	Name    string   `json:"name"`
+	Path    string   `json:"path,omitempty"`
+	Version string   `json:"version,omitempty"`
+	Hooks   []string `json:"hooks,omitempty"`
+	Enabled bool     `json:"enabled"`
+	Error   string   `json:"error,omitempty"`
+}
+
+func runPlugins(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	var statuses []pluginStatus
+	failed := false
+	for _, name := range cfg.Plugins {
+		st := describePlugin(cwd, name)
+		failed = failed || st.Error != ""
+		statuses = append(statuses, st)
+	}
+	var available []string
+	for _, name := range plugin.Discover() {
+		if !slices.Contains(cfg.Plugins, name) {
+			available = append(available, name)
+			statuses = append(statuses, pluginStatus{Name: name, Enabled: false})
+		}
+	}
+
+	if output.IsJSON() {
+		if statuses == nil {
+			statuses = []pluginStatus{}
+		}
+		printJSON(map[string]interface{}{"plugins": statuses})
+	} else {
+		printPlugins(statuses, available)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// describePlugin resolves an enabled plugin and runs its describe hook
+func describePlugin(root, name string) pluginStatus {
+	st := pluginStatus{Name: name, Enabled: true}
+	p, err := plugin.Resolve(name)
+	if err != nil {
+		st.Error = err.Error()
+		return st
+	}
+	st.Path = p.Path
+	resp, err := p.Call(context.Background(), plugin.HookDescribe, root, nil)
+	if err != nil {
+		st.Error = err.Error()
+		return st
+	}
+	st.Version = resp.Version
+	st.Hooks = resp.Hooks
+	return st
+}
+
+func printPlugins(statuses []pluginStatus, available []string) {
+	enabled := len(statuses) - len(available)
+	if enabled == 0 {
+		output.Println("🧩 No plugins enabled")
+	} else {
+		output.Printf("🧩 Plugins (%d enabled)\n", enabled)
+		output.Println()
+		for _, st := range statuses[:enabled] {
+			if st.Error != "" {
+				output.Printf("   ❌ %s: %s\n", st.Name, st.Error)
+				continue
+			}
+			line := "   ✅ " + st.Name
+			if st.Version != "" {
+				line += " " + st.Version
+			}
+			if len(st.Hooks) > 0 {
+				line += " (" + strings.Join(st.Hooks, ", ") + ")"
+			}
+			output.Printf("%s  %s\n", line, st.Path)
+		}
+	}
+
+	if len(available) > 0 {
+		output.Println()
+		output.Printf("📦 Installed but not enabled: %s\n", strings.Join(available, ", "))
+		output.Info("   Enable them under plugins: in .contextpilot/config.yaml")
+	} else if enabled == 0 {
+		output.Info()
+		output.Infof("Install a %s<name> executable on PATH and list it under\n", plugin.Prefix)
+		output.Info("plugins: in .contextpilot/config.yaml. See 'contextpilot plugins --help'.")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(pluginsCmd)
+}
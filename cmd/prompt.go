@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptIncludes []string
+	promptBudget   int
+	promptCopy     bool
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt [question]",
+	Short: "Build a paste-ready context prompt for chat tools",
+	Long: `Assemble a paste-ready prompt from selected pieces of project context,
+for tools that don't read rules files (ChatGPT web, Gemini, ...).
+
+Pieces (--include, comma-separated or repeated, in the order given):
+  stack        Framework, languages, package manager and test command
+  conventions  Detected naming, layout and testing conventions
+  decisions    Active architectural decisions, newest kept first
+  session      The saved work session for this branch
+  files=<glob> Contents of matching files (e.g. files=src/auth/**)
+
+--budget caps the prompt size in tokens (0 for no limit). Pieces that
+don't fit are left out, and a note on stderr says what was dropped.
+Files excluded by .contextpilotignore are never included.
+
+Examples:
+  contextpilot prompt
+  contextpilot prompt --include stack,decisions,files=src/auth/** --copy
+  contextpilot prompt "Why does login redirect twice?" --budget 2000 | llm`,
+	Run: runPrompt,
+}
+
+func runPrompt(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	pieces, err := prompt.Parse(promptIncludes)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if len(pieces) == 0 {
+		output.Errorf("❌ Nothing to include (see --include)\n")
+		os.Exit(1)
+	}
+
+	result, err := prompt.Build(cwd, pieces, promptBudget, strings.Join(args, " "))
+	if err != nil {
+		output.Errorf("❌ Error building prompt: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, o := range result.Omitted {
+		output.Infof("⚠️  Left out: %s\n", o)
+	}
+
+	if promptCopy {
+		if err := copyToClipboard(result.Text); err != nil {
+			output.Errorf("⚠️  Could not copy to clipboard: %v\n", err)
+		} else {
+			output.Infof("✅ Prompt copied to clipboard (~%d tokens)\n", result.Tokens)
+			return
+		}
+	}
+	output.Write(result.Text)
+	output.Infof("📏 ~%d tokens\n", result.Tokens)
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+	promptCmd.Flags().StringSliceVarP(&promptIncludes, "include", "i", prompt.DefaultIncludes, "Pieces to include: stack, conventions, decisions, session, files=<glob>")
+	promptCmd.Flags().IntVar(&promptBudget, "budget", 4000, "Maximum prompt size in tokens (0 for no limit)")
+	promptCmd.Flags().BoolVarP(&promptCopy, "copy", "c", false, "Copy to clipboard instead of printing")
+}
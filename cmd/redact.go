@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/redact"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var redactAudit bool
+
+var redactCmd = &cobra.Command{
+	Use:   "redact [file...]",
+	Short: "Apply the redaction policy, or audit the context files for leaks",
+	Long: `Keep details that must not reach third-party AI tools out of the context
+files and prompts. The policy lives in .contextpilot/config.yaml:
+
+  redact:
+    patterns: ['ACME-\d+', 'Project Falcon']  # regular expressions
+    hostnames: true        # *.internal, *.corp, *.lan, private IPs
+    domains: [acme.io]     # the company's domains and hosts under them
+    dependencies: [react, next, '@types/*']  # only these may be named
+    replacement: '[redacted]'
+
+Once set, it applies to everything ContextPilot writes for AI tools:
+the context files (init, sync, watch), onboard, prompt, resume,
+context-header, env-export, standup, changelog, explain, enrich requests
+and the MCP session and decision resources.
+
+With files, or text on stdin, redact prints them with the policy applied,
+for anything else about to be pasted into a chat.
+
+--audit instead reports every match in the context files on disk (or the
+files given), with its line and rule, and exits 1 if there are any: files
+generated before the policy, or edited by hand since. Run 'contextpilot
+sync' to regenerate them.
+
+Examples:
+  contextpilot redact --audit
+  contextpilot redact notes.md
+  git diff | contextpilot redact | pbcopy`,
+	Annotations: jsonCapable,
+	Run:         runRedact,
+}
+
+func runRedact(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !redactAudit {
+		policy := projectPolicy(cwd, nil)
+		if len(args) == 0 {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				output.Errorf("❌ Error reading stdin: %v\n", err)
+				os.Exit(1)
+			}
+			output.Write(policy.Apply(string(data)))
+			return
+		}
+		for _, f := range args {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				output.Errorf("❌ Error reading %s: %v\n", f, err)
+				os.Exit(1)
+			}
+			output.Write(policy.Apply(string(data)))
+		}
+		return
+	}
+
+	analysis, err := analyzer.New(cwd).Analyze()
+	if err != nil {
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
+		os.Exit(1)
+	}
+	policy := projectPolicy(cwd, analysis)
+	if !policy.Enabled() {
+		if output.IsJSON() {
+			printJSON(map[string]interface{}{"enabled": false, "files": []string{}, "findings": []redact.Finding{}})
+			return
+		}
+		output.Println("ℹ️  No redaction policy in .contextpilot/config.yaml")
+		output.Info("Add a redact section; see 'contextpilot redact --help'.")
+		return
+	}
+
+	files := args
+	if len(files) == 0 {
+		for _, f := range generator.New(analysis, cwd).Files() {
+			if _, err := os.Stat(filepath.Join(cwd, f)); err == nil {
+				files = append(files, f)
+			}
+		}
+	}
+	findings := []redact.Finding{}
+	for _, f := range files {
+		data, err := os.ReadFile(auditPath(cwd, f, len(args) > 0))
+		if err != nil {
+			output.Errorf("❌ Error reading %s: %v\n", f, err)
+			os.Exit(1)
+		}
+		findings = append(findings, policy.Audit(filepath.ToSlash(f), string(data))...)
+	}
+	if files == nil {
+		files = []string{}
+	}
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{"enabled": true, "files": files, "findings": findings})
+	} else {
+		printRedactAudit(files, findings)
+	}
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
+
+// auditPath returns where to read file: files given as arguments are
+// relative to the working directory, context files to the project
+func auditPath(cwd, file string, given bool) string {
+	if given || filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(cwd, file)
+}
+
+func printRedactAudit(files []string, findings []redact.Finding) {
+	if len(findings) == 0 {
+		output.Printf("✅ Nothing to redact in %d file(s)\n", len(files))
+		return
+	}
+	byFile := map[string][]redact.Finding{}
+	for _, f := range findings {
+		byFile[f.File] = append(byFile[f.File], f)
+	}
+	names := make([]string, 0, len(byFile))
+	for name := range byFile {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	output.Printf("🔒 %d match(es) of the redaction policy in %d file(s):\n", len(findings), len(names))
+	for _, name := range names {
+		output.Println()
+		output.Printf("   %s\n", name)
+		for _, f := range byFile[name] {
+			output.Printf("   %5d  %-10s  %s\n", f.Line, f.Rule, f.Match)
+		}
+	}
+	output.Info()
+	output.Info("Run 'contextpilot sync' to regenerate the context files with the policy applied.")
+}
+
+// projectPolicy returns the redaction policy of the project at cwd, for
+// what commands print for AI tools. analysis is nil when the command
+// didn't analyze the project; it is analyzed if the policy needs it.
+func projectPolicy(cwd string, analysis *analyzer.Analysis) *redact.Policy {
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		output.Errorf("❌ Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	if len(cfg.Redact.Dependencies) > 0 && analysis == nil {
+		if analysis, err = analyzer.New(cwd).Analyze(); err != nil {
+			output.Errorf("❌ Error analyzing codebase: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	policy, err := redact.New(cfg.Redact, analysis)
+	if err != nil {
+		output.Errorf("❌ Invalid redaction policy: %v\n", err)
+		os.Exit(1)
+	}
+	return policy
+}
+
+// redactDecisions applies policy to the text and context of decs, in place
+func redactDecisions(policy *redact.Policy, decs []decisions.Decision) {
+	for i := range decs {
+		decs[i].Text = policy.Apply(decs[i].Text)
+		decs[i].Context = policy.Apply(decs[i].Context)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(redactCmd)
+	redactCmd.Flags().BoolVar(&redactAudit, "audit", false, "Report what the policy matches in the context files, exit 1 if anything")
+}
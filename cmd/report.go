@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var reportTargets bool
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show the size of generated context files",
+	Long: `Report how many tokens each generated context file costs.
+
+With --targets, each file is broken down by section (stack, conventions,
+decisions, repo map, commands) with recommendations on what to trim.
+Token counts are estimates (~4 characters per token).
+
+Examples:
+  contextpilot report
+  contextpilot report --targets`,
+	Run: runReport,
+}
+
+func runReport(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	reports, err := report.Analyze(cwd)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	found := 0
+	for _, r := range reports {
+		if r.Exists {
+			found++
+		}
+	}
+	if found == 0 {
+		output.Println("📏 No context files found")
+		output.Info()
+		output.Info("Run 'contextpilot init' to generate context files.")
+		return
+	}
+
+	output.Printf("📏 Context size (budget: %d tokens per file)\n", report.Budget)
+	output.Println()
+
+	for _, r := range reports {
+		if !r.Exists {
+			output.Printf("   ⚪ %-32s not generated\n", r.Path)
+			continue
+		}
+
+		status := "🟢"
+		if r.Tokens > report.Budget {
+			status = "🔴"
+		}
+		output.Printf("   %s %-32s %6d tokens  (%s)\n", status, r.Path, r.Tokens, r.Tool)
+		if !reportTargets {
+			continue
+		}
+
+		for i, s := range r.Sections {
+			prefix := "├──"
+			if i == len(r.Sections)-1 && len(r.Recommendations) == 0 {
+				prefix = "└──"
+			}
+			output.Printf("      %s %-12s %6d tokens  %3d%%\n", prefix, s.Name, s.Tokens, s.Tokens*100/r.Tokens)
+		}
+		for i, rec := range r.Recommendations {
+			prefix := "├──"
+			if i == len(r.Recommendations)-1 {
+				prefix = "└──"
+			}
+			output.Printf("      %s 💡 %s\n", prefix, rec)
+		}
+		output.Println()
+	}
+
+	if !reportTargets {
+		output.Info()
+		output.Info("💡 Run 'contextpilot report --targets' for a per-section breakdown")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().BoolVar(&reportTargets, "targets", false, "Break each file down by section with trim recommendations")
+}
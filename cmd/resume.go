@@ -4,15 +4,22 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 
-	"github.com/jitin-nhz/contextpilot/internal/session"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/report"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
 	"github.com/spf13/cobra"
 )
 
 var (
-	resumeNoCopy bool
-	resumeFormat string
+	resumeNoCopy      bool
+	resumeFormat      string
+	resumeName        string
+	resumeWithContext bool
+	resumeBudget      int
+	resumeStdoutOnly  bool
 )
 
 var resumeCmd = &cobra.Command{
@@ -26,88 +33,135 @@ to restore your working context.
 Examples:
   contextpilot resume           # Copy to clipboard
   contextpilot resume --no-copy # Just print, don't copy
-  contextpilot resume --format markdown`,
+  contextpilot resume --name hotfix-123
+  contextpilot resume --format xml --stdout-only | llm
+  contextpilot resume --with-context --budget 300
+
+--with-context prepends a condensed project picture (stack, conventions,
+recent decisions) taken from CLAUDE.md, so a single paste gives the AI
+both the project and your working state. --budget caps its size in
+tokens; the oldest decisions are dropped first.
+
+Formats: markdown (default), plain (no markup), json (the session as
+structured data), xml (<context>...</context> tags, which some models
+follow more reliably). --stdout-only prints just the prompt, with no
+clipboard copy and no decoration, for piping into other tools.`,
 	Run: runResume,
 }
 
 func runResume(cmd *cobra.Command, args []string) {
-	cwd, err := os.Getwd()
+	cwd, err := projectDir()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		output.Errorf("❌ Error: %v\n", err)
 		os.Exit(1)
 	}
 
 	mgr := session.New(cwd)
-	s, err := mgr.Load()
+	s, err := mgr.LoadNamed(resumeName)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error loading session: %v\n", err)
+		output.Errorf("❌ Error loading session: %v\n", err)
 		os.Exit(1)
 	}
 
 	if s == nil {
-		fmt.Println("📋 No saved session for this branch")
-		fmt.Println()
-		fmt.Println("Save one with: contextpilot save \"Your task description\"")
+		if resumeName != "" && resumeName != session.DefaultName {
+			output.Infof("📋 No session named '%s' for this branch\n", resumeName)
+			output.Info()
+			output.Info("See saved sessions with: contextpilot sessions list")
+			return
+		}
+		output.Info("📋 No saved session for this branch")
+		output.Info()
+		output.Info("Save one with: contextpilot save \"Your task description\"")
 		return
 	}
 
 	// Generate prompt
-	prompt := mgr.GeneratePrompt(s)
+	project := ""
+	if resumeWithContext {
+		project = projectContext(cwd)
+	}
+	prompt, err := mgr.Render(s, resumeFormat, project)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	prompt = projectPolicy(cwd, nil).Apply(prompt)
+
+	if resumeStdoutOnly {
+		output.Write(prompt)
+		return
+	}
 
 	// Copy to clipboard (unless --no-copy)
 	if !resumeNoCopy {
 		if err := copyToClipboard(prompt); err != nil {
-			fmt.Fprintf(os.Stderr, "⚠️  Could not copy to clipboard: %v\n", err)
-			fmt.Println()
+			output.Errorf("⚠️  Could not copy to clipboard: %v\n", err)
+			output.Info()
 			resumeNoCopy = true // Fall back to printing
 		} else {
-			fmt.Println("✅ Session context copied to clipboard!")
-			fmt.Println()
-			fmt.Println("Paste into Cursor, Claude Code, or ChatGPT to resume.")
-			fmt.Println()
+			output.Info("✅ Session context copied to clipboard!")
+			output.Info()
+			output.Info("Paste into Cursor, Claude Code, or ChatGPT to resume.")
+			output.Info()
 		}
 	}
 
 	// Print preview or full content
 	if resumeNoCopy {
-		fmt.Println("📋 Session Context:")
-		fmt.Println(repeatStr("─", 50))
-		fmt.Println(prompt)
-		fmt.Println(repeatStr("─", 50))
+		output.Info("📋 Session Context:")
+		output.Info(repeatStr("─", 50))
+		output.Write(prompt)
+		output.Info(repeatStr("─", 50))
 	} else {
 		// Show preview
-		fmt.Printf("📝 Task: %s\n", s.Task)
+		output.Printf("📝 Task: %s\n", s.Task)
 		if s.State != "" {
-			fmt.Printf("📍 State: %s\n", s.State)
+			output.Printf("📍 State: %s\n", s.State)
 		}
 		if len(s.NextSteps) > 0 {
-			fmt.Printf("➡️  Next: %s\n", s.NextSteps[0])
+			output.Printf("➡️  Next: %s\n", s.NextSteps[0])
 			if len(s.NextSteps) > 1 {
-				fmt.Printf("   (+%d more steps)\n", len(s.NextSteps)-1)
+				output.Printf("   (+%d more steps)\n", len(s.NextSteps)-1)
 			}
 		}
 	}
 }
 
-func copyToClipboard(text string) error {
-	var cmd *exec.Cmd
+// projectContext returns the condensed CLAUDE.md, or "" if there is none
+func projectContext(cwd string) string {
+	data, err := os.ReadFile(filepath.Join(cwd, "CLAUDE.md"))
+	if err != nil {
+		output.Info("⚠️  No CLAUDE.md found, resuming without project context (run 'contextpilot init')")
+		return ""
+	}
+	return report.Condense(string(data), resumeBudget)
+}
 
+// clipboardCommand returns the command that copies its stdin to the
+// system clipboard
+func clipboardCommand() (*exec.Cmd, error) {
 	switch runtime.GOOS {
 	case "darwin":
-		cmd = exec.Command("pbcopy")
+		return exec.Command("pbcopy"), nil
 	case "linux":
 		// Try xclip first, then xsel
 		if _, err := exec.LookPath("xclip"); err == nil {
-			cmd = exec.Command("xclip", "-selection", "clipboard")
+			return exec.Command("xclip", "-selection", "clipboard"), nil
 		} else if _, err := exec.LookPath("xsel"); err == nil {
-			cmd = exec.Command("xsel", "--clipboard", "--input")
-		} else {
-			return fmt.Errorf("no clipboard tool found (install xclip or xsel)")
+			return exec.Command("xsel", "--clipboard", "--input"), nil
 		}
+		return nil, fmt.Errorf("no clipboard tool found (install xclip or xsel)")
 	case "windows":
-		cmd = exec.Command("clip")
-	default:
-		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+		return exec.Command("clip"), nil
+	}
+	return nil, fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+}
+
+func copyToClipboard(text string) error {
+	cmd, err := clipboardCommand()
+	if err != nil {
+		return err
 	}
 
 	stdin, err := cmd.StdinPipe()
@@ -137,5 +191,9 @@ func repeatStr(s string, n int) string {
 func init() {
 	rootCmd.AddCommand(resumeCmd)
 	resumeCmd.Flags().BoolVar(&resumeNoCopy, "no-copy", false, "Print instead of copying to clipboard")
-	resumeCmd.Flags().StringVar(&resumeFormat, "format", "markdown", "Output format (markdown, plain)")
+	resumeCmd.Flags().StringVar(&resumeFormat, "format", "markdown", "Output format (markdown, plain, json, xml)")
+	resumeCmd.Flags().BoolVar(&resumeStdoutOnly, "stdout-only", false, "Print only the prompt to stdout (no clipboard, no decoration)")
+	resumeCmd.Flags().BoolVar(&resumeWithContext, "with-context", false, "Prepend a condensed project context (stack, conventions, recent decisions) from CLAUDE.md")
+	resumeCmd.Flags().IntVar(&resumeBudget, "budget", 500, "Token budget for the project context added by --with-context")
+	resumeCmd.Flags().StringVar(&resumeName, "name", "", "Resume a named session instead of the default one")
 }
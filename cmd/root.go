@@ -2,8 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"strconv"
 
+	"github.com/jitin-nhz/contextpilot/internal/api"
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/logging"
+	"github.com/jitin-nhz/contextpilot/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +19,17 @@ var (
 	Date    = "unknown"
 )
 
+var (
+	plainOutput bool
+	quietOutput bool
+	noInput     bool
+	jsonOutput  bool
+	apiVersion  int
+	verboseLog  bool
+	debugLog    bool
+	logFile     bool
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "contextpilot",
 	Short: "Make every AI tool understand your codebase",
@@ -20,16 +37,187 @@ var rootCmd = &cobra.Command{
 (.cursorrules, CLAUDE.md, copilot-instructions.md) so your
 AI coding tools actually understand your codebase.
 
+New here? Run 'contextpilot demo' for a guided tour.
+
 Codebase Context:
   contextpilot init      Generate context files for current project
   contextpilot sync      Update context files after code changes
+  contextpilot status    Show unsynced commits and stale context files
+  contextpilot watch     Regenerate context files as the code changes
+  contextpilot hooks     Install git hooks that keep context fresh
   contextpilot decision  Log architectural decisions
   contextpilot score     Check your context quality
+  contextpilot check     Verify context in CI (exit codes, --json)
+  contextpilot ci        Comment the drift a pull request introduces
+  contextpilot suggest   Find busy areas with no recorded decisions
+  contextpilot explain   Show the context that applies to a file or folder
+  contextpilot enrich    Draft architecture prose with an LLM (opt-in)
+  contextpilot analyze   Show the stack, or what changed between two refs
+  contextpilot stats     Show languages, directories, deps and tests
+  contextpilot bench     Time analysis and find slow directories
+  contextpilot report    Show token size of generated context files
+  contextpilot doctor    Diagnose environment and config problems
+  contextpilot migrate   Upgrade files written by older releases
 
 Session Context:
   contextpilot save      Save current work session
-  contextpilot resume    Restore session and copy to clipboard`,
+  contextpilot resume    Restore session and copy to clipboard
+  contextpilot sessions  List saved sessions for this branch
+  contextpilot changelog Draft a CHANGELOG section from decisions and sessions
+  contextpilot onboard   Write one onboarding document for a new hire or agent
+
+Integration:
+  contextpilot mcp         Start MCP server for AI tool integration
+  contextpilot context-header
+                           Compact project header for ad-hoc prompts
+  contextpilot env-export  Export context as env vars or JSON
+  contextpilot redact      Apply the redaction policy, or --audit the context
+  contextpilot devcontainer
+                           Wire the MCP server into devcontainer.json
+  contextpilot serve       Serve analysis, score and decisions over HTTP
+  contextpilot plugins     List plugins that add detectors and outputs
+  contextpilot templates   Show or eject the templates files are rendered from
+  contextpilot self-update Update to the latest signed release (--channel beta)
+
+Output:
+  Data (results, tables, prompts) is written to stdout; progress,
+  hints and errors go to stderr. Use --plain (or CONTEXTPILOT_PLAIN=1)
+  to drop emoji and box-drawing characters, and --quiet (or
+  CONTEXTPILOT_QUIET=1) to drop progress and hints; errors are always
+  shown. --no-input (or CONTEXTPILOT_NO_INPUT=1, or CI=true) never
+  prompts: commands that would ask for something fail with a message
+  naming the flag to pass instead. --json (or
+  CONTEXTPILOT_OUTPUT=json) makes init, sync, score, stats, doctor,
+  decision, changelog, templates, self-update, explain and sessions
+  list print one JSON document instead. JSON output carries an
+  "apiVersion" field; pin it with --api-version so schemas don't change
+  under you between releases.
+
+Logging:
+  --verbose (or CONTEXTPILOT_VERBOSE=1) logs what analysis found and
+  which files were written to stderr; --debug (or CONTEXTPILOT_DEBUG=1)
+  adds phase timings and MCP request/response traces. --log-file, or
+  "logging: {file: true}" in config.yaml, keeps a debug log of every run
+  in .contextpilot/logs/, rotated at 1 MB.`,
 	Version: fmt.Sprintf("%s (commit: %s, built: %s)", Version, Commit, Date),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if os.Getenv("CONTEXTPILOT_PLAIN") != "" {
+			plainOutput = true
+		}
+		output.SetPlain(plainOutput)
+		if os.Getenv("CONTEXTPILOT_QUIET") != "" {
+			quietOutput = true
+		}
+		output.SetQuiet(quietOutput)
+		if os.Getenv("CONTEXTPILOT_NO_INPUT") != "" || os.Getenv("CI") == "true" {
+			noInput = true
+		}
+		setupLogging(cmd, args)
+
+		switch {
+		case jsonOutput && !supportsJSON(cmd):
+			output.Errorf("❌ --json is not supported by '%s'\n", cmd.CommandPath())
+			os.Exit(1)
+		case jsonOutput:
+			output.SetJSON(true)
+		case !cmd.Flags().Changed("json") && os.Getenv("CONTEXTPILOT_OUTPUT") == "json":
+			// The environment sets a preference; commands without JSON
+			// output keep printing text
+			output.SetJSON(supportsJSON(cmd))
+		}
+
+		if !cmd.Flags().Changed("api-version") {
+			if v, err := strconv.Atoi(os.Getenv("CONTEXTPILOT_API_VERSION")); err == nil {
+				apiVersion = v
+			}
+		}
+		if err := api.Select(apiVersion); err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		if cmd != migrateCmd {
+			autoMigrate(cmd)
+		}
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		updateNotice(cmd)
+	},
+}
+
+// setupLogging installs the logger asked for by --verbose, --debug and
+// --log-file (or logging.file in config.yaml)
+func setupLogging(cmd *cobra.Command, args []string) {
+	if os.Getenv("CONTEXTPILOT_VERBOSE") != "" {
+		verboseLog = true
+	}
+	if os.Getenv("CONTEXTPILOT_DEBUG") != "" {
+		debugLog = true
+	}
+
+	var opts logging.Options
+	switch {
+	case debugLog:
+		level := slog.LevelDebug
+		opts.Level = &level
+	case verboseLog:
+		level := slog.LevelInfo
+		opts.Level = &level
+	}
+	// Only initialized projects get a log file
+	if cwd, err := projectDir(); err == nil {
+		if cfg, err := config.Load(cwd); err == nil && config.Exists(cwd) && (logFile || cfg.Logging.File) {
+			opts.Root = cwd
+		}
+	}
+
+	if err := logging.Setup(opts); err != nil {
+		output.Errorf("⚠️  Logging disabled: %v\n", err)
+		return
+	}
+	slog.Debug("command started", "command", cmd.CommandPath(), "args", args, "version", Version)
+}
+
+// projectDir returns the project commands work on: the nearest directory
+// from the working directory up to the top of its git repository with a
+// .contextpilot/config.yaml, so they can run anywhere inside it, or the
+// working directory itself
+func projectDir() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return config.Root(cwd), nil
+}
+
+// jsonAnnotation marks commands that can print their result as JSON
+const jsonAnnotation = "contextpilot/json"
+
+// jsonCapable is the Annotations value for commands supporting --json
+var jsonCapable = map[string]string{jsonAnnotation: "true"}
+
+func supportsJSON(cmd *cobra.Command) bool {
+	return cmd.Annotations[jsonAnnotation] == "true"
+}
+
+// printJSON writes doc, stamped with the API version, as the command's
+// JSON result
+func printJSON(doc map[string]interface{}) {
+	doc["apiVersion"] = api.Selected()
+	if err := output.JSON(doc); err != nil {
+		output.Errorf("❌ Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// inputDisabled reports, with an error naming the alternative, that what
+// would prompt but --no-input is set
+func inputDisabled(what, alternative string) bool {
+	if !noInput {
+		return false
+	}
+	output.Errorf("❌ %s needs input, but --no-input is set: %s\n", what, alternative)
+	return true
 }
 
 func Execute() {
@@ -42,4 +230,12 @@ func Execute() {
 func init() {
 	rootCmd.SetVersionTemplate(`ContextPilot {{.Version}}
 `)
+	rootCmd.PersistentFlags().BoolVar(&plainOutput, "plain", false, "Plain output: no emoji or box-drawing characters")
+	rootCmd.PersistentFlags().BoolVar(&quietOutput, "quiet", false, "Suppress progress and hints; only data and errors are printed")
+	rootCmd.PersistentFlags().BoolVar(&noInput, "no-input", false, "Never prompt for input; fail instead (or CONTEXTPILOT_NO_INPUT=1, CI=true)")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Print the result as JSON (or CONTEXTPILOT_OUTPUT=json)")
+	rootCmd.PersistentFlags().BoolVar(&verboseLog, "verbose", false, "Log what was analyzed and written to stderr (or CONTEXTPILOT_VERBOSE=1)")
+	rootCmd.PersistentFlags().BoolVar(&debugLog, "debug", false, "Log timings and MCP traces too (or CONTEXTPILOT_DEBUG=1)")
+	rootCmd.PersistentFlags().BoolVar(&logFile, "log-file", false, "Keep a debug log in .contextpilot/logs/")
+	rootCmd.PersistentFlags().IntVar(&apiVersion, "api-version", 0, fmt.Sprintf("Machine API version for JSON and MCP output (default %d, or CONTEXTPILOT_API_VERSION)", api.Current))
 }
@@ -2,20 +2,37 @@ package cmd
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
-	"github.com/jitin-nhz/contextpilot/internal/session"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
 	"github.com/spf13/cobra"
 )
 
 var (
-	saveTask      string
-	saveGoal      string
-	saveState     string
-	saveNotes     string
-	saveQuick     bool
+	saveTask     string
+	saveGoal     string
+	saveState    string
+	saveNotes    string
+	saveName     string
+	saveQuick    bool
+	saveWatch    bool
+	saveInterval time.Duration
+	saveNext     []string
+	saveApproach []string
+	saveDecision []string
+	saveIssue    string
+	savePR       string
+	saveStdin    bool
 )
 
 var saveCmd = &cobra.Command{
@@ -28,26 +45,66 @@ Captures: task, goal, approaches tried, decisions, current state, next steps.
 Examples:
   contextpilot save "Refactoring payment service"
   contextpilot save --task "Auth migration" --state "JWT implemented, testing SSO"
+  contextpilot save "Hotfix for checkout" --name hotfix-123
   contextpilot save  # Interactive mode
+  contextpilot save "Auth migration" --watch --interval 5m
+  contextpilot save --next "write tests" --next "update docs"
+  contextpilot save --approach "tried caching tokens, too stale"
+  contextpilot save "Checkout bug" --issue https://linear.app/acme/issue/PAY-42
+  echo '{"task": "Auth migration", "nextSteps": ["write tests"]}' | contextpilot save --stdin
 
-The session is scoped to your current git branch.`,
+The session is scoped to your current git branch. Use --name to keep
+several sessions on the same branch; without it the branch's default
+session is used.
+
+With --watch, save keeps running and snapshots the session (HEAD commit
+and uncommitted files) every --interval and whenever you commit or
+check out another branch. Stop it with Ctrl+C.
+
+--next and --approach can be repeated and append to the existing session,
+so it works as a lightweight branch-scoped todo list. Check steps off with
+'contextpilot session done <n>'.
+
+--issue and --pr link the session to a ticket or pull request (URL or
+reference). Without --issue, keys in the branch name such as
+feat/PROJ-123-foo or fix/42-typo are detected automatically.
+
+--stdin reads the session as a JSON object with the same fields as
+'resume --format json' (task, goal, state, notes, issue, pr, approaches,
+decisions, nextSteps); flags take precedence over it. Together with
+--no-input this lets scripts save a session without any prompts.`,
 	Run: runSave,
 }
 
 func runSave(cmd *cobra.Command, args []string) {
-	cwd, err := os.Getwd()
+	cwd, err := projectDir()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if saveInterval <= 0 {
+		output.Errorf("❌ --interval must be positive, got %s\n", saveInterval)
 		os.Exit(1)
 	}
 
 	mgr := session.New(cwd)
 
 	// Load existing session or create new
-	s, _ := mgr.Load()
+	s, _ := mgr.LoadNamed(saveName)
 	if s == nil {
 		s = &session.Session{}
 	}
+	if saveName != session.DefaultName {
+		s.Name = saveName
+	}
+
+	if saveStdin {
+		if err := readSessionJSON(s, os.Stdin); err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	// Get task from args or flag
 	if len(args) > 0 {
@@ -66,75 +123,200 @@ func runSave(cmd *cobra.Command, args []string) {
 	if saveNotes != "" {
 		s.Notes = saveNotes
 	}
+	if saveIssue != "" {
+		s.Issue = saveIssue
+	}
+	if savePR != "" {
+		s.PR = savePR
+	}
+	s.NextSteps = session.AddUnique(s.NextSteps, saveNext...)
+	s.Approaches = session.AddUnique(s.Approaches, saveApproach...)
+	s.Decisions = session.AddUnique(s.Decisions, saveDecision...)
 
 	// Interactive mode if no task provided
-	if s.Task == "" && !saveQuick {
+	if s.Task == "" && !saveQuick && !saveStdin {
+		if inputDisabled("save", "pass the task as an argument, with --task or via --stdin") {
+			os.Exit(1)
+		}
 		s = interactiveSession(s)
 	} else if s.Task == "" {
-		fmt.Println("❌ Please provide a task description")
-		fmt.Println()
-		fmt.Println("Usage: contextpilot save \"Your task description\"")
-		fmt.Println("   or: contextpilot save  # for interactive mode")
+		output.Errorf("❌ Please provide a task description\n")
+		output.Info()
+		output.Info("Usage: contextpilot save \"Your task description\"")
+		output.Info("   or: contextpilot save  # for interactive mode")
+		output.Info("   or: echo '{\"task\": \"...\"}' | contextpilot save --stdin")
 		os.Exit(1)
 	}
 
 	// Save session
 	if err := mgr.Save(s); err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error saving session: %v\n", err)
+		output.Errorf("❌ Error saving session: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Println("✅ Session saved!")
-	fmt.Println()
-	fmt.Printf("   📝 Task: %s\n", s.Task)
+	output.Println("✅ Session saved!")
+	output.Println()
+	if !s.IsDefault() {
+		output.Printf("   🏷️  Name: %s\n", s.Name)
+	}
+	output.Printf("   📝 Task: %s\n", s.Task)
 	if s.Goal != "" {
-		fmt.Printf("   🎯 Goal: %s\n", s.Goal)
+		output.Printf("   🎯 Goal: %s\n", s.Goal)
 	}
 	if s.State != "" {
-		fmt.Printf("   📍 State: %s\n", s.State)
+		output.Printf("   📍 State: %s\n", s.State)
+	}
+	if s.Issue != "" {
+		output.Printf("   🎫 Issue: %s\n", s.Issue)
+	}
+	if s.PR != "" {
+		output.Printf("   🔀 PR: %s\n", s.PR)
 	}
 	if len(s.Approaches) > 0 {
-		fmt.Printf("   🔄 Approaches: %d logged\n", len(s.Approaches))
+		output.Printf("   🔄 Approaches: %d logged\n", len(s.Approaches))
 	}
 	if len(s.NextSteps) > 0 {
-		fmt.Printf("   ➡️  Next steps: %d items\n", len(s.NextSteps))
+		output.Printf("   ➡️  Next steps: %d items\n", len(s.NextSteps))
+	}
+	output.Info()
+	if saveWatch {
+		watchSession(mgr)
+		return
+	}
+	if s.IsDefault() {
+		output.Info("💡 Run 'contextpilot resume' to restore this context")
+	} else {
+		output.Infof("💡 Run 'contextpilot resume --name %s' to restore this context\n", s.Name)
+	}
+}
+
+// watchSession snapshots the session periodically and on git events
+// (commit, checkout) until interrupted
+func watchSession(mgr *session.Manager) {
+	output.Infof("👀 Watching session (snapshot every %s, and on commit/checkout). Press Ctrl+C to stop.\n", saveInterval)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+
+	poll := time.NewTicker(5 * time.Second)
+	defer poll.Stop()
+	timer := time.NewTicker(saveInterval)
+	defer timer.Stop()
+
+	branch := mgr.CurrentBranch()
+	head := git.Head(mgr.RootPath())
+
+	snapshot := func(reason string) {
+		// Other saves, 'session done' and the MCP server write the session
+		// too, so snapshot what is on disk now rather than what was loaded
+		s, err := mgr.LoadNamed(saveName)
+		if err != nil {
+			output.Errorf("⚠️  Snapshot failed: %v\n", err)
+			return
+		}
+		if s == nil {
+			return
+		}
+		commit, changed := mgr.GitState()
+		if s.Commit == commit && slices.Equal(s.ChangedFiles, changed) {
+			return
+		}
+		if err := mgr.Snapshot(s); err != nil {
+			output.Errorf("⚠️  Snapshot failed: %v\n", err)
+			return
+		}
+		output.Infof("📸 %s snapshot (%s): %d uncommitted file(s)\n", time.Now().Format("15:04:05"), reason, len(s.ChangedFiles))
+	}
+	snapshot("start")
+
+	for {
+		select {
+		case <-sigs:
+			snapshot("exit")
+			output.Info("👋 Stopped watching")
+			return
+		case <-timer.C:
+			snapshot("interval")
+		case <-poll.C:
+			newBranch := mgr.CurrentBranch()
+			newHead := git.Head(mgr.RootPath())
+			if newBranch != branch {
+				// Follow the checkout to that branch's session
+				branch, head = newBranch, newHead
+				if s, _ := mgr.LoadNamed(saveName); s == nil {
+					output.Infof("📋 No session on %s, snapshots paused until you switch back or save one\n", branch)
+					continue
+				}
+				snapshot("checkout")
+			} else if newHead != head {
+				head = newHead
+				snapshot("commit")
+			}
+		}
+	}
+}
+
+// readSessionJSON merges a JSON session from r into s. Fields present in
+// the input replace scalar values and extend lists.
+func readSessionJSON(s *session.Session, r io.Reader) error {
+	var in session.Session
+	if err := json.NewDecoder(r).Decode(&in); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to parse session from stdin: %w", err)
+	}
+
+	for _, f := range []struct {
+		dst *string
+		val string
+	}{
+		{&s.Task, in.Task},
+		{&s.Goal, in.Goal},
+		{&s.State, in.State},
+		{&s.Notes, in.Notes},
+		{&s.Issue, in.Issue},
+		{&s.PR, in.PR},
+	} {
+		if f.val != "" {
+			*f.dst = f.val
+		}
 	}
-	fmt.Println()
-	fmt.Println("💡 Run 'contextpilot resume' to restore this context")
+	s.Approaches = session.AddUnique(s.Approaches, in.Approaches...)
+	s.Decisions = session.AddUnique(s.Decisions, in.Decisions...)
+	s.NextSteps = session.AddUnique(s.NextSteps, in.NextSteps...)
+	return nil
 }
 
 func interactiveSession(s *session.Session) *session.Session {
 	reader := bufio.NewReader(os.Stdin)
 
-	fmt.Println("📝 Save Session Context")
-	fmt.Println("(Press Enter to skip optional fields)")
-	fmt.Println()
+	output.Info("📝 Save Session Context")
+	output.Info("(Press Enter to skip optional fields)")
+	output.Info()
 
 	// Task (required)
 	if s.Task == "" {
-		fmt.Print("Task (what are you working on?): ")
+		output.Infof("Task (what are you working on?): ")
 		s.Task = readLine(reader)
 		if s.Task == "" {
-			fmt.Println("❌ Task is required")
+			output.Errorf("❌ Task is required\n")
 			os.Exit(1)
 		}
 	} else {
-		fmt.Printf("Task [%s]: ", s.Task)
+		output.Infof("Task [%s]: ", s.Task)
 		if input := readLine(reader); input != "" {
 			s.Task = input
 		}
 	}
 
 	// Goal
-	fmt.Print("Goal (why?): ")
+	output.Infof("Goal (why?): ")
 	if input := readLine(reader); input != "" {
 		s.Goal = input
 	}
 
 	// Approaches
-	fmt.Println("Approaches tried (one per line, empty line to finish):")
+	output.Info("Approaches tried (one per line, empty line to finish):")
 	for {
-		fmt.Print("  - ")
+		output.Infof("  - ")
 		input := readLine(reader)
 		if input == "" {
 			break
@@ -143,15 +325,15 @@ func interactiveSession(s *session.Session) *session.Session {
 	}
 
 	// Current state
-	fmt.Print("Current state (where did you leave off?): ")
+	output.Infof("Current state (where did you leave off?): ")
 	if input := readLine(reader); input != "" {
 		s.State = input
 	}
 
 	// Next steps
-	fmt.Println("Next steps (one per line, empty line to finish):")
+	output.Info("Next steps (one per line, empty line to finish):")
 	for {
-		fmt.Print("  - ")
+		output.Infof("  - ")
 		input := readLine(reader)
 		if input == "" {
 			break
@@ -160,7 +342,7 @@ func interactiveSession(s *session.Session) *session.Session {
 	}
 
 	// Notes
-	fmt.Print("Notes (anything else?): ")
+	output.Infof("Notes (anything else?): ")
 	if input := readLine(reader); input != "" {
 		s.Notes = input
 	}
@@ -179,5 +361,14 @@ func init() {
 	saveCmd.Flags().StringVarP(&saveGoal, "goal", "g", "", "Goal/purpose")
 	saveCmd.Flags().StringVarP(&saveState, "state", "s", "", "Current state")
 	saveCmd.Flags().StringVarP(&saveNotes, "notes", "n", "", "Additional notes")
+	saveCmd.Flags().StringVar(&saveName, "name", "", "Session name (default: the branch's default session)")
 	saveCmd.Flags().BoolVarP(&saveQuick, "quick", "q", false, "Quick save (skip interactive)")
+	saveCmd.Flags().StringArrayVar(&saveNext, "next", nil, "Add a next step (repeatable)")
+	saveCmd.Flags().StringArrayVar(&saveApproach, "approach", nil, "Log an approach tried (repeatable)")
+	saveCmd.Flags().StringArrayVar(&saveDecision, "decision", nil, "Record a decision made during the session (repeatable)")
+	saveCmd.Flags().BoolVar(&saveStdin, "stdin", false, "Read the session as JSON from stdin")
+	saveCmd.Flags().StringVar(&saveIssue, "issue", "", "Link an issue (URL or key, e.g. PROJ-123); detected from the branch name if omitted")
+	saveCmd.Flags().StringVar(&savePR, "pr", "", "Link a pull request (URL or number)")
+	saveCmd.Flags().BoolVar(&saveWatch, "watch", false, "Keep running and snapshot the session on an interval and on git events")
+	saveCmd.Flags().DurationVar(&saveInterval, "interval", 10*time.Minute, "Snapshot interval for --watch")
 }
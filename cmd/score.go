@@ -4,14 +4,30 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
-	"github.com/jitin-nhz/contextpilot/internal/analyzer"
-	"github.com/jitin-nhz/contextpilot/internal/decisions"
+	"github.com/jitin-nhz/contextpilot/internal/badge"
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/drift"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/specificity"
+	"github.com/jitin-nhz/contextpilot/internal/suggest"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+var (
+	scoreBadge    bool
+	scoreBadgeDir string
+	scoreFile     string
+)
+
 var scoreCmd = &cobra.Command{
 	Use:   "score",
 	Short: "Check your context quality score",
@@ -19,43 +35,139 @@ var scoreCmd = &cobra.Command{
 
 Scores based on:
   - Completeness (tech stack, conventions, decisions)
-  - Freshness (how recently updated vs code changes)
+  - Freshness (commits touching code since the last sync; days since
+    the last sync outside git)
   - Specificity (generic vs project-specific content)
 
-Provides actionable suggestions for improvement.`,
-	Run: runScore,
+Specificity is scored per context file: mentioning the project's own
+dependencies, folders and commands raises it, stock phrases that fit any
+project ("follow existing patterns") lower it.
+
+Provides actionable suggestions for improvement.
+
+Teams can reweight the categories and add their own rules under score:
+in .contextpilot/config.yaml:
+
+  score:
+    weights:
+      completeness: 30
+      freshness: 20
+      decisions: 30
+      specificity: 20
+    rules:
+      - name: Ten decisions
+        minDecisions: 10
+        points: 30
+      - file: CLAUDE.md
+        contains: migration
+
+A rule earns its points (10 by default) when all of its conditions hold:
+minDecisions, file (must exist), contains (case-insensitive) and matches
+(a regular expression). The total is scaled back to 100.
+
+--file scores one context file on its own instead, out of 100: its size
+against the 2000-token budget, which sections it covers (stack,
+commands, structure, conventions, decisions), its specificity, and
+whether the versions it mentions still match what the lockfile
+(pnpm-lock.yaml, package-lock.json, yarn.lock or go.mod) resolved.
+Suggestions point at the lines they are about. It works on any file,
+generated or written by hand, and doesn't need 'contextpilot init'.
+
+With --badge, the score is also written as a README badge: badge.svg and
+a shields.io endpoint file, badge.json, in .contextpilot/ (or --badge-dir).
+Commit them and show the badge with either of:
+
+  ![Context](.contextpilot/badge.svg)
+  ![Context](https://img.shields.io/endpoint?url=<raw URL of badge.json>)
+
+Examples:
+  contextpilot score
+  contextpilot score --badge
+  contextpilot score --file CLAUDE.md`,
+	Annotations: jsonCapable,
+	Run:         runScore,
 }
 
 type scoreResult struct {
 	total       int
-	completeness int
-	freshness   int
-	decisions   int
+	categories  []scoreCategory
+	specificity []specificity.Report
+	unsynced    int // commits touching code since the last sync, -1 if unknown
+	drift       []drift.Item
 	issues      []string
 	suggestions []string
 }
 
+// scoreCategory is one row of the breakdown: a built-in category scaled
+// to its configured weight, or a custom rule from config.yaml
+type scoreCategory struct {
+	name  string
+	score int
+	max   int
+	rule  bool
+}
+
 func runScore(cmd *cobra.Command, args []string) {
-	cwd, err := os.Getwd()
+	cwd, err := projectDir()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		output.Errorf("❌ Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	if scoreFile != "" {
+		if scoreBadge {
+			output.Errorf("❌ --badge shows the project's score; it can't be combined with --file\n")
+			os.Exit(1)
+		}
+		runScoreFile(cwd)
+		return
+	}
+
 	configPath := filepath.Join(cwd, ".contextpilot", "config.yaml")
 
 	// Check if initialized
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		fmt.Println("📊 Context Quality Score: N/A")
-		fmt.Println()
-		fmt.Println("❌ No context files found")
-		fmt.Println()
-		fmt.Println("Run 'contextpilot init' to generate context files.")
+		if scoreBadge {
+			output.Errorf("❌ No score to put on a badge: run 'contextpilot init' first\n")
+			os.Exit(1)
+		}
+		if output.IsJSON() {
+			printJSON(map[string]interface{}{"initialized": false, "score": nil, "max": 100})
+			return
+		}
+		output.Println("📊 Context Quality Score: N/A")
+		output.Info()
+		output.Info("❌ No context files found")
+		output.Info()
+		output.Info("Run 'contextpilot init' to generate context files.")
 		os.Exit(0)
 	}
 
 	result := calculateScore(cwd)
 
+	var svgPath, endpointPath string
+	if scoreBadge {
+		dir := scoreBadgeDir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(cwd, dir)
+		}
+		svgPath, endpointPath, err = badge.Write(dir, result.total)
+		if err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		svgPath, endpointPath = filepath.ToSlash(displayPath(cwd, svgPath)), filepath.ToSlash(displayPath(cwd, endpointPath))
+	}
+
+	if output.IsJSON() {
+		doc := scoreDoc(result)
+		if scoreBadge {
+			doc["badge"] = map[string]string{"svg": svgPath, "endpoint": endpointPath}
+		}
+		printJSON(doc)
+		return
+	}
+
 	// Display score
 	emoji := "🟢"
 	if result.total < 50 {
@@ -64,47 +176,109 @@ func runScore(cmd *cobra.Command, args []string) {
 		emoji = "🟡"
 	}
 
-	fmt.Printf("📊 Context Quality Score: %s %d/100\n", emoji, result.total)
-	fmt.Println()
+	output.Printf("📊 Context Quality Score: %s %d/100\n", emoji, result.total)
+	output.Println()
 
 	// Breakdown
-	fmt.Println("┌────────────────────┬───────┬─────────────────────────────────┐")
-	fmt.Println("│ Category           │ Score │ Status                          │")
-	fmt.Println("├────────────────────┼───────┼─────────────────────────────────┤")
-	fmt.Printf("│ Completeness       │ %2d/40 │ %-31s │\n", result.completeness, getStatus(result.completeness, 40))
-	fmt.Printf("│ Freshness          │ %2d/30 │ %-31s │\n", result.freshness, getStatus(result.freshness, 30))
-	fmt.Printf("│ Decisions          │ %2d/30 │ %-31s │\n", result.decisions, getStatus(result.decisions, 30))
-	fmt.Println("└────────────────────┴───────┴─────────────────────────────────┘")
-	fmt.Println()
+	output.Println("┌────────────────────┬───────┬─────────────────────────────────┐")
+	output.Println("│ Category           │ Score │ Status                          │")
+	output.Println("├────────────────────┼───────┼─────────────────────────────────┤")
+	for _, c := range result.categories {
+		name := c.name
+		if len([]rune(name)) > 18 {
+			name = string([]rune(name)[:17]) + "…"
+		}
+		output.Printf("│ %-18s │ %5s │ %-31s │\n", name, fmt.Sprintf("%d/%d", c.score, c.max), getStatus(c.score, c.max))
+	}
+	output.Println("└────────────────────┴───────┴─────────────────────────────────┘")
+	output.Println()
+
+	if result.unsynced >= 0 {
+		output.Printf("🔀 Unsynced commits: %d\n", result.unsynced)
+		output.Println()
+	}
+
+	// Per-file specificity
+	if len(result.specificity) > 0 {
+		output.Println("🎯 Specificity:")
+		for _, r := range result.specificity {
+			output.Printf("   • %s: %d%% (%d project terms", r.Path, r.Score, len(r.Terms))
+			if len(r.Boilerplate) > 0 {
+				output.Printf(", %d generic phrases", len(r.Boilerplate))
+			}
+			output.Println(")")
+		}
+		output.Println()
+	}
 
 	// Issues
 	if len(result.issues) > 0 {
-		fmt.Println("⚠️  Issues:")
+		output.Println("⚠️  Issues:")
 		for _, issue := range result.issues {
-			fmt.Printf("   • %s\n", issue)
+			output.Printf("   • %s\n", issue)
 		}
-		fmt.Println()
+		output.Println()
 	}
 
 	// Suggestions
 	if len(result.suggestions) > 0 {
-		fmt.Println("💡 Suggestions:")
+		output.Println("💡 Suggestions:")
 		for _, sug := range result.suggestions {
-			fmt.Printf("   • %s\n", sug)
+			output.Printf("   • %s\n", sug)
 		}
-		fmt.Println()
+		output.Println()
+	}
+
+	if scoreBadge {
+		output.Printf("🏷️  Badge: %s, %s\n", svgPath, endpointPath)
+		output.Info()
+		output.Info("💡 Add it to your README:")
+		output.Infof("   ![Context](%s)\n", svgPath)
 	}
 
 	if result.total >= 75 {
-		fmt.Println("🎉 Great job! Your context files are in good shape.")
+		output.Info("🎉 Great job! Your context files are in good shape.")
+	}
+}
+
+// scoreDoc is the JSON form of a score, shared by 'score --json' and the
+// API server
+func scoreDoc(result scoreResult) map[string]interface{} {
+	breakdown := map[string]interface{}{}
+	rules := []map[string]interface{}{}
+	for _, c := range result.categories {
+		if c.rule {
+			rules = append(rules, map[string]interface{}{"name": c.name, "score": c.score, "max": c.max, "passed": c.score == c.max})
+			continue
+		}
+		breakdown[strings.ToLower(c.name)] = map[string]int{"score": c.score, "max": c.max}
+	}
+	doc := map[string]interface{}{
+		"initialized": true,
+		"score":       result.total,
+		"max":         100,
+		"breakdown":   breakdown,
+		"rules":       rules,
+		"specificity": result.specificity,
+		"issues":      result.issues,
+		"suggestions": result.suggestions,
 	}
+	if result.unsynced >= 0 {
+		doc["unsyncedCommits"] = result.unsynced
+	}
+	if result.drift != nil {
+		doc["drift"] = result.drift
+	}
+	return doc
 }
 
 func calculateScore(cwd string) scoreResult {
 	result := scoreResult{
+		unsynced:    -1,
 		issues:      []string{},
 		suggestions: []string{},
 	}
+	var completeness, freshness, decisionPoints int
 
 	// Check file existence (completeness)
 	files := []struct {
@@ -120,7 +294,7 @@ func calculateScore(cwd string) scoreResult {
 
 	for _, f := range files {
 		if _, err := os.Stat(filepath.Join(cwd, f.path)); err == nil {
-			result.completeness += f.points
+			completeness += f.points
 		} else {
 			result.issues = append(result.issues, fmt.Sprintf("Missing: %s", f.name))
 		}
@@ -137,6 +311,39 @@ func calculateScore(cwd string) scoreResult {
 		}
 	}
 
+	// Check for drift since the last sync
+	if err == nil {
+		if d, err := drift.Detect(cwd, analysis, generator.Outputs(cwd)); err == nil {
+			result.drift = d.Items
+			for _, item := range d.Items {
+				result.issues = append(result.issues, "Drift: "+item.Message)
+			}
+		}
+	}
+
+	// Check specificity of each context file
+	var terms []string
+	if err == nil {
+		terms = specificity.Terms(analysis)
+	}
+	specificityTotal := 0
+	for _, path := range generator.Outputs(cwd) {
+		data, err := os.ReadFile(filepath.Join(cwd, path))
+		if err != nil {
+			continue
+		}
+		r := specificity.Analyze(path, string(data), terms)
+		result.specificity = append(result.specificity, r)
+		specificityTotal += r.Score
+		if r.Score < 60 {
+			result.suggestions = append(result.suggestions, specificitySuggestion(r))
+		}
+	}
+	specificityPoints := 0
+	if len(result.specificity) > 0 {
+		specificityPoints = specificityTotal / len(result.specificity)
+	}
+
 	// Check freshness
 	configPath := filepath.Join(cwd, ".contextpilot", "config.yaml")
 	if data, err := os.ReadFile(configPath); err == nil {
@@ -144,17 +351,11 @@ func calculateScore(cwd string) scoreResult {
 			LastSync time.Time `yaml:"lastSync"`
 		}
 		if yaml.Unmarshal(data, &cfg) == nil && !cfg.LastSync.IsZero() {
-			daysSinceSync := int(time.Since(cfg.LastSync).Hours() / 24)
-			if daysSinceSync == 0 {
-				result.freshness = 30 // Synced today
-			} else if daysSinceSync <= 7 {
-				result.freshness = 25 // Synced this week
-			} else if daysSinceSync <= 30 {
-				result.freshness = 15 // Synced this month
-				result.suggestions = append(result.suggestions, "Run 'contextpilot sync' — last sync was over a week ago")
+			if n, err := unsyncedCommits(cwd, cfg.LastSync); err == nil {
+				result.unsynced = n
+				freshness = commitFreshness(n, &result)
 			} else {
-				result.freshness = 5 // Stale
-				result.issues = append(result.issues, fmt.Sprintf("Context files stale (%d days since sync)", daysSinceSync))
+				freshness = ageFreshness(cfg.LastSync, &result)
 			}
 		}
 	}
@@ -165,21 +366,165 @@ func calculateScore(cwd string) scoreResult {
 	decCount := len(decs)
 
 	if decCount == 0 {
-		result.decisions = 5
+		decisionPoints = 5
 		result.suggestions = append(result.suggestions, "Add architectural decisions with 'contextpilot decision \"...\"'")
 	} else if decCount < 3 {
-		result.decisions = 15
+		decisionPoints = 15
 		result.suggestions = append(result.suggestions, fmt.Sprintf("Add more decisions (currently %d, aim for 5+)", decCount))
 	} else if decCount < 5 {
-		result.decisions = 22
+		decisionPoints = 22
 	} else {
-		result.decisions = 30 // 5+ decisions is great
+		decisionPoints = 30 // 5+ decisions is great
+	}
+
+	// Busy areas without decisions cost 5 points each, up to 10
+	if git.IsRepo(cwd) {
+		hotspots, _ := suggest.Hotspots(cwd, suggest.DefaultDays, suggest.DefaultMinCommits, decs)
+		for i, h := range hotspots {
+			if i < 2 {
+				decisionPoints -= 5
+			}
+			if i < 3 {
+				result.suggestions = append(result.suggestions, h.String(suggest.DefaultDays))
+			}
+		}
+		if decisionPoints < 0 {
+			decisionPoints = 0
+		}
+		if len(hotspots) > 0 {
+			result.suggestions = append(result.suggestions, "Run 'contextpilot suggest' to see areas worth a decision")
+		}
+	}
+
+	// Built-in categories are scored out of 40/30/30/100 and scaled to the
+	// configured weights; custom rules add their own points
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	wCompleteness, wFreshness, wDecisions, wSpecificity := cfg.Score.Weights.Resolve()
+	for _, c := range []struct {
+		name          string
+		raw, of, into int
+	}{
+		{"Completeness", completeness, 40, wCompleteness},
+		{"Freshness", freshness, 30, wFreshness},
+		{"Decisions", decisionPoints, 30, wDecisions},
+		{"Specificity", specificityPoints, 100, wSpecificity},
+	} {
+		if c.into > 0 {
+			result.categories = append(result.categories, scoreCategory{name: c.name, score: scaled(c.raw, c.into, c.of), max: c.into})
+		}
+	}
+
+	active := 0
+	for _, d := range decs {
+		if d.Active() {
+			active++
+		}
+	}
+	for _, rule := range cfg.Score.Rules {
+		c := scoreCategory{name: rule.Label(), max: rule.MaxPoints(), rule: true}
+		if err := rule.Validate(); err != nil {
+			result.issues = append(result.issues, err.Error())
+		} else if reason := checkScoreRule(cwd, rule, active); reason != "" {
+			result.issues = append(result.issues, fmt.Sprintf("Rule %q failed: %s", rule.Label(), reason))
+		} else {
+			c.score = c.max
+		}
+		result.categories = append(result.categories, c)
 	}
 
-	result.total = result.completeness + result.freshness + result.decisions
+	earned, max := 0, 0
+	for _, c := range result.categories {
+		earned += c.score
+		max += c.max
+	}
+	if max > 0 {
+		result.total = scaled(earned, 100, max)
+	}
 	return result
 }
 
+// commitFreshness scores freshness out of 30 by how many commits have
+// touched code since the last sync, so an idle repo stays fresh
+func commitFreshness(unsynced int, result *scoreResult) int {
+	switch {
+	case unsynced == 0:
+		return 30
+	case unsynced <= 5:
+		return 25
+	case unsynced <= 20:
+		result.suggestions = append(result.suggestions, fmt.Sprintf("Run 'contextpilot sync' — %d commits since last sync", unsynced))
+		return 15
+	}
+	result.issues = append(result.issues, fmt.Sprintf("Context files stale (%d commits since last sync)", unsynced))
+	return 5
+}
+
+// ageFreshness scores freshness out of 30 by days since the last sync,
+// for projects outside git
+func ageFreshness(lastSync time.Time, result *scoreResult) int {
+	daysSinceSync := int(time.Since(lastSync).Hours() / 24)
+	switch {
+	case daysSinceSync == 0:
+		return 30 // Synced today
+	case daysSinceSync <= 7:
+		return 25 // Synced this week
+	case daysSinceSync <= 30:
+		result.suggestions = append(result.suggestions, "Run 'contextpilot sync' — last sync was over a week ago")
+		return 15 // Synced this month
+	}
+	result.issues = append(result.issues, fmt.Sprintf("Context files stale (%d days since sync)", daysSinceSync))
+	return 5 // Stale
+}
+
+// checkScoreRule returns why rule fails, or "" if it passes
+func checkScoreRule(cwd string, rule config.ScoreRule, activeDecisions int) string {
+	if activeDecisions < rule.MinDecisions {
+		return fmt.Sprintf("%d decision(s), need %d", activeDecisions, rule.MinDecisions)
+	}
+	if rule.File == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(cwd, rule.File))
+	if err != nil {
+		return rule.File + " not found"
+	}
+	if rule.Contains != "" && !strings.Contains(strings.ToLower(string(data)), strings.ToLower(rule.Contains)) {
+		return fmt.Sprintf("%s doesn't mention %q", rule.File, rule.Contains)
+	}
+	if rule.Matches != "" && !regexp.MustCompile(rule.Matches).Match(data) {
+		return fmt.Sprintf("%s doesn't match %s", rule.File, rule.Matches)
+	}
+	return ""
+}
+
+// specificitySuggestion says what would make a generic context file more
+// specific: dropping stock phrases and naming a few of the project's terms
+func specificitySuggestion(r specificity.Report) string {
+	var fixes []string
+	if len(r.Boilerplate) > 0 {
+		fixes = append(fixes, fmt.Sprintf("replace generic phrases like %q", r.Boilerplate[0]))
+	}
+	if len(r.Missing) > 0 {
+		missing := r.Missing
+		if len(missing) > 3 {
+			missing = missing[:3]
+		}
+		fixes = append(fixes, "mention "+strings.Join(missing, ", "))
+	}
+	if len(fixes) == 0 {
+		return fmt.Sprintf("Make %s more project-specific", r.Path)
+	}
+	return fmt.Sprintf("Make %s more project-specific: %s", r.Path, strings.Join(fixes, "; "))
+}
+
+// scaled converts n out of of into points out of into, rounding to nearest
+func scaled(n, into, of int) int {
+	return (n*into + of/2) / of
+}
+
 func getStatus(score, max int) string {
 	pct := float64(score) / float64(max) * 100
 	if pct >= 80 {
@@ -194,4 +539,7 @@ func getStatus(score, max int) string {
 
 func init() {
 	rootCmd.AddCommand(scoreCmd)
+	scoreCmd.Flags().BoolVar(&scoreBadge, "badge", false, "Write the score as an SVG badge and a shields.io endpoint file")
+	scoreCmd.Flags().StringVar(&scoreBadgeDir, "badge-dir", ".contextpilot", "Directory for badge.svg and badge.json")
+	scoreCmd.Flags().StringVar(&scoreFile, "file", "", "Score one context file, with line-level suggestions")
 }
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/filescore"
+	"github.com/jitin-nhz/contextpilot/internal/lockfile"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// runScoreFile scores the one context file --file names
+func runScoreFile(cwd string) {
+	path := scoreFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		output.Errorf("❌ Error reading %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	project := filescore.Project{}
+	if analysis, err := analyzer.New(cwd).Analyze(); err == nil {
+		project.Analysis = analysis
+	}
+	project.Resolved, project.Lockfile, err = lockfile.Read(cwd)
+	if err != nil {
+		output.Errorf("⚠️  Versions not checked: error reading %s: %v\n", project.Lockfile, err)
+		project.Resolved, project.Lockfile = nil, ""
+	}
+
+	name := filepath.ToSlash(path)
+	if abs, err := filepath.Abs(path); err == nil {
+		if rel, err := filepath.Rel(cwd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			name = filepath.ToSlash(rel)
+		}
+	}
+	r := filescore.Score(name, string(data), project)
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{"file": r})
+		return
+	}
+
+	emoji := "🟢"
+	if r.Score < 50 {
+		emoji = "🔴"
+	} else if r.Score < 75 {
+		emoji = "🟡"
+	}
+	output.Printf("📄 %s: %s %d/100\n", r.Path, emoji, r.Score)
+	output.Println()
+	output.Printf("   %-12s %d (budget %d)\n", "Tokens", r.Tokens, r.Budget)
+	sections := fmt.Sprintf("%d/%d", len(r.Sections), len(filescore.Sections))
+	if len(r.MissingSections) > 0 {
+		sections += " (missing " + strings.Join(r.MissingSections, ", ") + ")"
+	}
+	output.Printf("   %-12s %s\n", "Sections", sections)
+	specific := fmt.Sprintf("%d%% (%d project terms", r.Specificity.Score, len(r.Specificity.Terms))
+	if n := len(r.Specificity.Boilerplate); n > 0 {
+		specific += fmt.Sprintf(", %d generic phrases", n)
+	}
+	output.Printf("   %-12s %s)\n", "Specificity", specific)
+	switch {
+	case r.Lockfile == "":
+		output.Printf("   %-12s not checked (no lockfile)\n", "Versions")
+	case len(r.Stale) == 0:
+		output.Printf("   %-12s up to date with %s\n", "Versions", r.Lockfile)
+	default:
+		output.Printf("   %-12s %d out of date with %s\n", "Versions", len(r.Stale), r.Lockfile)
+	}
+
+	if len(r.Suggestions) > 0 {
+		output.Println()
+		output.Println("💡 Suggestions:")
+		for _, s := range r.Suggestions {
+			if s.Line > 0 {
+				output.Printf("   %s:%d: %s\n", r.Path, s.Line, s.Message)
+			} else {
+				output.Printf("   %s: %s\n", r.Path, s.Message)
+			}
+		}
+	}
+}
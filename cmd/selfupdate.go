@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	selfUpdateChannel string
+	selfUpdateCheck   bool
+)
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update contextpilot to the latest release",
+	Long: `Download the latest release for this platform and replace the
+running binary with it. The release's checksums must carry a valid
+signature from the ContextPilot release key, and the archive must match
+its checksum, or nothing is replaced.
+
+--channel picks stable releases (the default) or beta, which includes
+pre-releases; set "updates: {channel: beta}" in the user config to make
+it stick. Installs managed by Homebrew, Scoop, npm or go install are
+left to their package manager, and self-update prints the command to
+run instead.
+
+With "updates: {notify: true}" in the user config, commands also mention
+a newer release on stderr, checking at most once a day. The notice is
+never shown in CI, with --json or --quiet, in offline mode, or with
+CONTEXTPILOT_NO_UPDATE_NOTICE=1.
+
+Examples:
+  contextpilot self-update
+  contextpilot self-update --check
+  contextpilot self-update --channel beta`,
+	Args:        cobra.NoArgs,
+	Annotations: jsonCapable,
+	Run:         runSelfUpdate,
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) {
+	channel := selfUpdateChannel
+	if !cmd.Flags().Changed("channel") {
+		if user, err := config.LoadUser(); err == nil {
+			channel = user.UpdateChannel()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+	defer cancel()
+	latest, err := selfupdate.Latest(ctx, channel)
+	if err != nil {
+		output.Errorf("❌ Error looking for a release: %v\n", err)
+		os.Exit(1)
+	}
+	available := selfupdate.Newer(latest.Version, Version)
+
+	result := map[string]interface{}{
+		"current":   Version,
+		"latest":    latest.Version,
+		"channel":   channel,
+		"available": available,
+		"updated":   false,
+	}
+	if !available || selfUpdateCheck {
+		if output.IsJSON() {
+			printJSON(result)
+			return
+		}
+		if !available {
+			output.Printf("✅ ContextPilot %s is up to date (%s channel)\n", Version, channel)
+			return
+		}
+		output.Printf("⬆️  ContextPilot %s is available (%s channel, you have %s)\n", latest.Version, channel, Version)
+		output.Info("💡 Run 'contextpilot self-update' to install it")
+		return
+	}
+
+	exe, err := selfupdate.Executable()
+	if err != nil {
+		output.Errorf("❌ Error finding the contextpilot binary: %v\n", err)
+		os.Exit(1)
+	}
+	if method := selfupdate.DetectMethod(exe); method.Managed() {
+		output.Errorf("❌ contextpilot was installed with %s; update it with: %s\n", method.Name, method.Upgrade)
+		os.Exit(1)
+	}
+
+	spinner := output.StartSpinner("⬇️  Downloading " + latest.Version + "...")
+	path, err := selfupdate.Install(ctx, latest)
+	spinner.Stop()
+	if err != nil {
+		output.Errorf("❌ Error updating: %v\n", err)
+		os.Exit(1)
+	}
+
+	if output.IsJSON() {
+		result["updated"] = true
+		result["path"] = path
+		printJSON(result)
+		return
+	}
+	output.Printf("✅ Updated ContextPilot %s → %s (%s)\n", Version, latest.Version, path)
+}
+
+// updateNotice mentions a newer release on stderr, looking at most once a
+// day, for users who turned it on. It stays out of the way of scripts and
+// servers even then.
+func updateNotice(cmd *cobra.Command) {
+	switch cmd {
+	case selfUpdateCmd, mcpCmd, serveCmd:
+		return
+	}
+	if output.IsJSON() || quietOutput || noInput || os.Getenv(selfupdate.NoticeEnv) != "" ||
+		strings.HasSuffix(Version, "-dev") || !output.IsTerminal(os.Stderr) || config.Offline() {
+		return
+	}
+	user, err := config.LoadUser()
+	if err != nil || !user.Updates.Notify {
+		return
+	}
+	latest := selfupdate.Available(Version, user.UpdateChannel())
+	if latest == "" {
+		return
+	}
+	upgrade := "contextpilot self-update"
+	if exe, err := selfupdate.Executable(); err == nil {
+		if method := selfupdate.DetectMethod(exe); method.Managed() {
+			upgrade = method.Upgrade
+		}
+	}
+	output.Info()
+	output.Infof("⬆️  ContextPilot %s is available (you have %s): run '%s'\n", latest, Version, upgrade)
+}
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+	selfUpdateCmd.Flags().StringVar(&selfUpdateChannel, "channel", "stable", "Release channel: stable or beta")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateCheck, "check", false, "Only report whether a newer release is available")
+}
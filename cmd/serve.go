@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/api"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+	"github.com/spf13/cobra"
+)
+
+// apiTokenEnv holds the bearer token that unlocks the write endpoints
+const apiTokenEnv = "CONTEXTPILOT_API_TOKEN"
+
+var (
+	servePort int
+	serveHost string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve project context over an HTTP API",
+	Long: `Serve the project's analysis, score, decisions and current session
+as JSON over HTTP, for dashboards and bots that shouldn't parse CLI output.
+
+Read endpoints:
+  GET  /analysis          Detected stack, structure and conventions
+  GET  /score             Context quality score (as 'score --json')
+  GET  /decisions         Logged decisions
+  GET  /sessions/current  Session of the checked-out branch (?name= for a named one)
+
+Write endpoints, which need "Authorization: Bearer <token>" matching
+$CONTEXTPILOT_API_TOKEN and are disabled when it isn't set:
+  POST /decisions         Log a decision: {"text": "...", "context": "...",
+                          "files": ["src/auth/*"], "tags": ["security"]}
+
+Every response carries "apiVersion" (see --api-version). The server binds
+to localhost unless --host says otherwise.
+
+Examples:
+  contextpilot serve
+  CONTEXTPILOT_API_TOKEN=secret contextpilot serve --port 9000 --host 0.0.0.0`,
+	Args: cobra.NoArgs,
+	Run:  runServe,
+}
+
+func runServe(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	addr := net.JoinHostPort(serveHost, strconv.Itoa(servePort))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		output.Errorf("❌ Failed to listen on %s: %v\n", addr, err)
+		os.Exit(1)
+	}
+
+	srv := &apiServer{root: cwd, token: os.Getenv(apiTokenEnv)}
+	output.Printf("🌐 Serving %s on http://%s\n", filepath.Base(cwd), ln.Addr())
+	if srv.token == "" {
+		output.Infof("🔒 Write endpoints are disabled: set %s to enable them\n", apiTokenEnv)
+	}
+	output.Info("   Press Ctrl+C to stop")
+
+	server := &http.Server{Handler: srv.routes(), ReadHeaderTimeout: 10 * time.Second}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		output.Errorf("❌ Server error: %v\n", err)
+		os.Exit(1)
+	}
+	output.Info("👋 Server stopped")
+}
+
+// apiServer answers API requests for the project at root. Requests are
+// handled one at a time, as the CLI would run them.
+type apiServer struct {
+	root  string
+	token string
+	mu    sync.Mutex
+}
+
+func (s *apiServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/analysis", s.handle(http.MethodGet, s.analysis))
+	mux.HandleFunc("/score", s.handle(http.MethodGet, s.score))
+	mux.HandleFunc("/sessions/current", s.handle(http.MethodGet, s.currentSession))
+	mux.HandleFunc("/decisions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			s.handle(http.MethodPost, s.addDecision)(w, r)
+			return
+		}
+		s.handle(http.MethodGet, s.decisions)(w, r)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPI(w, http.StatusNotFound, map[string]interface{}{"error": "no such endpoint: " + r.URL.Path})
+	})
+	return mux
+}
+
+// apiError is an error with the HTTP status to answer it with
+type apiError struct {
+	status int
+	msg    string
+}
+
+func (e *apiError) Error() string { return e.msg }
+
+// handle wraps an endpoint: it checks the method, authenticates writes,
+// serializes access to the project and encodes the result or error
+func (s *apiServer) handle(method string, fn func(r *http.Request) (int, map[string]interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeAPI(w, http.StatusMethodNotAllowed, map[string]interface{}{"error": "method not allowed"})
+			return
+		}
+		if method != http.MethodGet {
+			if err := s.authorize(r); err != nil {
+				writeAPI(w, err.status, map[string]interface{}{"error": err.msg})
+				return
+			}
+		}
+
+		s.mu.Lock()
+		status, doc, err := fn(r)
+		s.mu.Unlock()
+		if err != nil {
+			status = http.StatusInternalServerError
+			var ae *apiError
+			if errors.As(err, &ae) {
+				status = ae.status
+			}
+			doc = map[string]interface{}{"error": err.Error()}
+		}
+		writeAPI(w, status, doc)
+	}
+}
+
+func (s *apiServer) authorize(r *http.Request) *apiError {
+	if s.token == "" {
+		return &apiError{http.StatusForbidden, "write endpoints are disabled: start the server with " + apiTokenEnv + " set"}
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) != 1 {
+		return &apiError{http.StatusUnauthorized, "missing or invalid bearer token"}
+	}
+	return nil
+}
+
+func (s *apiServer) analysis(r *http.Request) (int, map[string]interface{}, error) {
+	analysis, err := analyzer.New(s.root).Analyze()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to analyze project: %w", err)
+	}
+	return http.StatusOK, map[string]interface{}{"analysis": analysis}, nil
+}
+
+func (s *apiServer) score(r *http.Request) (int, map[string]interface{}, error) {
+	if _, err := os.Stat(filepath.Join(s.root, ".contextpilot", "config.yaml")); os.IsNotExist(err) {
+		return http.StatusOK, map[string]interface{}{"initialized": false, "score": nil, "max": 100}, nil
+	}
+	return http.StatusOK, scoreDoc(calculateScore(s.root)), nil
+}
+
+func (s *apiServer) decisions(r *http.Request) (int, map[string]interface{}, error) {
+	decs, err := decisions.New(s.root).List()
+	if err != nil {
+		return 0, nil, err
+	}
+	if decs == nil {
+		decs = []decisions.Decision{}
+	}
+	return http.StatusOK, map[string]interface{}{"decisions": decs}, nil
+}
+
+func (s *apiServer) addDecision(r *http.Request) (int, map[string]interface{}, error) {
+	var body struct {
+		Text    string   `json:"text"`
+		Context string   `json:"context"`
+		Files   []string `json:"files"`
+		Tags    []string `json:"tags"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&body); err != nil {
+		return 0, nil, &apiError{http.StatusBadRequest, "invalid JSON body: " + err.Error()}
+	}
+	if strings.TrimSpace(body.Text) == "" {
+		return 0, nil, &apiError{http.StatusBadRequest, "text is required"}
+	}
+
+	dec, err := decisions.New(s.root).AddDecision(decisions.Decision{
+		Text:    body.Text,
+		Context: body.Context,
+		Files:   body.Files,
+		Tags:    body.Tags,
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to log decision: %w", err)
+	}
+	return http.StatusCreated, map[string]interface{}{"decision": dec}, nil
+}
+
+func (s *apiServer) currentSession(r *http.Request) (int, map[string]interface{}, error) {
+	mgr := session.New(s.root)
+	sess, err := mgr.LoadNamed(r.URL.Query().Get("name"))
+	if err != nil {
+		return 0, nil, err
+	}
+	if sess == nil {
+		return 0, nil, &apiError{http.StatusNotFound, "no saved session for branch " + mgr.CurrentBranch()}
+	}
+	return http.StatusOK, map[string]interface{}{"branch": mgr.CurrentBranch(), "session": sess}, nil
+}
+
+// writeAPI sends doc, stamped with the API version, as the response
+func writeAPI(w http.ResponseWriter, status int, doc map[string]interface{}) {
+	doc["apiVersion"] = api.Selected()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(doc)
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().IntVarP(&servePort, "port", "p", 8080, "Port to listen on")
+	serveCmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "Address to bind; 0.0.0.0 exposes the API to your network")
+}
@@ -0,0 +1,549 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/changelog"
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/redact"
+	"github.com/jitin-nhz/contextpilot/internal/remote"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionsAllBranches bool
+	pruneMaxEntries     int
+	pruneMaxAge         string
+	pruneDryRun         bool
+	gcHistory           bool
+	gcArchive           bool
+	gcDryRun            bool
+	gcBase              string
+	gcToDecision        bool
+	gcToChangelog       bool
+	doneName            string
+	remoteProject       string
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:     "sessions",
+	Aliases: []string{"session"},
+	Short:   "Manage saved work sessions",
+	Long: `Inspect the work sessions saved for your current git branch, or
+find sessions across every branch.
+
+Examples:
+  contextpilot sessions list
+  contextpilot sessions list --all-branches
+  contextpilot sessions search "payment"
+  contextpilot sessions prune --dry-run
+  contextpilot sessions gc
+  contextpilot sessions push
+  contextpilot session done 1`,
+}
+
+var sessionsDoneCmd = &cobra.Command{
+	Use:   "done [n]",
+	Short: "Check off a next step of the current session",
+	Long: `Mark next step number n (as shown by 'contextpilot session done'
+without arguments) as completed. Completed steps stay in the session and
+show up checked in the resume prompt.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runSessionsDone,
+}
+
+var sessionsListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List sessions saved for the current branch",
+	Annotations: jsonCapable,
+	Run:         runSessionsList,
+}
+
+var sessionsSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search sessions on all branches, including history",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runSessionsSearch,
+}
+
+var sessionsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Compact session history using the retention policy",
+	Long: `Remove old entries from .contextpilot/sessions/history.jsonl.
+
+Limits come from the history section of .contextpilot/config.yaml:
+
+  history:
+    maxEntries: 1000   # keep at most this many snapshots
+    maxAge: 180d       # drop snapshots older than this (d, w, or Go units)
+
+Flags override the configured values for a single run.`,
+	Run: runSessionsPrune,
+}
+
+func runSessionsList(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	mgr := session.New(cwd)
+
+	if sessionsAllBranches {
+		sessions, err := mgr.ListAll()
+		if err != nil {
+			output.Errorf("❌ Error listing sessions: %v\n", err)
+			os.Exit(1)
+		}
+		if output.IsJSON() {
+			if sessions == nil {
+				sessions = []session.Session{}
+			}
+			printJSON(map[string]interface{}{"sessions": sessions})
+			return
+		}
+		if len(sessions) == 0 {
+			output.Println("📋 No saved sessions")
+			return
+		}
+		output.Println("📋 Sessions on all branches")
+		output.Println()
+		printSessionRows(sessions)
+		return
+	}
+
+	sessions, err := mgr.List()
+	if err != nil {
+		output.Errorf("❌ Error listing sessions: %v\n", err)
+		os.Exit(1)
+	}
+
+	if output.IsJSON() {
+		if sessions == nil {
+			sessions = []session.Session{}
+		}
+		printJSON(map[string]interface{}{"branch": mgr.CurrentBranch(), "sessions": sessions})
+		return
+	}
+
+	if len(sessions) == 0 {
+		output.Println("📋 No saved sessions for this branch")
+		output.Info()
+		output.Info("Save one with: contextpilot save \"Your task description\"")
+		return
+	}
+
+	output.Printf("📋 Sessions on %s\n", sessions[0].Branch)
+	output.Println()
+	for _, s := range sessions {
+		output.Printf("   • %-20s %s (%s)\n", s.DisplayName(), s.Task, formatAge(s.UpdatedAt))
+	}
+	output.Info()
+	output.Info("💡 Resume one with: contextpilot resume --name <name>")
+}
+
+func runSessionsSearch(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	query := strings.Join(args, " ")
+	matches, err := session.New(cwd).Search(query)
+	if err != nil {
+		output.Errorf("❌ Error searching sessions: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(matches) == 0 {
+		output.Printf("🔍 No sessions matching \"%s\"\n", query)
+		return
+	}
+
+	output.Printf("🔍 %d session(s) matching \"%s\"\n", len(matches), query)
+	output.Println()
+	printSessionRows(matches)
+}
+
+func runSessionsPrune(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	mgr := session.New(cwd)
+	maxEntries, maxAge := mgr.Retention()
+	if cmd.Flags().Changed("max-entries") {
+		maxEntries = pruneMaxEntries
+	}
+	if cmd.Flags().Changed("max-age") {
+		maxAge, err = config.ParseAge(pruneMaxAge)
+		if err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	removed, err := mgr.Prune(maxEntries, maxAge, pruneDryRun)
+	if err != nil {
+		output.Errorf("❌ Error pruning history: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch {
+	case removed == 0:
+		output.Println("✅ History is within retention limits, nothing to prune")
+	case pruneDryRun:
+		output.Printf("🔍 Would remove %d history entr%s\n", removed, pluralY(removed))
+	default:
+		output.Printf("🧹 Removed %d history entr%s\n", removed, pluralY(removed))
+	}
+}
+
+func runSessionsDone(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	mgr := session.New(cwd)
+	s, err := mgr.LoadNamed(doneName)
+	if err != nil || s == nil {
+		output.Errorf("❌ No saved session for this branch\n")
+		os.Exit(1)
+	}
+
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			output.Errorf("❌ Invalid step number %q\n", args[0])
+			os.Exit(1)
+		}
+		step, err := s.CompleteStep(n)
+		if err != nil {
+			output.Errorf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if err := mgr.Save(s); err != nil {
+			output.Errorf("❌ Error saving session: %v\n", err)
+			os.Exit(1)
+		}
+		output.Printf("✅ Done: %s\n", step)
+		output.Println()
+	}
+
+	if len(s.NextSteps) == 0 {
+		output.Println("🎉 No open next steps")
+		return
+	}
+	output.Println("➡️  Next steps:")
+	for i, step := range s.NextSteps {
+		output.Printf("   %d. %s\n", i+1, step)
+	}
+}
+
+func pluralY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
+
+var sessionsGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Archive sessions of merged or deleted branches",
+	Long: `Find session files whose git branch has been deleted, or merged into
+the default branch, and reclaim them.
+
+By default each stale session is moved to .contextpilot/sessions/archive/.
+With --history it is folded into history.jsonl instead (it stays
+searchable with 'contextpilot sessions search') and its file is removed.
+
+Completed work can be recorded on the way out: --to-decision logs each
+session as a decision, --to-changelog adds it to the Unreleased section
+of CHANGELOG.md.
+
+Examples:
+  contextpilot sessions gc --dry-run
+  contextpilot sessions gc --to-changelog
+  contextpilot sessions gc --base develop`,
+	Run: runSessionsGC,
+}
+
+func runSessionsGC(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	base := gcBase
+	if base == "" {
+		base = git.DefaultBranch(cwd)
+	}
+
+	mgr := session.New(cwd)
+	stale, err := mgr.Stale(base)
+	if err != nil {
+		output.Errorf("❌ Error finding stale sessions: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(stale) == 0 {
+		output.Println("✅ No sessions of merged or deleted branches")
+		return
+	}
+
+	action := "Archived"
+	if gcHistory {
+		action = "Moved to history"
+	}
+	if gcDryRun {
+		action = "Would reclaim"
+	}
+
+	decs := decisions.New(cwd)
+	policy := projectPolicy(cwd, nil)
+	reclaimed := 0
+	for i := range stale {
+		s := &stale[i].Session
+		if !gcDryRun {
+			var err error
+			if gcHistory {
+				err = mgr.Retire(s)
+			} else {
+				err = mgr.Archive(s)
+			}
+			if err != nil {
+				output.Errorf("⚠️  %s: %v\n", sessionLabel(*s), err)
+				continue
+			}
+
+			// Only sessions actually reclaimed are recorded, so a rerun
+			// after a failure doesn't log them twice
+			if err := recordCompletedSession(cwd, decs, policy, s); err != nil {
+				output.Errorf("⚠️  %s: reclaimed, but not recorded: %v\n", sessionLabel(*s), err)
+			}
+		}
+		reclaimed++
+		output.Printf("   • %s: %s, branch %s (%s)\n", action, sessionLabel(*s), stale[i].Reason, s.Task)
+	}
+
+	output.Println()
+	if gcDryRun {
+		output.Printf("🔍 %d session file(s) would be reclaimed\n", reclaimed)
+		return
+	}
+	output.Printf("🧹 Reclaimed %d session file(s)\n", reclaimed)
+	if gcToDecision {
+		output.Info("💡 Run 'contextpilot sync' to include the new decisions in context files")
+	}
+}
+
+// recordCompletedSession logs s as a decision and/or changelog entry,
+// as requested by --to-decision and --to-changelog
+func recordCompletedSession(cwd string, decs *decisions.Manager, policy *redact.Policy, s *session.Session) error {
+	links := []string{}
+	for _, l := range []string{s.Issue, s.PR} {
+		if l != "" {
+			links = append(links, l)
+		}
+	}
+
+	if gcToDecision {
+		context := []string{}
+		if s.Goal != "" {
+			context = append(context, s.Goal)
+		}
+		context = append(context, s.Decisions...)
+		context = append(context, links...)
+		if _, err := decs.Add("Completed: "+s.Task, strings.Join(context, "; ")); err != nil {
+			return err
+		}
+	}
+
+	if gcToChangelog {
+		if err := changelog.AddUnreleased(cwd, policy.Apply(sessionEntry(*s))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printSessionRows prints one line per session with branch, name, task and age
+func printSessionRows(sessions []session.Session) {
+	width := 0
+	for _, s := range sessions {
+		if n := len(sessionLabel(s)); n > width {
+			width = n
+		}
+	}
+	for _, s := range sessions {
+		output.Printf("   • %-*s  %s (%s)\n", width, sessionLabel(s), s.Task, formatAge(s.UpdatedAt))
+	}
+}
+
+func sessionLabel(s session.Session) string {
+	if s.IsDefault() {
+		return s.Branch
+	}
+	return s.Branch + " [" + s.Name + "]"
+}
+
+// formatAge renders how long ago t was in a compact human form
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+var sessionsPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Upload sessions to your remote",
+	Long: `Upload this project's sessions to the remote in your user config,
+~/.config/contextpilot/config.yaml, so 'sessions pull' can fetch them on
+another machine:
+
+  sessions:
+    remote:
+      url: https://dav.example.com/contextpilot   # or s3://bucket/prefix, file:///path
+      username: me          # HTTP basic auth; password from $CONTEXTPILOT_REMOTE_PASSWORD
+      # HTTP without username: bearer token from $CONTEXTPILOT_REMOTE_TOKEN
+      # S3: credentials from $AWS_ACCESS_KEY_ID and $AWS_SECRET_ACCESS_KEY
+      # endpoint: https://minio.example.com   region: us-east-1
+
+Sessions are stored per project, keyed by the origin remote so every
+clone of a repository shares them. When both sides have a session, the
+one updated last wins: push never overwrites a newer remote copy.`,
+	Annotations: jsonCapable,
+	Run:         runSessionsRemote,
+}
+
+var sessionsPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Download sessions from your remote",
+	Long: `Download this project's sessions from the remote set up in your
+user config (see 'contextpilot sessions push --help'). A local session
+updated after the remote copy is kept.`,
+	Annotations: jsonCapable,
+	Run:         runSessionsRemote,
+}
+
+// runSessionsRemote runs 'sessions push' or 'sessions pull'
+func runSessionsRemote(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	user, err := config.LoadUser()
+	if err != nil {
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	if user.Sessions.Remote.URL == "" {
+		path, _ := config.UserPath()
+		output.Errorf("❌ No remote configured\n")
+		output.Info()
+		output.Infof("💡 Set sessions.remote.url in %s\n", path)
+		output.Info("   See: contextpilot sessions push --help")
+		os.Exit(1)
+	}
+	store, err := remote.New(user.Sessions.Remote)
+	if err != nil {
+		output.Errorf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	project := remoteProject
+	if project == "" {
+		project = remote.ProjectKey(cwd)
+	}
+	mgr := session.New(cwd)
+	sync, verb := remote.Push, "push"
+	if cmd.Name() == "pull" {
+		sync, verb = remote.Pull, "pull"
+	}
+	changes, err := sync(store, mgr, project)
+	if err != nil {
+		output.Errorf("❌ Failed to %s sessions: %v\n", verb, err)
+		os.Exit(1)
+	}
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{"remote": store.String(), "project": project, "sessions": changes})
+		return
+	}
+	if len(changes) == 0 {
+		output.Printf("📋 No sessions to %s\n", verb)
+		return
+	}
+
+	output.Printf("☁️  %s (%s)\n", store, project)
+	output.Println()
+	moved := 0
+	for _, c := range changes {
+		icon := "⏭️ "
+		switch c.Action {
+		case remote.Pushed:
+			icon = "⬆️ "
+			moved++
+		case remote.Pulled:
+			icon = "⬇️ "
+			moved++
+		case remote.UpToDate:
+			icon = "✅"
+		}
+		output.Printf("   %s %s: %s (%s)\n", icon, sessionLabel(c.Session), c.Action, formatAge(c.Session.UpdatedAt))
+	}
+	output.Println()
+	output.Printf("✅ %d of %d session(s) %sed\n", moved, len(changes), verb)
+}
+
+func init() {
+	rootCmd.AddCommand(sessionsCmd)
+	sessionsCmd.AddCommand(sessionsListCmd)
+	sessionsCmd.AddCommand(sessionsSearchCmd)
+	sessionsCmd.AddCommand(sessionsPruneCmd)
+	sessionsCmd.AddCommand(sessionsGCCmd)
+	sessionsCmd.AddCommand(sessionsDoneCmd)
+	sessionsCmd.AddCommand(sessionsPushCmd)
+	sessionsCmd.AddCommand(sessionsPullCmd)
+	for _, c := range []*cobra.Command{sessionsPushCmd, sessionsPullCmd} {
+		c.Flags().StringVar(&remoteProject, "project", "", "Project name in the remote (default: from the origin remote)")
+	}
+	sessionsDoneCmd.Flags().StringVar(&doneName, "name", "", "Session name (default: the branch's default session)")
+	sessionsGCCmd.Flags().BoolVar(&gcHistory, "history", false, "Fold stale sessions into history.jsonl instead of archiving them")
+	// --archive picked archiving when folding into history was the default
+	sessionsGCCmd.Flags().BoolVar(&gcArchive, "archive", false, "Archive stale sessions (the default)")
+	sessionsGCCmd.Flags().MarkDeprecated("archive", "archiving is the default now")
+	sessionsGCCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "Report stale sessions without changing anything")
+	sessionsGCCmd.Flags().StringVar(&gcBase, "base", "", "Branch that merged work lands on (default: origin/HEAD, main or master)")
+	sessionsGCCmd.Flags().BoolVar(&gcToDecision, "to-decision", false, "Log each reclaimed session as a decision")
+	sessionsGCCmd.Flags().BoolVar(&gcToChangelog, "to-changelog", false, "Add each reclaimed session to the Unreleased section of CHANGELOG.md")
+	sessionsPruneCmd.Flags().IntVar(&pruneMaxEntries, "max-entries", 0, "Keep at most this many entries (0 = no limit)")
+	sessionsPruneCmd.Flags().StringVar(&pruneMaxAge, "max-age", "", "Drop entries older than this, e.g. 30d (0 = no limit)")
+	sessionsPruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Report what would be removed without changing history")
+	sessionsListCmd.Flags().BoolVarP(&sessionsAllBranches, "all-branches", "a", false, "List sessions from every branch")
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/redact"
+	"github.com/jitin-nhz/contextpilot/internal/standup"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	standupDate string
+	standupCopy bool
+)
+
+var standupCmd = &cobra.Command{
+	Use:   "standup",
+	Short: "Summarize yesterday's work for a standup",
+	Long: `Compile the sessions saved and decisions logged on the last workday
+(yesterday, or Friday on a Monday) into a short summary to paste into a
+standup channel, grouped by branch:
+
+  Worked on  each task saved that day, with its last saved state
+  Done       next steps checked off that day ('session done')
+  Next       next steps still open
+
+Sessions come from the session history, so history.maxAge in
+.contextpilot/config.yaml must reach back to the day summarized.
+
+Examples:
+  contextpilot standup
+  contextpilot standup --date 2026-03-13
+  contextpilot standup --copy`,
+	Args:        cobra.NoArgs,
+	Annotations: jsonCapable,
+	Run:         runStandup,
+}
+
+func runStandup(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	day := standup.LastWorkday(time.Now())
+	if standupDate != "" {
+		if day, err = time.ParseInLocation("2006-01-02", standupDate, time.Local); err != nil {
+			output.Errorf("❌ --date %q is not a YYYY-MM-DD date\n", standupDate)
+			os.Exit(1)
+		}
+	}
+
+	history, err := session.New(cwd).History()
+	if err != nil {
+		output.Errorf("❌ Error reading sessions: %v\n", err)
+		os.Exit(1)
+	}
+	decs, err := decisions.New(cwd).List()
+	if err != nil {
+		output.Errorf("❌ Error reading decisions: %v\n", err)
+		os.Exit(1)
+	}
+	s := standup.Build(day, history, decs)
+	redactStandup(projectPolicy(cwd, nil), &s)
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{
+			"day":       s.Day,
+			"branches":  s.Branches,
+			"decisions": s.Decisions,
+			"text":      s.Markdown(),
+		})
+		return
+	}
+	if s.Empty() {
+		output.Infof("📋 No sessions saved or decisions logged on %s\n", s.Day)
+		return
+	}
+
+	text := s.Markdown()
+	if standupCopy {
+		if err := copyToClipboard(text); err != nil {
+			output.Errorf("⚠️  Could not copy to clipboard: %v\n", err)
+		} else {
+			output.Info("✅ Standup copied to clipboard!")
+			return
+		}
+	}
+	output.Write(text)
+}
+
+// redactStandup applies policy to the text of s field by field, so the
+// JSON stays valid whatever the replacement
+func redactStandup(policy *redact.Policy, s *standup.Standup) {
+	for i := range s.Branches {
+		b := &s.Branches[i]
+		b.Branch, b.Issue, b.PR = policy.Apply(b.Branch), policy.Apply(b.Issue), policy.Apply(b.PR)
+		for j := range b.Tasks {
+			b.Tasks[j].Task = policy.Apply(b.Tasks[j].Task)
+			b.Tasks[j].State = policy.Apply(b.Tasks[j].State)
+		}
+		for j := range b.Done {
+			b.Done[j] = policy.Apply(b.Done[j])
+		}
+		for j := range b.Next {
+			b.Next[j] = policy.Apply(b.Next[j])
+		}
+	}
+	redactDecisions(policy, s.Decisions)
+}
+
+func init() {
+	rootCmd.AddCommand(standupCmd)
+	standupCmd.Flags().StringVar(&standupDate, "date", "", "Day to summarize (YYYY-MM-DD, default the last workday)")
+	standupCmd.Flags().BoolVarP(&standupCopy, "copy", "c", false, "Copy to clipboard instead of printing")
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var statsTop int
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show codebase statistics",
+	Long: `Show what the analyzer knows about the current project: the language
+breakdown, the largest top-level directories, how the number of tracked
+files has changed in git over the last year, dependency counts and how
+many code files are tests.
+
+Test files are recognized by name (foo_test.go, foo.test.ts,
+test_foo.py, FooTest.java) or by living in a test, tests, __tests__ or
+spec directory. Directories in the ignore list in
+.contextpilot/config.yaml are left out, as they are for the context
+files.
+
+Examples:
+  contextpilot stats
+  contextpilot stats --top 10
+  contextpilot stats --json`,
+	Args:        cobra.NoArgs,
+	Annotations: jsonCapable,
+	Run:         runStats,
+}
+
+// statsTrendPoints are how far back 'stats' looks for file counts
+var statsTrendPoints = []struct {
+	label string
+	since string
+}{
+	{"now", "now"},
+	{"30 days ago", "30.days.ago"},
+	{"90 days ago", "90.days.ago"},
+	{"180 days ago", "180.days.ago"},
+	{"1 year ago", "1.year.ago"},
+}
+
+type statsDir struct {
+	Path      string `json:"path"`
+	CodeFiles int    `json:"codeFiles"`
+	TestFiles int    `json:"testFiles"`
+}
+
+type statsTrend struct {
+	When  string `json:"when"`
+	Files int    `json:"files"`
+}
+
+func runStats(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	spin := output.StartSpinner("📈 Collecting stats...")
+	a := analyzer.New(cwd)
+	analysis, err := a.Analyze()
+	if err != nil {
+		spin.Stop()
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
+		os.Exit(1)
+	}
+
+	languages := append([]analyzer.Language(nil), analysis.Languages...)
+	sort.SliceStable(languages, func(i, j int) bool {
+		if languages[i].FileCount != languages[j].FileCount {
+			return languages[i].FileCount > languages[j].FileCount
+		}
+		return languages[i].Name < languages[j].Name
+	})
+	codeFiles, testFiles := 0, 0
+	for _, l := range languages {
+		codeFiles += l.FileCount
+		testFiles += l.TestFiles
+	}
+
+	dirs := []statsDir{}
+	for _, d := range a.Profile().Dirs {
+		if d.CodeFiles > 0 {
+			dirs = append(dirs, statsDir{Path: d.Path, CodeFiles: d.CodeFiles, TestFiles: d.TestFiles})
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].CodeFiles != dirs[j].CodeFiles {
+			return dirs[i].CodeFiles > dirs[j].CodeFiles
+		}
+		return dirs[i].Path < dirs[j].Path
+	})
+	if statsTop > 0 && len(dirs) > statsTop {
+		dirs = dirs[:statsTop]
+	}
+
+	trend := []statsTrend{}
+	if git.IsRepo(cwd) {
+		for _, p := range statsTrendPoints {
+			n, ok := git.FileCountAt(cwd, p.since)
+			if !ok {
+				break
+			}
+			trend = append(trend, statsTrend{When: p.label, Files: n})
+		}
+	}
+	spin.Stop()
+
+	deps, devDeps := len(analysis.Packages.Dependencies), len(analysis.Packages.DevDeps)
+	ratio := 0.0
+	if codeFiles > testFiles {
+		ratio = float64(testFiles) / float64(codeFiles-testFiles)
+	}
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{
+			"codeFiles":   codeFiles,
+			"languages":   languages,
+			"directories": dirs,
+			"trend":       trend,
+			"dependencies": map[string]interface{}{
+				"manager": analysis.Packages.Manager,
+				"runtime": deps,
+				"dev":     devDeps,
+			},
+			"tests": map[string]interface{}{
+				"files":       testFiles,
+				"sourceFiles": codeFiles - testFiles,
+				"ratio":       ratio,
+			},
+		})
+		return
+	}
+
+	output.Printf("📈 %s: %d code files\n", filepath.Base(cwd), codeFiles)
+	output.Println()
+	if len(languages) == 0 {
+		output.Info("No code files found. Are you in the project directory?")
+		return
+	}
+
+	output.Println("🗣️  Languages:")
+	for _, l := range languages {
+		output.Printf("   %-18s %6d  %5.1f%%  (%d tests)\n", l.Name, l.FileCount, l.Percentage, l.TestFiles)
+	}
+	output.Println()
+
+	if len(dirs) > 0 {
+		output.Println("📁 Largest directories:")
+		for _, d := range dirs {
+			output.Printf("   %-24s %6d code files, %d tests\n", d.Path+"/", d.CodeFiles, d.TestFiles)
+		}
+		output.Println()
+	}
+
+	if len(trend) > 0 {
+		output.Println("🕰️  Tracked files in git:")
+		now := trend[0].Files
+		for i, t := range trend {
+			if i == 0 {
+				output.Printf("   %-14s %6d\n", t.When, t.Files)
+				continue
+			}
+			output.Printf("   %-14s %6d  (%+d since)\n", t.When, t.Files, now-t.Files)
+		}
+		output.Println()
+	}
+
+	if analysis.Packages.Manager != "" {
+		output.Printf("📦 Dependencies (%s): %d runtime, %d dev\n", analysis.Packages.Manager, deps, devDeps)
+	}
+	output.Printf("🧪 Tests: %d of %d code files (%.2f tests per source file)\n", testFiles, codeFiles, ratio)
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().IntVar(&statsTop, "top", 5, "Number of directories to list (0 for all)")
+}
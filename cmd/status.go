@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether context files are in sync with the code",
+	Long: `Show when context files were last synced, how many commits have
+touched code since then, and whether the context files match the code.
+
+Examples:
+  contextpilot status
+  contextpilot status --json`,
+	Annotations: jsonCapable,
+	Args:        cobra.NoArgs,
+	Run:         runStatus,
+}
+
+func runStatus(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	if !config.Exists(cwd) {
+		output.Errorf("❌ ContextPilot not initialized in this directory\n")
+		output.Info()
+		output.Info("Run 'contextpilot init' first to generate context files.")
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	unsynced := -1
+	if !cfg.LastSync.IsZero() {
+		if n, err := unsyncedCommits(cwd, cfg.LastSync); err == nil {
+			unsynced = n
+		}
+	}
+	stale, d, err := contextState(cwd)
+	if err != nil {
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
+		os.Exit(1)
+	}
+	decs, _ := decisions.New(cwd).List()
+	active := 0
+	for _, dec := range decs {
+		if dec.Active() {
+			active++
+		}
+	}
+
+	if output.IsJSON() {
+		doc := map[string]interface{}{
+			"lastSync":        nil,
+			"unsyncedCommits": nil,
+			"upToDate":        len(stale) == 0,
+			"stale":           stale,
+			"drift":           d.Items,
+			"decisions":       active,
+		}
+		if !cfg.LastSync.IsZero() {
+			doc["lastSync"] = cfg.LastSync.Format(time.RFC3339)
+		}
+		if unsynced >= 0 {
+			doc["unsyncedCommits"] = unsynced
+		}
+		printJSON(doc)
+		return
+	}
+
+	output.Println("📍 ContextPilot status")
+	if cfg.LastSync.IsZero() {
+		output.Println("   🕐 Last sync: never")
+	} else {
+		output.Printf("   🕐 Last sync: %s\n", formatAge(cfg.LastSync))
+	}
+	if unsynced >= 0 {
+		output.Printf("   🔀 Unsynced commits: %d\n", unsynced)
+	}
+	if len(stale) == 0 {
+		output.Println("   ✅ Context files: up to date")
+	} else {
+		output.Printf("   ❌ Context files: out of date (%s)\n", strings.Join(stale, ", "))
+	}
+	output.Printf("   🧭 Decisions: %d active\n", active)
+	if len(d.Items) > 0 {
+		output.Println()
+		printDrift(d.Items)
+	}
+
+	if len(stale) > 0 || unsynced > 0 || len(d.Items) > 0 {
+		output.Info()
+		output.Info("💡 Run 'contextpilot sync' to update them")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
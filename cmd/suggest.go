@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/suggest"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	suggestDays       int
+	suggestMinCommits int
+)
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Find busy areas with no recorded decisions",
+	Long: `Look at recent git history for areas with heavy churn but no logged
+decisions, so you can write down the reasoning while it's still fresh.
+
+A decision covers an area when it links files there (--files) or was logged
+recently and mentions the area by name.
+
+Examples:
+  contextpilot suggest
+  contextpilot suggest --days 14 --min-commits 3`,
+	Run: runSuggest,
+}
+
+func runSuggest(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	if !git.IsRepo(cwd) {
+		output.Errorf("❌ Not a git repository\n")
+		os.Exit(1)
+	}
+
+	decs, _ := decisions.New(cwd).List()
+	hotspots, err := suggest.Hotspots(cwd, suggestDays, suggestMinCommits, decs)
+	if err != nil {
+		output.Errorf("❌ Error reading git history: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(hotspots) == 0 {
+		output.Printf("✅ No busy areas without decisions in the last %d days\n", suggestDays)
+		return
+	}
+
+	output.Println("💡 Decisions worth recording:")
+	output.Println()
+	for _, h := range hotspots {
+		output.Printf("   • %s\n", h.String(suggestDays))
+	}
+	output.Info()
+	output.Info("Record one with:")
+	output.Infof("  contextpilot decision \"...\" --files '%s'\n", hotspots[0].Glob())
+}
+
+func init() {
+	rootCmd.AddCommand(suggestCmd)
+	suggestCmd.Flags().IntVar(&suggestDays, "days", suggest.DefaultDays, "How far back to look")
+	suggestCmd.Flags().IntVar(&suggestMinCommits, "min-commits", suggest.DefaultMinCommits, "Commits an area needs to count as busy")
+}
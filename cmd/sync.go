@@ -1,21 +1,31 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/jitin-nhz/contextpilot/internal/analyzer"
-	"github.com/jitin-nhz/contextpilot/internal/generator"
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/drift"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
-var forceSyncFlag bool
+var (
+	forceSyncFlag  bool
+	checkSyncFlag  bool
+	syncDryRun     bool
+	syncRecentFlag int
+)
 
 var syncCmd = &cobra.Command{
 	Use:   "sync",
@@ -27,19 +37,54 @@ Uses git diff to detect:
   - Deleted or renamed files  
   - Significant code changes
 
-Regenerates context files with latest analysis.`,
-	Run: runSync,
+Regenerates context files with latest analysis. When the analysis is
+the same as at the last sync and the context files still match it, sync
+stops there with "Context is up to date"; --force regenerates them
+anyway.
+
+With --check, nothing is written: sync exits with status 1 when the
+context files are out of date, for git hooks and CI.
+
+With --dry-run, nothing is written either: sync prints the files it
+would create, update or remove, the sections that would change in each,
+and the changes to the stack since the last sync that call for it. It
+exits 0 either way, so scripts can run it safely. Neither upgrades
+project files from an older release; they point to 'contextpilot
+migrate' instead.
+
+With --recent-changes N, the context files get a "Recent Changes"
+section summarizing the last N commits on the branch (merged pull
+requests by title), grouped by directory, so AI tools know what's being
+worked on; 0 leaves it out. To include it on every sync, set it in
+.contextpilot/config.yaml:
+
+  recentChanges:
+    enabled: true
+    count: 10
+
+The section changes with every commit, so 'sync --check' and 'status'
+report the context files as out of date until the next sync.
+
+Examples:
+  contextpilot sync
+  contextpilot sync --recent-changes 20
+  contextpilot sync --dry-run
+  contextpilot sync --force
+  contextpilot sync --check`,
+	Annotations: jsonCapable,
+	Run:         runSync,
 }
 
 type configFile struct {
-	Version  int       `yaml:"version"`
-	LastSync time.Time `yaml:"lastSync"`
+	Version      int       `yaml:"version"`
+	LastSync     time.Time `yaml:"lastSync"`
+	AnalysisHash string    `yaml:"analysisHash"`
 }
 
 func runSync(cmd *cobra.Command, args []string) {
-	cwd, err := os.Getwd()
+	cwd, err := projectDir()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error getting current directory: %v\n", err)
+		output.Errorf("❌ Error getting current directory: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -47,53 +92,80 @@ func runSync(cmd *cobra.Command, args []string) {
 
 	// Check if initialized
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		fmt.Println("❌ ContextPilot not initialized in this directory")
-		fmt.Println()
-		fmt.Println("Run 'contextpilot init' first to generate context files.")
+		output.Errorf("❌ ContextPilot not initialized in this directory\n")
+		output.Info()
+		output.Info("Run 'contextpilot init' first to generate context files.")
 		os.Exit(1)
 	}
 
+	if checkSyncFlag {
+		stale, d, err := contextState(cwd)
+		if err != nil {
+			output.Errorf("❌ Error analyzing codebase: %v\n", err)
+			os.Exit(1)
+		}
+		if output.IsJSON() {
+			printJSON(map[string]interface{}{"upToDate": len(stale) == 0, "stale": stale, "drift": d.Items})
+		} else if len(stale) == 0 {
+			output.Println("✅ Context files are up to date")
+		} else {
+			output.Printf("❌ Context files are out of date: %s\n", strings.Join(stale, ", "))
+		}
+		if !output.IsJSON() {
+			printDrift(d.Items)
+			if len(stale) > 0 || len(d.Items) > 0 {
+				output.Info("💡 Run 'contextpilot sync' to update them")
+			}
+		}
+		if len(stale) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Read last sync time
 	var lastSync time.Time
+	var lastHash string
 	if data, err := os.ReadFile(configPath); err == nil {
 		var cfg configFile
 		if yaml.Unmarshal(data, &cfg) == nil {
-			lastSync = cfg.LastSync
+			lastSync, lastHash = cfg.LastSync, cfg.AnalysisHash
 		}
 	}
 
-	fmt.Println("🔄 Checking for changes since last sync...")
+	output.Info("🔄 Checking for changes since last sync...")
 
 	// Show git changes if available
 	changes := getGitChanges(cwd, lastSync)
 	if len(changes) > 0 {
-		fmt.Printf("   ├── %d file(s) changed since last sync\n", len(changes))
+		output.Printf("📂 %d file(s) changed since last sync\n", len(changes))
 		// Show up to 5 changes
 		shown := 0
 		for _, c := range changes {
 			if shown >= 5 {
-				fmt.Printf("   │  └── ... and %d more\n", len(changes)-5)
+				output.Printf("   └── ... and %d more\n", len(changes)-5)
 				break
 			}
 			prefix := "├──"
-			if shown == len(changes)-1 || shown == 4 {
+			if shown == len(changes)-1 || (shown == 4 && len(changes) == 5) {
 				prefix = "└──"
 			}
-			fmt.Printf("   │  %s %s\n", prefix, c)
+			output.Printf("   %s %s\n", prefix, c)
 			shown++
 		}
 	} else {
-		fmt.Println("   ├── No git changes detected (or not a git repo)")
+		output.Println("📂 No git changes detected (or not a git repo)")
 	}
 
 	// Re-run analysis
-	fmt.Println("   └── Re-analyzing codebase...")
-	fmt.Println()
+	output.Println()
+	spin := output.StartSpinner("🔍 Re-analyzing codebase...")
 
 	a := analyzer.New(cwd)
 	analysis, err := a.Analyze()
+	spin.Stop()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error analyzing codebase: %v\n", err)
+		output.Errorf("❌ Error analyzing codebase: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -101,38 +173,195 @@ func runSync(cmd *cobra.Command, args []string) {
 	sort.Slice(analysis.Languages, func(i, j int) bool {
 		return analysis.Languages[i].FileCount > analysis.Languages[j].FileCount
 	})
+	scanned, skipped := describeTotals(analysis.Totals)
+	output.Printf("📁 %s\n", scanned)
+	if skipped != "" {
+		output.Printf("   %s\n", skipped)
+	}
+
+	gen := generator.NewWithOptions(analysis, generator.Options{Root: cwd, RecentChanges: syncRecentChanges(cmd)})
+	if syncDryRun {
+		runSyncDryRun(cwd, gen, analysis, changes)
+		return
+	}
+
+	// Nothing to do when the analysis is the one last synced and the
+	// files still match it, unless --force
+	if !forceSyncFlag && upToDate(cwd, gen, analysis, lastHash) {
+		if err := config.TouchLastSync(cwd, time.Now(), ""); err != nil {
+			output.Errorf("⚠️  Could not update lastSync: %v\n", err)
+		}
+		if output.IsJSON() {
+			if changes == nil {
+				changes = []string{}
+			}
+			printJSON(map[string]interface{}{"changedFiles": changes, "analysis": analysis, "files": []string{}, "drift": []drift.Item{}, "upToDate": true})
+			return
+		}
+		output.Println("✅ Context is up to date")
+		output.Info("💡 Run 'contextpilot sync --force' to regenerate the files anyway")
+		return
+	}
+
+	// Compare with the last sync before the snapshot is replaced
+	d, err := drift.Detect(cwd, analysis, generator.Outputs(cwd))
+	if err != nil {
+		output.Errorf("⚠️  Could not check for drift: %v\n", err)
+	}
+	printDrift(d.Items)
 
 	// Generate updated files
-	fmt.Println("📝 Updating context files...")
-	gen := generator.New(analysis, cwd)
+	output.Info("📝 Updating context files...")
 	if err := gen.GenerateAll(); err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error generating files: %v\n", err)
+		output.Errorf("❌ Error generating files: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Println("   ├── .cursorrules")
-	fmt.Println("   ├── CLAUDE.md")
-	fmt.Println("   ├── .github/copilot-instructions.md")
-	fmt.Println("   └── .contextpilot/config.yaml")
-	fmt.Println()
-	fmt.Println("✅ Context files updated!")
+	printGeneratedFiles(gen.Files(), false)
+	if output.IsJSON() {
+		if changes == nil {
+			changes = []string{}
+		}
+		if d.Items == nil {
+			d.Items = []drift.Item{}
+		}
+		printJSON(map[string]interface{}{"changedFiles": changes, "analysis": analysis, "files": generatedFiles(gen.Files()), "drift": d.Items, "upToDate": false})
+		return
+	}
+	output.Info()
+	output.Info("✅ Context files updated!")
 
 	// Show summary
 	if analysis.Framework != nil {
-		fmt.Printf("\n📊 Current state: %s", analysis.Framework.Name)
+		output.Printf("\n📊 Current state: %s", analysis.Framework.Name)
 		if len(analysis.Languages) > 0 {
-			fmt.Printf(" + %s", analysis.Languages[0].Name)
+			output.Printf(" + %s", analysis.Languages[0].Name)
 		}
-		fmt.Println()
+		output.Println()
+	}
+}
+
+// upToDate reports whether sync would only rewrite the dates: the analysis
+// hashes to lastHash, the one recorded at the last sync, the baseline
+// snapshot is there and the context files on disk match what would be
+// generated
+func upToDate(cwd string, gen *generator.Generator, analysis *analyzer.Analysis, lastHash string) bool {
+	if lastHash == "" || analysis.Hash() != lastHash {
+		return false
+	}
+	if _, ok, err := drift.Load(cwd); err != nil || !ok {
+		return false
+	}
+	return len(gen.Stale()) == 0
+}
+
+// runSyncDryRun prints what sync would change and why, writing nothing
+func runSyncDryRun(cwd string, gen *generator.Generator, analysis *analyzer.Analysis, changes []string) {
+	plan := gen.Plan()
+	old, baseline, err := drift.Load(cwd)
+	if err != nil {
+		output.Errorf("⚠️  Could not read the last sync's analysis: %v\n", err)
+	}
+	delta := []drift.Item{}
+	if baseline {
+		delta = drift.Delta(old, drift.NewSnapshot(analysis))
+	}
+
+	if output.IsJSON() {
+		if changes == nil {
+			changes = []string{}
+		}
+		printJSON(map[string]interface{}{"changedFiles": changes, "analysis": analysis, "changes": plan, "baseline": baseline, "drift": delta, "dryRun": true})
+		return
+	}
+
+	output.Info()
+	output.Info("🔍 Dry run - no files written")
+	output.Info()
+	if len(plan) == 0 {
+		output.Println("✅ Context files are up to date; sync would change nothing")
+		return
+	}
+	output.Printf("Would change %d file(s):\n", len(plan))
+	for i, c := range plan {
+		prefix := "├──"
+		if i == len(plan)-1 {
+			prefix = "└──"
+		}
+		line := c.File + ": " + c.Action
+		if len(c.Sections) > 0 {
+			line += " (" + strings.Join(c.Sections, ", ") + ")"
+		}
+		output.Printf("   %s %s\n", prefix, line)
+	}
+	output.Println()
+	output.Println("Because:")
+	switch {
+	case !baseline:
+		output.Println("   • There is no analysis from a previous sync to compare with")
+	case len(delta) == 0:
+		output.Println("   • The stack is unchanged since the last sync; the content comes from files, decisions or settings that changed")
+	}
+	for _, item := range delta {
+		output.Printf("   • %s\n", item)
+	}
+}
+
+// syncRecentChanges returns the generator's RecentChanges for
+// --recent-changes: 0 (what config.yaml says) unless the flag is set
+func syncRecentChanges(cmd *cobra.Command) int {
+	if !cmd.Flags().Changed("recent-changes") {
+		return 0
+	}
+	if syncRecentFlag <= 0 {
+		return -1
+	}
+	return syncRecentFlag
+}
+
+// staleContextFiles returns the context files that sync would change
+func staleContextFiles(cwd string) ([]string, error) {
+	stale, _, err := contextState(cwd)
+	return stale, err
+}
+
+// contextState returns the context files that sync would change and how
+// the stack has drifted since the last sync
+func contextState(cwd string) ([]string, drift.Result, error) {
+	analysis, err := analyzer.New(cwd).Analyze()
+	if err != nil {
+		return nil, drift.Result{}, err
+	}
+	sort.Slice(analysis.Languages, func(i, j int) bool {
+		return analysis.Languages[i].FileCount > analysis.Languages[j].FileCount
+	})
+	stale := generator.New(analysis, cwd).Stale()
+	if stale == nil {
+		stale = []string{}
+	}
+	d, err := drift.Detect(cwd, analysis, generator.Outputs(cwd))
+	if err != nil {
+		return nil, d, err
+	}
+	return stale, d, nil
+}
+
+// printDrift lists drift items under a heading
+func printDrift(items []drift.Item) {
+	if len(items) == 0 {
+		return
+	}
+	output.Println("🧭 Drift since last sync:")
+	for _, item := range items {
+		output.Printf("   • %s\n", item)
 	}
 }
 
 func getGitChanges(cwd string, since time.Time) []string {
 	var changes []string
 
-	// Check if git repo
-	gitDir := filepath.Join(cwd, ".git")
-	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
+	// Check if git repo (works from subdirectories and worktrees too)
+	if !git.IsRepo(cwd) {
 		return changes
 	}
 
@@ -169,6 +398,26 @@ func getGitChanges(cwd string, since time.Time) []string {
 	return changes
 }
 
+// unsyncedCommits counts commits since lastSync that touch code; commits
+// that only change context files, lockfiles or hidden paths don't count
+func unsyncedCommits(cwd string, lastSync time.Time) (int, error) {
+	// --since is inclusive, and the sync itself ran within that second
+	commits, err := git.LogFiles(cwd, lastSync.Add(time.Second).Format(time.RFC3339))
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, files := range commits {
+		for _, f := range files {
+			if isRelevantFile(f) && !slices.Contains(generator.ContextFiles, f) && path.Base(f) != generator.AgentsFile {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
 func isRelevantFile(path string) bool {
 	// Skip common non-code files
 	skip := []string{
@@ -194,5 +443,8 @@ func isRelevantFile(path string) bool {
 
 func init() {
 	rootCmd.AddCommand(syncCmd)
-	syncCmd.Flags().BoolVarP(&forceSyncFlag, "force", "f", false, "Force sync even if no changes detected")
+	syncCmd.Flags().BoolVarP(&forceSyncFlag, "force", "f", false, "Regenerate the context files even if nothing changed")
+	syncCmd.Flags().BoolVar(&checkSyncFlag, "check", false, "Exit 1 if context files are out of date, without writing them")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Show which files and sections would change and why, without writing them")
+	syncCmd.Flags().IntVar(&syncRecentFlag, "recent-changes", 0, "Summarize the last N commits in the context files (0 for none)")
 }
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var templatesEject bool
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Show which templates generate the context files",
+	Long: `List the templates the context files and config.yaml are rendered
+from, and where each one comes from. The first of these wins:
+
+  1. .contextpilot/templates/<name> in the project
+  2. the same in the monorepo root, for a package with a config.yaml of
+     its own
+  3. ~/.config/contextpilot/templates/<name> for every project
+  4. the template built into contextpilot
+
+--eject copies the templates the project doesn't override yet into
+.contextpilot/templates, ready to edit: the monorepo root's where it has
+one, else the built-in one. Templates use Go's
+text/template with the project analysis as data.
+
+Examples:
+  contextpilot templates
+  contextpilot templates --eject`,
+	Args:        cobra.NoArgs,
+	Annotations: jsonCapable,
+	Run:         runTemplates,
+}
+
+// templateStatus is one template as 'templates' reports it
+type templateStatus struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+func runTemplates(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+	gen := generator.New(nil, cwd)
+
+	var statuses []templateStatus
+	var ejected []string
+	for _, name := range generator.Templates {
+		text, source, err := gen.Template(name)
+		if err != nil {
+			output.Errorf("❌ Error reading template %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		if templatesEject && source != generator.SourceProject {
+			if source != generator.SourceParent {
+				text, err = generator.BuiltinTemplate(name)
+			}
+			if err == nil {
+				dir := filepath.Join(cwd, filepath.FromSlash(generator.TemplateDir))
+				if err = os.MkdirAll(dir, 0755); err == nil {
+					err = os.WriteFile(filepath.Join(dir, name), []byte(text), 0644)
+				}
+			}
+			if err != nil {
+				output.Errorf("❌ Error ejecting %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			ejected = append(ejected, name)
+			source = generator.SourceProject
+		}
+		statuses = append(statuses, templateStatus{Name: name, Source: source})
+	}
+
+	if output.IsJSON() {
+		printJSON(map[string]interface{}{"templates": statuses, "ejected": len(ejected)})
+		return
+	}
+	if templatesEject {
+		output.Printf("✅ Ejected %d template(s) to %s/\n", len(ejected), generator.TemplateDir)
+		output.Println()
+	}
+	output.Println("📄 Templates:")
+	for _, st := range statuses {
+		output.Printf("   %-36s %s\n", st.Name, st.Source)
+	}
+	if len(ejected) > 0 {
+		output.Info()
+		output.Info("💡 Edit them, then run 'contextpilot sync' to regenerate the context files")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+	templatesCmd.Flags().BoolVar(&templatesEject, "eject", false, "Copy the built-in templates into .contextpilot/templates to customize them")
+}
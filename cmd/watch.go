@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/output"
+	"github.com/jitin-nhz/contextpilot/internal/watch"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var watchDebounce time.Duration
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keep context files fresh as the code changes",
+	Long: `Watch the project and regenerate context files whenever a change
+affects them, so context never drifts from the code.
+
+Changes are debounced, and only additions, removals and renames, or edits to
+files the analysis reads (package.json, go.mod, decisions, config), trigger
+a re-analysis. Context files are rewritten only when their content changes.
+
+Examples:
+  contextpilot watch
+  contextpilot watch --debounce 5s`,
+	Run: runWatch,
+}
+
+func runWatch(cmd *cobra.Command, args []string) {
+	cwd, err := projectDir()
+	if err != nil {
+		output.Errorf("❌ Error getting current directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !config.Exists(cwd) {
+		output.Errorf("❌ ContextPilot not initialized in this directory\n")
+		output.Info()
+		output.Info("Run 'contextpilot init' first to generate context files.")
+		os.Exit(1)
+	}
+
+	var contentDirs []string
+	if cfg, err := config.Load(cwd); err == nil && cfg.Decisions.Backend == "madr" {
+		dir := cfg.Decisions.Dir
+		if dir == "" {
+			dir = decisions.DefaultADRDir
+		}
+		contentDirs = append(contentDirs, dir)
+	}
+
+	w, err := watch.New(cwd, watch.Options{
+		Debounce:    watchDebounce,
+		SkipDir:     analyzer.New(cwd).Ignores,
+		Ignore:      generator.ContextFiles,
+		ContentDirs: contentDirs,
+	})
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	defer w.Close()
+
+	// Catch up on anything that changed while we weren't watching
+	refreshContext(cwd, "start")
+
+	output.Infof("👀 Watching %d directories for changes. Press Ctrl+C to stop.\n", w.Dirs())
+
+	stop := make(chan struct{})
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		close(stop)
+	}()
+
+	err = w.Run(stop, func(c watch.Change) {
+		if !c.NeedsAnalysis {
+			return
+		}
+		refreshContext(cwd, describeChange(c.Paths))
+	})
+	if err != nil {
+		output.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	output.Info("👋 Stopped watching")
+}
+
+// refreshContext re-analyzes the project and rewrites the context files
+// that are out of date, logging one line when something changed
+func refreshContext(cwd, reason string) {
+	analysis, err := analyzer.New(cwd).Analyze()
+	if err != nil {
+		output.Errorf("⚠️  %s analysis failed: %v\n", time.Now().Format("15:04:05"), err)
+		return
+	}
+	sort.Slice(analysis.Languages, func(i, j int) bool {
+		return analysis.Languages[i].FileCount > analysis.Languages[j].FileCount
+	})
+
+	gen := generator.New(analysis, cwd)
+	stale := gen.Stale()
+	if len(stale) == 0 {
+		return
+	}
+	if err := gen.GenerateAll(); err != nil {
+		output.Errorf("⚠️  %s regeneration failed: %v\n", time.Now().Format("15:04:05"), err)
+		return
+	}
+	output.Infof("🔄 %s %s → updated %s\n", time.Now().Format("15:04:05"), reason, strings.Join(stale, ", "))
+}
+
+// describeChange summarizes changed paths for the log line
+func describeChange(paths []string) string {
+	switch len(paths) {
+	case 0:
+		return "change"
+	case 1:
+		return paths[0]
+	case 2, 3:
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:2], ", "), len(paths)-2)
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", watch.DefaultDebounce, "Quiet period before changes are processed")
+}
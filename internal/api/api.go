@@ -0,0 +1,86 @@
+// Package api versions ContextPilot's machine-readable outputs: JSON
+// printed by CLI commands and the data exchanged over MCP.
+//
+// Compatibility policy:
+//
+//   - Within an API version, fields are only ever added. Existing fields
+//     keep their name, type and meaning.
+//   - Removing, renaming or retyping a field requires a new API version.
+//   - A release that introduces version N+1 keeps emitting version N when
+//     asked (--api-version N) for at least the next two minor releases.
+//   - Every JSON document carries "apiVersion" so consumers can check
+//     what they received.
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Current is the newest API version this build produces
+const Current = 1
+
+// Supported lists the API versions this build can produce, oldest first
+var Supported = []int{1}
+
+var selected = Current
+
+// Select sets the API version used for output. 0 selects Current.
+func Select(v int) error {
+	if v == 0 {
+		selected = Current
+		return nil
+	}
+	if !IsSupported(v) {
+		return fmt.Errorf("unsupported API version %d (supported: %s)", v, supportedList())
+	}
+	selected = v
+	return nil
+}
+
+// Selected returns the API version used for output
+func Selected() int {
+	return selected
+}
+
+// IsSupported reports whether this build can produce version v
+func IsSupported(v int) bool {
+	for _, s := range Supported {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate picks the version to use with a peer that asked for requested:
+// requested itself if supported, otherwise the newest supported version
+// below it, otherwise the oldest supported version. 0 means no preference
+// and yields the selected version.
+func Negotiate(requested int) int {
+	if requested == 0 {
+		return selected
+	}
+	if IsSupported(requested) {
+		return requested
+	}
+	best := 0
+	for _, s := range Supported {
+		if s < requested && s > best {
+			best = s
+		}
+	}
+	if best == 0 {
+		return Supported[0]
+	}
+	return best
+}
+
+func supportedList() string {
+	parts := make([]string, len(Supported))
+	for i, v := range Supported {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ", ")
+}
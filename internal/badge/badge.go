@@ -0,0 +1,109 @@
+// Package badge renders the context quality score as a README badge: a
+// static SVG and a shields.io endpoint file.
+package badge
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+)
+
+// Label is the text on the left of the badge
+const Label = "context"
+
+// File names written by Write
+const (
+	SVGFile      = "badge.svg"
+	EndpointFile = "badge.json"
+)
+
+// Endpoint is the shields.io endpoint schema
+// (https://shields.io/badges/endpoint-badge)
+type Endpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// colors maps shields.io color names to the hex values SVG needs
+var colors = map[string]string{
+	"brightgreen": "#4c1",
+	"yellow":      "#dfb317",
+	"red":         "#e05d44",
+}
+
+// Color picks the badge color for score, matching the thresholds
+// 'contextpilot score' uses for its 🟢/🟡/🔴
+func Color(score int) string {
+	switch {
+	case score >= 75:
+		return "brightgreen"
+	case score >= 50:
+		return "yellow"
+	}
+	return "red"
+}
+
+// Message is the text on the right of the badge
+func Message(score int) string {
+	return fmt.Sprintf("%d/100", score)
+}
+
+// NewEndpoint returns the shields.io endpoint for score
+func NewEndpoint(score int) Endpoint {
+	return Endpoint{SchemaVersion: 1, Label: Label, Message: Message(score), Color: Color(score)}
+}
+
+// SVG renders a flat badge for score
+func SVG(score int) string {
+	label, message := Label, Message(score)
+	lw, mw := textWidth(label), textWidth(message)
+	w := lw + mw
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">
+<title>%s: %s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>
+<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>
+</g>
+</svg>
+`,
+		w, html.EscapeString(label), html.EscapeString(message),
+		html.EscapeString(label), html.EscapeString(message),
+		w, lw, lw, mw, colors[Color(score)], w,
+		lw/2, html.EscapeString(label), lw/2, html.EscapeString(label),
+		lw+mw/2, html.EscapeString(message), lw+mw/2, html.EscapeString(message))
+}
+
+// textWidth approximates the width of s in 11px Verdana, plus padding
+func textWidth(s string) int {
+	return len([]rune(s))*7 + 10
+}
+
+// Write writes the SVG badge and the endpoint file into dir, returning
+// their paths
+func Write(dir string, score int) (svgPath, endpointPath string, err error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	svgPath = filepath.Join(dir, SVGFile)
+	if err := os.WriteFile(svgPath, []byte(SVG(score)), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write badge: %w", err)
+	}
+
+	data, err := json.MarshalIndent(NewEndpoint(score), "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode badge endpoint: %w", err)
+	}
+	endpointPath = filepath.Join(dir, EndpointFile)
+	if err := os.WriteFile(endpointPath, append(data, '\n'), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write badge endpoint: %w", err)
+	}
+	return svgPath, endpointPath, nil
+}
@@ -0,0 +1,163 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileName is the changelog kept at the repository root
+const FileName = "CHANGELOG.md"
+
+const (
+	header     = "# Changelog\n"
+	unreleased = "## [Unreleased]"
+)
+
+// AddUnreleased appends entry as a bullet to the "## [Unreleased]" section
+// of CHANGELOG.md in root, creating the file or section if needed
+func AddUnreleased(root, entry string) error {
+	path := filepath.Join(root, FileName)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+
+	content := string(data)
+	if strings.TrimSpace(content) == "" {
+		content = header + "\n"
+	}
+
+	bullet := "- " + entry + "\n"
+	lines := strings.SplitAfter(content, "\n")
+	idx := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == unreleased {
+			idx = i
+			break
+		}
+	}
+
+	if idx == -1 {
+		// Insert a new section before the first release heading, or at the end
+		insert := len(lines)
+		for i, line := range lines {
+			if strings.HasPrefix(line, "## ") {
+				insert = i
+				break
+			}
+		}
+		section := []string{unreleased + "\n", "\n", bullet, "\n"}
+		if insert == len(lines) && !strings.HasSuffix(content, "\n\n") {
+			section = append([]string{"\n"}, section...)
+		}
+		lines = append(lines[:insert], append(section, lines[insert:]...)...)
+	} else {
+		// Append after the last bullet of the section
+		insert := idx + 1
+		for i := idx + 1; i < len(lines); i++ {
+			if strings.HasPrefix(lines[i], "## ") {
+				break
+			}
+			if strings.HasPrefix(lines[i], "- ") || strings.HasPrefix(lines[i], "* ") {
+				insert = i + 1
+			}
+		}
+		if insert == idx+1 {
+			lines = append(lines[:insert], append([]string{"\n", bullet}, lines[insert:]...)...)
+		} else {
+			lines = append(lines[:insert], append([]string{bullet}, lines[insert:]...)...)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "")), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", FileName, err)
+	}
+	return nil
+}
+
+// Entry is one line of a drafted release section
+type Entry struct {
+	Tag  string `json:"tag"`
+	Text string `json:"text"`
+}
+
+// Other heads the entries that carry no tag
+const Other = "Other"
+
+// Section renders entries as a "## [title] - date" section with one
+// "### Tag" heading per tag, sorted, and untagged entries last
+func Section(title, date string, entries []Entry) string {
+	byTag := map[string][]string{}
+	for _, e := range entries {
+		tag := e.Tag
+		if tag == "" {
+			tag = Other
+		}
+		byTag[tag] = append(byTag[tag], e.Text)
+	}
+	tags := make([]string, 0, len(byTag))
+	for t := range byTag {
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if (tags[i] == Other) != (tags[j] == Other) {
+			return tags[j] == Other
+		}
+		return tags[i] < tags[j]
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## [%s] - %s\n", title, date)
+	for _, t := range tags {
+		fmt.Fprintf(&b, "\n### %s\n\n", t)
+		for _, text := range byTag[t] {
+			b.WriteString("- " + text + "\n")
+		}
+	}
+	return b.String()
+}
+
+// Insert adds section to CHANGELOG.md in root, above the newest release
+// and below "## [Unreleased]", creating the file if needed. It fails if a
+// section with the same heading is already there.
+func Insert(root, section string) error {
+	path := filepath.Join(root, FileName)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+
+	content := string(data)
+	if strings.TrimSpace(content) == "" {
+		content = header + "\n"
+	}
+	heading, _, _ := strings.Cut(section, " - ")
+
+	lines := strings.SplitAfter(content, "\n")
+	insert := len(lines)
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if h, _, _ := strings.Cut(strings.TrimSpace(line), " - "); h == heading {
+			return fmt.Errorf("%s already has a %s section", FileName, strings.TrimPrefix(heading, "## "))
+		}
+		if strings.TrimSpace(line) != unreleased && insert == len(lines) {
+			insert = i
+		}
+	}
+
+	block := section + "\n"
+	if insert == len(lines) && !strings.HasSuffix(content, "\n\n") {
+		block = "\n" + block
+	}
+	lines = append(lines[:insert], append([]string{block}, lines[insert:]...)...)
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "")), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", FileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,591 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config.yaml format written by this release. Older
+// files are upgraded by 'contextpilot migrate'.
+const CurrentVersion = 1
+
+// Config mirrors .contextpilot/config.yaml
+type Config struct {
+	Version   int       `yaml:"version"`
+	LastSync  time.Time `yaml:"lastSync"`
+	Outputs   []string  `yaml:"outputs"`
+	Ignore    []string  `yaml:"ignore"`
+	Structure string    `yaml:"structure"` // "monorepo" or "single"; empty auto-detects
+	History   History   `yaml:"history"`
+	MCP       MCP       `yaml:"mcp"`
+	Decisions Decisions `yaml:"decisions"`
+	Check     Check     `yaml:"check"`
+	Hooks     Hooks     `yaml:"hooks"`
+	Score     Score     `yaml:"score"`
+	Logging   Logging   `yaml:"logging"`
+	// Storage is where sessions and their history are kept (see
+	// StorageFiles and StorageSQLite); empty means files
+	Storage string `yaml:"storage"`
+	// AnalysisHash is the analysis the context files were last synced
+	// with (see analyzer.Analysis.Hash)
+	AnalysisHash string `yaml:"analysisHash"`
+	// Plugins are run during analysis and generation: names of
+	// contextpilot-plugin-<name> executables on PATH, or absolute paths
+	Plugins []string `yaml:"plugins"`
+	// Instructions are extra rules for the context files, keyed by tool
+	// (see InstructionTools)
+	Instructions map[string][]string `yaml:"instructions"`
+	// CustomContext is the older spelling of instructions.all
+	CustomContext []string `yaml:"customContext"`
+	// RecentChanges summarizes recent commits in the context files
+	RecentChanges RecentChanges `yaml:"recentChanges"`
+	// LLM is the model 'enrich' and 'decision mine --llm' call, overriding
+	// the one in the user config
+	LLM LLM `yaml:"llm"`
+	// Agents configures AGENTS.md
+	Agents Agents `yaml:"agents"`
+	// Redact keeps details out of the generated files and prompts
+	Redact Redact `yaml:"redact"`
+}
+
+// Redact lists what must never appear in the context files and prompts,
+// for companies whose context gets pasted into third-party AI tools.
+// Whatever a rule matches is replaced with Replacement.
+type Redact struct {
+	// Patterns are regular expressions, e.g. ticket IDs or customer names
+	Patterns []string `yaml:"patterns"`
+	// Hostnames redacts internal hostnames: hosts under .internal, .corp,
+	// .lan, .intranet and .home.arpa, and private IPv4 addresses
+	Hostnames bool `yaml:"hostnames"`
+	// Domains are the company's own domains, e.g. acme.io, redacted with
+	// every host under them
+	Domains []string `yaml:"domains"`
+	// Dependencies, when set, allows only these dependencies to be named:
+	// names or globs such as @types/*; every other dependency is redacted
+	Dependencies []string `yaml:"dependencies"`
+	// Replacement defaults to DefaultReplacement
+	Replacement string `yaml:"replacement"`
+}
+
+// DefaultReplacement stands in for redacted text
+const DefaultReplacement = "[redacted]"
+
+// Enabled reports whether any rule is set
+func (r Redact) Enabled() bool {
+	return len(r.Patterns) > 0 || r.Hostnames || len(r.Domains) > 0 || len(r.Dependencies) > 0
+}
+
+// Validate reports patterns that don't compile
+func (r Redact) Validate() error {
+	for _, p := range r.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("redact pattern %q: %w", p, err)
+		}
+	}
+	for _, d := range r.Dependencies {
+		if _, err := path.Match(d, ""); err != nil {
+			return fmt.Errorf("redact dependency %q: %w", d, err)
+		}
+	}
+	return nil
+}
+
+// Agents configures AGENTS.md, the file Codex CLI and Amp read
+type Agents struct {
+	// Nested also writes an AGENTS.md into every monorepo package, which
+	// those tools read on top of the root one when working inside it
+	Nested bool `yaml:"nested"`
+}
+
+// LLM configures the language model that LLM-assisted commands call. Off
+// unless Provider is set; the API key is read from the environment.
+type LLM struct {
+	// Provider is "openai" (or any OpenAI-compatible server), "anthropic"
+	// or "ollama"
+	Provider string `yaml:"provider"`
+	// Model defaults per provider
+	Model string `yaml:"model"`
+	// URL is the API base URL, e.g. https://api.openai.com/v1, with
+	// $VARIABLES expanded; it defaults per provider
+	URL string `yaml:"url"`
+	// APIKeyEnv names the variable holding the API key (default
+	// OPENAI_API_KEY or ANTHROPIC_API_KEY; Ollama needs none)
+	APIKeyEnv string `yaml:"apiKeyEnv"`
+}
+
+// DefaultRecentChanges is how many commits recentChanges summarizes when
+// it sets no count
+const DefaultRecentChanges = 10
+
+// RecentChanges configures the "Recent changes" section of the context
+// files
+type RecentChanges struct {
+	Enabled bool `yaml:"enabled"`
+	// Count is how many commits on the current branch to summarize
+	Count int `yaml:"count"`
+}
+
+// Commits returns how many commits to summarize, 0 when the section is off
+func (r RecentChanges) Commits() int {
+	switch {
+	case !r.Enabled:
+		return 0
+	case r.Count > 0:
+		return r.Count
+	default:
+		return DefaultRecentChanges
+	}
+}
+
+// InstructionTools are the keys instructions accepts: "all" for every
+// context file, or the tool whose file alone gets the rules
+var InstructionTools = []string{"all", "cursor", "claude", "copilot", "gemini", "agents"}
+
+// InstructionsFor returns the extra rules for tool's context file: those
+// for all tools first, then its own
+func (c *Config) InstructionsFor(tool string) []string {
+	var rules []string
+	for _, r := range append(append(c.CustomContext, c.Instructions["all"]...), c.Instructions[tool]...) {
+		if r = strings.TrimSpace(r); r != "" {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// Logging configures the project's log file
+type Logging struct {
+	// File keeps a debug log of every run in .contextpilot/logs/, rotated
+	// as it grows
+	File bool `yaml:"file"`
+}
+
+// Score configures how 'contextpilot score' weighs its categories and
+// which project-specific rules it adds
+type Score struct {
+	Weights ScoreWeights `yaml:"weights"`
+	Rules   []ScoreRule  `yaml:"rules"`
+}
+
+// ScoreWeights sets the points each built-in category is worth. Unset
+// weights keep the defaults (40/30/30/20); 0 leaves a category out.
+type ScoreWeights struct {
+	Completeness *int `yaml:"completeness"`
+	Freshness    *int `yaml:"freshness"`
+	Decisions    *int `yaml:"decisions"`
+	Specificity  *int `yaml:"specificity"`
+}
+
+// Default category weights
+const (
+	DefaultCompletenessWeight = 40
+	DefaultFreshnessWeight    = 30
+	DefaultDecisionsWeight    = 30
+	DefaultSpecificityWeight  = 20
+	DefaultRulePoints         = 10
+)
+
+// Resolve returns the effective weights
+func (w ScoreWeights) Resolve() (completeness, freshness, decisions, specificity int) {
+	pick := func(v *int, def int) int {
+		if v == nil {
+			return def
+		}
+		return *v
+	}
+	return pick(w.Completeness, DefaultCompletenessWeight),
+		pick(w.Freshness, DefaultFreshnessWeight),
+		pick(w.Decisions, DefaultDecisionsWeight),
+		pick(w.Specificity, DefaultSpecificityWeight)
+}
+
+// ScoreRule is a custom check worth Points (DefaultRulePoints if unset).
+// It passes when every condition it sets holds: at least MinDecisions
+// active decisions, File existing, File containing Contains (ignoring
+// case), File matching the regular expression Matches.
+type ScoreRule struct {
+	Name         string `yaml:"name"`
+	Points       int    `yaml:"points"`
+	MinDecisions int    `yaml:"minDecisions"`
+	File         string `yaml:"file"`
+	Contains     string `yaml:"contains"`
+	Matches      string `yaml:"matches"`
+}
+
+// Validate reports rules that can never be evaluated
+func (r ScoreRule) Validate() error {
+	if r.MinDecisions == 0 && r.File == "" {
+		return fmt.Errorf("score rule %q checks nothing (set minDecisions or file)", r.Label())
+	}
+	if (r.Contains != "" || r.Matches != "") && r.File == "" {
+		return fmt.Errorf("score rule %q sets contains/matches without a file", r.Label())
+	}
+	if r.Matches != "" {
+		if _, err := regexp.Compile(r.Matches); err != nil {
+			return fmt.Errorf("score rule %q: invalid matches pattern: %w", r.Label(), err)
+		}
+	}
+	return nil
+}
+
+// Label names the rule in output, falling back to a description of it
+func (r ScoreRule) Label() string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Contains != "":
+		return fmt.Sprintf("%s mentions %s", r.File, r.Contains)
+	case r.Matches != "":
+		return fmt.Sprintf("%s matches %s", r.File, r.Matches)
+	case r.File != "":
+		return r.File + " exists"
+	case r.MinDecisions > 0:
+		return fmt.Sprintf("%d+ decisions", r.MinDecisions)
+	}
+	return "unnamed"
+}
+
+// MaxPoints returns what the rule is worth
+func (r ScoreRule) MaxPoints() int {
+	if r.Points == 0 {
+		return DefaultRulePoints
+	}
+	return r.Points
+}
+
+// Check configures 'contextpilot check'
+type Check struct {
+	// MinScore is the lowest passing context quality score; 0 keeps
+	// DefaultMinScore
+	MinScore int `yaml:"minScore"`
+}
+
+// DefaultMinScore is the score 'contextpilot check' requires by default
+const DefaultMinScore = 50
+
+// Hooks configures the git hooks 'contextpilot hooks install' adds
+type Hooks struct {
+	// PrePush is what the pre-push hook does when dependencies or
+	// top-level folders changed since the last sync: "block" (the
+	// default) or "warn"
+	PrePush string `yaml:"prePush"`
+}
+
+// BlockPush reports whether the pre-push hook stops the push
+func (h Hooks) BlockPush() bool {
+	return h.PrePush != "warn"
+}
+
+// Storage options for sessions and their history
+const (
+	// StorageFiles keeps one JSON file per session and history.jsonl in
+	// .contextpilot/sessions
+	StorageFiles = "files"
+	// StorageSQLite keeps them in .contextpilot/contextpilot.db
+	StorageSQLite = "sqlite"
+)
+
+// Decisions configures where architectural decisions are stored
+type Decisions struct {
+	// Backend is "markdown" (.contextpilot/decisions.json rendered to
+	// decisions.md, the default), "madr" (one docs/adr/NNNN-title.md file
+	// per decision) or "sqlite" (.contextpilot/contextpilot.db)
+	Backend string `yaml:"backend"`
+	// Dir is the ADR directory for the madr backend (default docs/adr)
+	Dir string `yaml:"dir"`
+	// Template is an optional text/template file for new ADRs
+	Template string `yaml:"template"`
+}
+
+// MCP configures optional MCP server features
+type MCP struct {
+	// Sampling lets the server ask the client's model to summarize sessions
+	// and draft decisions. Off by default since it spends the user's tokens.
+	Sampling bool `yaml:"sampling"`
+	// Timeout bounds each tool call's analysis and file work, e.g. "30s";
+	// "0" disables it and empty keeps DefaultMCPTimeout
+	Timeout string `yaml:"timeout"`
+}
+
+// DefaultMCPTimeout is how long an MCP tool call may run when config.yaml
+// doesn't say
+const DefaultMCPTimeout = 2 * time.Minute
+
+// ToolTimeout returns the effective MCP tool timeout, 0 for none. An
+// invalid value keeps the default ('contextpilot doctor' reports it).
+func (c *Config) ToolTimeout() time.Duration {
+	if c.MCP.Timeout == "" {
+		return DefaultMCPTimeout
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(c.MCP.Timeout))
+	if err != nil || d < 0 {
+		return DefaultMCPTimeout
+	}
+	return d
+}
+
+// History controls session history retention
+type History struct {
+	MaxEntries int    `yaml:"maxEntries"` // 0 keeps the default
+	MaxAge     string `yaml:"maxAge"`     // e.g. "90d", "720h"; empty keeps the default
+}
+
+// Default retention applied when config.yaml doesn't override it
+const (
+	DefaultHistoryMaxEntries = 1000
+	DefaultHistoryMaxAge     = "180d"
+)
+
+// Retention returns the effective history limits
+func (c *Config) Retention() (maxEntries int, maxAge time.Duration) {
+	maxEntries = c.History.MaxEntries
+	if maxEntries == 0 {
+		maxEntries = DefaultHistoryMaxEntries
+	}
+	age := c.History.MaxAge
+	if age == "" {
+		age = DefaultHistoryMaxAge
+	}
+	maxAge, _ = ParseAge(age)
+	return maxEntries, maxAge
+}
+
+// ParseAge parses a duration that may use a "d" (days) or "w" (weeks)
+// suffix in addition to Go duration units. "0" disables the limit.
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(s, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+			if err != nil {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// Path returns the config file location for a project root
+func Path(rootPath string) string {
+	return filepath.Join(rootPath, filepath.FromSlash(File))
+}
+
+// File is the config's slash-separated path within a project
+const File = ".contextpilot/config.yaml"
+
+// Exists reports whether the project has been initialized
+func Exists(rootPath string) bool {
+	_, err := os.Stat(Path(rootPath))
+	return err == nil
+}
+
+// Root returns the project dir belongs to: the nearest directory from dir
+// up to the top of its git repository that has a config.yaml, or dir
+// itself when none has
+func Root(dir string) string {
+	if Exists(dir) {
+		return dir
+	}
+	if parent := Parent(dir); parent != "" {
+		return parent
+	}
+	return dir
+}
+
+// Parent returns the nearest directory above dir that has a config.yaml,
+// e.g. the monorepo root above a package with a config.yaml of its own,
+// or "" if there is none. The search stops at the top of the git
+// repository dir is in, so a repository cloned inside another project
+// isn't mistaken for one of its packages.
+func Parent(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	var top os.FileInfo
+	if path, err := git.Output(abs, "rev-parse", "--show-toplevel"); err == nil {
+		top, _ = os.Stat(path)
+	}
+	for {
+		if top != nil {
+			if info, err := os.Stat(abs); err == nil && os.SameFile(info, top) {
+				return ""
+			}
+		}
+		up := filepath.Dir(abs)
+		if up == abs {
+			return ""
+		}
+		if abs = up; Exists(abs) {
+			return abs
+		}
+	}
+}
+
+// Load reads the project config. A missing file yields an empty Config.
+func Load(rootPath string) (*Config, error) {
+	return LoadFS(fsys.OS(rootPath))
+}
+
+// LoadFS is Load for a project in fsys. A project on disk below another
+// one, like a monorepo package with a config.yaml of its own, inherits
+// every setting of the config.yaml above it that its own doesn't set
+// (see inherited); lists are replaced, not appended to.
+func LoadFS(files fs.FS) (*Config, error) {
+	cfg := &Config{}
+	if dir := fsys.Dir(files); dir != "" {
+		if parent := Parent(dir); parent != "" {
+			base, err := Load(parent)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", Path(parent), err)
+			}
+			cfg = base.inherited()
+		}
+	}
+	data, err := fs.ReadFile(files, File)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return cfg, nil
+}
+
+// inherited returns the settings a package's config.yaml starts from:
+// c's, less those about c's own files
+func (c *Config) inherited() *Config {
+	cfg := *c
+	cfg.Version, cfg.LastSync, cfg.AnalysisHash, cfg.Structure = 0, time.Time{}, "", ""
+	return &cfg
+}
+
+var (
+	lastSyncLine  = regexp.MustCompile(`(?m)^lastSync:.*$`)
+	hashLine      = regexp.MustCompile(`(?m)^analysisHash:.*$`)
+	versionLine   = regexp.MustCompile(`(?m)^version:.*$`)
+	structureLine = regexp.MustCompile(`(?m)^structure:.*$`)
+	outputsBlock  = regexp.MustCompile(`(?m)^outputs:[ \t]*\n(?:[ \t]+-.*\n?)*`)
+)
+
+// SetOutputs replaces the outputs list in an existing config.yaml, leaving
+// the rest of the file untouched
+func SetOutputs(rootPath string, outputs []string) error {
+	path := Path(rootPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	block := "outputs:\n"
+	for _, o := range outputs {
+		block += "  - " + o + "\n"
+	}
+	content := string(data)
+	if outputsBlock.MatchString(content) {
+		content = outputsBlock.ReplaceAllLiteralString(content, block)
+	} else {
+		content = strings.TrimRight(content, "\n") + "\n\n" + block
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// SetStructure sets structure in an existing config.yaml, leaving the rest
+// of the file untouched
+func SetStructure(rootPath, structure string) error {
+	path := Path(rootPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	line := "structure: " + structure
+	content := string(data)
+	if structureLine.MatchString(content) {
+		content = structureLine.ReplaceAllLiteralString(content, line)
+	} else {
+		content = strings.TrimRight(content, "\n") + "\n\n# Repository layout, overriding detection (monorepo or single)\n" + line + "\n"
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// SetVersion sets version in an existing config.yaml, leaving the rest of
+// the file untouched. A missing version line is added at the top, after
+// any leading comments.
+func SetVersion(rootPath string, v int) error {
+	path := Path(rootPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	line := fmt.Sprintf("version: %d", v)
+	content := string(data)
+	if versionLine.MatchString(content) {
+		content = versionLine.ReplaceAllLiteralString(content, line)
+	} else {
+		lines := strings.SplitAfter(content, "\n")
+		i := 0
+		for i < len(lines) && (strings.HasPrefix(lines[i], "#") || strings.TrimSpace(lines[i]) == "") {
+			i++
+		}
+		content = strings.Join(lines[:i], "") + line + "\n" + strings.Join(lines[i:], "")
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// TouchLastSync sets lastSync in an existing config.yaml, and analysisHash
+// unless hash is empty, leaving the rest of the file (including user
+// settings and comments) untouched
+func TouchLastSync(rootPath string, t time.Time, hash string) error {
+	return TouchLastSyncFS(fsys.OS(rootPath), t, hash)
+}
+
+// TouchLastSyncFS is TouchLastSync for a project in fsys
+func TouchLastSyncFS(files fsys.WriteFS, t time.Time, hash string) error {
+	data, err := fs.ReadFile(files, File)
+	if err != nil {
+		return err
+	}
+
+	line := "lastSync: " + t.Format(time.RFC3339)
+	content := string(data)
+	if lastSyncLine.MatchString(content) {
+		content = lastSyncLine.ReplaceAllLiteralString(content, line)
+	} else {
+		content = strings.TrimRight(content, "\n") + "\n" + line + "\n"
+	}
+	if hash != "" {
+		// Right below lastSync, where the template puts it
+		if hashLine.MatchString(content) {
+			content = hashLine.ReplaceAllLiteralString(content, "analysisHash: "+hash)
+		} else {
+			content = strings.Replace(content, line, line+"\nanalysisHash: "+hash, 1)
+		}
+	}
+	return files.WriteFile(File, []byte(content), 0644)
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// OfflineEnv turns on offline mode when set to a true value, like
+// offline: true in the user config
+const OfflineEnv = "CONTEXTPILOT_OFFLINE"
+
+// Offline reports whether offline mode is on. ContextPilot never makes a
+// network request unless a command is explicitly run or configured to,
+// such as self-update, the update notice, a session remote or an LLM; in
+// offline mode even those are refused.
+func Offline() bool {
+	if v := os.Getenv(OfflineEnv); v != "" {
+		on, err := strconv.ParseBool(v)
+		return err != nil || on
+	}
+	user, err := LoadUser()
+	return err == nil && user.Offline
+}
+
+// CheckOnline returns an error when offline mode is on and rawURL is on
+// another machine; file:// URLs and loopback hosts such as a local Ollama
+// are always allowed
+func CheckOnline(rawURL string) error {
+	if !Offline() {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("offline mode is on (%s or offline: true in the user config): not connecting to %s", OfflineEnv, host)
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// User mirrors the per-user config, ~/.config/contextpilot/config.yaml
+// (see UserPath). It holds settings that follow a person rather than a
+// project, and secrets never go in it: they are read from the environment.
+type User struct {
+	Sessions UserSessions `yaml:"sessions"`
+	// LLM is the model LLM-assisted commands use in every project that
+	// doesn't configure its own
+	LLM LLM `yaml:"llm"`
+	// Offline refuses every network request (see Offline)
+	Offline bool `yaml:"offline"`
+	// Updates configures 'self-update' and the new-version notice
+	Updates Updates `yaml:"updates"`
+}
+
+// Updates picks the release channel and whether commands mention newer
+// releases
+type Updates struct {
+	// Channel is "stable" (the default) or "beta", which also gets
+	// pre-releases
+	Channel string `yaml:"channel"`
+	// Notify shows a once-a-day notice when a newer release is out. It
+	// is off by default, as looking is a network request.
+	Notify bool `yaml:"notify"`
+}
+
+// UpdateChannel returns the configured release channel
+func (u *User) UpdateChannel() string {
+	if u.Updates.Channel == "" {
+		return "stable"
+	}
+	return u.Updates.Channel
+}
+
+// UserSessions configures where sessions are backed up
+type UserSessions struct {
+	Remote Remote `yaml:"remote"`
+}
+
+// Remote is the backend 'sessions push' and 'sessions pull' use. URL picks
+// the kind: https:// or http:// (any server accepting GET and PUT, such as
+// WebDAV), s3://bucket/prefix (AWS or an S3-compatible service at
+// Endpoint), or file:///path (e.g. a synced folder).
+type Remote struct {
+	URL string `yaml:"url"`
+
+	// HTTP: basic auth as Username with the password in PasswordEnv, else
+	// a bearer token from TokenEnv when that variable is set
+	Username    string `yaml:"username"`
+	PasswordEnv string `yaml:"passwordEnv"`
+	TokenEnv    string `yaml:"tokenEnv"`
+
+	// S3: Endpoint defaults to AWS in Region; credentials come from
+	// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+	Endpoint string `yaml:"endpoint"`
+	Region   string `yaml:"region"`
+}
+
+// Default environment variables for remote credentials
+const (
+	DefaultPasswordEnv = "CONTEXTPILOT_REMOTE_PASSWORD"
+	DefaultTokenEnv    = "CONTEXTPILOT_REMOTE_TOKEN"
+	DefaultRegion      = "us-east-1"
+)
+
+// UserPath returns the per-user config location
+func UserPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user config directory: %w", err)
+	}
+	return filepath.Join(dir, "contextpilot", "config.yaml"), nil
+}
+
+// LoadUser reads the per-user config. A missing file yields an empty User.
+func LoadUser() (*User, error) {
+	cfg := &User{}
+	path, err := UserPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read user config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
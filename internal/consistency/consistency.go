@@ -0,0 +1,365 @@
+// Package consistency checks that the context files for different tools
+// still tell an assistant the same things: the same stack, conventions
+// and decisions. Each file is compared with what ContextPilot would
+// generate for it, so what differs is what someone changed by hand.
+package consistency
+
+import (
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/drift"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+)
+
+// Targets are the context files compared, in report order
+var Targets = []string{".cursorrules", "CLAUDE.md", "GEMINI.md", generator.AgentsFile, ".github/copilot-instructions.md"}
+
+// templates are the templates the Targets are rendered from
+var templates = map[string]string{
+	".cursorrules":                    generator.CursorTemplate,
+	"CLAUDE.md":                       generator.ClaudeTemplate,
+	"GEMINI.md":                       generator.GeminiTemplate,
+	generator.AgentsFile:              generator.AgentsTemplate,
+	".github/copilot-instructions.md": generator.CopilotTemplate,
+}
+
+// marker is in every file ContextPilot generates
+const marker = "Generated by ContextPilot"
+
+// Kinds of fact, and of the section a line is in
+const (
+	KindStack      = "stack"
+	KindConvention = "convention"
+	KindDecision   = "decision"
+	KindOther      = "other"
+)
+
+// Fact is one thing the context files should agree on
+type Fact struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+// Missing is a fact a file doesn't state although its generated version
+// would, and other files do
+type Missing struct {
+	Fact
+	// In are the files that state it
+	In []string `json:"in"`
+}
+
+// Line is a line of a file that neither its generated version nor any
+// other file says
+type Line struct {
+	// Kind is the section it's in
+	Kind string `json:"kind"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// File is how one context file compares with the others
+type File struct {
+	Path string `json:"path"`
+	// Generated is false for a file written by hand from scratch, which
+	// is only checked for missing facts
+	Generated bool      `json:"generated"`
+	Drifted   bool      `json:"drifted"`
+	Missing   []Missing `json:"missing"`
+	Added     []Line    `json:"added"`
+}
+
+// Report compares the context files of a project
+type Report struct {
+	Files      []File `json:"files"`
+	Consistent bool   `json:"consistent"`
+}
+
+// Drifted returns the files that differ from the others
+func (r Report) Drifted() []string {
+	var drifted []string
+	for _, f := range r.Files {
+		if f.Drifted {
+			drifted = append(drifted, f.Path)
+		}
+	}
+	return drifted
+}
+
+// Project is what the context files of a project are compared with
+type Project struct {
+	// Preview is what ContextPilot would write for each file (see
+	// generator.Preview)
+	Preview map[string]string
+	// Templates are the template text of each file. Their words, like
+	// headings and labels, never make a line distinctive.
+	Templates map[string]string
+	// Baseline names the stack recorded at the last sync. Its names don't
+	// make a line distinctive either, so what that sync wrote before the
+	// stack changed isn't mistaken for a hand edit.
+	Baseline string
+	// Facts are what the files should agree on (see Facts)
+	Facts []Fact
+}
+
+// Load gathers what the context files of the project at root are
+// compared with, for analysis
+func Load(root string, analysis *analyzer.Analysis) (Project, error) {
+	decs, err := decisions.New(root).List()
+	if err != nil {
+		return Project{}, err
+	}
+	gen := generator.New(analysis, root)
+	p := Project{Preview: gen.Preview(), Templates: map[string]string{}, Facts: Facts(analysis, decs)}
+	for file, name := range templates {
+		if text, _, err := gen.Template(name); err == nil {
+			p.Templates[file] = text
+		}
+	}
+	if snap, ok, err := drift.Load(root); err == nil && ok {
+		names := append(append([]string{snap.Framework}, snap.Dependencies...), snap.Folders...)
+		for _, tool := range snap.Tools {
+			names = append(names, tool)
+		}
+		sort.Strings(names)
+		p.Baseline = strings.Join(names, "\n")
+	}
+	return p, nil
+}
+
+// Facts lists what the context files say about a project: its stack and
+// conventions from the analysis, and its decisions
+func Facts(a *analyzer.Analysis, decs []decisions.Decision) []Fact {
+	var facts []Fact
+	seen := map[Fact]bool{}
+	add := func(kind, text string) {
+		f := Fact{Kind: kind, Text: strings.TrimSpace(text)}
+		if f.Text != "" && !seen[f] {
+			seen[f] = true
+			facts = append(facts, f)
+		}
+	}
+
+	if a.Framework != nil {
+		add(KindStack, a.Framework.Name)
+	}
+	for _, l := range a.Languages {
+		add(KindStack, l.Name)
+	}
+	add(KindStack, a.Packages.Manager)
+	p := a.Patterns
+	for _, tool := range []string{p.TestFramework, p.TestRunner, p.TypeChecker, p.Linter, p.Formatter, p.StateManagement} {
+		add(KindStack, tool)
+	}
+	for _, o := range p.ORMs {
+		add(KindStack, o.Name)
+	}
+	for _, s := range p.Styles {
+		add(KindStack, s.Name)
+	}
+
+	add(KindConvention, p.NamingConvention)
+	if p.Commits != nil {
+		add(KindConvention, p.Commits.Style)
+	}
+	for _, c := range p.Conventions {
+		add(KindConvention, c)
+	}
+
+	for _, d := range decs {
+		add(KindDecision, d.Text)
+	}
+	return facts
+}
+
+// target is a context file read for comparison
+type target struct {
+	path      string
+	disk      string
+	generated string
+	// known is text whose words aren't distinctive: the generated
+	// version, its template and the baseline
+	known string
+	// ours is set when the file on disk was generated
+	ours bool
+}
+
+// Compare compares the Targets present in files with each other and
+// with p.
+//
+// A file has drifted when it lacks a fact that its generated version
+// states and another file does, or has a line its generated version
+// doesn't, with words no other file uses. Changes the files share, such
+// as a stack that changed since they were all synced, are not drift.
+func Compare(files fs.FS, p Project) Report {
+	var targets []target
+	for _, path := range Targets {
+		data, err := fs.ReadFile(files, path)
+		if err != nil {
+			continue
+		}
+		disk := string(data)
+		targets = append(targets, target{
+			path:      path,
+			disk:      disk,
+			generated: p.Preview[path],
+			known:     strings.Join([]string{p.Preview[path], p.Templates[path], p.Baseline}, "\n"),
+			ours:      strings.Contains(disk, marker),
+		})
+	}
+
+	report := Report{Files: []File{}, Consistent: true}
+	for i, t := range targets {
+		f := File{Path: t.path, Generated: t.ours, Missing: []Missing{}, Added: []Line{}}
+		var others []target
+		for j, o := range targets {
+			if j != i {
+				others = append(others, o)
+			}
+		}
+
+		for _, fact := range p.Facts {
+			if !t.expects(fact) || mentions(t.disk, fact.Text) {
+				continue
+			}
+			var in []string
+			for _, o := range others {
+				if o.expects(fact) && mentions(o.disk, fact.Text) {
+					in = append(in, o.path)
+				}
+			}
+			if len(in) > 0 {
+				f.Missing = append(f.Missing, Missing{Fact: fact, In: in})
+			}
+		}
+		if t.ours {
+			f.Added = t.added(others)
+		}
+
+		f.Drifted = len(f.Missing) > 0 || len(f.Added) > 0
+		report.Consistent = report.Consistent && !f.Drifted
+		report.Files = append(report.Files, f)
+	}
+	return report
+}
+
+// expects reports whether t should state fact: its generated version
+// does, or t was written by hand
+func (t target) expects(fact Fact) bool {
+	return !t.ours || mentions(t.generated, fact.Text)
+}
+
+// added returns the lines of t that its generated version doesn't have
+// and whose distinctive words (those t.known doesn't use) no other file
+// has all of
+func (t target) added(others []target) []Line {
+	generated := map[string]bool{}
+	for _, line := range strings.Split(t.generated, "\n") {
+		generated[normalize(line)] = true
+	}
+
+	lines := []Line{}
+	kind := KindOther
+	comment := false
+	for i, line := range strings.Split(t.disk, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case comment || strings.HasPrefix(trimmed, "<!--"):
+			comment = !strings.Contains(trimmed, "-->")
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			// Headings only place what follows; "# Last updated:" changes
+			// with every sync
+			kind = sectionKind(strings.TrimLeft(trimmed, "# "))
+			continue
+		}
+		n := normalize(line)
+		if n == "" || generated[n] {
+			continue
+		}
+		var distinctive []string
+		for _, term := range terms(n) {
+			if !mentions(t.known, term) {
+				distinctive = append(distinctive, term)
+			}
+		}
+		if len(distinctive) == 0 || saidElsewhere(distinctive, others) {
+			continue
+		}
+		lines = append(lines, Line{Kind: kind, Line: i + 1, Text: trimmed})
+	}
+	return lines
+}
+
+// saidElsewhere reports whether one of others uses every term
+func saidElsewhere(terms []string, others []target) bool {
+	for _, o := range others {
+		all := true
+		for _, term := range terms {
+			if !mentions(o.disk, term) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// sectionKind classifies a section by its heading
+func sectionKind(heading string) string {
+	h := strings.ToLower(heading)
+	switch {
+	case strings.Contains(h, "decision"):
+		return KindDecision
+	case strings.Contains(h, "convention"), strings.Contains(h, "style"), strings.Contains(h, "naming"), strings.Contains(h, "rules"):
+		return KindConvention
+	case strings.Contains(h, "stack"), strings.Contains(h, "tech"), strings.Contains(h, "framework"), strings.Contains(h, "language"), strings.Contains(h, "dependenc"):
+		return KindStack
+	}
+	return KindOther
+}
+
+// markup matches list markers and emphasis, which don't change what a
+// line says
+var markup = regexp.MustCompile("^\\s*(?:[-*+]|\\d+\\.)\\s+|[*`_]")
+
+// normalize reduces line to what it says, for comparison
+func normalize(line string) string {
+	return strings.Join(strings.Fields(strings.ToLower(markup.ReplaceAllString(line, ""))), " ")
+}
+
+// word matches a term: a word, or a name such as next.js or @scope/pkg
+var word = regexp.MustCompile(`[a-z0-9@][a-z0-9.+#@/_-]*[a-z0-9+#]`)
+
+// stopWords carry no meaning of their own
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "use": true, "using": true,
+	"from": true, "this": true, "that": true, "into": true, "are": true, "not": true,
+	"all": true, "any": true, "when": true, "your": true, "you": true, "our": true,
+	"its": true, "has": true, "have": true, "was": true, "via": true, "per": true,
+	"each": true, "should": true, "must": true, "always": true, "never": true,
+}
+
+// terms returns the meaningful words of a normalized line
+func terms(line string) []string {
+	var out []string
+	for _, w := range word.FindAllString(line, -1) {
+		if len(w) >= 3 && !stopWords[w] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// mentions reports whether doc names name as a whole word, ignoring case
+func mentions(doc, name string) bool {
+	re, err := regexp.Compile(`(?i)(^|[^\w-])` + regexp.QuoteMeta(name) + `($|[^\w-])`)
+	return err == nil && re.MatchString(doc)
+}
@@ -0,0 +1,86 @@
+// Package db opens the SQLite database that holds sessions, their history
+// and decisions for projects whose config.yaml sets storage: sqlite
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	_ "modernc.org/sqlite"
+)
+
+// File is the database, relative to the project root
+const File = ".contextpilot/contextpilot.db"
+
+// driver is the database/sql driver, a pure Go SQLite that builds without
+// cgo, so every release binary supports storage: sqlite
+const driver = "sqlite"
+
+// schema creates the tables on first use. Rows keep the record as JSON
+// next to the columns queries filter and sort on.
+const schema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	branch     TEXT NOT NULL,
+	name       TEXT NOT NULL,
+	updated_at TEXT NOT NULL,
+	data       TEXT NOT NULL,
+	PRIMARY KEY (branch, name)
+);
+CREATE TABLE IF NOT EXISTS session_history (
+	seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+	branch     TEXT NOT NULL,
+	updated_at TEXT NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS session_history_branch ON session_history (branch, updated_at);
+CREATE TABLE IF NOT EXISTS session_archive (
+	seq    INTEGER PRIMARY KEY AUTOINCREMENT,
+	branch TEXT NOT NULL,
+	data   TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS decisions (
+	id   INTEGER PRIMARY KEY,
+	date TEXT NOT NULL,
+	text TEXT NOT NULL,
+	data TEXT NOT NULL
+);
+`
+
+var (
+	mu   sync.Mutex
+	open = map[string]*sql.DB{}
+)
+
+// Open returns the database of the project at root, creating it and its
+// tables on first use. A handle is shared by every caller in the process
+// and stays open until it exits; SQLite's locking and a busy timeout let
+// the CLI and the MCP server write to it at the same time.
+func Open(root string) (*sql.DB, error) {
+	path, err := filepath.Abs(filepath.Join(root, filepath.FromSlash(File)))
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if db, ok := open[path]; ok {
+		return db, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(File), err)
+	}
+	db, err := sql.Open(driver, path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", File, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", File, err)
+	}
+	open[path] = db
+	return db, nil
+}
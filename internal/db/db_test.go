@@ -0,0 +1,44 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	root := t.TempDir()
+	db, err := Open(root)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(File))); err != nil {
+		t.Fatalf("database not created: %v", err)
+	}
+
+	if _, err := db.Exec(`INSERT INTO decisions (id, date, text, data) VALUES (1, '2026-10-16', 'Use Redis for caching', '{}')`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	again, err := Open(root)
+	if err != nil {
+		t.Fatalf("Open again: %v", err)
+	}
+	if again != db {
+		t.Errorf("Open again returned a new handle, want the shared one")
+	}
+
+	var text, mode string
+	if err := again.QueryRow(`SELECT text FROM decisions WHERE id = 1`).Scan(&text); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if text != "Use Redis for caching" {
+		t.Errorf("text = %q, want Use Redis for caching", text)
+	}
+	if err := again.QueryRow(`PRAGMA journal_mode`).Scan(&mode); err != nil {
+		t.Fatalf("journal_mode: %v", err)
+	}
+	if mode != "wal" {
+		t.Errorf("journal_mode = %q, want wal", mode)
+	}
+}
@@ -0,0 +1,185 @@
+// Package devcontainer wires ContextPilot into a project's
+// devcontainer.json, so Codespaces and dev containers start with the MCP
+// server registered and the context files picked up by VS Code and Cursor.
+package devcontainer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/jsonc"
+)
+
+// Paths devcontainer.json may live at, relative to the project root, in the
+// order tools look for them
+var Paths = []string{".devcontainer/devcontainer.json", ".devcontainer.json"}
+
+// DefaultImage is used when Inject creates a new devcontainer.json
+const DefaultImage = "mcr.microsoft.com/devcontainers/base:ubuntu"
+
+// InstallCommand installs contextpilot when the container is created
+const InstallCommand = "curl -fsSL https://raw.githubusercontent.com/contextpilot-dev/contextpilot/main/scripts/install.sh | sh"
+
+// Settings keys written under customizations.vscode.settings
+const (
+	useInstructionFiles = "github.copilot.chat.codeGeneration.useInstructionFiles"
+	instructions        = "github.copilot.chat.codeGeneration.instructions"
+)
+
+// Options is what Inject wires in
+type Options struct {
+	// Server is the MCP server entry, under customizations.vscode.mcp.servers
+	Server map[string]interface{}
+	// Instructions are context files, relative to the root, added to
+	// Copilot's instruction files
+	Instructions []string
+}
+
+// Result describes what Inject did
+type Result struct {
+	Path          string
+	Created       bool
+	Changed       []string // settings that were added or updated
+	HadComments   bool     // comments in the original file were dropped
+	OwnPostCreate bool     // postCreateCommand was already set, so it doesn't install contextpilot
+}
+
+// Path returns the project's devcontainer.json, or the default location
+// if there is none
+func Path(root string) string {
+	for _, p := range Paths {
+		if _, err := os.Stat(filepath.Join(root, p)); err == nil {
+			return filepath.Join(root, p)
+		}
+	}
+	return filepath.Join(root, Paths[0])
+}
+
+// Inject adds the MCP server and instruction files to the devcontainer.json
+// at path, creating it if needed. Other settings are kept. With dryRun the
+// file isn't written.
+func Inject(path string, opts Options, dryRun bool) (Result, error) {
+	res := Result{Path: path}
+	cfg := map[string]interface{}{}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		res.Created = true
+		cfg["name"] = filepath.Base(filepath.Dir(filepath.Dir(path)))
+		cfg["image"] = DefaultImage
+	case err != nil:
+		return res, fmt.Errorf("failed to read %s: %w", path, err)
+	default:
+		plain, comments := jsonc.Strip(data)
+		res.HadComments = comments
+		dec := json.NewDecoder(bytes.NewReader(plain))
+		dec.UseNumber()
+		if err := dec.Decode(&cfg); err != nil {
+			return res, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	vscode, err := object(cfg, "customizations", "vscode")
+	if err != nil {
+		return res, fmt.Errorf("%s: %w", path, err)
+	}
+	servers, err := object(vscode, "mcp", "servers")
+	if err != nil {
+		return res, fmt.Errorf("%s: %w", path, err)
+	}
+	if !sameJSON(servers["contextpilot"], opts.Server) {
+		servers["contextpilot"] = opts.Server
+		res.Changed = append(res.Changed, "customizations.vscode.mcp.servers.contextpilot")
+	}
+
+	settings, err := object(vscode, "settings")
+	if err != nil {
+		return res, fmt.Errorf("%s: %w", path, err)
+	}
+	if settings[useInstructionFiles] != true {
+		settings[useInstructionFiles] = true
+		res.Changed = append(res.Changed, useInstructionFiles)
+	}
+	if len(opts.Instructions) > 0 {
+		list, _ := settings[instructions].([]interface{})
+		added := false
+		for _, file := range opts.Instructions {
+			if !hasInstruction(list, file) {
+				list = append(list, map[string]interface{}{"file": file})
+				added = true
+			}
+		}
+		if added {
+			settings[instructions] = list
+			res.Changed = append(res.Changed, instructions)
+		}
+	}
+
+	if cmd, ok := cfg["postCreateCommand"]; !ok {
+		cfg["postCreateCommand"] = InstallCommand
+		res.Changed = append(res.Changed, "postCreateCommand")
+	} else if s, _ := json.Marshal(cmd); !strings.Contains(string(s), "contextpilot") {
+		res.OwnPostCreate = true
+	}
+
+	if len(res.Changed) == 0 || dryRun {
+		return res, nil
+	}
+
+	out, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return res, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return res, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(out, '\n'), 0644); err != nil {
+		return res, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return res, nil
+}
+
+// object returns the nested object at keys under m, creating missing
+// levels. It fails if a level exists but isn't an object.
+func object(m map[string]interface{}, keys ...string) (map[string]interface{}, error) {
+	for i, k := range keys {
+		v, ok := m[k]
+		if !ok {
+			child := map[string]interface{}{}
+			m[k] = child
+			m = child
+			continue
+		}
+		child, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not an object", strings.Join(keys[:i+1], "."))
+		}
+		m = child
+	}
+	return m, nil
+}
+
+func hasInstruction(list []interface{}, file string) bool {
+	for _, item := range list {
+		if entry, ok := item.(map[string]interface{}); ok && entry["file"] == file {
+			return true
+		}
+	}
+	return false
+}
+
+// sameJSON reports whether a and b encode to the same JSON
+func sameJSON(a, b interface{}) bool {
+	var x, y interface{}
+	da, _ := json.Marshal(a)
+	db, _ := json.Marshal(b)
+	json.Unmarshal(da, &x)
+	json.Unmarshal(db, &y)
+	return reflect.DeepEqual(x, y)
+}
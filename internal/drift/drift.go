@@ -0,0 +1,274 @@
+// Package drift compares the analysis recorded at the last sync with a
+// fresh one and reports the changes context files may not reflect yet:
+// new or removed dependencies, a changed framework or tooling, and new or
+// removed top-level folders.
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// SnapshotFile holds the analysis recorded at the last sync
+const SnapshotFile = ".contextpilot/analysis.json"
+
+// Kinds of drift
+const (
+	DependencyAdded   = "dependency-added"
+	DependencyRemoved = "dependency-removed"
+	FrameworkChanged  = "framework-changed"
+	ToolChanged       = "tool-changed"
+	FolderAdded       = "folder-added"
+	FolderRemoved     = "folder-removed"
+)
+
+// Snapshot is the part of an analysis drift compares. It leaves out
+// anything that changes with every edit (file counts, percentages) so the
+// committed file only changes when the stack does.
+type Snapshot struct {
+	Framework    string            `json:"framework,omitempty"`
+	Dependencies []string          `json:"dependencies"`
+	Folders      []string          `json:"folders"`
+	Tools        map[string]string `json:"tools"`
+}
+
+// Item is one semantic difference
+type Item struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+func (i Item) String() string {
+	return i.Message
+}
+
+// Result is the drift of a project since its last sync
+type Result struct {
+	// Baseline is false when there is no snapshot to compare against
+	Baseline bool   `json:"baseline"`
+	Items    []Item `json:"items"`
+}
+
+// NewSnapshot extracts the compared fields from an analysis
+func NewSnapshot(a *analyzer.Analysis) Snapshot {
+	s := Snapshot{Dependencies: []string{}, Folders: []string{}, Tools: map[string]string{}}
+	if a.Framework != nil {
+		s.Framework = a.Framework.Name
+	}
+	for d := range a.Packages.Dependencies {
+		s.Dependencies = append(s.Dependencies, d)
+	}
+	for d := range a.Packages.DevDeps {
+		s.Dependencies = append(s.Dependencies, d)
+	}
+	sort.Strings(s.Dependencies)
+	s.Folders = append(s.Folders, a.Structure.Folders...)
+	sort.Strings(s.Folders)
+
+	p := a.Patterns
+	for name, v := range map[string]string{
+		"test framework":   p.TestFramework,
+		"linter":           p.Linter,
+		"formatter":        p.Formatter,
+		"ORM":              p.ORM,
+		"state management": p.StateManagement,
+		"styling":          p.Styling,
+	} {
+		if v != "" {
+			s.Tools[name] = v
+		}
+	}
+	return s
+}
+
+// Save records a's snapshot as the baseline for the next comparison
+func Save(root string, a *analyzer.Analysis) error {
+	return SaveFS(fsys.OS(root), a)
+}
+
+// SaveFS is Save for a project in files
+func SaveFS(files fsys.WriteFS, a *analyzer.Analysis) error {
+	data, err := json.MarshalIndent(NewSnapshot(a), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode analysis snapshot: %w", err)
+	}
+	if err := files.MkdirAll(path.Dir(SnapshotFile), 0755); err != nil {
+		return err
+	}
+	return files.WriteFile(SnapshotFile, append(data, '\n'), 0644)
+}
+
+// Load reads the baseline snapshot; ok is false if there is none
+func Load(root string) (s Snapshot, ok bool, err error) {
+	data, err := os.ReadFile(filepath.Join(root, SnapshotFile))
+	if os.IsNotExist(err) {
+		return s, false, nil
+	}
+	if err != nil {
+		return s, false, err
+	}
+	s, err = Parse(data)
+	return s, err == nil, err
+}
+
+// Parse decodes a snapshot, e.g. one read from another commit
+func Parse(data []byte) (Snapshot, error) {
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("failed to parse %s: %w", SnapshotFile, err)
+	}
+	return s, nil
+}
+
+// Detect compares the baseline snapshot with a fresh analysis. docs are
+// the context files, relative to root, checked for mentions of what
+// changed.
+func Detect(root string, fresh *analyzer.Analysis, docs []string) (Result, error) {
+	old, ok, err := Load(root)
+	if err != nil || !ok {
+		return Result{Items: []Item{}}, err
+	}
+	return Result{Baseline: true, Items: Since(root, old, fresh, docs)}, nil
+}
+
+// Since compares old, e.g. the snapshot of another commit, with a fresh
+// analysis. docs are as for Detect.
+func Since(root string, old Snapshot, fresh *analyzer.Analysis, docs []string) []Item {
+	var text strings.Builder
+	documented := []string{}
+	for _, d := range docs {
+		if data, err := os.ReadFile(filepath.Join(root, d)); err == nil {
+			text.Write(data)
+			text.WriteByte('\n')
+			documented = append(documented, d)
+		}
+	}
+	docNames := ""
+	if len(documented) == 1 {
+		docNames = documented[0]
+	}
+	return Compare(old, NewSnapshot(fresh), text.String(), docNames)
+}
+
+// Compare lists the differences between two snapshots. doc is the text of
+// the context files (named docNames), used to skip new dependencies they
+// already mention and to flag removed ones they still do.
+func Compare(old, cur Snapshot, doc, docNames string) []Item {
+	if docNames == "" {
+		docNames = "the context files"
+	}
+	return compare(old, cur, &doc, docNames)
+}
+
+// Delta lists every difference between two snapshots, whether or not the
+// context files already reflect it: what regenerating them picks up
+func Delta(old, cur Snapshot) []Item {
+	return compare(old, cur, nil, "")
+}
+
+// compare is Compare, or Delta when doc is nil
+func compare(old, cur Snapshot, doc *string, docNames string) []Item {
+	items := []Item{}
+
+	for _, d := range added(old.Dependencies, cur.Dependencies) {
+		switch {
+		case doc == nil:
+			items = append(items, Item{DependencyAdded, d, "New dependency " + d})
+		case !mentions(*doc, d):
+			items = append(items, Item{DependencyAdded, d, fmt.Sprintf("New dependency %s is not mentioned in %s", d, docNames)})
+		}
+	}
+	for _, d := range added(cur.Dependencies, old.Dependencies) {
+		switch {
+		case doc == nil:
+			items = append(items, Item{DependencyRemoved, d, "Removed dependency " + d})
+		case mentions(*doc, d):
+			items = append(items, Item{DependencyRemoved, d, fmt.Sprintf("Removed dependency %s is still mentioned in %s", d, docNames)})
+		}
+	}
+
+	if old.Framework != cur.Framework {
+		switch {
+		case cur.Framework == "":
+			items = append(items, Item{FrameworkChanged, old.Framework, fmt.Sprintf("Framework %s is no longer detected", old.Framework)})
+		case old.Framework == "":
+			items = append(items, Item{FrameworkChanged, cur.Framework, fmt.Sprintf("Framework %s is now detected", cur.Framework)})
+		default:
+			items = append(items, Item{FrameworkChanged, cur.Framework, fmt.Sprintf("Framework changed from %s to %s", old.Framework, cur.Framework)})
+		}
+	}
+
+	var tools []string
+	for name := range old.Tools {
+		tools = append(tools, name)
+	}
+	for name := range cur.Tools {
+		if _, ok := old.Tools[name]; !ok {
+			tools = append(tools, name)
+		}
+	}
+	sort.Strings(tools)
+	for _, name := range tools {
+		was, now := old.Tools[name], cur.Tools[name]
+		switch {
+		case was == now:
+		case now == "":
+			items = append(items, Item{ToolChanged, name, fmt.Sprintf("%s %s is no longer detected", upperFirst(name), was)})
+		case was == "":
+			items = append(items, Item{ToolChanged, name, fmt.Sprintf("%s %s is now detected", upperFirst(name), now)})
+		default:
+			items = append(items, Item{ToolChanged, name, fmt.Sprintf("%s changed from %s to %s", upperFirst(name), was, now)})
+		}
+	}
+
+	for _, f := range added(old.Folders, cur.Folders) {
+		items = append(items, Item{FolderAdded, f, fmt.Sprintf("New top-level folder %s/", f)})
+	}
+	for _, f := range added(cur.Folders, old.Folders) {
+		switch {
+		case doc == nil:
+			items = append(items, Item{FolderRemoved, f, fmt.Sprintf("Removed folder %s/", f)})
+		case mentions(*doc, f):
+			items = append(items, Item{FolderRemoved, f, fmt.Sprintf("Removed folder %s/ is still mentioned in %s", f, docNames)})
+		}
+	}
+	return items
+}
+
+// added returns the entries of cur that aren't in old, in cur's order
+func added(old, cur []string) []string {
+	seen := map[string]bool{}
+	for _, s := range old {
+		seen[s] = true
+	}
+	var out []string
+	for _, s := range cur {
+		if !seen[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// mentions reports whether doc contains name as a whole word
+func mentions(doc, name string) bool {
+	re, err := regexp.Compile(`(?i)(^|[^\w-])` + regexp.QuoteMeta(name) + `($|[^\w-])`)
+	return err == nil && re.MatchString(doc)
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
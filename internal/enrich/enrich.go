@@ -0,0 +1,104 @@
+// Package enrich drafts architecture and conventions prose for the context
+// files with an LLM, from the structured analysis rather than source code
+package enrich
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+)
+
+// Sections are the headings the model is asked to write, in order
+var Sections = []string{"## Architecture Overview", "## Conventions in Detail"}
+
+// MaxTokens caps the length of the model's reply
+const MaxTokens = 1200
+
+const system = `You write context files that AI coding assistants read before working in a codebase.
+From the project analysis you are given, write exactly two markdown sections, in this order:
+
+## Architecture Overview
+How the project is organized and how its parts fit together.
+
+## Conventions in Detail
+How code here should be written: naming, layout, testing, tooling and workflow.
+
+Use only facts present in the analysis and files. Do not invent libraries, directories or commands.
+Write concise prose and bullet points, at most 200 words per section, and nothing outside the two sections.`
+
+// Input is what the model gets to see
+type Input struct {
+	Analysis  *analyzer.Analysis
+	Decisions []decisions.Decision
+	// Files are file contents the user chose to include, by path
+	Files map[string]string
+}
+
+// Prompt returns the system prompt and the user prompt for in. The
+// analysis is sent without the project's absolute path.
+func Prompt(in Input) (string, string, error) {
+	a := *in.Analysis
+	a.RootPath = ""
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("Project analysis (JSON):\n\n```json\n")
+	b.Write(data)
+	b.WriteString("\n```\n")
+
+	var active []string
+	for _, d := range in.Decisions {
+		if d.Active() {
+			active = append(active, "- "+d.Text)
+		}
+	}
+	if len(active) > 0 {
+		b.WriteString("\nArchitectural decisions:\n\n" + strings.Join(active, "\n") + "\n")
+	}
+
+	paths := make([]string, 0, len(in.Files))
+	for p := range in.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		fmt.Fprintf(&b, "\nFile %s:\n\n```\n%s\n```\n", p, strings.TrimRight(in.Files[p], "\n"))
+	}
+	return system, b.String(), nil
+}
+
+// Parse extracts the requested sections from the model's reply, dropping
+// anything around them
+func Parse(reply string) (string, error) {
+	start := strings.Index(reply, Sections[0])
+	if start == -1 {
+		return "", fmt.Errorf("reply has no %q section", strings.TrimPrefix(Sections[0], "## "))
+	}
+	body := reply[start:]
+	for _, heading := range Sections[1:] {
+		if !strings.Contains(body, heading) {
+			return "", fmt.Errorf("reply has no %q section", strings.TrimPrefix(heading, "## "))
+		}
+	}
+	body = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(body), "```"))
+	return body, nil
+}
+
+// Document renders body as the enrichment file, with a comment saying who
+// drafted it and how to review it
+func Document(body, model string, now time.Time) string {
+	return fmt.Sprintf(`<!-- Drafted by 'contextpilot enrich' with %s on %s.
+     Review and edit freely: 'contextpilot sync' copies everything below
+     into the context files. Delete this file to remove it again. -->
+
+%s
+`, model, now.Format("2006-01-02"), body)
+}
@@ -0,0 +1,220 @@
+// Package explain gathers what ContextPilot knows about one file or
+// directory of a project: the package it belongs to, the stack and
+// conventions that apply there, the decisions that cover it and how much
+// it changed recently
+package explain
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/suggest"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+)
+
+// DefaultDays is how far back churn is counted
+const DefaultDays = 90
+
+// recentCommits is how many of the latest commits an explanation lists
+const recentCommits = 5
+
+// Explanation is what ContextPilot knows about a path
+type Explanation struct {
+	// Path is slash-separated and relative to the project root, "." for
+	// the root itself
+	Path string `json:"path"`
+	Dir  bool   `json:"dir"`
+	// Languages is the file's language, or those of a directory's files,
+	// most files first
+	Languages []string `json:"languages,omitempty"`
+	// Files counts a directory's tracked files
+	Files int  `json:"files,omitempty"`
+	Test  bool `json:"test,omitempty"`
+	// Workspace is the monorepo package the path belongs to
+	Workspace *analyzer.WorkspacePackage `json:"workspace,omitempty"`
+	// Area is the part of the codebase 'suggest' and 'score' group it in
+	Area       string               `json:"area,omitempty"`
+	Frameworks []analyzer.Framework `json:"frameworks,omitempty"`
+	ORMs       []analyzer.ORM       `json:"orms,omitempty"`
+	// Conventions are the detected tools and patterns, e.g. "Linter: ESLint"
+	Conventions []string `json:"conventions,omitempty"`
+	// Instructions are the project's own rules for every tool
+	Instructions []string `json:"instructions,omitempty"`
+	// Decisions are the active decisions linked to the path by their
+	// files, or scoped to its package or area by a tag
+	Decisions []decisions.Decision `json:"decisions"`
+	Churn     Churn                `json:"churn"`
+}
+
+// Churn is how much a path changed recently
+type Churn struct {
+	Days    int `json:"days"`
+	Commits int `json:"commits"`
+	// Recent are the latest commits touching the path, newest first
+	Recent []git.LogEntry `json:"recent,omitempty"`
+}
+
+// Explain describes rel, a slash-separated path relative to root, from the
+// project's analysis, decisions and config and the last days of history
+func Explain(root, rel string, dir bool, analysis *analyzer.Analysis, decs []decisions.Decision, cfg *config.Config, days int) (*Explanation, error) {
+	rel = path.Clean(rel)
+	e := &Explanation{Path: rel, Dir: dir, Area: suggest.Area(rel), Decisions: []decisions.Decision{}}
+	if dir {
+		// The area of the files inside it
+		e.Area = suggest.Area(path.Join(rel, "file"))
+		e.Languages, e.Files = languages(root, rel)
+	} else {
+		if lang := analyzer.LanguageOf(rel); lang != "" {
+			e.Languages = []string{lang}
+		}
+		e.Test = analyzer.IsTestFile(rel)
+	}
+
+	for _, w := range analysis.Packages.Workspace {
+		if within(rel, w.Path) {
+			w := w
+			e.Workspace = &w
+			break
+		}
+	}
+	where := ""
+	if e.Workspace != nil {
+		where = e.Workspace.Path
+	}
+	for _, f := range analysis.Frameworks {
+		if f.Where == where {
+			e.Frameworks = append(e.Frameworks, f)
+		}
+	}
+	for _, o := range analysis.Patterns.ORMs {
+		if o.Where == where {
+			e.ORMs = append(e.ORMs, o)
+		}
+	}
+
+	e.Conventions = conventions(analysis)
+	if cfg != nil {
+		e.Instructions = cfg.InstructionsFor("all")
+	}
+
+	for _, d := range decs {
+		if d.Active() && e.covers(d) {
+			e.Decisions = append(e.Decisions, d)
+		}
+	}
+
+	e.Churn.Days = days
+	if git.IsRepo(root) {
+		log, err := git.Log(root, fmt.Sprintf("%d.days.ago", days))
+		if err != nil {
+			return nil, err
+		}
+		for i := len(log) - 1; i >= 0; i-- {
+			touched := false
+			for _, f := range log[i].Files {
+				if within(f, rel) {
+					touched = true
+					break
+				}
+			}
+			if !touched {
+				continue
+			}
+			e.Churn.Commits++
+			if len(e.Churn.Recent) < recentCommits {
+				c := log[i]
+				c.Files = nil
+				e.Churn.Recent = append(e.Churn.Recent, c)
+			}
+		}
+	}
+	return e, nil
+}
+
+// covers reports whether d is about the path: one of its files matches
+// the path, lies inside it or contains it, or a tag names the path's
+// package or area
+func (e *Explanation) covers(d decisions.Decision) bool {
+	for _, f := range d.Files {
+		f = path.Clean(strings.TrimPrefix(f, "./"))
+		if ok, _ := path.Match(f, e.Path); ok {
+			return true
+		}
+		base := strings.TrimSuffix(strings.TrimSuffix(f, "/**"), "/*")
+		if within(e.Path, base) || (e.Dir && within(base, e.Path)) {
+			return true
+		}
+	}
+	var scopes []string
+	if e.Workspace != nil {
+		scopes = append(scopes, e.Workspace.Name, e.Workspace.Path, path.Base(e.Workspace.Path))
+	}
+	if e.Area != "" {
+		scopes = append(scopes, e.Area, path.Base(e.Area))
+	}
+	for _, t := range d.Tags {
+		for _, s := range scopes {
+			if strings.EqualFold(t, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// within reports whether name is dir or lies inside it
+func within(name, dir string) bool {
+	return dir == "." || name == dir || strings.HasPrefix(name, dir+"/")
+}
+
+// languages returns the languages of the code files git tracks under dir,
+// most files first, and how many files it tracks
+func languages(root, dir string) ([]string, int) {
+	files := git.TrackedFiles(root, dir)
+	counts := map[string]int{}
+	for _, f := range files {
+		if lang := analyzer.LanguageOf(f); lang != "" {
+			counts[lang]++
+		}
+	}
+	var langs []string
+	for lang := range counts {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if counts[langs[i]] != counts[langs[j]] {
+			return counts[langs[i]] > counts[langs[j]]
+		}
+		return langs[i] < langs[j]
+	})
+	return langs, len(files)
+}
+
+// conventions lists the tools and patterns the analysis found
+func conventions(analysis *analyzer.Analysis) []string {
+	p := analysis.Patterns
+	var lines []string
+	add := func(label, value string) {
+		if value != "" {
+			lines = append(lines, label+": "+value)
+		}
+	}
+	add("Naming", p.NamingConvention)
+	add("Exports", p.ExportStyle)
+	add("Tests", p.TestFramework)
+	add("Test runner", p.TestRunner)
+	add("Linter", p.Linter)
+	add("Formatter", p.Formatter)
+	add("Type checker", p.TypeChecker)
+	add("Styling", p.Styling)
+	add("State management", p.StateManagement)
+	if p.Commits != nil {
+		add("Commits", p.Commits.String())
+	}
+	return append(lines, p.Conventions...)
+}
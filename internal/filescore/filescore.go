@@ -0,0 +1,328 @@
+// Package filescore scores one context file on its own: its size in
+// tokens, the sections it covers, how specific it is to the project and
+// whether the versions it mentions still match the lockfile, with
+// suggestions tied to the lines they are about.
+package filescore
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/report"
+	"github.com/jitin-nhz/contextpilot/internal/specificity"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+)
+
+// Sections a context file should cover, as report.Classify names them
+var Sections = []string{report.SectionStack, report.SectionCommands, report.SectionRepoMap, report.SectionConventions, report.SectionDecisions}
+
+// What each part of the score is worth, out of 100
+const (
+	sizePoints        = 20
+	sectionPoints     = 30
+	specificityPoints = 30
+	versionPoints     = 20
+	// stalePenalty is what each out-of-date version costs
+	stalePenalty = 5
+)
+
+// Project is what a file is scored against
+type Project struct {
+	Analysis *analyzer.Analysis
+	// Resolved are the versions in the lockfile, by package; Lockfile
+	// names it
+	Resolved map[string]string
+	Lockfile string
+}
+
+// StaleVersion is a version the file mentions that the lockfile no longer
+// resolves to
+type StaleVersion struct {
+	Line      int    `json:"line"`
+	Name      string `json:"name"`
+	Package   string `json:"package"`
+	Mentioned string `json:"mentioned"`
+	Resolved  string `json:"resolved"`
+}
+
+// Suggestion is something to fix, on Line or, when 0, in the file as a
+// whole
+type Suggestion struct {
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report is the score of one file
+type Report struct {
+	Path            string             `json:"path"`
+	Score           int                `json:"score"` // 0-100
+	Tokens          int                `json:"tokens"`
+	Budget          int                `json:"budget"`
+	Sections        []string           `json:"sections"`
+	MissingSections []string           `json:"missingSections"`
+	Specificity     specificity.Report `json:"specificity"`
+	Lockfile        string             `json:"lockfile,omitempty"`
+	Stale           []StaleVersion     `json:"stale"`
+	Suggestions     []Suggestion       `json:"suggestions"`
+}
+
+// heading is a ## (or deeper) heading and the section it starts
+type heading struct {
+	line    int
+	text    string
+	section string
+	tokens  int
+}
+
+// Score scores content, the file at path, against p
+func Score(path, content string, p Project) Report {
+	r := Report{
+		Path:            path,
+		Tokens:          report.EstimateTokens(content),
+		Budget:          report.Budget,
+		Sections:        []string{},
+		MissingSections: []string{},
+		Lockfile:        p.Lockfile,
+		Stale:           []StaleVersion{},
+		Suggestions:     []Suggestion{},
+	}
+	lines := strings.Split(content, "\n")
+	headings := headings(lines)
+
+	// Size
+	points := sizePoints
+	if r.Tokens > r.Budget {
+		points = sizePoints * r.Budget / r.Tokens
+		r.Suggestions = append(r.Suggestions, Suggestion{0, fmt.Sprintf("%d tokens, %d over the %d-token budget; every request pays for them", r.Tokens, r.Tokens-r.Budget, r.Budget)})
+		if h := largest(headings); h != nil && h.tokens*100/r.Tokens >= 25 {
+			r.Suggestions = append(r.Suggestions, Suggestion{h.line, fmt.Sprintf("%q takes %d%% of the file; shorten it first", h.text, h.tokens*100/r.Tokens)})
+		}
+	}
+
+	// Section coverage
+	found := map[string]bool{}
+	for _, h := range headings {
+		found[h.section] = true
+	}
+	for _, s := range Sections {
+		if found[s] {
+			r.Sections = append(r.Sections, s)
+		} else {
+			r.MissingSections = append(r.MissingSections, s)
+			r.Suggestions = append(r.Suggestions, Suggestion{0, missingSection(s, p.Analysis)})
+		}
+	}
+	points += sectionPoints * len(r.Sections) / len(Sections)
+
+	// Specificity, with the generic phrases pointed out where they are
+	var terms []string
+	if p.Analysis != nil {
+		terms = specificity.Terms(p.Analysis)
+	}
+	r.Specificity = specificity.Analyze(path, content, terms)
+	points += specificityPoints * r.Specificity.Score / 100
+	for i, line := range lines {
+		lower := strings.ToLower(line)
+		for _, phrase := range r.Specificity.Boilerplate {
+			if strings.Contains(lower, phrase) {
+				r.Suggestions = append(r.Suggestions, Suggestion{i + 1, fmt.Sprintf("%q fits any project; say what it means here", phrase)})
+			}
+		}
+	}
+	if r.Specificity.Score < 60 && len(r.Specificity.Missing) > 0 {
+		missing := r.Specificity.Missing
+		if len(missing) > 3 {
+			missing = missing[:3]
+		}
+		r.Suggestions = append(r.Suggestions, Suggestion{0, "Mention the project's own names, such as " + strings.Join(missing, ", ")})
+	}
+
+	// Versions
+	r.Stale = staleVersions(lines, p)
+	points += max(0, versionPoints-stalePenalty*len(r.Stale))
+	for _, s := range r.Stale {
+		r.Suggestions = append(r.Suggestions, Suggestion{s.Line, fmt.Sprintf("%s %s is out of date: %s has %s %s", s.Name, s.Mentioned, p.Lockfile, s.Package, s.Resolved)})
+	}
+
+	sort.SliceStable(r.Suggestions, func(i, j int) bool {
+		a, b := r.Suggestions[i].Line, r.Suggestions[j].Line
+		return a != 0 && (b == 0 || a < b)
+	})
+	r.Score = points
+	return r
+}
+
+// headings returns the file's ## and deeper headings with the tokens of
+// the section each starts
+func headings(lines []string) []heading {
+	var hs []heading
+	for i, line := range lines {
+		if strings.HasPrefix(line, "##") {
+			text := strings.TrimSpace(strings.TrimLeft(line, "#"))
+			hs = append(hs, heading{line: i + 1, text: text, section: report.Classify(text)})
+		}
+		if n := len(hs); n > 0 {
+			hs[n-1].tokens += report.EstimateTokens(line + "\n")
+		}
+	}
+	return hs
+}
+
+// largest returns the heading of the largest section
+func largest(hs []heading) *heading {
+	var big *heading
+	for i := range hs {
+		if big == nil || hs[i].tokens > big.tokens {
+			big = &hs[i]
+		}
+	}
+	return big
+}
+
+// missingSection suggests what a section missing from the file should say
+func missingSection(section string, a *analyzer.Analysis) string {
+	switch section {
+	case report.SectionStack:
+		return "Add a \"## Tech Stack\" section: the framework, languages and package manager"
+	case report.SectionCommands:
+		msg := "Add a \"## Commands\" section with how to build, test and lint"
+		if a != nil {
+			var runs []string
+			for _, c := range generator.Commands(a) {
+				if c.Name == "build" || c.Name == "test" || c.Name == "lint" {
+					runs = append(runs, c.Run)
+				}
+			}
+			if len(runs) > 0 {
+				msg += " (" + strings.Join(runs, ", ") + ")"
+			}
+		}
+		return msg
+	case report.SectionRepoMap:
+		return "Add a \"## Project Structure\" section naming the key folders"
+	case report.SectionConventions:
+		return "Add a \"## Conventions\" section with the rules an assistant can't infer from the code"
+	}
+	return "Add a \"## Decisions\" section; record them with 'contextpilot decision'"
+}
+
+// versionMention matches a version right after a name, as in "react
+// 18.2", "**Next.js** (^14.2.0)" or "zod@3.23.8"
+const versionMention = "(?:\\*\\*|`)?[\\s:@(]*(\\^|~|>=|v)?(\\d+\\.\\d+(?:\\.\\d+)?(?:-[\\w.]+)?)"
+
+// staleVersions finds the versions lines mention for the project's
+// dependencies and frameworks that the lockfile doesn't satisfy
+func staleVersions(lines []string, p Project) []StaleVersion {
+	if len(p.Resolved) == 0 {
+		return []StaleVersion{}
+	}
+	// Names to look for, and the package each stands for: the direct
+	// dependencies when the manifest lists them, and the frameworks
+	names := map[string]string{}
+	if a := p.Analysis; a != nil {
+		for _, deps := range []map[string]string{a.Packages.Dependencies, a.Packages.DevDeps} {
+			for name := range deps {
+				if _, ok := p.Resolved[name]; ok {
+					names[name] = name
+				}
+			}
+		}
+		for _, f := range a.Frameworks {
+			for _, pkg := range analyzer.FrameworkPackages(f.Name) {
+				if _, ok := p.Resolved[pkg]; ok {
+					names[f.Name] = pkg
+					break
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		for name := range p.Resolved {
+			names[name] = name
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	stale := []StaleVersion{}
+	for _, name := range sorted {
+		lower := strings.ToLower(name)
+		re := regexp.MustCompile(`(?i)(?:^|[^\w@/.-])` + regexp.QuoteMeta(name) + versionMention)
+		resolved := p.Resolved[names[name]]
+		for i, line := range lines {
+			if !strings.Contains(strings.ToLower(line), lower) {
+				continue
+			}
+			for _, m := range re.FindAllStringSubmatchIndex(line, -1) {
+				// A percentage isn't a version
+				if end := m[1]; end < len(line) && line[end] == '%' {
+					continue
+				}
+				op, v := "", line[m[4]:m[5]]
+				if m[2] >= 0 {
+					op = line[m[2]:m[3]]
+				}
+				if !satisfies(op, v, resolved) {
+					stale = append(stale, StaleVersion{i + 1, name, names[name], op + v, resolved})
+				}
+			}
+		}
+	}
+	sort.SliceStable(stale, func(i, j int) bool { return stale[i].Line < stale[j].Line })
+	return stale
+}
+
+// satisfies reports whether resolved is a version the mention allows: the
+// same major for ^, major and minor for ~, any newer one for >=, and
+// otherwise every part the mention gives
+func satisfies(op, mentioned, resolved string) bool {
+	m, r := versionParts(mentioned), versionParts(resolved)
+	if len(m) == 0 || len(r) == 0 {
+		return true
+	}
+	n := len(m)
+	switch op {
+	case "^":
+		n = 1
+		if m[0] == 0 {
+			n = 2
+		}
+	case "~":
+		n = 2
+	case ">=":
+		for i := range m {
+			if i >= len(r) || r[i] != m[i] {
+				return i < len(r) && r[i] > m[i]
+			}
+		}
+		return true
+	}
+	for i := 0; i < n && i < len(m); i++ {
+		if i >= len(r) || r[i] != m[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// versionParts returns the numbers of a version, up to a pre-release
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "-")
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,492 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Output runs git with args in dir and returns trimmed stdout
+func Output(dir string, args ...string) (string, error) {
+	out, err := run(dir, args...)
+	return strings.TrimSpace(out), err
+}
+
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+		}
+		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
+
+// Head returns the full SHA of HEAD, or "" if it cannot be resolved
+func Head(dir string) string {
+	sha, err := Output(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return sha
+}
+
+// ShortSHA resolves rev to an abbreviated commit SHA
+func ShortSHA(dir, rev string) (string, error) {
+	return Output(dir, "rev-parse", "--short", rev+"^{commit}")
+}
+
+// StagedFiles returns the paths staged for the next commit
+func StagedFiles(dir string) []string {
+	out, err := Output(dir, "diff", "--cached", "--name-only")
+	if err != nil || out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// StagedStat returns a diffstat summary of the staged changes
+func StagedStat(dir string) string {
+	out, _ := Output(dir, "diff", "--cached", "--stat")
+	return out
+}
+
+// TrackedFiles returns the files under paths that are in git's index,
+// relative to dir
+func TrackedFiles(dir string, paths ...string) []string {
+	out, err := Output(dir, append([]string{"ls-files", "--"}, paths...)...)
+	if err != nil || out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// FileCountAt returns how many files were tracked under dir in the last
+// commit before date (any git approxidate, e.g. "90.days.ago"), and false
+// if there was no commit by then
+func FileCountAt(dir, date string) (int, bool) {
+	rev, err := Output(dir, "rev-list", "-1", "--before="+date, "HEAD")
+	if err != nil || rev == "" {
+		return 0, false
+	}
+	out, err := Output(dir, "ls-tree", "-r", "--name-only", rev)
+	if err != nil {
+		return 0, false
+	}
+	if out == "" {
+		return 0, true
+	}
+	return strings.Count(out, "\n") + 1, true
+}
+
+// Commit is a commit's subject and the files it changed
+type Commit struct {
+	Subject string
+	Files   []string
+}
+
+// mergeRequest matches the subject GitHub gives merged pull requests
+var mergeRequest = regexp.MustCompile(`^Merge pull request (#\d+) from \S+$`)
+
+// RecentCommits returns the last n commits on the current branch, newest
+// first, following first parents so a merged pull request is one commit.
+// Merged pull requests are reported by their title. Only commits that
+// changed files under dir count, and Files are relative to dir.
+func RecentCommits(dir string, n int) ([]Commit, error) {
+	out, err := run(dir, "log", "--first-parent", "-m", fmt.Sprintf("-n%d", n), "--name-only", "--relative", "--format=%x00%s%n%b%x01", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, block := range strings.Split(out, "\x00") {
+		message, files, ok := strings.Cut(block, "\x01")
+		if !ok {
+			continue
+		}
+		c := Commit{Subject: subject(message)}
+		for _, f := range strings.Split(files, "\n") {
+			if f = strings.TrimSpace(f); f != "" {
+				c.Files = append(c.Files, f)
+			}
+		}
+		if len(c.Files) > 0 {
+			commits = append(commits, c)
+		}
+	}
+	return commits, nil
+}
+
+// subject returns the first line of a commit message, or the title of a
+// merged pull request followed by its number
+func subject(message string) string {
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	first := strings.TrimSpace(lines[0])
+	if m := mergeRequest.FindStringSubmatch(first); m != nil {
+		for _, line := range lines[1:] {
+			if line = strings.TrimSpace(line); line != "" {
+				return line + " (" + m[1] + ")"
+			}
+		}
+	}
+	return first
+}
+
+// LogEntry is a commit in the history returned by Log
+type LogEntry struct {
+	SHA     string    `json:"sha"` // abbreviated
+	Time    time.Time `json:"time"`
+	Subject string    `json:"subject"`
+	Files   []string  `json:"files,omitempty"`
+}
+
+// Log returns the commits reachable from HEAD since the given date (any
+// git approxidate, e.g. "3.months.ago"), oldest first. Merged pull
+// requests are reported by their title; other merge commits are left out.
+func Log(dir, since string) ([]LogEntry, error) {
+	out, err := run(dir, "log", "--since="+since, "--reverse", "--name-only", "--format=%x00%h %cI%n%s%n%b%x01")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []LogEntry
+	for _, block := range strings.Split(out, "\x00") {
+		message, files, ok := strings.Cut(block, "\x01")
+		if !ok {
+			continue
+		}
+		head, message, _ := strings.Cut(message, "\n")
+		sha, date, _ := strings.Cut(head, " ")
+		t, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			continue
+		}
+		e := LogEntry{SHA: sha, Time: t, Subject: subject(message)}
+		if strings.HasPrefix(e.Subject, "Merge ") {
+			continue
+		}
+		for _, f := range strings.Split(files, "\n") {
+			if f = strings.TrimSpace(f); f != "" {
+				e.Files = append(e.Files, f)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Subjects returns the subjects of the last n non-merge commits, newest
+// first
+func Subjects(dir string, n int) []string {
+	out, err := Output(dir, "log", "--no-merges", fmt.Sprintf("-n%d", n), "--format=%s")
+	if err != nil || out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// Branches returns the names of the local and remote-tracking branches,
+// without the remote name, sorted and without duplicates
+func Branches(dir string) []string {
+	out, err := Output(dir, "for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	if err != nil || out == "" {
+		return nil
+	}
+	seen := map[string]bool{}
+	var branches []string
+	for _, ref := range strings.Split(out, "\n") {
+		name, ok := strings.CutPrefix(ref, "refs/heads/")
+		if !ok {
+			// refs/remotes/<remote>/<branch>
+			parts := strings.SplitN(strings.TrimPrefix(ref, "refs/remotes/"), "/", 2)
+			if len(parts) < 2 || parts[1] == "HEAD" {
+				continue
+			}
+			name = parts[1]
+		}
+		if !seen[name] {
+			seen[name] = true
+			branches = append(branches, name)
+		}
+	}
+	sort.Strings(branches)
+	return branches
+}
+
+// CommitFiles returns the paths changed by commit rev
+func CommitFiles(dir, rev string) []string {
+	out, err := Output(dir, "diff-tree", "--no-commit-id", "--name-only", "-r", "--root", rev)
+	if err != nil || out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// Show returns the content of file, relative to dir, at rev
+func Show(dir, rev, file string) ([]byte, error) {
+	out, err := run(dir, "show", rev+":./"+file)
+	return []byte(out), err
+}
+
+// Archive returns the files under dir at rev as a tar stream, with paths
+// relative to dir
+func Archive(dir, rev string) ([]byte, error) {
+	out, err := run(dir, "archive", "--format=tar", rev)
+	return []byte(out), err
+}
+
+// DiffStat returns the files under dir changed from the merge base of
+// base and HEAD to HEAD, relative to dir, and the lines added plus
+// removed: what a pull request diff shows. Binary files count no lines.
+func DiffStat(dir, base string) (files []string, lines int, err error) {
+	out, err := run(dir, "diff", "--numstat", "--no-renames", "--relative", base+"...HEAD")
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		for _, n := range fields[:2] {
+			if v, err := strconv.Atoi(n); err == nil {
+				lines += v
+			}
+		}
+		files = append(files, fields[2])
+	}
+	return files, lines, nil
+}
+
+// CommitStat returns the subject and diffstat of commit rev
+func CommitStat(dir, rev string) string {
+	out, _ := Output(dir, "show", "--stat", "--format=%h %s", rev)
+	return out
+}
+
+// LogFiles returns the files touched by each non-merge commit since the
+// given date (any git approxidate, e.g. "30.days.ago"), relative to dir
+func LogFiles(dir, since string) ([][]string, error) {
+	out, err := run(dir, "log", "--no-merges", "--since="+since, "--name-only", "--relative", "--format=%x00")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits [][]string
+	for _, block := range strings.Split(out, "\x00") {
+		var files []string
+		for _, line := range strings.Split(block, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				files = append(files, line)
+			}
+		}
+		if len(files) > 0 {
+			commits = append(commits, files)
+		}
+	}
+	return commits, nil
+}
+
+// ChangedFiles returns paths with uncommitted changes (staged, unstaged or untracked)
+func ChangedFiles(dir string) []string {
+	out, err := run(dir, "status", "--porcelain")
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		path := line[3:]
+		// Renames are reported as "old -> new"
+		if idx := strings.Index(path, " -> "); idx != -1 {
+			path = path[idx+4:]
+		}
+		files = append(files, path)
+	}
+	return files
+}
+
+// CurrentBranch returns the checked-out branch for the repository containing
+// dir. A detached HEAD is reported as its short SHA. When the git binary is
+// unavailable, HEAD is read directly from the resolved git dir (which also
+// handles worktrees and submodules where .git is a file).
+func CurrentBranch(dir string) (string, error) {
+	// symbolic-ref also works on an unborn branch with no commits yet
+	if branch, err := Output(dir, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil && branch != "" {
+		return branch, nil
+	}
+	if sha, err := Output(dir, "rev-parse", "--short", "HEAD"); err == nil && sha != "" {
+		return sha, nil
+	}
+	return readHead(dir)
+}
+
+// IsRepo reports whether dir is inside a git work tree
+func IsRepo(dir string) bool {
+	out, err := Output(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// Dir returns the git directory for the repository containing dir,
+// following "gitdir:" indirection used by worktrees and submodules
+func Dir(dir string) (string, error) {
+	if out, err := Output(dir, "rev-parse", "--absolute-git-dir"); err == nil && out != "" {
+		return out, nil
+	}
+
+	for cur := dir; ; {
+		candidate := filepath.Join(cur, ".git")
+		if info, err := os.Stat(candidate); err == nil {
+			if info.IsDir() {
+				return candidate, nil
+			}
+			data, err := os.ReadFile(candidate)
+			if err != nil {
+				return "", err
+			}
+			line := strings.TrimSpace(string(data))
+			if !strings.HasPrefix(line, "gitdir:") {
+				return "", fmt.Errorf("unrecognized .git file: %s", candidate)
+			}
+			gitDir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(cur, gitDir)
+			}
+			return gitDir, nil
+		}
+
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return "", fmt.Errorf("not a git repository: %s", dir)
+		}
+		cur = parent
+	}
+}
+
+func readHead(dir string) (string, error) {
+	gitDir, err := Dir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", err
+	}
+
+	head := strings.TrimSpace(string(data))
+	if strings.HasPrefix(head, "ref: refs/heads/") {
+		return strings.TrimPrefix(head, "ref: refs/heads/"), nil
+	}
+	if len(head) >= 7 {
+		return head[:7], nil // Detached HEAD
+	}
+	return "", fmt.Errorf("unrecognized HEAD: %q", head)
+}
+
+// Resolves reports whether name resolves to a commit (a branch, tag or SHA)
+func Resolves(dir, name string) bool {
+	_, err := Output(dir, "rev-parse", "--verify", "--quiet", name+"^{commit}")
+	return err == nil
+}
+
+// CommitTime returns the committer date of rev
+func CommitTime(dir, rev string) (time.Time, error) {
+	out, err := Output(dir, "log", "-1", "--format=%cI", rev+"^{commit}", "--")
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, out)
+}
+
+// LatestTag returns the most recent tag reachable from HEAD, or "" if
+// there is none
+func LatestTag(dir string) string {
+	tag, err := Output(dir, "describe", "--tags", "--abbrev=0")
+	if err != nil {
+		return ""
+	}
+	return tag
+}
+
+// DefaultBranch returns the repository's main line: the branch origin/HEAD
+// points to, else "main" or "master" if present, else ""
+func DefaultBranch(dir string) string {
+	if ref, err := Output(dir, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil && ref != "" {
+		return strings.TrimPrefix(ref, "origin/")
+	}
+	for _, name := range []string{"main", "master"} {
+		if _, err := Output(dir, "show-ref", "--verify", "--quiet", "refs/heads/"+name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// MergedBranches returns local branches fully merged into base, excluding
+// base itself and branches still pointing at base's tip (created but never
+// committed to)
+func MergedBranches(dir, base string) []string {
+	out, err := Output(dir, "branch", "--merged", base, "--format=%(refname:short) %(objectname)")
+	if err != nil || out == "" {
+		return nil
+	}
+	baseTip, _ := Output(dir, "rev-parse", base)
+
+	branches := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] == base || fields[1] == baseTip {
+			continue
+		}
+		branches = append(branches, fields[0])
+	}
+	return branches
+}
+
+// HooksDir returns the directory git runs hooks from: core.hooksPath when
+// set (relative to the work tree root), otherwise the hooks directory of the
+// main repository, which worktrees share
+func HooksDir(dir string) (string, error) {
+	if path, err := Output(dir, "config", "core.hooksPath"); err == nil && path != "" {
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		if !filepath.IsAbs(path) {
+			top, err := Output(dir, "rev-parse", "--show-toplevel")
+			if err != nil {
+				return "", err
+			}
+			path = filepath.Join(top, path)
+		}
+		return path, nil
+	}
+
+	common, err := Output(dir, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(dir, common)
+	}
+	return filepath.Join(common, "hooks"), nil
+}
@@ -0,0 +1,160 @@
+// Package github reads the pull request a GitHub Actions run is for and
+// keeps a comment on it up to date through the REST API
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+)
+
+// DefaultAPIURL is the API of github.com; GitHub Enterprise runners set
+// GITHUB_API_URL to their own
+const DefaultAPIURL = "https://api.github.com"
+
+// pageSize is how many comments are listed per request, GitHub's maximum
+const pageSize = 100
+
+// Client talks to the API on behalf of one repository
+type Client struct {
+	api    string
+	repo   string
+	token  string
+	client *http.Client
+}
+
+// Comment is a pull request conversation comment
+type Comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	URL  string `json:"html_url"`
+}
+
+// FromEnv returns a client for the repository and token GitHub Actions
+// provide in GITHUB_REPOSITORY and GITHUB_TOKEN
+func FromEnv() (*Client, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil, errors.New("GITHUB_TOKEN is not set (pass secrets.GITHUB_TOKEN to the step)")
+	}
+	repo := os.Getenv("GITHUB_REPOSITORY")
+	if !strings.Contains(repo, "/") {
+		return nil, errors.New("GITHUB_REPOSITORY is not set to owner/repo")
+	}
+	api := os.Getenv("GITHUB_API_URL")
+	if api == "" {
+		api = DefaultAPIURL
+	}
+	return &Client{
+		api:    strings.TrimRight(api, "/"),
+		repo:   repo,
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// PullRequest returns the number of the pull request that triggered the
+// workflow, read from the event payload at GITHUB_EVENT_PATH. It is 0 for
+// events that aren't about a pull request.
+func PullRequest() (int, error) {
+	name := os.Getenv("GITHUB_EVENT_PATH")
+	if name == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return 0, err
+	}
+	var event struct {
+		PullRequest struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return event.PullRequest.Number, nil
+}
+
+// Find returns the first comment on pull request pr containing marker, or
+// nil if there is none
+func (c *Client) Find(pr int, marker string) (*Comment, error) {
+	for page := 1; ; page++ {
+		var comments []Comment
+		target := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=%d&page=%d", c.repo, pr, pageSize, page)
+		if err := c.do(http.MethodGet, target, nil, &comments); err != nil {
+			return nil, err
+		}
+		for _, cm := range comments {
+			if strings.Contains(cm.Body, marker) {
+				return &cm, nil
+			}
+		}
+		if len(comments) < pageSize {
+			return nil, nil
+		}
+	}
+}
+
+// Create comments body on pull request pr
+func (c *Client) Create(pr int, body string) (*Comment, error) {
+	var cm Comment
+	target := fmt.Sprintf("/repos/%s/issues/%d/comments", c.repo, pr)
+	if err := c.do(http.MethodPost, target, map[string]string{"body": body}, &cm); err != nil {
+		return nil, err
+	}
+	return &cm, nil
+}
+
+// Update replaces the body of comment id
+func (c *Client) Update(id int64, body string) (*Comment, error) {
+	var cm Comment
+	target := fmt.Sprintf("/repos/%s/issues/comments/%d", c.repo, id)
+	if err := c.do(http.MethodPatch, target, map[string]string{"body": body}, &cm); err != nil {
+		return nil, err
+	}
+	return &cm, nil
+}
+
+// do sends in as JSON to the API path target and decodes the reply into out
+func (c *Client) do(method, target string, in, out interface{}) error {
+	if err := config.CheckOnline(c.api); err != nil {
+		return err
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.api+target, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "contextpilot")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		path, _, _ := strings.Cut(target, "?")
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
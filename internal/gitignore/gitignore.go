@@ -0,0 +1,146 @@
+// Package gitignore adds ContextPilot's entries to a project's .gitignore,
+// or to the repository's untracked .git/info/exclude
+package gitignore
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// header introduces the entries ContextPilot appends
+const header = "# ContextPilot"
+
+// Add appends the entries missing from root/.gitignore, creating the file
+// if needed, and returns the ones it added. Entries already present, with
+// or without a leading slash, are left alone.
+func Add(root string, entries []string) ([]string, error) {
+	return appendEntries(filepath.Join(root, ".gitignore"), ".gitignore", entries)
+}
+
+// Exclude is Add for .git/info/exclude, which git honors like .gitignore
+// but which is never committed. Entries are relative to root, which may be
+// below the top of the work tree.
+func Exclude(root string, entries []string) ([]string, error) {
+	path, err := ExcludeFile(root)
+	if err != nil {
+		return nil, err
+	}
+	top, err := git.Output(root, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the work tree: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	prefix := ""
+	if rel, err := filepath.Rel(top, root); err == nil && rel != "." {
+		prefix = filepath.ToSlash(rel) + "/"
+	}
+	// Anchor entries to root so they don't match elsewhere in the repository
+	anchored := make([]string, len(entries))
+	for i, e := range entries {
+		anchored[i] = "/" + prefix + strings.TrimPrefix(e, "/")
+	}
+	added, err := appendEntries(path, "info/exclude", anchored)
+	for i := range added {
+		added[i] = strings.TrimPrefix(added[i], "/"+prefix)
+	}
+	return added, err
+}
+
+// ExcludeFile returns the path of the info/exclude file of the repository
+// containing root
+func ExcludeFile(root string) (string, error) {
+	path, err := git.Output(root, "rev-parse", "--git-path", "info/exclude")
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %s", root)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	return path, nil
+}
+
+// IgnoreDir creates dir if needed and makes it ignore its own contents
+// with a .gitignore holding "*", so files written there stay local even in
+// projects whose root .gitignore doesn't list it
+func IgnoreDir(dir string) error {
+	return IgnoreDirFS(fsys.OS(dir), ".")
+}
+
+// IgnoreDirFS is IgnoreDir for dir within files
+func IgnoreDirFS(files fsys.WriteFS, dir string) error {
+	name := path.Join(dir, ".gitignore")
+	if fsys.Exists(files, name) {
+		return nil
+	}
+	if err := files.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return files.WriteFile(name, []byte("# Local to this machine, written by ContextPilot\n*\n"), 0644)
+}
+
+// appendEntries appends the entries missing from the file at path (called
+// name in errors) under the ContextPilot header
+func appendEntries(path, name string, entries []string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	content := string(data)
+
+	present := map[string]bool{}
+	for _, line := range strings.Split(content, "\n") {
+		present[normalize(line)] = true
+	}
+
+	var added []string
+	for _, e := range entries {
+		if !present[normalize(e)] {
+			present[normalize(e)] = true
+			added = append(added, e)
+		}
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if content != "" {
+		content = strings.TrimRight(content, "\n") + "\n\n"
+	}
+	if !present[header] {
+		content += header + "\n"
+	}
+	content += strings.Join(added, "\n") + "\n"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return added, nil
+}
+
+// Contains reports whether root/.gitignore lists entry
+func Contains(root, entry string) bool {
+	data, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if normalize(line) == normalize(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(line string) string {
+	return strings.TrimPrefix(strings.TrimSpace(line), "/")
+}
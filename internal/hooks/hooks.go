@@ -0,0 +1,115 @@
+// Package hooks installs ContextPilot into git hooks without disturbing
+// whatever else the hooks already run
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Markers delimit the block ContextPilot owns inside a hook script
+const (
+	beginMarker = "# >>> contextpilot >>>"
+	endMarker   = "# <<< contextpilot <<<"
+	shebang     = "#!/bin/sh"
+)
+
+// Hook is a git hook and the command ContextPilot runs from it
+type Hook struct {
+	Name    string
+	Command string
+}
+
+// Status of a hook after Install or Uninstall
+const (
+	Installed = "installed"
+	Updated   = "updated"
+	Unchanged = "unchanged"
+	Removed   = "removed"
+	Missing   = "not installed"
+)
+
+// Install adds (or refreshes) the ContextPilot block in dir/<hook>. An
+// existing script keeps its own content; a new one is created executable.
+func Install(dir string, h Hook) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	path := filepath.Join(dir, h.Name)
+	block := beginMarker + "\n" + h.Command + "\n" + endMarker + "\n"
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s hook: %w", h.Name, err)
+	}
+	content := string(data)
+
+	status := Installed
+	switch {
+	case content == "":
+		content = shebang + "\n\n" + block
+	case strings.Contains(content, beginMarker):
+		updated := replaceBlock(content, block)
+		if updated == content {
+			return Unchanged, nil
+		}
+		content = updated
+		status = Updated
+	default:
+		content = strings.TrimRight(content, "\n") + "\n\n" + block
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		return "", fmt.Errorf("failed to write %s hook: %w", h.Name, err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0755); err != nil {
+		return "", fmt.Errorf("failed to make %s hook executable: %w", h.Name, err)
+	}
+	return status, nil
+}
+
+// Uninstall removes the ContextPilot block from dir/<name>, deleting the
+// script when nothing but the shebang is left
+func Uninstall(dir, name string) (string, error) {
+	path := filepath.Join(dir, name)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Missing, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s hook: %w", name, err)
+	}
+	content := string(data)
+	if !strings.Contains(content, beginMarker) {
+		return Missing, nil
+	}
+
+	content = strings.TrimRight(replaceBlock(content, ""), "\n") + "\n"
+	if strings.TrimSpace(content) == shebang || strings.TrimSpace(content) == "" {
+		if err := os.Remove(path); err != nil {
+			return "", fmt.Errorf("failed to remove %s hook: %w", name, err)
+		}
+		return Removed, nil
+	}
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		return "", fmt.Errorf("failed to write %s hook: %w", name, err)
+	}
+	return Removed, nil
+}
+
+// replaceBlock swaps the marked block in content for block
+func replaceBlock(content, block string) string {
+	start := strings.Index(content, beginMarker)
+	end := strings.Index(content, endMarker)
+	if start == -1 || end < start {
+		return content
+	}
+	end += len(endMarker)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return content[:start] + block + content[end:]
+}
@@ -0,0 +1,144 @@
+package hooks
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PreCommitConfig is the config file of the pre-commit framework
+// (pre-commit.com), which owns .git/hooks/pre-commit when in use
+const PreCommitConfig = ".pre-commit-config.yaml"
+
+// preCommitHookID identifies ContextPilot's entry in PreCommitConfig
+const preCommitHookID = "contextpilot-sync-check"
+
+// UsesPreCommit reports whether root is set up for the pre-commit framework
+func UsesPreCommit(root string) bool {
+	_, err := os.Stat(filepath.Join(root, PreCommitConfig))
+	return err == nil
+}
+
+// AddPreCommitHook adds a local hook running entry to root's
+// .pre-commit-config.yaml, creating the file if needed
+func AddPreCommitHook(root, entry string) (string, error) {
+	doc, err := loadPreCommitConfig(root)
+	if err != nil {
+		return "", err
+	}
+	repos := reposNode(doc)
+	if findLocalHook(repos) != -1 {
+		return Unchanged, nil
+	}
+
+	var repo yaml.Node
+	err = yaml.Unmarshal([]byte(fmt.Sprintf(`repo: local
+hooks:
+  - id: %s
+    name: contextpilot sync --check
+    entry: %s
+    language: system
+    pass_filenames: false
+    always_run: true
+`, preCommitHookID, entry)), &repo)
+	if err != nil {
+		return "", err
+	}
+	repos.Content = append(repos.Content, repo.Content[0])
+	if err := savePreCommitConfig(root, doc); err != nil {
+		return "", err
+	}
+	return Installed, nil
+}
+
+// RemovePreCommitHook removes ContextPilot's entry from root's
+// .pre-commit-config.yaml
+func RemovePreCommitHook(root string) (string, error) {
+	if !UsesPreCommit(root) {
+		return Missing, nil
+	}
+	doc, err := loadPreCommitConfig(root)
+	if err != nil {
+		return "", err
+	}
+	repos := reposNode(doc)
+	i := findLocalHook(repos)
+	if i == -1 {
+		return Missing, nil
+	}
+	repos.Content = append(repos.Content[:i], repos.Content[i+1:]...)
+	if err := savePreCommitConfig(root, doc); err != nil {
+		return "", err
+	}
+	return Removed, nil
+}
+
+func loadPreCommitConfig(root string) (*yaml.Node, error) {
+	var doc yaml.Node
+	data, err := os.ReadFile(filepath.Join(root, PreCommitConfig))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", PreCommitConfig, err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		data = []byte("repos: []\n")
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", PreCommitConfig, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is not a mapping", PreCommitConfig)
+	}
+	return &doc, nil
+}
+
+func savePreCommitConfig(root string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", PreCommitConfig, err)
+	}
+	if err := os.WriteFile(filepath.Join(root, PreCommitConfig), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", PreCommitConfig, err)
+	}
+	return nil
+}
+
+// reposNode returns the "repos" sequence, adding it if missing
+func reposNode(doc *yaml.Node) *yaml.Node {
+	m := doc.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == "repos" {
+			seq := m.Content[i+1]
+			seq.Kind, seq.Tag, seq.Style = yaml.SequenceNode, "!!seq", 0
+			return seq
+		}
+	}
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "repos"}, seq)
+	return seq
+}
+
+// findLocalHook returns the index of the repo entry holding ContextPilot's
+// hook, or -1
+func findLocalHook(repos *yaml.Node) int {
+	for i, repo := range repos.Content {
+		var r struct {
+			Hooks []struct {
+				ID string `yaml:"id"`
+			} `yaml:"hooks"`
+		}
+		if repo.Decode(&r) != nil {
+			continue
+		}
+		for _, h := range r.Hooks {
+			if h.ID == preCommitHookID {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,206 @@
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileName is the ignore file controlling content access
+const FileName = ".contextpilotignore"
+
+// Defaults are always applied before the project's own rules, so secrets
+// are never read even without a .contextpilotignore. A project can re-allow
+// any of them with a "!" rule.
+var Defaults = []string{
+	".env",
+	".env.*",
+	"*.pem",
+	"*.key",
+	"*.p12",
+	"*.pfx",
+	"id_rsa*",
+	"id_ed25519*",
+	".npmrc",
+	".pypirc",
+	".netrc",
+	"secrets/",
+	".contextpilot/sessions/",
+}
+
+// Rule is one parsed pattern line
+type Rule struct {
+	Pattern string
+	Source  string // "default" or "<file>:<line>"
+	negate  bool
+	dirOnly bool
+	re      *regexp.Regexp
+}
+
+// Matcher decides which files content-based features may read. It never
+// affects analysis statistics: ignored files are still counted, but their
+// contents are never sampled, embedded, or sent anywhere.
+type Matcher struct {
+	rules []Rule
+}
+
+// Load builds a Matcher from the defaults plus rootPath/.contextpilotignore
+// (if present). A pattern that can't be compiled is an error naming its
+// line.
+func Load(rootPath string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, p := range Defaults {
+		if err := m.add(p, "default"); err != nil {
+			return nil, err
+		}
+	}
+
+	path := filepath.Join(rootPath, FileName)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", FileName, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if err := m.add(scanner.Text(), fmt.Sprintf("%s:%d", FileName, lineNo)); err != nil {
+			return nil, err
+		}
+	}
+	return m, scanner.Err()
+}
+
+// Allowed reports whether the content of rel (slash- or OS-separated,
+// relative to the project root) may be read
+func (m *Matcher) Allowed(rel string, isDir bool) bool {
+	rule := m.Match(rel, isDir)
+	return rule == nil || rule.negate
+}
+
+// Match returns the last rule that applies to rel, or nil. Like gitignore,
+// later rules override earlier ones.
+func (m *Matcher) Match(rel string, isDir bool) *Rule {
+	rel = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(rel)), "./")
+
+	var matched *Rule
+	for i := range m.rules {
+		r := &m.rules[i]
+		loc := r.re.FindStringSubmatchIndex(rel)
+		if loc == nil {
+			continue
+		}
+		// Directory-only rules match the directory itself or anything below it
+		inside := loc[2] != loc[3]
+		if r.dirOnly && !isDir && !inside {
+			continue
+		}
+		matched = r
+	}
+	return matched
+}
+
+// Negated reports whether the rule re-allows paths with "!"
+func (r *Rule) Negated() bool {
+	return r.negate
+}
+
+// ReadFile reads rootPath/rel if the matcher allows it
+func (m *Matcher) ReadFile(rootPath, rel string) ([]byte, error) {
+	if !m.Allowed(rel, false) {
+		return nil, fmt.Errorf("%s is excluded by %s", rel, FileName)
+	}
+	return os.ReadFile(filepath.Join(rootPath, rel))
+}
+
+func (m *Matcher) add(line, source string) error {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	r := Rule{Pattern: line, Source: source}
+	p := line
+	if strings.HasPrefix(p, "!") {
+		r.negate = true
+		p = p[1:]
+	}
+	if strings.HasSuffix(p, "/") {
+		r.dirOnly = true
+		p = strings.TrimRight(p, "/")
+	}
+	if p == "" {
+		return nil
+	}
+
+	// Patterns containing a slash are anchored to the root; bare names
+	// match at any depth
+	prefix := "(?:^|.*/)"
+	if strings.Contains(p, "/") {
+		prefix = "^"
+		p = strings.TrimPrefix(p, "/")
+	}
+
+	re, err := regexp.Compile(prefix + globToRegexp(p) + "(/.*)?$")
+	if err != nil {
+		return fmt.Errorf("%s: invalid pattern %q", source, line)
+	}
+	r.re = re
+	m.rules = append(m.rules, r)
+	return nil
+}
+
+// MatchGlob reports whether the slash-separated path rel matches glob,
+// using the same syntax as .contextpilotignore patterns. Unlike ignore
+// rules, the glob is always anchored to the root and must match the whole
+// path, so "src/auth/**" matches files below src/auth only.
+func MatchGlob(glob, rel string) bool {
+	glob = strings.TrimPrefix(filepath.ToSlash(glob), "./")
+	rel = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(rel)), "./")
+	re, err := regexp.Compile("^" + globToRegexp(glob) + "$")
+	return err == nil && re.MatchString(rel)
+}
+
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			if end := strings.IndexByte(glob[i:], ']'); end > 0 {
+				class := glob[i+1 : i+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				b.WriteString("[" + class + "]")
+				i += end
+			} else {
+				b.WriteString(`\[`)
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
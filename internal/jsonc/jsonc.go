@@ -0,0 +1,58 @@
+// Package jsonc reads the JSON-with-comments dialect of devcontainer.json,
+// deno.jsonc, tsconfig.json and the MCP client configs
+package jsonc
+
+import "bytes"
+
+// Strip turns JSONC into JSON by removing // and /* */ comments and
+// trailing commas. It reports whether there were any comments.
+func Strip(data []byte) ([]byte, bool) {
+	var out []byte
+	comments := false
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			comments = true
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			comments = true
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(data)
+			} else {
+				i += end + 3
+			}
+		case c == '}' || c == ']':
+			// Drop a trailing comma before the closing bracket
+			j := len(out) - 1
+			for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out, comments
+}
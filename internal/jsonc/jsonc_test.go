@@ -0,0 +1,24 @@
+package jsonc
+
+import "testing"
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		in, want string
+		comments bool
+	}{
+		{`{"a": 1}`, `{"a": 1}`, false},
+		{"{\n  // line\n  \"a\": 1\n}", "{\n  \n  \"a\": 1\n}", true},
+		{`{"a": /* block */ 1}`, `{"a":  1}`, true},
+		{`{"a": [1, 2,], "b": {"c": 3,},}`, `{"a": [1, 2], "b": {"c": 3}}`, false},
+		{`{"url": "https://deno.land/x", "s": "/* not a comment */"}`, `{"url": "https://deno.land/x", "s": "/* not a comment */"}`, false},
+		{`{"q": "say \"hi\" // still a string"}`, `{"q": "say \"hi\" // still a string"}`, false},
+		{`{"a": 1} /* unterminated`, `{"a": 1} `, true},
+	}
+	for _, tt := range tests {
+		got, comments := Strip([]byte(tt.in))
+		if string(got) != tt.want || comments != tt.comments {
+			t.Errorf("Strip(%q) = %q, %v, want %q, %v", tt.in, got, comments, tt.want, tt.comments)
+		}
+	}
+}
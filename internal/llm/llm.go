@@ -0,0 +1,197 @@
+// Package llm sends prompts to the language model configured under llm:
+// in .contextpilot/config.yaml or the user config: OpenAI-compatible
+// servers, Anthropic or a local Ollama
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+)
+
+// Providers lists the supported values of llm.provider
+var Providers = []string{"openai", "anthropic", "ollama"}
+
+// ErrNotConfigured is returned by Load when no provider is set
+var ErrNotConfigured = errors.New("no LLM configured: set llm.provider in .contextpilot/config.yaml or your user config")
+
+// defaults per provider
+var defaults = map[string]struct {
+	url, model, keyEnv string
+}{
+	"openai":    {"https://api.openai.com/v1", "gpt-4o-mini", "OPENAI_API_KEY"},
+	"anthropic": {"https://api.anthropic.com", "claude-3-5-haiku-latest", "ANTHROPIC_API_KEY"},
+	"ollama":    {"http://localhost:11434", "llama3.1", ""},
+}
+
+// Client completes prompts with one configured model
+type Client struct {
+	provider string
+	model    string
+	url      string
+	key      string
+	http     *http.Client
+}
+
+// Load returns a client for the LLM configured for the project at root,
+// falling back to the user config
+func Load(root string) (*Client, error) {
+	cfg, err := config.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	settings := cfg.LLM
+	if settings.Provider == "" {
+		user, err := config.LoadUser()
+		if err != nil {
+			return nil, err
+		}
+		settings = user.LLM
+	}
+	return New(settings)
+}
+
+// New returns a client for settings
+func New(settings config.LLM) (*Client, error) {
+	if settings.Provider == "" {
+		return nil, ErrNotConfigured
+	}
+	def, ok := defaults[settings.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown llm.provider %q (use %s)", settings.Provider, strings.Join(Providers, ", "))
+	}
+
+	c := &Client{
+		provider: settings.Provider,
+		model:    settings.Model,
+		url:      strings.TrimRight(os.ExpandEnv(settings.URL), "/"),
+		http:     &http.Client{Timeout: 2 * time.Minute},
+	}
+	if c.model == "" {
+		c.model = def.model
+	}
+	if c.url == "" {
+		c.url = def.url
+	}
+	if err := config.CheckOnline(c.url); err != nil {
+		return nil, err
+	}
+	keyEnv := settings.APIKeyEnv
+	if keyEnv == "" {
+		keyEnv = def.keyEnv
+	}
+	if keyEnv != "" {
+		c.key = os.Getenv(keyEnv)
+		// Ollama and self-hosted OpenAI-compatible servers may not need a key
+		if c.key == "" && settings.Provider == "anthropic" {
+			return nil, fmt.Errorf("no API key: set $%s", keyEnv)
+		}
+	}
+	return c, nil
+}
+
+// String names the provider and model, e.g. "openai gpt-4o-mini"
+func (c *Client) String() string {
+	return c.provider + " " + c.model
+}
+
+// Complete sends system and prompt to the model and returns its reply
+func (c *Client) Complete(ctx context.Context, system, prompt string, maxTokens int) (string, error) {
+	messages := []map[string]string{{"role": "user", "content": prompt}}
+
+	var target string
+	var body map[string]interface{}
+	switch c.provider {
+	case "anthropic":
+		target = c.url + "/v1/messages"
+		body = map[string]interface{}{"model": c.model, "system": system, "messages": messages, "max_tokens": maxTokens}
+	case "ollama":
+		target = c.url + "/api/chat"
+		messages = append([]map[string]string{{"role": "system", "content": system}}, messages...)
+		body = map[string]interface{}{"model": c.model, "messages": messages, "stream": false}
+	default:
+		target = c.url + "/chat/completions"
+		messages = append([]map[string]string{{"role": "system", "content": system}}, messages...)
+		body = map[string]interface{}{"model": c.model, "messages": messages, "max_tokens": maxTokens}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	switch {
+	case c.provider == "anthropic":
+		req.Header.Set("x-api-key", c.key)
+		req.Header.Set("anthropic-version", "2023-06-01")
+	case c.key != "":
+		req.Header.Set("Authorization", "Bearer "+c.key)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", c.provider, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s: %s", c.provider, resp.Status, strings.TrimSpace(string(raw)))
+	}
+
+	var reply struct {
+		// OpenAI
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		// Anthropic
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		// Ollama
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &reply); err != nil {
+		return "", fmt.Errorf("%s: invalid response: %w", c.provider, err)
+	}
+
+	var text string
+	switch c.provider {
+	case "anthropic":
+		for _, part := range reply.Content {
+			if part.Type == "text" {
+				text += part.Text
+			}
+		}
+	case "ollama":
+		text = reply.Message.Content
+	default:
+		if len(reply.Choices) > 0 {
+			text = reply.Choices[0].Message.Content
+		}
+	}
+	if text = strings.TrimSpace(text); text == "" {
+		return "", fmt.Errorf("%s: empty response", c.provider)
+	}
+	return text, nil
+}
@@ -0,0 +1,37 @@
+// Package lock serializes writes to a project's ContextPilot files, so the
+// CLI, the MCP server and the API server can run side by side without
+// interleaving their read-modify-write cycles
+package lock
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/jitin-nhz/contextpilot/internal/gitignore"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// Dir holds the lock files, which are never committed
+const Dir = ".contextpilot/locks"
+
+// Names of the project's locks
+const (
+	Sessions  = "sessions"
+	Decisions = "decisions"
+)
+
+// Acquire takes the named project lock in files and returns the function
+// that releases it
+func Acquire(files fsys.WriteFS, name string) (func(), error) {
+	if _, ok := files.(fsys.Locker); !ok {
+		return func() {}, nil
+	}
+	if err := gitignore.IgnoreDirFS(files, Dir); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	unlock, err := fsys.Lock(files, path.Join(Dir, name+".lock"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", name, err)
+	}
+	return unlock, nil
+}
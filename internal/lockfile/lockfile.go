@@ -0,0 +1,185 @@
+// Package lockfile reads the versions a project's lockfile resolved its
+// dependencies to, as opposed to the ranges its manifest asks for.
+package lockfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lockfiles are read in this order; the first one present wins
+var Lockfiles = []string{"pnpm-lock.yaml", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "go.mod"}
+
+// Read returns the resolved version of each dependency of the project at
+// root, by package name, and the lockfile it came from. A project without
+// a lockfile returns nil and "".
+func Read(root string) (map[string]string, string, error) {
+	for _, name := range Lockfiles {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		var versions map[string]string
+		switch name {
+		case "pnpm-lock.yaml":
+			versions, err = parsePnpm(data)
+		case "package-lock.json", "npm-shrinkwrap.json":
+			versions, err = parseNpm(data)
+		case "yarn.lock":
+			versions = parseYarn(data)
+		case "go.mod":
+			versions = parseGoMod(data)
+		}
+		if err != nil {
+			return nil, name, err
+		}
+		return versions, name, nil
+	}
+	return nil, "", nil
+}
+
+// parseNpm reads package-lock.json: "packages" keyed by install path in
+// lockfileVersion 2 and 3, "dependencies" keyed by name in version 1
+func parseNpm(data []byte) (map[string]string, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	versions := map[string]string{}
+	for p, pkg := range lock.Packages {
+		// Packages installed at the root or in a workspace package, not
+		// copies nested under another package
+		i := strings.LastIndex(p, "node_modules/")
+		if i < 0 || strings.Contains(p[:i], "node_modules/") || pkg.Version == "" {
+			continue
+		}
+		name := p[i+len("node_modules/"):]
+		if _, ok := versions[name]; !ok || i == 0 {
+			versions[name] = pkg.Version
+		}
+	}
+	for name, dep := range lock.Dependencies {
+		if _, ok := versions[name]; !ok && dep.Version != "" {
+			versions[name] = dep.Version
+		}
+	}
+	return versions, nil
+}
+
+// parsePnpm reads pnpm-lock.yaml: the importers (workspace packages) of
+// lockfile v6 and later, or the top-level dependencies of older versions
+func parsePnpm(data []byte) (map[string]string, error) {
+	type deps map[string]yaml.Node
+	var lock struct {
+		Importers map[string]struct {
+			Dependencies         deps `yaml:"dependencies"`
+			DevDependencies      deps `yaml:"devDependencies"`
+			OptionalDependencies deps `yaml:"optionalDependencies"`
+		} `yaml:"importers"`
+		Dependencies    deps `yaml:"dependencies"`
+		DevDependencies deps `yaml:"devDependencies"`
+	}
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	versions := map[string]string{}
+	add := func(d deps) {
+		for name, node := range d {
+			// v6+: {specifier: ^18.2.0, version: 18.3.1(react@18.3.1)}; v5: 18.3.1
+			v := node.Value
+			if node.Kind == yaml.MappingNode {
+				var entry struct {
+					Version string `yaml:"version"`
+				}
+				node.Decode(&entry)
+				v = entry.Version
+			}
+			v, _, _ = strings.Cut(v, "(")
+			if _, ok := versions[name]; !ok && v != "" && !strings.HasPrefix(v, "link:") {
+				versions[name] = v
+			}
+		}
+	}
+	// The root importer first, so its versions win over the packages'
+	if root, ok := lock.Importers["."]; ok {
+		add(root.Dependencies)
+		add(root.DevDependencies)
+		add(root.OptionalDependencies)
+	}
+	for _, imp := range lock.Importers {
+		add(imp.Dependencies)
+		add(imp.DevDependencies)
+		add(imp.OptionalDependencies)
+	}
+	add(lock.Dependencies)
+	add(lock.DevDependencies)
+	return versions, nil
+}
+
+// parseYarn reads yarn.lock, classic and Berry: an entry's header lists
+// the name@range pairs it resolves, its version line the version
+func parseYarn(data []byte) map[string]string {
+	versions := map[string]string{}
+	var names []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case !strings.HasPrefix(line, " "):
+			names = names[:0]
+			for _, spec := range strings.Split(strings.TrimSuffix(line, ":"), ",") {
+				spec = strings.Trim(strings.TrimSpace(spec), `"`)
+				// The version separator is the last @, not a scope's
+				if i := strings.LastIndex(spec, "@"); i > 0 {
+					names = append(names, spec[:i])
+				}
+			}
+		case strings.HasPrefix(strings.TrimSpace(line), "version"):
+			v := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "version"))
+			v = strings.Trim(strings.TrimSpace(strings.TrimPrefix(v, ":")), `"`)
+			for _, name := range names {
+				if _, ok := versions[name]; !ok {
+					versions[name] = v
+				}
+			}
+		}
+	}
+	return versions
+}
+
+// parseGoMod reads the require directives of go.mod, whose versions are
+// the ones the build uses
+func parseGoMod(data []byte) map[string]string {
+	versions := map[string]string{}
+	block := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			block = true
+		case block && fields[0] == ")":
+			block = false
+		case block && len(fields) >= 2:
+			versions[fields[0]] = fields[1]
+		case fields[0] == "require" && len(fields) >= 3:
+			versions[fields[1]] = fields[2]
+		}
+	}
+	return versions
+}
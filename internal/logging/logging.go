@@ -0,0 +1,177 @@
+// Package logging sets up ContextPilot's diagnostic log: structured slog
+// records on stderr when asked for with --verbose or --debug, and an
+// optional rotating file in .contextpilot/logs/ that keeps a debug trail of
+// every run
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/jitin-nhz/contextpilot/internal/gitignore"
+)
+
+// Dir holds the log files, which are never committed
+const Dir = ".contextpilot/logs"
+
+// FileName is the current log file in Dir
+const FileName = "contextpilot.log"
+
+// Rotation limits: the log is rotated once it grows past MaxSize, keeping
+// MaxBackups older files (contextpilot.log.1 is the most recent)
+const (
+	MaxSize    = 1 << 20
+	MaxBackups = 3
+)
+
+// Options configures Setup
+type Options struct {
+	// Level is the lowest level written to stderr; nil writes nothing there
+	Level *slog.Level
+	// Root, when set, is the project whose log file receives every record
+	// down to debug
+	Root string
+}
+
+// Setup installs the default slog logger described by opts. Records are
+// written as they happen, so there is nothing to flush at exit.
+func Setup(opts Options) error {
+	var handlers []slog.Handler
+	if opts.Level != nil {
+		handlers = append(handlers, slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+			Level: opts.Level,
+			// The terminal doesn't need timestamps on every line
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+	}
+
+	if opts.Root != "" {
+		dir := filepath.Join(opts.Root, filepath.FromSlash(Dir))
+		if err := gitignore.IgnoreDir(dir); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+		w := &rotatingFile{path: filepath.Join(dir, FileName)}
+		handlers = append(handlers, slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	var handler slog.Handler = discard{}
+	switch len(handlers) {
+	case 1:
+		handler = handlers[0]
+	case 2:
+		handler = fanout(handlers)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// discard drops every record, so library code logging through
+// slog.Default stays quiet unless logging was asked for
+type discard struct{}
+
+func (discard) Enabled(context.Context, slog.Level) bool  { return false }
+func (discard) Handle(context.Context, slog.Record) error { return nil }
+func (d discard) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discard) WithGroup(string) slog.Handler           { return d }
+
+// fanout sends each record to every handler that wants it
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+// rotatingFile appends to path, shifting it to path.1, path.2, ... once it
+// passes MaxSize. The file is opened on first write, so runs that log
+// nothing don't touch it.
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+}
+
+func (w *rotatingFile) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.size > 0 && w.size+int64(len(p)) > MaxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotatingFile) open() error {
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file, w.size = f, info.Size()
+	return nil
+}
+
+func (w *rotatingFile) rotate() error {
+	w.file.Close()
+	w.file = nil
+	for i := MaxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", w.path, i), fmt.Sprintf("%s.%d", w.path, i+1))
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return w.open()
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ClientCapabilities are the optional features a client declares in initialize
+type ClientCapabilities struct {
+	Sampling    *struct{} `json:"sampling,omitempty"`
+	Elicitation *struct{} `json:"elicitation,omitempty"`
+	Roots       *struct {
+		ListChanged bool `json:"listChanged,omitempty"`
+	} `json:"roots,omitempty"`
+}
+
+// outgoingRequest is a request the server sends to the client
+type outgoingRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// clientRequestTimeout bounds how long we wait for the client (and, for
+// sampling, the user approving the request and the model responding)
+const clientRequestTimeout = 2 * time.Minute
+
+// pendingCall is a request sent to the client whose response hasn't been
+// collected yet
+type pendingCall struct {
+	id     int64
+	method string
+	ch     chan *incoming
+}
+
+// call sends a request to the client and waits for its response, or until
+// ctx is done
+func (s *Server) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	return s.await(ctx, s.request(method, params))
+}
+
+// request sends a request to the client without waiting, so the read loop
+// can issue it and keep reading the response
+func (s *Server) request(method string, params interface{}) *pendingCall {
+	s.pendingMu.Lock()
+	s.nextID++
+	c := &pendingCall{id: s.nextID, method: method, ch: make(chan *incoming, 1)}
+	s.pending[c.id] = c.ch
+	s.pendingMu.Unlock()
+
+	s.send(outgoingRequest{JSONRPC: "2.0", ID: c.id, Method: method, Params: params})
+	return c
+}
+
+// await waits for the response to c, or until ctx is done
+func (s *Server) await(ctx context.Context, c *pendingCall) (json.RawMessage, error) {
+	defer func() {
+		s.pendingMu.Lock()
+		delete(s.pending, c.id)
+		s.pendingMu.Unlock()
+	}()
+
+	var resp *incoming
+	select {
+	case resp = <-c.ch:
+	case <-s.closed:
+		// A response read just before the client hung up still counts
+		select {
+		case resp = <-c.ch:
+		default:
+			return nil, fmt.Errorf("%s failed: client disconnected", c.method)
+		}
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%s: %w", c.method, ctx.Err())
+	case <-time.After(clientRequestTimeout):
+		return nil, fmt.Errorf("%s timed out after %s", c.method, clientRequestTimeout)
+	}
+
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s failed: %s", c.method, resp.Error.Message)
+	}
+	return resp.Result, nil
+}
+
+// deliver routes a client response to the goroutine waiting in call
+func (s *Server) deliver(msg *incoming) {
+	// JSON numbers decode as float64
+	num, ok := msg.ID.(float64)
+	if !ok {
+		return
+	}
+
+	s.pendingMu.Lock()
+	ch := s.pending[int64(num)]
+	s.pendingMu.Unlock()
+
+	if ch == nil {
+		return
+	}
+	// The channel holds one response; a duplicate, or one arriving after
+	// call gave up, is dropped rather than blocking the read loop
+	select {
+	case ch <- msg:
+	default:
+	}
+}
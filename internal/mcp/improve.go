@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// defaultImproveSection is the CLAUDE.md section contextpilot_improve rewrites
+const defaultImproveSection = "Coding Conventions"
+
+// improveEnabled reports whether contextpilot_improve can run: it needs
+// sampling (opted in and supported) to draft, and elicitation to confirm
+func (s *Server) improveEnabled() bool {
+	return s.samplingEnabled() && s.clientCaps.Elicitation != nil
+}
+
+func improveTool() Tool {
+	return Tool{
+		Name:        "contextpilot_improve",
+		Description: "Use the client's model to draft an improved CLAUDE.md section, then ask the user to confirm before writing it. Note that 'contextpilot sync' regenerates CLAUDE.md.",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"section": {Type: "string", Description: "CLAUDE.md section to improve (default: Coding Conventions)"},
+				"focus":   {Type: "string", Description: "What the new section should cover or fix"},
+			},
+		},
+	}
+}
+
+// elicit asks the user to confirm message through the client. It reports
+// whether they accepted and ticked the confirmation box.
+func (s *Server) elicit(ctx context.Context, message, field, title string) (bool, error) {
+	params := map[string]interface{}{
+		"message": message,
+		"requestedSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				field: map[string]interface{}{"type": "boolean", "title": title, "default": true},
+			},
+			"required": []string{field},
+		},
+	}
+
+	raw, err := s.call(ctx, "elicitation/create", params)
+	if err != nil {
+		return false, err
+	}
+
+	var result struct {
+		Action  string          `json:"action"`
+		Content map[string]bool `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return false, fmt.Errorf("invalid elicitation result: %w", err)
+	}
+	return result.Action == "accept" && result.Content[field], nil
+}
+
+// toolImprove drafts a CLAUDE.md section with the client's model and
+// writes it once the user confirms. timeout bounds the analysis only.
+func (s *Server) toolImprove(ctx context.Context, root string, args json.RawMessage, timeout time.Duration) (string, error) {
+	var params struct {
+		Section string `json:"section"`
+		Focus   string `json:"focus"`
+	}
+	json.Unmarshal(args, &params)
+	section := strings.TrimSpace(strings.TrimLeft(params.Section, "# "))
+	if section == "" {
+		section = defaultImproveSection
+	}
+
+	path := filepath.Join(root, "CLAUDE.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("CLAUDE.md not found, run contextpilot_sync first")
+		}
+		return "", fmt.Errorf("failed to read CLAUDE.md: %w", err)
+	}
+
+	actx, cancel := withTimeout(ctx, timeout)
+	analysis, err := analyzer.New(root).AnalyzeContext(actx)
+	cancel()
+	if err != nil {
+		return "", partial(err, analysis, nil)
+	}
+	facts, _ := json.Marshal(analysis)
+
+	prompt := fmt.Sprintf("Current CLAUDE.md:\n\n%s\n\nDetected project facts (JSON):\n%s\n\nRewrite the \"## %s\" section.", data, facts, section)
+	if params.Focus != "" {
+		prompt += " Focus on: " + params.Focus
+	}
+	draft, err := s.sample(ctx,
+		"You improve CLAUDE.md context files for AI coding assistants. Reply with only the body of the requested section as concise markdown bullet points: concrete, project-specific conventions an assistant must follow. No heading, no preamble.",
+		prompt,
+		800,
+	)
+	if err != nil {
+		return "", err
+	}
+	draft = stripHeading(draft, section)
+	if draft == "" {
+		return "", fmt.Errorf("model returned an empty section")
+	}
+
+	ok, err := s.elicit(ctx,
+		fmt.Sprintf("Replace the \"%s\" section of CLAUDE.md with:\n\n%s", section, draft),
+		"apply", "Write to CLAUDE.md",
+	)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return fmt.Sprintf("Not written. Draft \"%s\" section:\n\n%s", section, draft), nil
+	}
+
+	content := replaceSection(string(data), section, draft)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write CLAUDE.md: %w", err)
+	}
+	s.log("info", "Updated the %s section of %s", section, path)
+	return fmt.Sprintf("Updated the \"%s\" section of CLAUDE.md", section), nil
+}
+
+// stripHeading drops a leading "## section" line the model may echo back
+func stripHeading(draft, section string) string {
+	lines := strings.SplitN(strings.TrimSpace(draft), "\n", 2)
+	if strings.EqualFold(strings.TrimSpace(strings.TrimLeft(lines[0], "#")), section) {
+		if len(lines) == 1 {
+			return ""
+		}
+		return strings.TrimSpace(lines[1])
+	}
+	return strings.TrimSpace(draft)
+}
+
+// replaceSection replaces the body of the "## heading" section of a
+// markdown document, up to the next "## " heading or "---" rule. A missing
+// section is inserted before the trailing rule, or appended.
+func replaceSection(content, heading, body string) string {
+	lines := strings.Split(content, "\n")
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if start == -1 {
+			if strings.HasPrefix(trimmed, "## ") && strings.EqualFold(strings.TrimSpace(trimmed[3:]), heading) {
+				start = i
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "## ") || trimmed == "---" {
+			end = i
+			break
+		}
+	}
+
+	section := []string{"## " + heading, "", body, ""}
+	if start == -1 {
+		// Keep the "Managed by" footer last
+		for i := len(lines) - 1; i >= 0; i-- {
+			if strings.TrimSpace(lines[i]) == "---" {
+				start, end = i, i
+				break
+			}
+		}
+		if start == -1 {
+			return strings.TrimRight(content, "\n") + "\n\n" + strings.Join(section, "\n")
+		}
+	}
+
+	out := append([]string{}, lines[:start]...)
+	out = append(out, section...)
+	out = append(out, lines[end:]...)
+	return strings.Join(out, "\n")
+}
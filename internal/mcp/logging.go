@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// logLevels are the MCP (syslog) levels, least severe first
+var logLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+// defaultLogLevel applies until the client calls logging/setLevel
+const defaultLogLevel = "warning"
+
+func levelRank(level string) int {
+	for i, l := range logLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) handleSetLevel(req *Request) {
+	var params struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || levelRank(params.Level) < 0 {
+		s.sendError(req.ID, -32602, fmt.Sprintf("Invalid log level %q", params.Level))
+		return
+	}
+
+	s.logMu.Lock()
+	s.logLevel = params.Level
+	s.logMu.Unlock()
+
+	s.sendResult(req.ID, map[string]interface{}{})
+}
+
+// maxTrace caps how much of a message is written to the debug log
+const maxTrace = 4096
+
+// traceText returns msg for the debug log, truncated to maxTrace bytes
+func traceText(msg []byte) string {
+	if len(msg) <= maxTrace {
+		return string(msg)
+	}
+	return fmt.Sprintf("%s... (%d bytes)", msg[:maxTrace], len(msg))
+}
+
+// log sends a notifications/message to the client when level is at or
+// above the level it asked for
+func (s *Server) log(level, format string, args ...interface{}) {
+	s.logMu.Lock()
+	min := s.logLevel
+	s.logMu.Unlock()
+	if min == "" {
+		min = defaultLogLevel
+	}
+	if levelRank(level) < levelRank(min) {
+		return
+	}
+
+	s.notify("notifications/message", map[string]interface{}{
+		"level":  level,
+		"logger": "contextpilot",
+		"data":   fmt.Sprintf(format, args...),
+	})
+}
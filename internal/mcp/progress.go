@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// phaseMessages describe analyzer phases to the user
+var phaseMessages = map[string]string{
+	"walk":      "Walking files",
+	"framework": "Detecting framework",
+	"structure": "Analyzing structure",
+	"patterns":  "Detecting patterns",
+	"generate":  "Writing context files",
+}
+
+// progress sends notifications/progress for one tool call. The zero value
+// (no progress token from the client) sends nothing. Either way it records
+// how far the call got, for the report when it times out.
+type progress struct {
+	s     *Server
+	token json.RawMessage
+	mu    sync.Mutex
+	step  int
+
+	// current is the phase the call is in; done are those it finished
+	current string
+	done    []string
+	files   int
+}
+
+func (s *Server) newProgress(token json.RawMessage) *progress {
+	if len(token) == 0 || string(token) == "null" {
+		return &progress{}
+	}
+	return &progress{s: s, token: token}
+}
+
+// phase reports that the call reached phase, having walked files so far.
+// Progress only ever increases, as the spec requires.
+func (p *progress) phase(phase string, files int) {
+	p.mu.Lock()
+	if phase != p.current {
+		if p.current != "" {
+			p.done = append(p.done, p.current)
+		}
+		p.current = phase
+	}
+	p.files = max(p.files, files)
+	p.mu.Unlock()
+
+	if p.s == nil {
+		return
+	}
+	msg := phaseMessages[phase]
+	if msg == "" {
+		msg = phase
+	}
+	if files > 0 {
+		msg = fmt.Sprintf("%s (%d files walked)", msg, files)
+	}
+
+	p.mu.Lock()
+	p.step++
+	step := p.step
+	p.mu.Unlock()
+
+	p.s.notify("notifications/progress", map[string]interface{}{
+		"progressToken": p.token,
+		"progress":      step,
+		"message":       msg,
+	})
+}
+
+// reached returns the phase the call is in, the phases it finished and how
+// many files it walked
+func (p *progress) reached() (current string, done []string, files int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current, append([]string{}, p.done...), p.files
+}
@@ -0,0 +1,202 @@
+package mcp
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+)
+
+// Resource URI prefixes served through templates
+const (
+	contextURIPrefix = "contextpilot://context/"
+	decisionsURI     = "contextpilot://decisions"
+)
+
+// ResourceTemplate describes a family of resources by URI template (RFC 6570)
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// packageDirs hold the packages of a monorepo
+var packageDirs = []string{"apps", "packages", "services", "libs", "modules"}
+
+// packageManifests mark a directory as a package
+var packageManifests = []string{"package.json", "go.mod", "pyproject.toml", "Cargo.toml", "deno.json"}
+
+func (s *Server) handleResourceTemplatesList(req *Request) {
+	templates := []ResourceTemplate{
+		{
+			URITemplate: contextURIPrefix + "{path}",
+			Name:        "Package Context",
+			Description: "Context for one directory of a monorepo, e.g. contextpilot://context/apps/web",
+			MimeType:    "text/markdown",
+		},
+		{
+			URITemplate: decisionsURI + "{?tag,status}",
+			Name:        "Decisions",
+			Description: "Architectural decisions, optionally filtered by tag or status, e.g. contextpilot://decisions?tag=db",
+			MimeType:    "text/markdown",
+		},
+	}
+	s.sendResult(req.ID, map[string]interface{}{"resourceTemplates": templates})
+}
+
+// packageResources lists a context resource per monorepo package
+func packageResources(root string) []Resource {
+	var resources []Resource
+	for _, pkg := range workspacePackages(root) {
+		resources = append(resources, Resource{
+			URI:         contextURIPrefix + pkg,
+			Name:        "Context: " + pkg,
+			Description: "Project context scoped to " + pkg,
+			MimeType:    "text/markdown",
+		})
+	}
+	return resources
+}
+
+// workspacePackages returns the slash-separated paths of directories under
+// the usual monorepo folders that have a package manifest
+func workspacePackages(root string) []string {
+	var pkgs []string
+	for _, dir := range packageDirs {
+		entries, err := os.ReadDir(filepath.Join(root, dir))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			for _, m := range packageManifests {
+				if _, err := os.Stat(filepath.Join(root, dir, e.Name(), m)); err == nil {
+					pkgs = append(pkgs, dir+"/"+e.Name())
+					break
+				}
+			}
+		}
+	}
+	sort.Strings(pkgs)
+	return pkgs
+}
+
+// readPackageContext returns the context for a directory inside root: its
+// own CLAUDE.md when it has one, otherwise context generated from analyzing
+// just that directory, followed by the project decisions linked to it
+func readPackageContext(root, rel string) (string, error) {
+	rel = path.Clean(strings.Trim(rel, "/"))
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") || path.IsAbs(rel) {
+		return "", fmt.Errorf("invalid context path %q", rel)
+	}
+	dir := filepath.Join(root, filepath.FromSlash(rel))
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("no such directory: %s", rel)
+	}
+
+	var content string
+	if data, err := os.ReadFile(filepath.Join(dir, "CLAUDE.md")); err == nil {
+		content = string(data)
+	} else {
+		analysis, err := analyzer.New(dir).Analyze()
+		if err != nil {
+			return "", err
+		}
+		content = generator.New(analysis, dir).Preview()["CLAUDE.md"]
+	}
+
+	decs, _ := decisions.New(root).List()
+	var related []string
+	for _, d := range decs {
+		if d.Active() && linksPath(d, rel) {
+			related = append(related, fmt.Sprintf("- **%s:** %s", d.Date, d.Text))
+		}
+	}
+	if len(related) > 0 {
+		content = strings.TrimRight(content, "\n") + "\n\n## Project Decisions for " + rel + "\n\n" + strings.Join(related, "\n") + "\n"
+	}
+	return content, nil
+}
+
+// linksPath reports whether d links files inside rel (or rel's parents)
+func linksPath(d decisions.Decision, rel string) bool {
+	for _, f := range d.Files {
+		f = strings.TrimSuffix(strings.TrimSuffix(path.Clean(f), "/*"), "/**")
+		if f == rel || strings.HasPrefix(f, rel+"/") || strings.HasPrefix(rel, f+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// readDecisions renders decisions filtered by the tag and status query
+// parameters of uri
+func readDecisions(root, uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid resource URI: %w", err)
+	}
+	tag := u.Query().Get("tag")
+	status := u.Query().Get("status")
+
+	decs, err := decisions.New(root).List()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Decisions")
+	if tag != "" {
+		sb.WriteString(" tagged " + tag)
+	}
+	if status != "" {
+		sb.WriteString(" (" + status + ")")
+	}
+	sb.WriteString("\n\n")
+
+	n := 0
+	for _, d := range decs {
+		if tag != "" && !hasTag(d, tag) {
+			continue
+		}
+		if status != "" && !strings.EqualFold(decisionStatus(d), status) {
+			continue
+		}
+		n++
+		sb.WriteString(fmt.Sprintf("- **#%d %s:** %s", d.ID, d.Date, d.Text))
+		if d.Context != "" {
+			sb.WriteString(" — " + d.Context)
+		}
+		sb.WriteString("\n")
+	}
+	if n == 0 {
+		sb.WriteString("No matching decisions.\n")
+	}
+	return sb.String(), nil
+}
+
+func hasTag(d decisions.Decision, tag string) bool {
+	for _, t := range d.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func decisionStatus(d decisions.Decision) string {
+	if d.Status == "" {
+		return "accepted"
+	}
+	return d.Status
+}
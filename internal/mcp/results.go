@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ToolResult is the result of a tools/call. Data-returning tools set
+// StructuredContent and mirror it as JSON text for clients that only read
+// content blocks.
+type ToolResult struct {
+	Content           []ContentBlock `json:"content"`
+	StructuredContent interface{}    `json:"structuredContent,omitempty"`
+	IsError           bool           `json:"isError,omitempty"`
+}
+
+// ContentBlock is one piece of tool output
+type ContentBlock struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// textResult returns plain text or markdown
+func textResult(text string) *ToolResult {
+	return &ToolResult{Content: []ContentBlock{{Type: "text", Text: text}}}
+}
+
+// jsonResult returns v as structured content plus an indented JSON text
+// block. v must marshal to a JSON object.
+func jsonResult(v interface{}) (*ToolResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	return &ToolResult{
+		Content:           []ContentBlock{{Type: "text", Text: string(data), MimeType: "application/json"}},
+		StructuredContent: v,
+	}, nil
+}
+
+// errorResult reports a tool failure to the model rather than as a
+// protocol error, so it can correct the call
+func errorResult(err error) *ToolResult {
+	return &ToolResult{
+		Content: []ContentBlock{{Type: "text", Text: fmt.Sprintf("Error: %v", err)}},
+		IsError: true,
+	}
+}
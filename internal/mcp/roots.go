@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// rootsWait bounds how long a request waits for the client's first roots
+// before falling back to the server's working directory
+const rootsWait = 5 * time.Second
+
+// workspaceRoot is a directory the client exposes to the server
+type workspaceRoot struct {
+	Name string
+	Path string
+}
+
+// fetchRoots asks a roots-capable client for its roots. The request is sent
+// from the read loop so the response can't arrive before it is registered;
+// the response is processed in the background.
+func (s *Server) fetchRoots() {
+	if s.clientCaps.Roots == nil {
+		return
+	}
+	c := s.request("roots/list", nil)
+	go func() {
+		defer s.rootsLoaded()
+
+		raw, err := s.await(context.Background(), c)
+		if err != nil {
+			s.log("warning", "Could not list client roots: %v", err)
+			return
+		}
+		var result struct {
+			Roots []struct {
+				URI  string `json:"uri"`
+				Name string `json:"name"`
+			} `json:"roots"`
+		}
+		if err := json.Unmarshal(raw, &result); err != nil {
+			s.log("warning", "Invalid roots/list result: %v", err)
+			return
+		}
+
+		var roots []workspaceRoot
+		for _, r := range result.Roots {
+			path, err := fileURIPath(r.URI)
+			if err != nil {
+				s.log("warning", "Ignoring root %s: %v", r.URI, err)
+				continue
+			}
+			roots = append(roots, workspaceRoot{Name: r.Name, Path: path})
+		}
+
+		s.rootsMu.Lock()
+		s.roots = roots
+		s.rootsMu.Unlock()
+		s.log("info", "Serving %d client root(s)", len(roots))
+	}()
+}
+
+// rootsLoaded unblocks requests waiting for the first roots/list response
+func (s *Server) rootsLoaded() {
+	s.rootsOnce.Do(func() { close(s.rootsReady) })
+}
+
+// clientRoots returns the client's roots, waiting briefly for the first
+// roots/list response when the client supports roots
+func (s *Server) clientRoots() []workspaceRoot {
+	if s.clientCaps.Roots != nil {
+		select {
+		case <-s.rootsReady:
+		case <-time.After(rootsWait):
+		}
+	}
+	s.rootsMu.Lock()
+	defer s.rootsMu.Unlock()
+	return s.roots
+}
+
+// defaultRoot is the client's first root, or the server's working
+// directory for clients without roots
+func (s *Server) defaultRoot() string {
+	if roots := s.clientRoots(); len(roots) > 0 {
+		return roots[0].Path
+	}
+	return s.rootPath
+}
+
+// resolveRoot maps a tool's "root" argument to a project directory. It
+// accepts a root name, a file:// URI or a path; relative paths (such as a
+// monorepo package) are taken from the default root. The result must lie
+// within one of the client's roots.
+func (s *Server) resolveRoot(requested string) (string, error) {
+	roots := s.clientRoots()
+	if len(roots) == 0 {
+		roots = []workspaceRoot{{Path: s.rootPath}}
+	}
+	if requested == "" {
+		return roots[0].Path, nil
+	}
+
+	for _, r := range roots {
+		if r.Name != "" && r.Name == requested {
+			return r.Path, nil
+		}
+	}
+
+	path := requested
+	if strings.HasPrefix(requested, "file://") {
+		p, err := fileURIPath(requested)
+		if err != nil {
+			return "", err
+		}
+		path = p
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(roots[0].Path, path)
+	}
+	path = filepath.Clean(path)
+
+	for _, r := range roots {
+		if rel, err := filepath.Rel(r.Path, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("root %s is outside the client's roots", requested)
+}
+
+func fileURIPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported root URI scheme %q", u.Scheme)
+	}
+	return filepath.Clean(filepath.FromSlash(u.Path)), nil
+}
@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+)
+
+// SamplingMessage is one message in a sampling/createMessage request
+type SamplingMessage struct {
+	Role    string          `json:"role"`
+	Content SamplingContent `json:"content"`
+}
+
+// SamplingContent is text content exchanged with the client's model
+type SamplingContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// samplingEnabled reports whether the project opted in to sampling and the
+// connected client supports it
+func (s *Server) samplingEnabled() bool {
+	if s.clientCaps.Sampling == nil {
+		return false
+	}
+	cfg, err := config.Load(s.defaultRoot())
+	return err == nil && cfg.MCP.Sampling
+}
+
+func samplingTools() []Tool {
+	return []Tool{
+		{
+			Name:        "contextpilot_summarize_session",
+			Description: "Use the client's model to condense the saved session into a compact resume block",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"name": {Type: "string", Description: "Session name (omit for the branch's default session)"},
+				},
+			},
+		},
+		{
+			Name:        "contextpilot_draft_decision",
+			Description: "Use the client's model to draft an architectural decision record from conversation text",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"conversation": {Type: "string", Description: "Conversation or notes the decision was made in"},
+					"save":         {Type: "boolean", Description: "Log the drafted decision instead of only returning it"},
+				},
+				Required: []string{"conversation"},
+			},
+		},
+	}
+}
+
+// sample asks the client's model to complete prompt
+func (s *Server) sample(ctx context.Context, system, prompt string, maxTokens int) (string, error) {
+	params := map[string]interface{}{
+		"messages": []SamplingMessage{
+			{Role: "user", Content: SamplingContent{Type: "text", Text: prompt}},
+		},
+		"systemPrompt":   system,
+		"maxTokens":      maxTokens,
+		"includeContext": "none",
+	}
+
+	raw, err := s.call(ctx, "sampling/createMessage", params)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Content SamplingContent `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return "", fmt.Errorf("invalid sampling result: %w", err)
+	}
+	if result.Content.Type != "text" {
+		return "", fmt.Errorf("unexpected sampling content type %q", result.Content.Type)
+	}
+	return strings.TrimSpace(result.Content.Text), nil
+}
+
+func (s *Server) toolSummarizeSession(ctx context.Context, root string, args json.RawMessage) (string, error) {
+	var params struct {
+		Name string `json:"name"`
+	}
+	json.Unmarshal(args, &params)
+
+	mgr := session.New(root)
+	sess, err := mgr.LoadNamed(params.Name)
+	if err != nil {
+		return "", err
+	}
+	if sess == nil {
+		return "No saved session for this branch", nil
+	}
+
+	summary, err := s.sample(ctx,
+		"You compress developer work-session notes into a short resume block for an AI coding assistant. Keep concrete names, files, and next steps. Use at most 8 markdown bullet points.",
+		"Summarize this work session:\n\n"+mgr.GeneratePrompt(sess),
+		500,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return "## Session Summary\n\n" + summary + "\n", nil
+}
+
+func (s *Server) toolDraftDecision(ctx context.Context, root string, args json.RawMessage) (string, error) {
+	var params struct {
+		Conversation string `json:"conversation"`
+		Save         bool   `json:"save"`
+	}
+	json.Unmarshal(args, &params)
+	if strings.TrimSpace(params.Conversation) == "" {
+		return "", fmt.Errorf("conversation is required")
+	}
+
+	draft, err := s.sample(ctx,
+		"You extract architectural decisions from engineering conversations. Reply with exactly two lines:\nDECISION: <one sentence stating what was decided>\nCONTEXT: <one or two sentences on why>",
+		params.Conversation,
+		300,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	text, context := parseDecisionDraft(draft)
+	if text == "" {
+		return "", fmt.Errorf("model did not return a decision: %s", draft)
+	}
+
+	if !params.Save {
+		return fmt.Sprintf("Draft decision (not saved):\n\nDecision: %s\nContext: %s", text, context), nil
+	}
+
+	dec, err := decisions.New(root).Add(text, context)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Decision #%d logged: %s", dec.ID, text), nil
+}
+
+// parseDecisionDraft extracts the DECISION/CONTEXT lines from a model reply
+func parseDecisionDraft(draft string) (text, context string) {
+	for _, line := range strings.Split(draft, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(strings.ToUpper(line), "DECISION:"):
+			text = strings.TrimSpace(line[len("DECISION:"):])
+		case strings.HasPrefix(strings.ToUpper(line), "CONTEXT:"):
+			context = strings.TrimSpace(line[len("CONTEXT:"):])
+		}
+	}
+	return text, context
+}
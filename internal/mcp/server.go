@@ -1,16 +1,30 @@
 package mcp
 
 import (
-	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
-
-	"github.com/jitin-nhz/contextpilot/internal/analyzer"
-	"github.com/jitin-nhz/contextpilot/internal/decisions"
-	"github.com/jitin-nhz/contextpilot/internal/generator"
-	"github.com/jitin-nhz/contextpilot/internal/session"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/api"
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/drift"
+	"github.com/jitin-nhz/contextpilot/internal/explain"
+	"github.com/jitin-nhz/contextpilot/internal/redact"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
 )
 
 // JSON-RPC types
@@ -40,14 +54,35 @@ type ServerInfo struct {
 }
 
 type InitializeResult struct {
-	ProtocolVersion string       `json:"protocolVersion"`
-	ServerInfo      ServerInfo   `json:"serverInfo"`
-	Capabilities    Capabilities `json:"capabilities"`
+	ProtocolVersion string                 `json:"protocolVersion"`
+	ServerInfo      ServerInfo             `json:"serverInfo"`
+	Capabilities    Capabilities           `json:"capabilities"`
+	Meta            map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// protocolVersions are the MCP revisions the server speaks, newest first
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// negotiateProtocol returns the client's requested revision when the server
+// supports it, and the newest supported revision otherwise (the client then
+// decides whether it can continue)
+func negotiateProtocol(requested string) string {
+	for _, v := range protocolVersions {
+		if v == requested {
+			return v
+		}
+	}
+	return protocolVersions[0]
 }
 
+// apiVersionKey is the _meta key clients use to request a ContextPilot
+// API version in initialize, and the server uses to confirm it
+const apiVersionKey = "contextpilot/apiVersion"
+
 type Capabilities struct {
 	Tools     *ToolsCapability     `json:"tools,omitempty"`
 	Resources *ResourcesCapability `json:"resources,omitempty"`
+	Logging   *struct{}            `json:"logging,omitempty"`
 }
 
 type ToolsCapability struct {
@@ -93,47 +128,176 @@ type ResourceContent struct {
 type Server struct {
 	rootPath string
 	version  string
+	// timeout overrides mcp.timeout for every tool call when set
+	timeout time.Duration
+
+	in    io.Reader
+	out   io.Writer
+	outMu sync.Mutex
+
+	// closed is closed once the client goes away, failing pending requests
+	closed chan struct{}
+
+	// Requests sent to the client (e.g. sampling) awaiting a response
+	pendingMu sync.Mutex
+	pending   map[int64]chan *incoming
+	nextID    int64
+
+	clientCaps      ClientCapabilities
+	apiVersion      int
+	protocolVersion string
+
+	logMu    sync.Mutex
+	logLevel string
+
+	subsMu        sync.Mutex
+	subscriptions map[string]bool
+
+	// Workspace roots reported by the client (roots capability)
+	rootsMu    sync.Mutex
+	roots      []workspaceRoot
+	rootsReady chan struct{}
+	rootsOnce  sync.Once
+}
+
+// incoming is any message read from the client: a request, a notification,
+// or a response to a request the server sent
+type incoming struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
 }
 
 // NewServer creates a new MCP server
 func NewServer(rootPath, version string) *Server {
 	return &Server{
-		rootPath: rootPath,
-		version:  version,
+		rootPath:   rootPath,
+		version:    version,
+		in:         os.Stdin,
+		out:        os.Stdout,
+		closed:     make(chan struct{}),
+		pending:    make(map[int64]chan *incoming),
+		apiVersion: api.Selected(),
+
+		subscriptions: make(map[string]bool),
+		rootsReady:    make(chan struct{}),
 	}
 }
 
-// Run starts the MCP server on stdio
+// Run starts the MCP server on stdio. It returns once stdin is closed or
+// the process gets SIGINT/SIGTERM, after in-flight requests have had
+// shutdownGrace to write their responses.
 func (s *Server) Run() error {
-	scanner := bufio.NewScanner(os.Stdin)
-	// Increase buffer size for large messages
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
+	done := make(chan struct{})
+	defer close(done)
+	go s.watchBranch(done)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	readErr := make(chan error, 1)
+	lines := readMessages(s.in, readErr)
+
+	var wg sync.WaitGroup
+	defer s.drain(&wg)
+
+	for {
+		var line []byte
+		var ok bool
+		select {
+		case line, ok = <-lines:
+		case err := <-readErr:
+			return fmt.Errorf("failed to read request: %w", err)
+		case sig := <-sigs:
+			s.log("info", "Shutting down on %s", sig)
+			return nil
+		}
+		if !ok {
+			return nil
+		}
+		if line == nil {
+			s.sendError(nil, -32600, fmt.Sprintf("Request too large (limit %d MB)", maxMessageSize/(1024*1024)))
 			continue
 		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		slog.Debug("mcp received", "message", traceText(line))
 
-		var req Request
-		if err := json.Unmarshal([]byte(line), &req); err != nil {
+		var msg incoming
+		if err := json.Unmarshal(line, &msg); err != nil {
 			s.sendError(nil, -32700, "Parse error")
 			continue
 		}
 
-		s.handleRequest(&req)
+		// Responses to our own requests are routed to the waiting caller
+		if msg.Method == "" {
+			s.deliver(&msg)
+			continue
+		}
+
+		if msg.Method == "notifications/initialized" || msg.Method == "notifications/roots/list_changed" {
+			s.fetchRoots()
+			continue
+		}
+
+		// The handshake and log level changes run inline so they take effect
+		// before any later, concurrently handled request
+		if msg.Method == "initialize" || msg.Method == "logging/setLevel" {
+			s.handleRequest(&Request{JSONRPC: msg.JSONRPC, ID: msg.ID, Method: msg.Method, Params: msg.Params})
+			continue
+		}
+
+		// Handle requests concurrently so a tool call waiting on the client
+		// (sampling) doesn't block reading the client's reply
+		req := &Request{JSONRPC: msg.JSONRPC, ID: msg.ID, Method: msg.Method, Params: msg.Params}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handleRequest(req)
+		}()
 	}
+}
 
-	return scanner.Err()
+// drain fails requests still waiting on the client and gives in-flight
+// handlers shutdownGrace to finish writing their responses
+func (s *Server) drain(wg *sync.WaitGroup) {
+	close(s.closed)
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(shutdownGrace):
+	}
 }
 
 func (s *Server) handleRequest(req *Request) {
+	// Notifications never get a response
+	if strings.HasPrefix(req.Method, "notifications/") {
+		return
+	}
+	start := time.Now()
+	defer func() {
+		slog.Debug("mcp handled", "method", req.Method, "id", req.ID, "duration", time.Since(start))
+	}()
+
 	switch req.Method {
 	case "initialize":
 		s.handleInitialize(req)
 	case "initialized":
 		// Notification, no response needed
+	case "ping":
+		s.sendResult(req.ID, map[string]interface{}{})
+	case "logging/setLevel":
+		s.handleSetLevel(req)
 	case "tools/list":
 		s.handleToolsList(req)
 	case "tools/call":
@@ -142,22 +306,47 @@ func (s *Server) handleRequest(req *Request) {
 		s.handleResourcesList(req)
 	case "resources/read":
 		s.handleResourcesRead(req)
+	case "resources/templates/list":
+		s.handleResourceTemplatesList(req)
+	case "resources/subscribe":
+		s.handleSubscribe(req, true)
+	case "resources/unsubscribe":
+		s.handleSubscribe(req, false)
 	default:
 		s.sendError(req.ID, -32601, fmt.Sprintf("Method not found: %s", req.Method))
 	}
 }
 
 func (s *Server) handleInitialize(req *Request) {
+	var params struct {
+		ProtocolVersion string                 `json:"protocolVersion"`
+		Capabilities    ClientCapabilities     `json:"capabilities"`
+		Meta            map[string]interface{} `json:"_meta"`
+	}
+	if len(req.Params) > 0 {
+		json.Unmarshal(req.Params, &params)
+	}
+	s.clientCaps = params.Capabilities
+	s.protocolVersion = negotiateProtocol(params.ProtocolVersion)
+
+	requested := 0
+	if n, ok := params.Meta[apiVersionKey].(float64); ok {
+		requested = int(n)
+	}
+	s.apiVersion = api.Negotiate(requested)
+
 	result := InitializeResult{
-		ProtocolVersion: "2024-11-05",
+		ProtocolVersion: s.protocolVersion,
 		ServerInfo: ServerInfo{
 			Name:    "contextpilot",
 			Version: s.version,
 		},
 		Capabilities: Capabilities{
 			Tools:     &ToolsCapability{},
-			Resources: &ResourcesCapability{},
+			Resources: &ResourcesCapability{Subscribe: true},
+			Logging:   &struct{}{},
 		},
+		Meta: map[string]interface{}{apiVersionKey: s.apiVersion},
 	}
 	s.sendResult(req.ID, result)
 }
@@ -174,6 +363,9 @@ func (s *Server) handleToolsList(req *Request) {
 					"goal":  {Type: "string", Description: "Why you are doing it"},
 					"state": {Type: "string", Description: "Current progress/state"},
 					"notes": {Type: "string", Description: "Additional notes"},
+					"name":  {Type: "string", Description: "Session name (omit for the branch's default session)"},
+					"issue": {Type: "string", Description: "Linked issue URL or key (detected from the branch name if omitted)"},
+					"pr":    {Type: "string", Description: "Linked pull request URL or number"},
 				},
 				Required: []string{"task"},
 			},
@@ -183,6 +375,9 @@ func (s *Server) handleToolsList(req *Request) {
 			Description: "Get saved session context for current branch",
 			InputSchema: InputSchema{
 				Type: "object",
+				Properties: map[string]Property{
+					"name": {Type: "string", Description: "Session name (omit for the branch's default session)"},
+				},
 			},
 		},
 		{
@@ -204,6 +399,53 @@ func (s *Server) handleToolsList(req *Request) {
 				Required: []string{"text"},
 			},
 		},
+		{
+			Name:        "contextpilot_decisions_list",
+			Description: "List logged architectural decisions with their IDs, context and links",
+			InputSchema: InputSchema{
+				Type: "object",
+			},
+		},
+		{
+			Name:        "contextpilot_decision_delete",
+			Description: "Delete an architectural decision by ID",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"id": {Type: "integer", Description: "ID of the decision to delete"},
+				},
+				Required: []string{"id"},
+			},
+		},
+		{
+			Name:        "contextpilot_history",
+			Description: "Get past session snapshots for the current branch, oldest first",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"limit": {Type: "integer", Description: "Maximum number of entries (default 10)"},
+				},
+			},
+		},
+		{
+			Name:        "contextpilot_analyze",
+			Description: "Analyze the codebase and return the detected stack, structure and patterns as JSON",
+			InputSchema: InputSchema{
+				Type: "object",
+			},
+		},
+		{
+			Name:        "contextpilot_explain",
+			Description: "Explain the context that applies to a file or directory: its package, frameworks, conventions, related decisions and recent changes",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"path": {Type: "string", Description: "File or directory, relative to the project root"},
+					"days": {Type: "integer", Description: "How far back to count changes (default 90)"},
+				},
+				Required: []string{"path"},
+			},
+		},
 		{
 			Name:        "contextpilot_score",
 			Description: "Get context quality score",
@@ -211,6 +453,30 @@ func (s *Server) handleToolsList(req *Request) {
 				Type: "object",
 			},
 		},
+		{
+			Name:        "contextpilot_drift",
+			Description: "List what changed in the stack since the last sync (dependencies, framework, tooling, folders) that the context files may not reflect",
+			InputSchema: InputSchema{
+				Type: "object",
+			},
+		},
+	}
+
+	if s.samplingEnabled() {
+		tools = append(tools, samplingTools()...)
+	}
+	if s.improveEnabled() {
+		tools = append(tools, improveTool())
+	}
+
+	for i := range tools {
+		if tools[i].InputSchema.Properties == nil {
+			tools[i].InputSchema.Properties = map[string]Property{}
+		}
+		tools[i].InputSchema.Properties["root"] = Property{
+			Type:        "string",
+			Description: "Project to act on: a client root name, file:// URI or path, or a directory inside a root (default: the first root)",
+		}
 	}
 
 	s.sendResult(req.ID, map[string]interface{}{"tools": tools})
@@ -220,62 +486,128 @@ func (s *Server) handleToolsCall(req *Request) {
 	var params struct {
 		Name      string          `json:"name"`
 		Arguments json.RawMessage `json:"arguments"`
+		Meta      struct {
+			ProgressToken json.RawMessage `json:"progressToken"`
+		} `json:"_meta"`
 	}
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		s.sendError(req.ID, -32602, "Invalid params")
 		return
 	}
 
+	// Every tool runs against the project root the client picks
+	var scope struct {
+		Root string `json:"root"`
+	}
+	json.Unmarshal(params.Arguments, &scope)
+	root, err := s.resolveRoot(scope.Root)
+	if err != nil {
+		s.sendResult(req.ID, errorResult(err))
+		return
+	}
+
 	var result interface{}
-	var err error
+	prog := s.newProgress(params.Meta.ProgressToken)
+
+	// A network filesystem can stall analysis indefinitely; the deadline
+	// gets the call an answer with whatever was found in time
+	timeout := s.toolTimeout(root)
+	ctx, cancel := withTimeout(context.Background(), timeout)
+	defer cancel()
+	// Sampling tools mostly wait on the user, which clientRequestTimeout
+	// bounds instead; toolImprove applies the timeout to its analysis
+	sampling := context.WithoutCancel(ctx)
 
 	switch params.Name {
 	case "contextpilot_save":
-		result, err = s.toolSave(params.Arguments)
+		result, err = s.toolSave(ctx, root, params.Arguments)
 	case "contextpilot_resume":
-		result, err = s.toolResume()
+		result, err = s.toolResume(ctx, root, params.Arguments)
 	case "contextpilot_sync":
-		result, err = s.toolSync()
+		result, err = s.toolSync(ctx, root, prog)
 	case "contextpilot_decision":
-		result, err = s.toolDecision(params.Arguments)
+		result, err = s.toolDecision(ctx, root, params.Arguments)
+	case "contextpilot_decisions_list":
+		result, err = s.toolDecisionsList(ctx, root)
+	case "contextpilot_decision_delete":
+		result, err = s.toolDecisionDelete(ctx, root, params.Arguments)
+	case "contextpilot_history":
+		result, err = s.toolHistory(ctx, root, params.Arguments)
+	case "contextpilot_analyze":
+		result, err = s.toolAnalyze(ctx, root, prog)
+	case "contextpilot_explain":
+		result, err = s.toolExplain(ctx, root, params.Arguments, prog)
 	case "contextpilot_score":
-		result, err = s.toolScore()
+		result, err = s.toolScore(ctx, root)
+	case "contextpilot_drift":
+		result, err = s.toolDrift(ctx, root, prog)
+	case "contextpilot_improve":
+		if !s.improveEnabled() {
+			s.sendError(req.ID, -32602, "Tool contextpilot_improve requires mcp.sampling in config.yaml and a client that supports sampling and elicitation")
+			return
+		}
+		result, err = s.toolImprove(sampling, root, params.Arguments, timeout)
+	case "contextpilot_summarize_session", "contextpilot_draft_decision":
+		if !s.samplingEnabled() {
+			s.sendError(req.ID, -32602, fmt.Sprintf("Tool %s requires mcp.sampling in config.yaml and a client that supports sampling", params.Name))
+			return
+		}
+		if params.Name == "contextpilot_summarize_session" {
+			result, err = s.toolSummarizeSession(sampling, root, params.Arguments)
+		} else {
+			result, err = s.toolDraftDecision(sampling, root, params.Arguments)
+		}
 	default:
 		s.sendError(req.ID, -32602, fmt.Sprintf("Unknown tool: %s", params.Name))
 		return
 	}
 
+	if errors.Is(err, context.DeadlineExceeded) {
+		slog.Warn("mcp tool timed out", "tool", params.Name, "root", root, "timeout", timeout)
+		s.log("warning", "%s timed out after %s", params.Name, timeout)
+		s.sendResult(req.ID, timeoutResult(params.Name, timeout, prog, err))
+		return
+	}
 	if err != nil {
-		s.sendResult(req.ID, map[string]interface{}{
-			"content": []map[string]string{
-				{"type": "text", "text": fmt.Sprintf("Error: %v", err)},
-			},
-			"isError": true,
-		})
+		slog.Warn("mcp tool failed", "tool", params.Name, "root", root, "error", err)
+		s.log("error", "%s failed: %v", params.Name, err)
+		s.sendResult(req.ID, errorResult(err))
 		return
 	}
-
-	s.sendResult(req.ID, map[string]interface{}{
-		"content": []map[string]string{
-			{"type": "text", "text": fmt.Sprintf("%v", result)},
-		},
-	})
+	slog.Info("mcp tool called", "tool", params.Name, "root", root)
+	s.log("debug", "%s completed", params.Name)
+
+	switch r := result.(type) {
+	case *ToolResult:
+		s.sendResult(req.ID, r)
+	case string:
+		s.sendResult(req.ID, textResult(r))
+	}
 }
 
-func (s *Server) toolSave(args json.RawMessage) (string, error) {
+func (s *Server) toolSave(ctx context.Context, root string, args json.RawMessage) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 	var params struct {
 		Task  string `json:"task"`
 		Goal  string `json:"goal"`
 		State string `json:"state"`
 		Notes string `json:"notes"`
+		Name  string `json:"name"`
+		Issue string `json:"issue"`
+		PR    string `json:"pr"`
 	}
 	json.Unmarshal(args, &params)
 
-	mgr := session.New(s.rootPath)
-	sess, _ := mgr.Load()
+	mgr := session.New(root)
+	sess, _ := mgr.LoadNamed(params.Name)
 	if sess == nil {
 		sess = &session.Session{}
 	}
+	if params.Name != session.DefaultName {
+		sess.Name = params.Name
+	}
 
 	sess.Task = params.Task
 	if params.Goal != "" {
@@ -287,6 +619,12 @@ func (s *Server) toolSave(args json.RawMessage) (string, error) {
 	if params.Notes != "" {
 		sess.Notes = params.Notes
 	}
+	if params.Issue != "" {
+		sess.Issue = params.Issue
+	}
+	if params.PR != "" {
+		sess.PR = params.PR
+	}
 
 	if err := mgr.Save(sess); err != nil {
 		return "", err
@@ -295,62 +633,220 @@ func (s *Server) toolSave(args json.RawMessage) (string, error) {
 	return fmt.Sprintf("Session saved: %s", params.Task), nil
 }
 
-func (s *Server) toolResume() (string, error) {
-	mgr := session.New(s.rootPath)
-	sess, err := mgr.Load()
+func (s *Server) toolResume(ctx context.Context, root string, args json.RawMessage) (*ToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var params struct {
+		Name string `json:"name"`
+	}
+	json.Unmarshal(args, &params)
+
+	mgr := session.New(root)
+	sess, err := mgr.LoadNamed(params.Name)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if sess == nil {
-		return "No saved session for this branch", nil
+		return textResult("No saved session for this branch"), nil
 	}
 
-	return mgr.GeneratePrompt(sess), nil
+	// The prompt is markdown for the model; the session is structured for
+	// agents that want individual fields
+	policy, err := redact.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	result := textResult(policy.Apply(mgr.GeneratePrompt(sess)))
+	result.StructuredContent = map[string]interface{}{"session": sess}
+	return result, nil
 }
 
-func (s *Server) toolSync() (string, error) {
-	a := analyzer.New(s.rootPath)
-	analysis, err := a.Analyze()
+func (s *Server) toolSync(ctx context.Context, root string, prog *progress) (string, error) {
+	a := analyzer.New(root)
+	a.OnProgress(prog.phase)
+	analysis, err := a.AnalyzeContext(ctx)
 	if err != nil {
-		return "", err
+		return "", partial(err, analysis, nil)
 	}
 
-	gen := generator.New(analysis, s.rootPath)
-	if err := gen.GenerateAll(); err != nil {
-		return "", err
+	prog.phase("generate", 0)
+	gen := generator.New(analysis, root)
+	if err := gen.GenerateAllContext(ctx); err != nil {
+		return "", partial(err, analysis, gen.Written())
 	}
+	s.log("info", "Context files regenerated in %s", root)
 
 	return "Context files updated", nil
 }
 
-func (s *Server) toolDecision(args json.RawMessage) (string, error) {
+func (s *Server) toolDecision(ctx context.Context, root string, args json.RawMessage) (*ToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	var params struct {
 		Text    string `json:"text"`
 		Context string `json:"context"`
 	}
 	json.Unmarshal(args, &params)
 
-	mgr := decisions.New(s.rootPath)
+	mgr := decisions.New(root)
 	dec, err := mgr.Add(params.Text, params.Context)
 	if err != nil {
+		return nil, err
+	}
+
+	result := textResult(fmt.Sprintf("Decision #%d logged: %s", dec.ID, params.Text))
+	result.StructuredContent = map[string]interface{}{"decision": dec}
+	return result, nil
+}
+
+func (s *Server) toolDecisionsList(ctx context.Context, root string) (*ToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	decs, err := decisions.New(root).List()
+	if err != nil {
+		return nil, err
+	}
+	return jsonResult(map[string]interface{}{"decisions": decs})
+}
+
+func (s *Server) toolDecisionDelete(ctx context.Context, root string, args json.RawMessage) (string, error) {
+	if err := ctx.Err(); err != nil {
 		return "", err
 	}
+	var params struct {
+		ID int `json:"id"`
+	}
+	json.Unmarshal(args, &params)
+	if params.ID <= 0 {
+		return "", fmt.Errorf("id is required")
+	}
+
+	if err := decisions.New(root).Delete(params.ID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Decision #%d deleted", params.ID), nil
+}
+
+func (s *Server) toolHistory(ctx context.Context, root string, args json.RawMessage) (*ToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var params struct {
+		Limit int `json:"limit"`
+	}
+	json.Unmarshal(args, &params)
+	if params.Limit <= 0 {
+		params.Limit = 10
+	}
+
+	mgr := session.New(root)
+	history, err := mgr.GetHistory(params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		history = []session.Session{}
+	}
+	return jsonResult(map[string]interface{}{
+		"branch":   mgr.CurrentBranch(),
+		"sessions": history,
+	})
+}
 
-	return fmt.Sprintf("Decision #%d logged: %s", dec.ID, params.Text), nil
+func (s *Server) toolAnalyze(ctx context.Context, root string, prog *progress) (*ToolResult, error) {
+	a := analyzer.New(root)
+	a.OnProgress(prog.phase)
+	analysis, err := a.AnalyzeContext(ctx)
+	if err != nil {
+		return nil, partial(err, analysis, nil)
+	}
+	return jsonResult(analysis)
 }
 
-func (s *Server) toolScore() (string, error) {
+func (s *Server) toolExplain(ctx context.Context, root string, args json.RawMessage, prog *progress) (*ToolResult, error) {
+	var params struct {
+		Path string `json:"path"`
+		Days int    `json:"days"`
+	}
+	json.Unmarshal(args, &params)
+	if params.Path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	if params.Days <= 0 {
+		params.Days = explain.DefaultDays
+	}
+
+	target := params.Path
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(root, filepath.FromSlash(target))
+	}
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s is outside the project", params.Path)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+
+	a := analyzer.New(root)
+	a.OnProgress(prog.phase)
+	analysis, err := a.AnalyzeContext(ctx)
+	if err != nil {
+		return nil, partial(err, analysis, nil)
+	}
+	decs, _ := decisions.New(root).List()
+	cfg, _ := config.Load(root)
+	e, err := explain.Explain(root, filepath.ToSlash(rel), info.IsDir(), analysis, decs, cfg, params.Days)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResult(e)
+}
+
+func (s *Server) toolDrift(ctx context.Context, root string, prog *progress) (*ToolResult, error) {
+	a := analyzer.New(root)
+	a.OnProgress(prog.phase)
+	analysis, err := a.AnalyzeContext(ctx)
+	if err != nil {
+		return nil, partial(err, analysis, nil)
+	}
+	d, err := drift.Detect(root, analysis, generator.Outputs(root))
+	if err != nil {
+		return nil, err
+	}
+	return jsonResult(d)
+}
+
+func (s *Server) toolScore(ctx context.Context, root string) (*ToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	// Simple score calculation
+	type fileCheck struct {
+		Path    string `json:"path"`
+		Present bool   `json:"present"`
+	}
 	score := 0
+	var checks []fileCheck
 	files := []string{".cursorrules", "CLAUDE.md", ".github/copilot-instructions.md", ".contextpilot/config.yaml"}
-	
+
 	for _, f := range files {
-		if _, err := os.Stat(filepath.Join(s.rootPath, f)); err == nil {
+		_, err := os.Stat(filepath.Join(root, f))
+		if err == nil {
 			score += 25
 		}
+		checks = append(checks, fileCheck{Path: f, Present: err == nil})
 	}
 
-	return fmt.Sprintf("Context Quality Score: %d/100", score), nil
+	return jsonResult(map[string]interface{}{
+		"score": score,
+		"max":   100,
+		"files": checks,
+	})
 }
 
 func (s *Server) handleResourcesList(req *Request) {
@@ -367,7 +863,14 @@ func (s *Server) handleResourcesList(req *Request) {
 			Description: "Current work session context",
 			MimeType:    "text/markdown",
 		},
+		{
+			URI:         decisionsURI,
+			Name:        "Decisions",
+			Description: "All architectural decisions",
+			MimeType:    "text/markdown",
+		},
 	}
+	resources = append(resources, packageResources(s.defaultRoot())...)
 
 	s.sendResult(req.ID, map[string]interface{}{"resources": resources})
 }
@@ -382,20 +885,21 @@ func (s *Server) handleResourcesRead(req *Request) {
 	}
 
 	var content string
+	root := s.defaultRoot()
 
 	switch params.URI {
 	case "contextpilot://context":
 		// Read CLAUDE.md or .cursorrules
-		if data, err := os.ReadFile(filepath.Join(s.rootPath, "CLAUDE.md")); err == nil {
+		if data, err := os.ReadFile(filepath.Join(root, "CLAUDE.md")); err == nil {
 			content = string(data)
-		} else if data, err := os.ReadFile(filepath.Join(s.rootPath, ".cursorrules")); err == nil {
+		} else if data, err := os.ReadFile(filepath.Join(root, ".cursorrules")); err == nil {
 			content = string(data)
 		} else {
 			content = "No context files found. Run 'contextpilot init' to generate."
 		}
 
 	case "contextpilot://session":
-		mgr := session.New(s.rootPath)
+		mgr := session.New(root)
 		if sess, err := mgr.Load(); err == nil && sess != nil {
 			content = mgr.GeneratePrompt(sess)
 		} else {
@@ -403,9 +907,27 @@ func (s *Server) handleResourcesRead(req *Request) {
 		}
 
 	default:
-		s.sendError(req.ID, -32602, fmt.Sprintf("Unknown resource: %s", params.URI))
+		var err error
+		switch {
+		case strings.HasPrefix(params.URI, contextURIPrefix):
+			content, err = readPackageContext(root, strings.TrimPrefix(params.URI, contextURIPrefix))
+		case params.URI == decisionsURI || strings.HasPrefix(params.URI, decisionsURI+"?"):
+			content, err = readDecisions(root, params.URI)
+		default:
+			err = fmt.Errorf("Unknown resource: %s", params.URI)
+		}
+		if err != nil {
+			s.sendError(req.ID, -32602, err.Error())
+			return
+		}
+	}
+
+	policy, err := redact.Load(root)
+	if err != nil {
+		s.sendError(req.ID, -32603, err.Error())
 		return
 	}
+	content = policy.Apply(content)
 
 	s.sendResult(req.ID, map[string]interface{}{
 		"contents": []ResourceContent{
@@ -432,7 +954,10 @@ func (s *Server) sendError(id interface{}, code int, message string) {
 	s.send(resp)
 }
 
-func (s *Server) send(resp Response) {
-	data, _ := json.Marshal(resp)
-	fmt.Println(string(data))
+func (s *Server) send(msg interface{}) {
+	data, _ := json.Marshal(msg)
+	slog.Debug("mcp sent", "message", traceText(data))
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	fmt.Fprintln(s.out, string(data))
 }
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"time"
+)
+
+// maxMessageSize bounds a single JSON-RPC message. Lines are read with a
+// growing buffer, so anything up to this size is accepted.
+const maxMessageSize = 64 * 1024 * 1024
+
+// shutdownGrace is how long in-flight requests get to finish and write
+// their responses once the client disconnects or the server is signalled
+const shutdownGrace = 5 * time.Second
+
+// errMessageTooLarge is returned for a line longer than maxMessageSize; the
+// rest of the line has been discarded and reading can continue
+var errMessageTooLarge = errors.New("message too large")
+
+// readMessage reads one newline-delimited message from r without the
+// trailing newline (or carriage return)
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	tooLarge := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !tooLarge {
+			if len(line)+len(chunk) > maxMessageSize {
+				tooLarge = true
+				line = nil
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil && (err != io.EOF || (len(line) == 0 && !tooLarge)) {
+			return nil, err
+		}
+		break
+	}
+
+	if tooLarge {
+		return nil, errMessageTooLarge
+	}
+	for len(line) > 0 && (line[len(line)-1] == '\n' || line[len(line)-1] == '\r') {
+		line = line[:len(line)-1]
+	}
+	return line, nil
+}
+
+// readMessages feeds messages from in to the returned channel, closing it
+// at end of input. Read errors other than EOF are sent on errs.
+func readMessages(in io.Reader, errs chan<- error) <-chan []byte {
+	lines := make(chan []byte)
+	go func() {
+		defer close(lines)
+		r := bufio.NewReaderSize(in, 64*1024)
+		for {
+			line, err := readMessage(r)
+			if err == errMessageTooLarge {
+				lines <- nil
+				continue
+			}
+			if err != nil {
+				if err != io.EOF {
+					errs <- err
+				}
+				return
+			}
+			lines <- line
+		}
+	}()
+	return lines
+}
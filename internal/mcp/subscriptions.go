@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+)
+
+// sessionURI is the resource that changes when the git branch changes
+const sessionURI = "contextpilot://session"
+
+// branchPollInterval is how often the server checks for a branch switch
+const branchPollInterval = 2 * time.Second
+
+// notification is a message the server sends without expecting a response
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+func (s *Server) notify(method string, params interface{}) {
+	s.send(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *Server) handleSubscribe(req *Request, subscribe bool) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		s.sendError(req.ID, -32602, "Invalid params")
+		return
+	}
+
+	s.subsMu.Lock()
+	if subscribe {
+		s.subscriptions[params.URI] = true
+	} else {
+		delete(s.subscriptions, params.URI)
+	}
+	s.subsMu.Unlock()
+
+	s.sendResult(req.ID, map[string]interface{}{})
+}
+
+func (s *Server) subscribed(uri string) bool {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	return s.subscriptions[uri]
+}
+
+// watchBranch tells subscribed clients that the session resource changed
+// whenever the git branch changes, so they can show the session of the
+// branch the user just checked out. It returns when done is closed.
+func (s *Server) watchBranch(done <-chan struct{}) {
+	if !git.IsRepo(s.rootPath) {
+		return
+	}
+
+	branch, _ := git.CurrentBranch(s.rootPath)
+	ticker := time.NewTicker(branchPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			current, _ := git.CurrentBranch(s.rootPath)
+			if current == branch {
+				continue
+			}
+			s.log("info", "Branch changed from %s to %s", branch, current)
+			branch = current
+			if s.subscribed(sessionURI) {
+				s.notify("notifications/resources/updated", map[string]string{"uri": sessionURI})
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// SetTimeout bounds every tool call to d, overriding mcp.timeout in the
+// projects' config.yaml; 0 keeps the config's
+func (s *Server) SetTimeout(d time.Duration) {
+	s.timeout = d
+}
+
+// toolTimeout returns how long a tool call on root may run, 0 for no
+// limit
+func (s *Server) toolTimeout(root string) time.Duration {
+	if s.timeout > 0 {
+		return s.timeout
+	}
+	cfg, err := config.Load(root)
+	if err != nil {
+		return config.DefaultMCPTimeout
+	}
+	return cfg.ToolTimeout()
+}
+
+// withTimeout is context.WithTimeout, with no deadline when d is 0
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// interrupted is the error of a tool call whose context ran out, with
+// what it got done before then
+type interrupted struct {
+	err      error
+	analysis *analyzer.Analysis
+	written  []string
+}
+
+func (e *interrupted) Error() string { return e.err.Error() }
+func (e *interrupted) Unwrap() error { return e.err }
+
+// partial returns err as is, or, when the context ran out, with the
+// analysis and the files written so far
+func partial(err error, analysis *analyzer.Analysis, written []string) error {
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return &interrupted{err: err, analysis: analysis, written: written}
+}
+
+// Partial is what a tool call that timed out reports in structuredContent
+type Partial struct {
+	TimedOut bool   `json:"timedOut"`
+	Timeout  string `json:"timeout"`
+	// Phase is the phase the deadline interrupted, e.g. "walk"
+	Phase           string   `json:"phase,omitempty"`
+	CompletedPhases []string `json:"completedPhases"`
+	FilesWalked     int      `json:"filesWalked"`
+	// Written are the context files sync wrote before the deadline
+	Written  []string           `json:"written,omitempty"`
+	Analysis *analyzer.Analysis `json:"analysis,omitempty"`
+}
+
+// timeoutResult reports a tool call that ran out of time: how far it got
+// and what it found, so the model can use that or suggest a longer
+// timeout
+func timeoutResult(tool string, timeout time.Duration, prog *progress, err error) *ToolResult {
+	phase, done, files := prog.reached()
+	p := Partial{TimedOut: true, Timeout: timeout.String(), Phase: phase, CompletedPhases: done, FilesWalked: files}
+	var in *interrupted
+	if errors.As(err, &in) {
+		p.Analysis = in.analysis
+		p.Written = in.written
+		if in.analysis != nil {
+			p.FilesWalked = max(p.FilesWalked, in.analysis.Totals.FilesScanned)
+		}
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "%s timed out after %s", tool, timeout)
+	switch {
+	case phaseMessages[phase] != "":
+		fmt.Fprintf(&msg, " while %s (%d files walked)", strings.ToLower(phaseMessages[phase]), p.FilesWalked)
+	case phase != "":
+		fmt.Fprintf(&msg, " in the %s phase (%d files walked)", phase, p.FilesWalked)
+	}
+	msg.WriteString(".")
+	if len(done) > 0 {
+		fmt.Fprintf(&msg, " Finished: %s.", strings.Join(done, ", "))
+	}
+	if tool == "contextpilot_sync" {
+		if len(p.Written) > 0 {
+			fmt.Fprintf(&msg, " Wrote %s before the deadline; the other context files are unchanged.", strings.Join(p.Written, ", "))
+		} else {
+			msg.WriteString(" No context files were changed.")
+		}
+	}
+	if p.Analysis != nil {
+		msg.WriteString(" The partial analysis is in structuredContent.")
+	}
+	msg.WriteString(" Raise mcp.timeout in .contextpilot/config.yaml, or run the command in a terminal.")
+
+	result := &ToolResult{
+		Content:           []ContentBlock{{Type: "text", Text: msg.String()}},
+		StructuredContent: p,
+		IsError:           true,
+	}
+	if data, err := json.MarshalIndent(p, "", "  "); err == nil {
+		result.Content = append(result.Content, ContentBlock{Type: "text", Text: string(data), MimeType: "application/json"})
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package mcpconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Client is an MCP client whose config file ContextPilot can edit
+type Client struct {
+	ID   string
+	Name string
+	// Key holds the server list: "mcpServers", or "servers" for VS Code
+	Key string
+	// Project is true for configs that live in the project rather than
+	// the user's home directory
+	Project bool
+	path    func(home, root string) string
+}
+
+// Clients lists the supported MCP clients
+var Clients = []Client{
+	{ID: "claude", Name: "Claude Desktop", Key: "mcpServers", path: func(home, root string) string {
+		return ClaudeDesktopPath(home)
+	}},
+	{ID: "claude-code", Name: "Claude Code", Key: "mcpServers", Project: true, path: func(home, root string) string {
+		return filepath.Join(root, ".mcp.json")
+	}},
+	{ID: "cursor", Name: "Cursor", Key: "mcpServers", path: func(home, root string) string {
+		return filepath.Join(home, ".cursor", "mcp.json")
+	}},
+	{ID: "windsurf", Name: "Windsurf", Key: "mcpServers", path: func(home, root string) string {
+		return filepath.Join(home, ".codeium", "windsurf", "mcp_config.json")
+	}},
+	{ID: "gemini", Name: "Gemini CLI", Key: "mcpServers", path: func(home, root string) string {
+		return filepath.Join(home, ".gemini", "settings.json")
+	}},
+	{ID: "vscode", Name: "VS Code", Key: "servers", Project: true, path: func(home, root string) string {
+		return filepath.Join(root, ".vscode", "mcp.json")
+	}},
+}
+
+// Lookup returns the client with the given ID
+func Lookup(id string) (Client, bool) {
+	for _, c := range Clients {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Client{}, false
+}
+
+// IDs lists the client IDs, for help and error messages
+func IDs() []string {
+	ids := make([]string, len(Clients))
+	for i, c := range Clients {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+// Path returns the client's config file for the project at root
+func (c Client) Path(root string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil && !c.Project {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return c.path(home, root), nil
+}
+
+// Entry returns the server entry that starts contextpilot with launch as
+// an MCP server for the project at root
+func (c Client) Entry(launch Launch, root string) Server {
+	args := append(append([]string{}, launch.Args...), "mcp")
+	srv := Server{Command: launch.Command, Args: args, Cwd: root}
+	if c.ID == "vscode" {
+		srv.Type = "stdio"
+	}
+	return srv
+}
+
+// ClaudeDesktopPath returns where Claude Desktop keeps its config on this OS
+func ClaudeDesktopPath(home string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "Claude", "claude_desktop_config.json")
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), "Claude", "claude_desktop_config.json")
+	default:
+		return filepath.Join(home, ".config", "Claude", "claude_desktop_config.json")
+	}
+}
@@ -0,0 +1,74 @@
+package mcpconfig
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/selfupdate"
+)
+
+// Launch is the command an MCP client runs contextpilot with, before the
+// "mcp" argument
+type Launch struct {
+	Command string
+	Args    []string
+	// Via is how contextpilot was installed (see selfupdate.Method)
+	Via string
+}
+
+// Runner reports whether the client fetches contextpilot through a package
+// runner such as npx each time it starts the server
+func (l Launch) Runner() bool {
+	return selfupdate.Method{Name: l.Via}.Runner()
+}
+
+// LaunchFor returns how a client should start the contextpilot at exe so
+// that the entry keeps working after upgrades. Package runners like npx
+// unpack into a cache that gets cleaned, so they are run again by package
+// name; Homebrew's Cellar and Scoop's app directories are versioned, so
+// their stable links are used instead of the current version's path.
+func LaunchFor(exe string) Launch {
+	method := selfupdate.DetectMethod(exe)
+	launch := Launch{Command: exe, Via: method.Name}
+	switch method.Name {
+	case "npx":
+		launch.Command, launch.Args = "npx", []string{"-y", "contextpilot"}
+	case "pnpm dlx":
+		launch.Command, launch.Args = "pnpm", []string{"dlx", "contextpilot"}
+	case "bunx":
+		launch.Command, launch.Args = "bunx", []string{"contextpilot"}
+	case "homebrew":
+		// <prefix>/Cellar/contextpilot/<version>/bin/contextpilot is
+		// linked from <prefix>/bin/contextpilot
+		if i := strings.Index(exe, string(filepath.Separator)+"Cellar"+string(filepath.Separator)); i >= 0 {
+			launch.Command = stable(exe, filepath.Join(exe[:i], "bin", filepath.Base(exe)))
+		}
+	case "scoop":
+		// <root>/apps/contextpilot/<version>/contextpilot.exe has a shim
+		// in <root>/shims and a current/ link to the installed version
+		dir := filepath.Dir(exe)
+		if apps := filepath.Dir(filepath.Dir(dir)); filepath.Base(apps) == "apps" {
+			launch.Command = stable(exe,
+				filepath.Join(filepath.Dir(apps), "shims", filepath.Base(exe)),
+				filepath.Join(filepath.Dir(dir), "current", filepath.Base(exe)))
+		}
+	}
+	// Windows clients can't start npx.cmd directly
+	if method.Runner() && runtime.GOOS == "windows" {
+		launch.Args = append([]string{"/c", launch.Command}, launch.Args...)
+		launch.Command = "cmd"
+	}
+	return launch
+}
+
+// stable returns the first of candidates that exists, else exe
+func stable(exe string, candidates ...string) string {
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			return c
+		}
+	}
+	return exe
+}
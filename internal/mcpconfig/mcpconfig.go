@@ -0,0 +1,134 @@
+// Package mcpconfig edits the JSON files MCP clients read their server
+// list from (.mcp.json, .cursor/mcp.json, claude_desktop_config.json, ...)
+package mcpconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/jitin-nhz/contextpilot/internal/jsonc"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// ServerName is the key ContextPilot registers itself under
+const ServerName = "contextpilot"
+
+// Server is one MCP server entry
+type Server struct {
+	Type    string   `json:"type,omitempty"`
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	Cwd     string   `json:"cwd,omitempty"`
+}
+
+// Status of a config file after Add
+const (
+	Added     = "added"
+	Updated   = "updated"
+	Unchanged = "unchanged"
+)
+
+// Add writes srv as name under key ("mcpServers", or "servers" for VS
+// Code) in the config at path, creating the file if needed. Other servers
+// and settings in the file are kept; comments and trailing commas, which
+// some clients accept, are not.
+func Add(path, key, name string, srv Server) (string, error) {
+	var cfg map[string]json.RawMessage
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(data) > 0 {
+		data, _ = jsonc.Strip(data)
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	if cfg == nil {
+		cfg = map[string]json.RawMessage{}
+	}
+
+	var servers map[string]json.RawMessage
+	if raw, ok := cfg[key]; ok {
+		if err := json.Unmarshal(raw, &servers); err != nil {
+			return "", fmt.Errorf("failed to parse %s in %s: %w", key, path, err)
+		}
+	}
+	// "mcpServers": null leaves the map nil
+	if servers == nil {
+		servers = map[string]json.RawMessage{}
+	}
+
+	status := Added
+	if raw, ok := servers[name]; ok {
+		var existing Server
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return "", fmt.Errorf("failed to parse %s in %s: %w", name, path, err)
+		}
+		if reflect.DeepEqual(existing, srv) {
+			return Unchanged, nil
+		}
+		status = Updated
+	}
+
+	entry, err := json.Marshal(srv)
+	if err != nil {
+		return "", err
+	}
+	servers[name] = entry
+	if cfg[key], err = json.Marshal(servers); err != nil {
+		return "", err
+	}
+
+	out, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	// Replace the file through a rename so a client reading it never sees
+	// it half-written, and keep its permissions
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := fsys.WriteFileAtomic(fsys.OS(filepath.Dir(path)), filepath.Base(path), append(out, '\n'), perm); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return status, nil
+}
+
+// Get returns the server registered as name under key in the config at
+// path, or false if there is none
+func Get(path, key, name string) (Server, bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Server{}, false, nil
+	}
+	if err != nil {
+		return Server{}, false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	data, _ = jsonc.Strip(data)
+	var cfg map[string]json.RawMessage
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Server{}, false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	var servers map[string]json.RawMessage
+	if err := json.Unmarshal(cfg[key], &servers); err != nil && cfg[key] != nil {
+		return Server{}, false, fmt.Errorf("failed to parse %s in %s: %w", key, path, err)
+	}
+	raw, ok := servers[name]
+	if !ok {
+		return Server{}, false, nil
+	}
+	var srv Server
+	if err := json.Unmarshal(raw, &srv); err != nil {
+		return Server{}, false, fmt.Errorf("failed to parse %s in %s: %w", name, path, err)
+	}
+	return srv, true, nil
+}
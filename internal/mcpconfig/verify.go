@@ -0,0 +1,88 @@
+package mcpconfig
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// Verify starts srv the way a client would and performs the MCP
+// initialize handshake, returning the server's name and version
+func Verify(srv Server, timeout time.Duration) (string, error) {
+	c := exec.Command(srv.Command, srv.Args...)
+	c.Dir = srv.Cwd
+	stdin, err := c.StdinPipe()
+	if err != nil {
+		return "", err
+	}
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	if err := c.Start(); err != nil {
+		return "", fmt.Errorf("failed to start %s: %w", srv.Command, err)
+	}
+	defer func() {
+		stdin.Close()
+		done := make(chan struct{})
+		go func() {
+			c.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			c.Process.Kill()
+			<-done
+		}
+	}()
+
+	request := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"contextpilot-verify","version":"1"}}}` + "\n"
+	if _, err := io.WriteString(stdin, request); err != nil {
+		return "", fmt.Errorf("failed to send initialize: %w", err)
+	}
+
+	type reply struct {
+		info string
+		err  error
+	}
+	replies := make(chan reply, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var resp struct {
+				ID     int `json:"id"`
+				Result struct {
+					ServerInfo struct {
+						Name    string `json:"name"`
+						Version string `json:"version"`
+					} `json:"serverInfo"`
+				} `json:"result"`
+				Error *struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if json.Unmarshal(scanner.Bytes(), &resp) != nil || resp.ID != 1 {
+				continue
+			}
+			if resp.Error != nil {
+				replies <- reply{err: fmt.Errorf("initialize failed: %s", resp.Error.Message)}
+				return
+			}
+			replies <- reply{info: resp.Result.ServerInfo.Name + " " + resp.Result.ServerInfo.Version}
+			return
+		}
+		replies <- reply{err: fmt.Errorf("server exited without answering initialize")}
+	}()
+
+	select {
+	case r := <-replies:
+		return r.info, r.err
+	case <-time.After(timeout):
+		return "", fmt.Errorf("no initialize response within %s", timeout)
+	}
+}
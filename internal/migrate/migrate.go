@@ -0,0 +1,167 @@
+// Package migrate upgrades on-disk formats written by older ContextPilot
+// releases (config.yaml, session history, the decision index) to the ones
+// the current release writes. Files are backed up before they change.
+package migrate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/gitignore"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+)
+
+// BackupDir holds one timestamped directory per migration run
+const BackupDir = ".contextpilot/backups"
+
+// Migration upgrades one on-disk format
+type Migration struct {
+	Name string
+	// Files lists the paths, relative to the project root, Apply may
+	// rewrite or remove; they are backed up first
+	Files []string
+	// Check describes the pending change, or returns "" if there is none
+	Check func(root string) (string, error)
+	Apply func(root string) error
+}
+
+// Migrations run in order; each one is idempotent
+var Migrations = []Migration{
+	{
+		Name:  "config-version",
+		Files: []string{".contextpilot/config.yaml"},
+		Check: checkConfigVersion,
+		Apply: func(root string) error {
+			return config.SetVersion(root, config.CurrentVersion)
+		},
+	},
+	{
+		Name:  "decisions-index",
+		Files: []string{".contextpilot/decisions.md"},
+		Check: func(root string) (string, error) {
+			cfg, err := config.Load(root)
+			if err != nil {
+				return "", err
+			}
+			if b := cfg.Decisions.Backend; b != "" && b != "markdown" {
+				return "", nil
+			}
+			if !decisions.HasLegacyMarkdown(root) {
+				return "", nil
+			}
+			return "build decisions.json from decisions.md", nil
+		},
+		Apply: decisions.MigrateMarkdown,
+	},
+	{
+		Name:  "session-history",
+		Files: []string{".contextpilot/sessions/history.json", ".contextpilot/sessions/history.jsonl"},
+		Check: func(root string) (string, error) {
+			if !session.New(root).HasLegacyHistory() {
+				return "", nil
+			}
+			return "convert sessions/history.json to history.jsonl", nil
+		},
+		Apply: func(root string) error {
+			return session.New(root).MigrateHistory()
+		},
+	},
+}
+
+// Step is a migration that applies to a project
+type Step struct {
+	Migration
+	Change string
+}
+
+// Pending returns the migrations the project at root needs, in order. It
+// fails if the project was written by a newer release.
+func Pending(root string) ([]Step, error) {
+	steps := []Step{}
+	for _, m := range Migrations {
+		change, err := m.Check(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", m.Name, err)
+		}
+		if change != "" {
+			steps = append(steps, Step{Migration: m, Change: change})
+		}
+	}
+	return steps, nil
+}
+
+// Apply backs up the files steps touch and runs them in order. It returns
+// the backup directory, relative to root.
+func Apply(root string, steps []Step) (string, error) {
+	if len(steps) == 0 {
+		return "", nil
+	}
+
+	backup := filepath.Join(BackupDir, time.Now().Format("20060102-150405"))
+	for _, step := range steps {
+		for _, rel := range step.Files {
+			if err := copyFile(filepath.Join(root, rel), filepath.Join(root, backup, rel)); err != nil {
+				return "", fmt.Errorf("failed to back up %s: %w", rel, err)
+			}
+		}
+	}
+
+	if err := gitignore.IgnoreDir(filepath.Join(root, BackupDir)); err != nil {
+		return "", fmt.Errorf("failed to keep backups out of git: %w", err)
+	}
+
+	for _, step := range steps {
+		if err := step.Apply(root); err != nil {
+			return backup, fmt.Errorf("failed to apply %s: %w", step.Name, err)
+		}
+	}
+	return filepath.ToSlash(backup), nil
+}
+
+func checkConfigVersion(root string) (string, error) {
+	if !config.Exists(root) {
+		return "", nil
+	}
+	cfg, err := config.Load(root)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case cfg.Version > config.CurrentVersion:
+		return "", fmt.Errorf("config.yaml is version %d, but this release only understands up to %d; upgrade contextpilot", cfg.Version, config.CurrentVersion)
+	case cfg.Version < config.CurrentVersion:
+		return fmt.Sprintf("set config.yaml version %d → %d", cfg.Version, config.CurrentVersion), nil
+	}
+	return "", nil
+}
+
+// copyFile copies src to dst, creating dst's directory. A missing src is
+// not an error.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,251 @@
+// Package mine finds decisions that were made but never recorded, by
+// clustering commit messages and pull request titles
+package mine
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/llm"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+)
+
+// Candidate is a decision the history suggests was made
+type Candidate struct {
+	// Text is the decision as it would be logged
+	Text string `json:"text"`
+	// Summary explains why it was proposed, e.g. "Looks like you migrated
+	// from Jest to Vitest in March 2026"
+	Summary string `json:"summary"`
+	// Commits are the commits it was found in, oldest first; the first
+	// is the one the decision is linked to
+	Commits []git.LogEntry `json:"commits"`
+}
+
+// Month is when the candidate's first commit was made, e.g. "March 2026"
+func (c Candidate) Month() string {
+	if len(c.Commits) == 0 {
+		return ""
+	}
+	return c.Commits[0].Time.Format("January 2006")
+}
+
+// Files returns the paths changed by the candidate's commits, sorted
+func (c Candidate) Files() []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, e := range c.Commits {
+		for _, f := range e.Files {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Context describes the commits behind the candidate for the decision's
+// context
+func (c Candidate) Context() string {
+	if len(c.Commits) == 0 {
+		return ""
+	}
+	subjects := make([]string, len(c.Commits))
+	for i, e := range c.Commits {
+		subjects[i] = e.Subject
+	}
+	return fmt.Sprintf("Mined from %d commit(s) since %s: %s", len(c.Commits), c.Month(), strings.Join(subjects, "; "))
+}
+
+// term is a library or tool name such as Vitest, @tanstack/query or go-chi
+const term = `([A-Za-z][\w.@/+-]*\w|[A-Za-z])`
+
+var (
+	// conventionalPrefix is a Conventional Commits type and scope
+	conventionalPrefix = regexp.MustCompile(`^\w+(\([^)]*\))?!?:\s*`)
+	// migration matches "migrate (tests) from X to Y", "switch from X to Y"
+	migration = regexp.MustCompile(`(?i)\b(?:migrat\w*|switch\w*|mov(?:e|ed|ing)|port\w*|convert\w*)\b.*?\bfrom\s+` + term + `\s+to\s+` + term)
+	// replacement matches "replace X with Y"
+	replacement = regexp.MustCompile(`(?i)\breplac\w*\s+` + term + `\s+(?:with|by)\s+` + term)
+	// adoption matches "adopt Y", "introduce Y"
+	adoption = regexp.MustCompile(`(?i)\b(?:adopt\w*|introduc\w*)\s+` + term)
+)
+
+// cluster is a candidate being assembled from its seed commits
+type cluster struct {
+	from, to string
+	seeds    []git.LogEntry
+}
+
+// Heuristic proposes a candidate per technology the commits migrated to
+// or adopted, with the other commits that mention it, skipping those a
+// decision in decs already mentions
+func Heuristic(commits []git.LogEntry, decs []decisions.Decision) []Candidate {
+	clusters := map[string]*cluster{}
+	var order []string
+	for _, e := range commits {
+		from, to, ok := match(e.Subject)
+		if !ok {
+			continue
+		}
+		key := strings.ToLower(from + "\x00" + to)
+		c, found := clusters[key]
+		if !found {
+			c = &cluster{from: from, to: to}
+			clusters[key] = c
+			order = append(order, key)
+		}
+		c.seeds = append(c.seeds, e)
+	}
+
+	var candidates []Candidate
+	for _, key := range order {
+		c := clusters[key]
+		if recorded(decs, c.from, c.to) {
+			continue
+		}
+
+		mentions := mentionPattern(c.to)
+		related := []git.LogEntry{}
+		for _, e := range commits {
+			if containsEntry(c.seeds, e) || mentions.MatchString(e.Subject) {
+				related = append(related, e)
+			}
+		}
+
+		cand := Candidate{Commits: related}
+		if c.from != "" {
+			cand.Text = fmt.Sprintf("Use %s instead of %s", c.to, c.from)
+			cand.Summary = fmt.Sprintf("Looks like you migrated from %s to %s in %s", c.from, c.to, cand.Month())
+		} else {
+			cand.Text = "Adopt " + c.to
+			cand.Summary = fmt.Sprintf("Looks like you adopted %s in %s", c.to, cand.Month())
+		}
+		candidates = append(candidates, cand)
+	}
+	return candidates
+}
+
+// match extracts what a commit subject moved from and to; from is empty
+// for adoptions
+func match(subject string) (from, to string, ok bool) {
+	subject = conventionalPrefix.ReplaceAllString(subject, "")
+	if m := migration.FindStringSubmatch(subject); m != nil {
+		return m[1], m[2], true
+	}
+	if m := replacement.FindStringSubmatch(subject); m != nil {
+		return m[1], m[2], true
+	}
+	if m := adoption.FindStringSubmatch(subject); m != nil {
+		return "", m[1], true
+	}
+	return "", "", false
+}
+
+// mentionPattern matches subjects naming term as a word
+func mentionPattern(term string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(^|[^\w])` + regexp.QuoteMeta(term) + `($|[^\w])`)
+}
+
+// recorded reports whether a decision already mentions from and to
+func recorded(decs []decisions.Decision, from, to string) bool {
+	for _, d := range decs {
+		text := strings.ToLower(d.Text + " " + d.Context)
+		if strings.Contains(text, strings.ToLower(to)) && (from == "" || strings.Contains(text, strings.ToLower(from))) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEntry(entries []git.LogEntry, e git.LogEntry) bool {
+	for _, x := range entries {
+		if x.SHA == e.SHA {
+			return true
+		}
+	}
+	return false
+}
+
+const system = `You find architectural decisions in a project's git history that were never written down:
+technology migrations, adopted or dropped libraries, new patterns, changed conventions.
+Ignore routine fixes and features. For each decision reply with exactly one line:
+DECISION: <one sentence stating what was decided> | COMMITS: <comma-separated SHAs it is based on>
+Reply NONE if there are no such decisions.`
+
+// WithLLM asks client to find candidates in the commit subjects. Only
+// subjects, dates and SHAs are sent. Candidates a decision in decs already
+// states are skipped.
+func WithLLM(ctx context.Context, client *llm.Client, commits []git.LogEntry, decs []decisions.Decision) ([]Candidate, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	var b strings.Builder
+	b.WriteString("Commits, oldest first:\n\n")
+	for _, e := range commits {
+		fmt.Fprintf(&b, "%s %s %s\n", e.SHA, e.Time.Format("2006-01-02"), e.Subject)
+	}
+	reply, err := client.Complete(ctx, system, b.String(), 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	bySHA := map[string]git.LogEntry{}
+	for _, e := range commits {
+		bySHA[e.SHA] = e
+	}
+	known := map[string]bool{}
+	for _, d := range decs {
+		known[strings.ToLower(strings.TrimSpace(d.Text))] = true
+	}
+
+	var candidates []Candidate
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, "-* "))
+		rest, ok := cutPrefixFold(line, "DECISION:")
+		if !ok {
+			continue
+		}
+		text, shas, _ := strings.Cut(rest, "|")
+		text = strings.TrimSpace(text)
+		if text == "" || known[strings.ToLower(text)] {
+			continue
+		}
+		shas, _ = cutPrefixFold(strings.TrimSpace(shas), "COMMITS:")
+
+		cand := Candidate{Text: text}
+		for _, sha := range strings.Split(shas, ",") {
+			sha = strings.TrimSpace(sha)
+			for full, e := range bySHA {
+				if sha != "" && (strings.HasPrefix(full, sha) || strings.HasPrefix(sha, full)) {
+					cand.Commits = append(cand.Commits, e)
+					break
+				}
+			}
+		}
+		sort.Slice(cand.Commits, func(i, j int) bool {
+			return cand.Commits[i].Time.Before(cand.Commits[j].Time)
+		})
+		cand.Summary = fmt.Sprintf("Suggested by %s", client)
+		if month := cand.Month(); month != "" {
+			cand.Summary += " from " + month + " commits"
+		}
+		cand.Summary += ": " + text
+		candidates = append(candidates, cand)
+	}
+	return candidates, nil
+}
+
+// cutPrefixFold is strings.CutPrefix ignoring the prefix's case
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return strings.TrimSpace(s[len(prefix):]), true
+}
@@ -0,0 +1,240 @@
+// Package output implements ContextPilot's CLI output contract:
+//
+//   - stdout carries data (analysis results, tables, prompts, lists)
+//   - stderr carries progress, hints and errors
+//   - spinners are only drawn when stderr is a terminal
+//   - in plain mode emoji are stripped and box-drawing characters are
+//     replaced with ASCII, so logs and scripts see stable text
+//   - in quiet mode progress and hints are dropped; errors and data remain
+//   - in JSON mode the human-readable data is dropped and a command's
+//     result is written to stdout as a single JSON document
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var (
+	// Stdout receives data output
+	Stdout io.Writer = os.Stdout
+	// Stderr receives progress and diagnostic output
+	Stderr io.Writer = os.Stderr
+
+	plain    bool
+	quiet    bool
+	jsonMode bool
+)
+
+// SetPlain enables or disables plain (emoji- and box-free) output
+func SetPlain(enabled bool) {
+	plain = enabled
+}
+
+// IsPlain reports whether plain output is enabled
+func IsPlain() bool {
+	return plain
+}
+
+// SetQuiet enables or disables quiet mode, in which Info and Infof are
+// silent and no spinners are shown
+func SetQuiet(enabled bool) {
+	quiet = enabled
+}
+
+// IsQuiet reports whether quiet mode is enabled
+func IsQuiet() bool {
+	return quiet
+}
+
+// SetJSON enables or disables JSON mode, in which Print, Printf, Println
+// and Write are silent and commands report their result with JSON
+func SetJSON(enabled bool) {
+	jsonMode = enabled
+}
+
+// IsJSON reports whether JSON mode is enabled
+func IsJSON() bool {
+	return jsonMode
+}
+
+// JSON writes v to stdout as indented JSON
+func JSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(Stdout, string(data))
+	return err
+}
+
+// Print writes data to stdout
+func Print(a ...interface{}) {
+	if jsonMode {
+		return
+	}
+	fmt.Fprint(Stdout, render(fmt.Sprint(a...)))
+}
+
+// Printf writes formatted data to stdout
+func Printf(format string, a ...interface{}) {
+	if jsonMode {
+		return
+	}
+	fmt.Fprint(Stdout, render(fmt.Sprintf(format, a...)))
+}
+
+// Println writes a line of data to stdout
+func Println(a ...interface{}) {
+	if jsonMode {
+		return
+	}
+	fmt.Fprint(Stdout, render(fmt.Sprintln(a...)))
+}
+
+// Write writes s to stdout verbatim. Use it for payloads such as prompts
+// that must reach pipes unmodified, even in plain mode.
+func Write(s string) {
+	if jsonMode {
+		return
+	}
+	fmt.Fprint(Stdout, s)
+}
+
+// Info writes a line of progress or hint text to stderr
+func Info(a ...interface{}) {
+	if quiet {
+		return
+	}
+	fmt.Fprint(Stderr, render(fmt.Sprintln(a...)))
+}
+
+// Infof writes formatted progress or hint text to stderr
+func Infof(format string, a ...interface{}) {
+	if quiet {
+		return
+	}
+	fmt.Fprint(Stderr, render(fmt.Sprintf(format, a...)))
+}
+
+// Errorf writes a formatted error message to stderr
+func Errorf(format string, a ...interface{}) {
+	fmt.Fprint(Stderr, render(fmt.Sprintf(format, a...)))
+}
+
+// IsTerminal reports whether f is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Spinner shows an animated progress indicator on stderr
+type Spinner struct {
+	msg  string
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+// StartSpinner starts a spinner with msg. On a non-terminal stderr, or in
+// plain mode, msg is printed once as a regular progress line instead; in
+// quiet mode nothing is shown.
+func StartSpinner(msg string) *Spinner {
+	s := &Spinner{msg: msg}
+	f, ok := Stderr.(*os.File)
+	if quiet || plain || !ok || !IsTerminal(f) {
+		Info(msg)
+		return s
+	}
+
+	s.stop = make(chan struct{})
+	s.done.Add(1)
+	go func() {
+		defer s.done.Done()
+		frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(Stderr, "\r%s %s", frames[i%len(frames)], msg)
+			select {
+			case <-s.stop:
+				fmt.Fprintf(Stderr, "\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// Stop halts the spinner and clears its line
+func (s *Spinner) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.done.Wait()
+	s.stop = nil
+}
+
+// render applies plain-mode substitutions to s
+func render(s string) string {
+	if !plain {
+		return s
+	}
+	return Plain(s)
+}
+
+// Plain converts decorated text to ASCII-friendly output: box-drawing
+// characters become -, | and +, and emoji are removed.
+func Plain(s string) string {
+	var b strings.Builder
+	skipSpace := false
+	for _, r := range s {
+		switch r {
+		case '─':
+			b.WriteRune('-')
+		case '│':
+			b.WriteRune('|')
+		case '┌', '┐', '└', '┘', '├', '┤', '┬', '┴', '┼':
+			b.WriteRune('+')
+		case '•':
+			b.WriteRune('-')
+		case '→':
+			b.WriteString("->")
+		case '\uFE0F', '\u200D':
+			// Emoji variation selector / joiner
+			continue
+		default:
+			if isEmoji(r) {
+				skipSpace = true
+				continue
+			}
+			if skipSpace && r == ' ' {
+				skipSpace = false
+				continue
+			}
+			b.WriteRune(r)
+		}
+		skipSpace = false
+	}
+	return b.String()
+}
+
+func isEmoji(r rune) bool {
+	if r >= 0x1F000 && r <= 0x1FAFF {
+		return true
+	}
+	if r >= 0x2600 && r <= 0x27BF {
+		return true
+	}
+	return r > 0x2000 && unicode.Is(unicode.So, r) && (r < 0x2500 || r > 0x257F)
+}
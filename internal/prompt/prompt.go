@@ -0,0 +1,329 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/ignore"
+	"github.com/jitin-nhz/contextpilot/internal/redact"
+	"github.com/jitin-nhz/contextpilot/internal/report"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+)
+
+// Piece kinds accepted by --include
+const (
+	Stack       = "stack"
+	Conventions = "conventions"
+	Decisions   = "decisions"
+	Session     = "session"
+	Files       = "files"
+)
+
+// DefaultIncludes is used when no pieces are selected
+var DefaultIncludes = []string{Stack, Conventions, Decisions, Session}
+
+// maxFileSize skips files too large to be worth pasting whole
+const maxFileSize = 256 * 1024
+
+// Piece is one selectable part of the prompt. Glob is set for Files.
+type Piece struct {
+	Kind string
+	Glob string
+}
+
+// Parse turns --include values such as "stack", "decisions" or
+// "files=src/auth/**" into pieces, in order and without duplicates
+func Parse(specs []string) ([]Piece, error) {
+	pieces := []Piece{}
+	seen := map[Piece]bool{}
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		p := Piece{Kind: spec}
+		if kind, glob, ok := strings.Cut(spec, "="); ok {
+			if kind != Files || glob == "" {
+				return nil, fmt.Errorf("invalid piece %q (only files= takes a value, e.g. files=src/auth/**)", spec)
+			}
+			p = Piece{Kind: Files, Glob: glob}
+		}
+		switch p.Kind {
+		case Stack, Conventions, Decisions, Session:
+		case Files:
+			if p.Glob == "" {
+				return nil, fmt.Errorf("files needs a glob, e.g. files=src/auth/**")
+			}
+		default:
+			return nil, fmt.Errorf("unknown piece %q (want stack, conventions, decisions, session or files=<glob>)", spec)
+		}
+		if !seen[p] {
+			seen[p] = true
+			pieces = append(pieces, p)
+		}
+	}
+	return pieces, nil
+}
+
+// Result is an assembled prompt
+type Result struct {
+	Text    string
+	Tokens  int
+	Omitted []string // what was left out to fit the budget, or because it was empty
+}
+
+// builder appends sections while keeping track of the token budget
+type builder struct {
+	sb      strings.Builder
+	used    int
+	budget  int // 0 means unlimited
+	omitted []string
+}
+
+func (b *builder) fits(text string) bool {
+	return b.budget <= 0 || b.used+report.EstimateTokens(text) <= b.budget
+}
+
+func (b *builder) add(text string) {
+	b.sb.WriteString(text)
+	b.used += report.EstimateTokens(text)
+}
+
+// addSection adds a whole section, or records it as omitted
+func (b *builder) addSection(name, text string) {
+	if !b.fits(text) {
+		b.omitted = append(b.omitted, name+" (over budget)")
+		return
+	}
+	b.add(text)
+}
+
+// addBullets adds a titled list, dropping bullets that don't fit. With
+// newestLast the list is trimmed from the front, keeping the latest items.
+func (b *builder) addBullets(name, title string, bullets []string, newestLast bool) {
+	if len(bullets) == 0 {
+		b.omitted = append(b.omitted, name+" (none found)")
+		return
+	}
+	order := bullets
+	if newestLast {
+		order = reversed(bullets)
+	}
+	cost := report.EstimateTokens(title)
+	kept := []string{}
+	for _, line := range order {
+		n := report.EstimateTokens(line + "\n")
+		if b.budget > 0 && b.used+cost+n > b.budget {
+			break
+		}
+		kept = append(kept, line)
+		cost += n
+	}
+	if len(kept) == 0 {
+		b.omitted = append(b.omitted, name+" (over budget)")
+		return
+	}
+	if newestLast {
+		kept = reversed(kept)
+	}
+	if dropped := len(bullets) - len(kept); dropped > 0 {
+		b.omitted = append(b.omitted, fmt.Sprintf("%d of %d %s (over budget)", dropped, len(bullets), name))
+	}
+	b.add(title + strings.Join(kept, "\n") + "\n")
+}
+
+// Build assembles the selected pieces for the project at root, in the
+// order given, within budget tokens (0 for no limit). A question, if
+// given, is appended last and always included.
+func Build(root string, pieces []Piece, budget int, question string) (*Result, error) {
+	b := &builder{budget: budget}
+	b.add("# Project Context\n")
+
+	tail := ""
+	if question != "" {
+		tail = "\n## Question\n\n" + strings.TrimSpace(question) + "\n"
+		b.used += report.EstimateTokens(tail)
+	}
+
+	var analysis *analyzer.Analysis
+	analyze := func() (*analyzer.Analysis, error) {
+		if analysis != nil {
+			return analysis, nil
+		}
+		a, err := analyzer.New(root).Analyze()
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze codebase: %w", err)
+		}
+		sort.Slice(a.Languages, func(i, j int) bool {
+			return a.Languages[i].FileCount > a.Languages[j].FileCount
+		})
+		analysis = a
+		return a, nil
+	}
+
+	for _, p := range pieces {
+		switch p.Kind {
+		case Stack:
+			a, err := analyze()
+			if err != nil {
+				return nil, err
+			}
+			// The header's conventions line belongs to the conventions piece
+			lines := []string{}
+			for _, line := range strings.Split(strings.TrimSpace(generator.Header(a)), "\n") {
+				if !strings.HasPrefix(line, "Conventions:") {
+					lines = append(lines, "- "+line)
+				}
+			}
+			b.addSection("stack", "\n## Stack\n\n"+strings.Join(lines, "\n")+"\n")
+
+		case Conventions:
+			a, err := analyze()
+			if err != nil {
+				return nil, err
+			}
+			bullets := []string{}
+			for _, c := range generator.Conventions(a) {
+				bullets = append(bullets, "- "+c)
+			}
+			b.addBullets("conventions", "\n## Conventions\n\n", bullets, false)
+
+		case Decisions:
+			bullets := []string{}
+			for _, line := range strings.Split(decisions.New(root).GetForContext(), "\n") {
+				if line != "" {
+					bullets = append(bullets, line)
+				}
+			}
+			b.addBullets("decisions", "\n## Decisions\n\n", bullets, true)
+
+		case Session:
+			mgr := session.New(root)
+			s, err := mgr.Load()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load session: %w", err)
+			}
+			if s == nil {
+				b.omitted = append(b.omitted, "session (none saved for this branch)")
+				continue
+			}
+			b.addSection("session", "\n"+strings.Replace(mgr.GeneratePrompt(s), "## Session Context", "## Current Work", 1))
+
+		case Files:
+			if err := addFiles(b, root, p.Glob); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	b.sb.WriteString(tail)
+	result := &Result{Text: b.sb.String(), Tokens: b.used, Omitted: b.omitted}
+
+	// The prompt is going to a third-party tool, so the redaction policy
+	// applies to all of it, the files included
+	cfg, err := config.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Redact.Enabled() {
+		var a *analyzer.Analysis
+		if len(cfg.Redact.Dependencies) > 0 {
+			if a, err = analyze(); err != nil {
+				return nil, err
+			}
+		}
+		policy, err := redact.New(cfg.Redact, a)
+		if err != nil {
+			return nil, err
+		}
+		if text := policy.Apply(result.Text); text != result.Text {
+			result.Text, result.Tokens = text, report.EstimateTokens(text)
+		}
+	}
+	return result, nil
+}
+
+// addFiles adds every readable text file matching glob as a fenced block.
+// Files excluded by .contextpilotignore are never read.
+func addFiles(b *builder, root, glob string) error {
+	matcher, err := ignore.Load(root)
+	if err != nil {
+		return err
+	}
+	a := analyzer.New(root)
+
+	paths := []string{}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if d.Name() == ".git" || a.Ignores(rel) || !matcher.Allowed(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ignore.MatchGlob(glob, rel) && matcher.Allowed(rel, false) {
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list files: %w", err)
+	}
+	if len(paths) == 0 {
+		b.omitted = append(b.omitted, fmt.Sprintf("files=%s (no readable files match)", glob))
+		return nil
+	}
+
+	// Files stay in path order; any that don't fit are skipped, so a later
+	// small file can still make it in
+	section := fmt.Sprintf("\n## Files (%s)\n", glob)
+	kept, skipped := 0, 0
+	for _, rel := range paths {
+		data, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil || len(data) > maxFileSize || bytes.IndexByte(data, 0) >= 0 {
+			continue
+		}
+		block := fmt.Sprintf("\n### %s\n\n```%s\n%s", rel, strings.TrimPrefix(filepath.Ext(rel), "."), data)
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			block += "\n"
+		}
+		block += "```\n"
+		if !b.fits(section + block) {
+			skipped++
+			continue
+		}
+		section += block
+		kept++
+	}
+	if kept > 0 {
+		b.add(section)
+	}
+	if skipped > 0 {
+		b.omitted = append(b.omitted, fmt.Sprintf("%d of %d files matching %s (over budget)", skipped, len(paths), glob))
+	}
+	return nil
+}
+
+func reversed(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[len(lines)-1-i] = l
+	}
+	return out
+}
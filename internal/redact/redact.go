@@ -0,0 +1,251 @@
+// Package redact applies a project's redaction policy, the redact section
+// of config.yaml, to generated context and prompts, so details a company
+// must keep to itself don't end up pasted into third-party AI tools, and
+// audits text for what the policy would remove.
+package redact
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// Rules, as Finding reports them
+const (
+	Pattern    = "pattern"
+	Hostname   = "hostname"
+	Domain     = "domain"
+	Dependency = "dependency"
+)
+
+var (
+	// internalHost matches hosts under suffixes only private networks use
+	internalHost = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+(?:internal|corp|lan|intranet|home\.arpa)\b`)
+	// privateIP matches IPv4 addresses in the private ranges
+	privateIP = regexp.MustCompile(`\b(?:10(?:\.\d{1,3}){3}|192\.168(?:\.\d{1,3}){2}|172\.(?:1[6-9]|2\d|3[01])(?:\.\d{1,3}){2})\b`)
+)
+
+// Finding is one match of a rule
+type Finding struct {
+	File  string `json:"file,omitempty"`
+	Line  int    `json:"line"`
+	Rule  string `json:"rule"`
+	Match string `json:"match"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s %q", f.File, f.Line, f.Rule, f.Match)
+}
+
+// rule finds the spans of text one rule redacts
+type rule struct {
+	kind string
+	find func(text string) [][]int
+}
+
+// Policy redacts text. The zero Policy, and a nil one, redact nothing.
+type Policy struct {
+	rules       []rule
+	replacement string
+}
+
+// New builds the policy cfg describes. The dependency allowlist applies to
+// the dependencies of analysis, which may be nil when it sets none.
+func New(cfg config.Redact, analysis *analyzer.Analysis) (*Policy, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	p := &Policy{replacement: cfg.Replacement}
+	if p.replacement == "" {
+		p.replacement = config.DefaultReplacement
+	}
+
+	for _, expr := range cfg.Patterns {
+		re := regexp.MustCompile(expr)
+		p.rules = append(p.rules, rule{Pattern, func(text string) [][]int {
+			return re.FindAllStringIndex(text, -1)
+		}})
+	}
+	if cfg.Hostnames {
+		p.rules = append(p.rules, rule{Hostname, func(text string) [][]int {
+			return append(internalHost.FindAllStringIndex(text, -1), privateIP.FindAllStringIndex(text, -1)...)
+		}})
+	}
+	if len(cfg.Domains) > 0 {
+		quoted := make([]string, 0, len(cfg.Domains))
+		for _, d := range cfg.Domains {
+			quoted = append(quoted, regexp.QuoteMeta(strings.Trim(strings.TrimSpace(d), ".")))
+		}
+		re := regexp.MustCompile(`(?i)\b(?:[a-z0-9-]+\.)*(?:` + strings.Join(quoted, "|") + `)\b`)
+		p.rules = append(p.rules, rule{Domain, func(text string) [][]int {
+			return re.FindAllStringIndex(text, -1)
+		}})
+	}
+	if len(cfg.Dependencies) > 0 && analysis != nil {
+		var hidden []string
+		for _, name := range dependencies(analysis) {
+			if !allowed(name, cfg.Dependencies) {
+				hidden = append(hidden, name)
+			}
+		}
+		// Longer names first, so @acme/ui-kit isn't cut short by @acme/ui
+		sort.Slice(hidden, func(i, j int) bool { return len(hidden[i]) > len(hidden[j]) })
+		p.rules = append(p.rules, rule{Dependency, func(text string) [][]int {
+			var spans [][]int
+			for _, name := range hidden {
+				spans = append(spans, findName(text, name)...)
+			}
+			return spans
+		}})
+	}
+	return p, nil
+}
+
+// Load builds the policy in the config.yaml of the project at root,
+// analyzing it when there is a dependency allowlist to apply
+func Load(root string) (*Policy, error) {
+	cfg, err := config.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	var analysis *analyzer.Analysis
+	if len(cfg.Redact.Dependencies) > 0 {
+		if analysis, err = analyzer.New(root).Analyze(); err != nil {
+			return nil, fmt.Errorf("error analyzing codebase: %w", err)
+		}
+	}
+	return New(cfg.Redact, analysis)
+}
+
+// Enabled reports whether the policy redacts anything
+func (p *Policy) Enabled() bool {
+	return p != nil && len(p.rules) > 0
+}
+
+// Apply returns text with everything the policy matches replaced
+func (p *Policy) Apply(text string) string {
+	spans := p.spans(text)
+	if len(spans) == 0 {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		b.WriteString(text[last:s.start])
+		b.WriteString(p.replacement)
+		last = s.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// Audit returns what Apply would replace in text, by line, with file
+// set on each finding
+func (p *Policy) Audit(file, text string) []Finding {
+	var findings []Finding
+	for _, s := range p.spans(text) {
+		findings = append(findings, Finding{
+			File:  file,
+			Line:  strings.Count(text[:s.start], "\n") + 1,
+			Rule:  s.kind,
+			Match: text[s.start:s.end],
+		})
+	}
+	return findings
+}
+
+type span struct {
+	start, end int
+	kind       string
+}
+
+// spans returns the matches of every rule in order, overlapping ones
+// merged into the first
+func (p *Policy) spans(text string) []span {
+	if !p.Enabled() {
+		return nil
+	}
+	var all []span
+	for _, r := range p.rules {
+		for _, m := range r.find(text) {
+			if m[1] > m[0] {
+				all = append(all, span{m[0], m[1], r.kind})
+			}
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].start < all[j].start })
+	var merged []span
+	for _, s := range all {
+		if n := len(merged); n > 0 && s.start < merged[n-1].end {
+			merged[n-1].end = max(merged[n-1].end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// dependencies returns the names of the project's dependencies, dev
+// dependencies included
+func dependencies(a *analyzer.Analysis) []string {
+	var names []string
+	for name := range a.Packages.Dependencies {
+		names = append(names, name)
+	}
+	for name := range a.Packages.DevDeps {
+		if _, ok := a.Packages.Dependencies[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// allowed reports whether name matches one of the allowlist's names or
+// globs
+func allowed(name string, allowlist []string) bool {
+	for _, pattern := range allowlist {
+		if ok, _ := path.Match(pattern, name); ok || pattern == name {
+			return true
+		}
+	}
+	return false
+}
+
+// findName returns where name appears in text as a whole package name:
+// not as part of a longer one such as lodash.debounce or @types/lodash
+func findName(text, name string) [][]int {
+	var spans [][]int
+	for from := 0; ; {
+		i := strings.Index(text[from:], name)
+		if i < 0 {
+			return spans
+		}
+		start, end := from+i, from+i+len(name)
+		from = end
+		if start > 0 && (nameChar(text[start-1]) || text[start-1] == '.') {
+			continue
+		}
+		if end < len(text) && nameChar(text[end]) {
+			continue
+		}
+		if end+1 < len(text) && text[end] == '.' && nameChar(text[end+1]) && text[end+1] != '.' {
+			continue
+		}
+		spans = append(spans, []int{start, end})
+	}
+}
+
+// nameChar reports whether c can be part of a package name
+func nameChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-_@/", c) >= 0
+}
@@ -0,0 +1,124 @@
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+)
+
+// httpStore keeps objects under a base URL with GET and PUT, creating
+// WebDAV collections with MKCOL when a server asks for them
+type httpStore struct {
+	base     *url.URL
+	username string
+	password string
+	token    string
+	client   *http.Client
+}
+
+func newHTTPStore(u *url.URL, cfg config.Remote) *httpStore {
+	base := *u
+	base.User = nil
+	base.Path = strings.TrimRight(base.Path, "/")
+	return &httpStore{
+		base:     &base,
+		username: cfg.Username,
+		password: env(cfg.PasswordEnv, config.DefaultPasswordEnv),
+		token:    env(cfg.TokenEnv, config.DefaultTokenEnv),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *httpStore) url(key string) string {
+	u := *s.base
+	u.Path += "/" + key
+	return u.String()
+}
+
+func (s *httpStore) do(method, target string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case s.username != "":
+		req.SetBasicAuth(s.username, s.password)
+	case s.token != "":
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return s.client.Do(req)
+}
+
+func (s *httpStore) Get(key string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, s.url(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", key, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *httpStore) Put(key string, data []byte) error {
+	status, err := s.put(key, data)
+	if err != nil {
+		return err
+	}
+	// WebDAV answers 409 when the parent collection doesn't exist yet
+	if status == http.StatusConflict {
+		if err := s.mkcol(key); err != nil {
+			return err
+		}
+		if status, err = s.put(key, data); err != nil {
+			return err
+		}
+	}
+	if status < 200 || status > 299 {
+		return fmt.Errorf("PUT %s: %d %s", key, status, http.StatusText(status))
+	}
+	return nil
+}
+
+func (s *httpStore) put(key string, data []byte) (int, error) {
+	resp, err := s.do(http.MethodPut, s.url(key), data)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// mkcol creates the collections above key, outermost first
+func (s *httpStore) mkcol(key string) error {
+	parts := strings.Split(key, "/")
+	for i := 1; i < len(parts); i++ {
+		resp, err := s.do("MKCOL", s.url(strings.Join(parts[:i], "/"))+"/", nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		// 405: the collection already exists
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusMethodNotAllowed {
+			return fmt.Errorf("MKCOL %s: %s", strings.Join(parts[:i], "/"), resp.Status)
+		}
+	}
+	return nil
+}
+
+func (s *httpStore) String() string {
+	return s.base.String()
+}
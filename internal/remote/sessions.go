@@ -0,0 +1,191 @@
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+)
+
+// indexFile lists a project's sessions in the store
+const indexFile = "index.json"
+
+// index maps a session file name to the session's UpdatedAt
+type index map[string]time.Time
+
+// What Push and Pull did with a session
+const (
+	Pushed     = "pushed"
+	Pulled     = "pulled"
+	UpToDate   = "up to date"
+	KeptLocal  = "local is newer"
+	KeptRemote = "remote is newer"
+)
+
+// Change is what happened to one session
+type Change struct {
+	Session session.Session `json:"session"`
+	File    string          `json:"file"`
+	Action  string          `json:"action"`
+}
+
+var unsafeKey = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// ProjectKey names root's project in the store, so the same repository
+// checked out on different machines shares its sessions: the origin remote
+// (github.com-acme-shop), else the directory name. Projects below the top
+// of the work tree add their path.
+func ProjectKey(root string) string {
+	key := filepath.Base(root)
+	if origin, err := git.Output(root, "remote", "get-url", "origin"); err == nil && origin != "" {
+		key = origin
+		if i := strings.Index(key, "://"); i != -1 {
+			key = key[i+3:]
+		}
+		if i := strings.Index(key, "@"); i != -1 {
+			key = key[i+1:]
+		}
+		key = strings.TrimSuffix(strings.ReplaceAll(key, ":", "/"), ".git")
+		if top, err := git.Output(root, "rev-parse", "--show-toplevel"); err == nil {
+			if resolved, err := filepath.EvalSymlinks(root); err == nil {
+				root = resolved
+			}
+			if rel, err := filepath.Rel(top, root); err == nil && rel != "." {
+				key += "/" + filepath.ToSlash(rel)
+			}
+		}
+	}
+	return strings.Trim(unsafeKey.ReplaceAllString(strings.ToLower(key), "-"), "-")
+}
+
+// Push uploads the sessions that are newer locally than in the store
+func Push(store Store, mgr *session.Manager, project string) ([]Change, error) {
+	idx, err := loadIndex(store, project)
+	if err != nil {
+		return nil, err
+	}
+	local, err := mgr.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []Change{}
+	pushed := false
+	for _, s := range local {
+		c := Change{Session: s, File: mgr.FileName(s)}
+		remote, ok := idx[c.File]
+		switch {
+		case ok && remote.Equal(s.UpdatedAt):
+			c.Action = UpToDate
+		case ok && remote.After(s.UpdatedAt):
+			c.Action = KeptRemote
+		default:
+			data, err := json.MarshalIndent(s, "", "  ")
+			if err != nil {
+				return changes, err
+			}
+			if err := store.Put(project+"/"+c.File, data); err != nil {
+				return changes, fmt.Errorf("failed to push %s: %w", c.File, err)
+			}
+			idx[c.File] = s.UpdatedAt
+			c.Action = Pushed
+			pushed = true
+		}
+		changes = append(changes, c)
+	}
+
+	if pushed {
+		data, err := json.MarshalIndent(idx, "", "  ")
+		if err != nil {
+			return changes, err
+		}
+		if err := store.Put(project+"/"+indexFile, data); err != nil {
+			return changes, fmt.Errorf("failed to update the remote index: %w", err)
+		}
+	}
+	return changes, nil
+}
+
+// Pull downloads the sessions that are newer in the store than locally
+func Pull(store Store, mgr *session.Manager, project string) ([]Change, error) {
+	idx, err := loadIndex(store, project)
+	if err != nil {
+		return nil, err
+	}
+	local, err := mgr.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	byFile := map[string]session.Session{}
+	for _, s := range local {
+		byFile[mgr.FileName(s)] = s
+	}
+
+	files := make([]string, 0, len(idx))
+	for f := range idx {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+
+	changes := []Change{}
+	for _, f := range files {
+		c := Change{Session: session.Session{UpdatedAt: idx[f]}, File: f}
+		mine, ok := byFile[f]
+		if ok {
+			c.Session = mine
+		}
+		switch {
+		case ok && mine.UpdatedAt.Equal(idx[f]):
+			c.Action = UpToDate
+		case ok && mine.UpdatedAt.After(idx[f]):
+			c.Action = KeptLocal
+		default:
+			data, err := store.Get(project + "/" + f)
+			if err != nil {
+				return changes, fmt.Errorf("failed to pull %s: %w", f, err)
+			}
+			var s session.Session
+			if err := json.Unmarshal(data, &s); err != nil {
+				return changes, fmt.Errorf("failed to parse remote %s: %w", f, err)
+			}
+			// A session pushed under an older file name is still the
+			// local one when it saves under the current name
+			if mine, ok := byFile[mgr.FileName(s)]; ok && f != mgr.FileName(s) && !mine.UpdatedAt.Before(s.UpdatedAt) {
+				c.Session, c.Action = mine, KeptLocal
+				if mine.UpdatedAt.Equal(s.UpdatedAt) {
+					c.Action = UpToDate
+				}
+				changes = append(changes, c)
+				continue
+			}
+			if err := mgr.Import(&s); err != nil {
+				return changes, err
+			}
+			c.Session, c.Action = s, Pulled
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+func loadIndex(store Store, project string) (index, error) {
+	idx := index{}
+	data, err := store.Get(project + "/" + indexFile)
+	if errors.Is(err, ErrNotFound) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the remote index from %s: %w", store, err)
+	}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse the remote index: %w", err)
+	}
+	return idx, nil
+}
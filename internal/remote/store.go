@@ -0,0 +1,101 @@
+// Package remote backs sessions up to a per-user store (an HTTP/WebDAV
+// server, an S3-compatible bucket or a directory) so they follow a person
+// across machines. Conflicts are settled by the sessions' UpdatedAt: the
+// newer copy wins.
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+)
+
+// ErrNotFound is returned by Store.Get for a missing object
+var ErrNotFound = errors.New("not found")
+
+// Store reads and writes objects by slash-separated key
+type Store interface {
+	Get(key string) ([]byte, error)
+	Put(key string, data []byte) error
+	// String describes the store for messages, without credentials
+	String() string
+}
+
+// New returns the store cfg.URL points at, after expanding environment
+// variables in it (file://$HOME/Dropbox/contextpilot)
+func New(cfg config.Remote) (Store, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("no remote configured")
+	}
+	u, err := url.Parse(os.ExpandEnv(cfg.URL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote url %q: %w", cfg.URL, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		if err := config.CheckOnline(u.String()); err != nil {
+			return nil, err
+		}
+		return newHTTPStore(u, cfg), nil
+	case "s3":
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = "https://s3.amazonaws.com"
+		}
+		if err := config.CheckOnline(os.ExpandEnv(endpoint)); err != nil {
+			return nil, err
+		}
+		return newS3Store(u, cfg)
+	case "file":
+		return &dirStore{dir: filepath.FromSlash(u.Path)}, nil
+	}
+	return nil, fmt.Errorf("unsupported remote url %q (want https://, http://, s3:// or file://)", cfg.URL)
+}
+
+// env returns the value of the variable name, or of def if name is empty
+func env(name, def string) string {
+	if name == "" {
+		name = def
+	}
+	return os.Getenv(name)
+}
+
+// dirStore keeps objects as files under dir
+type dirStore struct {
+	dir string
+}
+
+func (s *dirStore) Get(key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *dirStore) Put(key string, data []byte) error {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (s *dirStore) String() string {
+	return s.dir
+}
+
+// joinKey joins a prefix and a key with exactly one slash
+func joinKey(prefix, key string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return key
+	}
+	return prefix + "/" + key
+}
@@ -0,0 +1,246 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Target is a generated context file consumed by an AI tool
+type Target struct {
+	Tool string
+	Path string
+}
+
+// Targets lists the context files ContextPilot generates
+var Targets = []Target{
+	{Tool: "Cursor", Path: ".cursorrules"},
+	{Tool: "Claude Code", Path: "CLAUDE.md"},
+	{Tool: "Copilot", Path: ".github/copilot-instructions.md"},
+	{Tool: "Gemini CLI", Path: "GEMINI.md"},
+	{Tool: "Codex CLI, Amp", Path: "AGENTS.md"},
+	{Tool: "Gemini Code Assist", Path: ".gemini/styleguide.md"},
+}
+
+// Budget is the recommended maximum size of one context file in tokens.
+// Context files are sent with every request, so larger files crowd out
+// the code the assistant actually needs to see.
+const Budget = 2000
+
+// Section categories used to group headings
+const (
+	SectionStack       = "stack"
+	SectionConventions = "conventions"
+	SectionDecisions   = "decisions"
+	SectionRepoMap     = "repo map"
+	SectionCommands    = "commands"
+	SectionOther       = "other"
+)
+
+// SectionSize is the token count of one section category in a file
+type SectionSize struct {
+	Name   string
+	Tokens int
+}
+
+// FileReport describes the size and composition of one context file
+type FileReport struct {
+	Target
+	Exists          bool
+	Tokens          int
+	Sections        []SectionSize
+	Recommendations []string
+}
+
+// Analyze reports on every target file under root
+func Analyze(root string) ([]FileReport, error) {
+	reports := []FileReport{}
+	for _, t := range Targets {
+		r := FileReport{Target: t}
+		data, err := os.ReadFile(filepath.Join(root, t.Path))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to read %s: %w", t.Path, err)
+			}
+			reports = append(reports, r)
+			continue
+		}
+
+		r.Exists = true
+		r.Tokens = EstimateTokens(string(data))
+		r.Sections = sections(string(data))
+		r.Recommendations = recommend(r)
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
+
+// EstimateTokens approximates the token count of text. It uses the common
+// heuristic of ~4 characters per token, which is close enough for budgeting
+// across tokenizers without shipping one.
+func EstimateTokens(text string) int {
+	n := len([]rune(text))
+	return (n + 3) / 4
+}
+
+// sections splits markdown content on ## headings and sums tokens per
+// category, in order of first appearance
+func sections(content string) []SectionSize {
+	totals := map[string]int{}
+	order := []string{}
+	add := func(name, text string) {
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		if _, ok := totals[name]; !ok {
+			order = append(order, name)
+		}
+		totals[name] += EstimateTokens(text)
+	}
+
+	current := SectionOther
+	var buf strings.Builder
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			add(current, buf.String())
+			buf.Reset()
+			current = Classify(strings.TrimSpace(strings.TrimPrefix(line, "## ")))
+		}
+		buf.WriteString(line)
+	}
+	add(current, buf.String())
+
+	result := make([]SectionSize, 0, len(order))
+	for _, name := range order {
+		result = append(result, SectionSize{Name: name, Tokens: totals[name]})
+	}
+	return result
+}
+
+// Classify maps a heading to a section category
+func Classify(heading string) string {
+	h := strings.ToLower(heading)
+	switch {
+	case strings.Contains(h, "decision"):
+		return SectionDecisions
+	case strings.Contains(h, "structure"):
+		return SectionRepoMap
+	case strings.Contains(h, "command"):
+		return SectionCommands
+	case strings.Contains(h, "stack"), strings.Contains(h, "about"), strings.Contains(h, "overview"):
+		return SectionStack
+	case strings.Contains(h, "convention"), strings.Contains(h, "guideline"), strings.Contains(h, "rule"),
+		strings.Contains(h, "when i ask"):
+		return SectionConventions
+	default:
+		return SectionOther
+	}
+}
+
+// recommend suggests what to trim when a file is over budget or one
+// section dominates it
+func recommend(r FileReport) []string {
+	recs := []string{}
+	if r.Tokens > Budget {
+		recs = append(recs, fmt.Sprintf("%d tokens over the %d-token budget", r.Tokens-Budget, Budget))
+	}
+
+	for _, s := range r.Sections {
+		share := 0
+		if r.Tokens > 0 {
+			share = s.Tokens * 100 / r.Tokens
+		}
+		if share < 40 || s.Tokens < 300 {
+			continue
+		}
+		switch s.Name {
+		case SectionDecisions:
+			recs = append(recs, fmt.Sprintf("Decisions take %d%%; remove superseded ones with 'contextpilot decision --delete <n>'", share))
+		case SectionRepoMap:
+			recs = append(recs, fmt.Sprintf("Project structure takes %d%%; add generated or vendored folders to ignore in .contextpilot/config.yaml", share))
+		case SectionConventions:
+			recs = append(recs, fmt.Sprintf("Conventions take %d%%; drop rules your linter already enforces", share))
+		default:
+			recs = append(recs, fmt.Sprintf("The %s section takes %d%%; consider shortening it", s.Name, share))
+		}
+	}
+	return recs
+}
+
+// condensedSections are the categories kept by Condense, in output order
+var condensedSections = []struct {
+	name  string
+	title string
+}{
+	{SectionStack, "Stack"},
+	{SectionConventions, "Conventions"},
+	{SectionDecisions, "Recent Decisions"},
+}
+
+// Condense extracts the stack, conventions and decisions bullets from a
+// generated context file into a compact block of at most budget tokens.
+// Decisions are taken newest first, so the oldest are dropped when the
+// budget runs out. It returns "" if nothing fits or nothing was found.
+func Condense(content string, budget int) string {
+	bullets := map[string][]string{}
+	current := SectionOther
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			heading := strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			current = Classify(heading)
+			// Task recipes are conventions, but too generic to spend the
+			// budget on
+			if strings.Contains(strings.ToLower(heading), "when i ask") {
+				current = SectionOther
+			}
+			continue
+		}
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "- ") {
+			bullets[current] = append(bullets[current], trimmed)
+		}
+	}
+
+	header := "## Project Context\n"
+	used := EstimateTokens(header)
+	blocks := []string{}
+	for _, sec := range condensedSections {
+		lines := bullets[sec.name]
+		if sec.name == SectionDecisions {
+			lines = reversed(lines)
+		}
+
+		title := fmt.Sprintf("\n**%s:**\n", sec.title)
+		kept := []string{}
+		cost := EstimateTokens(title)
+		for _, line := range lines {
+			n := EstimateTokens(line + "\n")
+			if used+cost+n > budget {
+				break
+			}
+			kept = append(kept, line)
+			cost += n
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		if sec.name == SectionDecisions {
+			kept = reversed(kept)
+		}
+		used += cost
+		blocks = append(blocks, title+strings.Join(kept, "\n")+"\n")
+	}
+
+	if len(blocks) == 0 {
+		return ""
+	}
+	return header + strings.Join(blocks, "")
+}
+
+func reversed(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[len(lines)-1-i] = l
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package selfupdate
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Method is how ContextPilot was installed
+type Method struct {
+	// Name is "npx", "pnpm dlx", "bunx", "homebrew", "scoop", "npm", "go"
+	// or "binary"
+	Name string
+	// Upgrade is the command that updates a package-managed install;
+	// only a plain binary can replace itself
+	Upgrade string
+}
+
+// Managed reports whether a package manager owns the binary, so that
+// replacing it would leave the package manager out of step
+func (m Method) Managed() bool {
+	return m.Name != "binary"
+}
+
+// Runner reports whether a package runner such as npx fetched the binary
+// into a cache for one run, rather than installing it
+func (m Method) Runner() bool {
+	return m.Name == "npx" || m.Name == "pnpm dlx" || m.Name == "bunx"
+}
+
+// DetectMethod tells from where exe lives how it was installed
+func DetectMethod(exe string) Method {
+	p := strings.ToLower(filepath.ToSlash(exe))
+	switch {
+	case strings.Contains(p, "/_npx/"):
+		return Method{"npx", "npx -y contextpilot@latest"}
+	case strings.Contains(p, "/dlx/") || strings.Contains(p, "/dlx-"):
+		return Method{"pnpm dlx", "pnpm dlx contextpilot@latest"}
+	case strings.Contains(p, "/bunx-"):
+		return Method{"bunx", "bunx contextpilot@latest"}
+	case strings.Contains(p, "/cellar/") || strings.Contains(p, "/homebrew/") || strings.Contains(p, "/linuxbrew/"):
+		return Method{"homebrew", "brew upgrade contextpilot"}
+	case strings.Contains(p, "/scoop/"):
+		return Method{"scoop", "scoop update contextpilot"}
+	case strings.Contains(p, "/node_modules/"):
+		return Method{"npm", "npm install -g contextpilot@latest"}
+	case strings.Contains(p, "/go/bin/") || strings.Contains(p, "/go/pkg/mod/"):
+		return Method{"go", "go install github.com/contextpilot-dev/contextpilot@latest"}
+	}
+	return Method{Name: "binary"}
+}
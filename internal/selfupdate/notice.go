@@ -0,0 +1,66 @@
+package selfupdate
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CheckInterval is how often the update notice looks for a new release
+const CheckInterval = 24 * time.Hour
+
+// NoticeEnv turns the update notice off when set, even with
+// updates: {notify: true} in the user config
+const NoticeEnv = "CONTEXTPILOT_NO_UPDATE_NOTICE"
+
+// check is the cached result of the last lookup
+type check struct {
+	Checked time.Time `json:"checked"`
+	Channel string    `json:"channel"`
+	Latest  string    `json:"latest"`
+}
+
+// cachePath returns where the last lookup is kept
+func cachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "contextpilot", "update-check.json"), nil
+}
+
+// Available returns the newest release on channel when it is newer than
+// current, else "". It asks the release server at most once per
+// CheckInterval, remembering the answer in between, and gives up quickly
+// so a slow network never holds up a command.
+func Available(current, channel string) string {
+	path, err := cachePath()
+	if err != nil {
+		return ""
+	}
+	var last check
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &last)
+	}
+
+	if last.Channel != channel || time.Since(last.Checked) >= CheckInterval {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		// A failed lookup is remembered too, so an unreachable server
+		// isn't retried on every command
+		last = check{Checked: time.Now().UTC(), Channel: channel}
+		if r, err := Latest(ctx, channel); err == nil {
+			last.Latest = r.Version
+		}
+		if data, err := json.Marshal(last); err == nil && os.MkdirAll(filepath.Dir(path), 0755) == nil {
+			os.WriteFile(path, data, 0644)
+		}
+	}
+
+	if last.Latest != "" && Newer(last.Latest, current) {
+		return last.Latest
+	}
+	return ""
+}
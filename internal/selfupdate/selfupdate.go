@@ -0,0 +1,349 @@
+// Package selfupdate finds newer ContextPilot releases, verifies their
+// signed checksums and replaces the running binary with them
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+)
+
+// DefaultURL is the GitHub API endpoint of the repository releases come
+// from; URLEnv points at a mirror with the same API instead
+const (
+	DefaultURL = "https://api.github.com/repos/contextpilot-dev/contextpilot"
+	URLEnv     = "CONTEXTPILOT_UPDATE_URL"
+)
+
+// Channels are the release channels: stable releases, or beta, which also
+// includes pre-releases such as v1.4.0-beta.1
+var Channels = []string{"stable", "beta"}
+
+// PublicKey is the base64 Ed25519 key release checksums are signed with.
+// It is set at build time (see the Makefile); a build without one can't
+// verify, and so can't install, updates.
+var PublicKey string
+
+// Executable returns the path of the binary to replace
+var Executable = os.Executable
+
+// ChecksumsFile lists the SHA-256 of every release archive, and
+// SignatureFile holds the base64 Ed25519 signature of ChecksumsFile
+const (
+	ChecksumsFile = "checksums.txt"
+	SignatureFile = "checksums.txt.sig"
+)
+
+// Release is a published version
+type Release struct {
+	Version    string `json:"version"`
+	Prerelease bool   `json:"prerelease"`
+	// Assets maps file names to their download URLs
+	Assets map[string]string `json:"-"`
+}
+
+var client = &http.Client{Timeout: 2 * time.Minute}
+
+// baseURL returns where releases are listed
+func baseURL() string {
+	if u := os.Getenv(URLEnv); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	return DefaultURL
+}
+
+// Latest returns the newest release on channel
+func Latest(ctx context.Context, channel string) (*Release, error) {
+	if channel != "stable" && channel != "beta" {
+		return nil, fmt.Errorf("unknown channel %q (use %s)", channel, strings.Join(Channels, " or "))
+	}
+	target := baseURL() + "/releases"
+	if err := config.CheckOnline(target); err != nil {
+		return nil, err
+	}
+	data, err := get(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	var list []struct {
+		TagName    string `json:"tag_name"`
+		Prerelease bool   `json:"prerelease"`
+		Draft      bool   `json:"draft"`
+		Assets     []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid release list: %w", err)
+	}
+
+	var latest *Release
+	for _, r := range list {
+		if r.Draft || (r.Prerelease && channel != "beta") {
+			continue
+		}
+		if latest != nil && !Newer(r.TagName, latest.Version) {
+			continue
+		}
+		latest = &Release{Version: r.TagName, Prerelease: r.Prerelease, Assets: map[string]string{}}
+		for _, a := range r.Assets {
+			latest.Assets[a.Name] = a.URL
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no %s release found", channel)
+	}
+	return latest, nil
+}
+
+// AssetName is the release archive for this platform, e.g.
+// contextpilot-linux-amd64.tar.gz
+func AssetName() string {
+	name := "contextpilot-" + runtime.GOOS + "-" + runtime.GOARCH
+	if runtime.GOOS == "windows" {
+		return name + ".zip"
+	}
+	return name + ".tar.gz"
+}
+
+// Install downloads r's archive for this platform, checks it against the
+// signed checksums and replaces the running binary with it. It returns
+// the path it replaced.
+func Install(ctx context.Context, r *Release) (string, error) {
+	key, err := publicKey()
+	if err != nil {
+		return "", err
+	}
+	asset := AssetName()
+	for _, name := range []string{asset, ChecksumsFile, SignatureFile} {
+		if r.Assets[name] == "" {
+			return "", fmt.Errorf("release %s has no %s", r.Version, name)
+		}
+	}
+
+	checksums, err := get(ctx, r.Assets[ChecksumsFile])
+	if err != nil {
+		return "", err
+	}
+	sig, err := get(ctx, r.Assets[SignatureFile])
+	if err != nil {
+		return "", err
+	}
+	if err := Verify(key, checksums, sig); err != nil {
+		return "", err
+	}
+	want, err := checksum(checksums, asset)
+	if err != nil {
+		return "", err
+	}
+
+	archive, err := get(ctx, r.Assets[asset])
+	if err != nil {
+		return "", err
+	}
+	got := sha256.Sum256(archive)
+	if hex.EncodeToString(got[:]) != want {
+		return "", fmt.Errorf("%s does not match its checksum", asset)
+	}
+	binary, err := extract(asset, archive)
+	if err != nil {
+		return "", err
+	}
+
+	exe, err := Executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, replace(exe, binary)
+}
+
+// Verify checks that sig is the base64 signature of data by key
+func Verify(key ed25519.PublicKey, data, sig []byte) error {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || !ed25519.Verify(key, data, raw) {
+		return errors.New("the release checksums are not signed by the ContextPilot release key")
+	}
+	return nil
+}
+
+// publicKey decodes PublicKey
+func publicKey() (ed25519.PublicKey, error) {
+	if PublicKey == "" {
+		return nil, errors.New("this build has no release signing key to verify updates with; download the release from GitHub instead")
+	}
+	key, err := base64.StdEncoding.DecodeString(PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("this build's release signing key is invalid")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// checksum finds name's SHA-256 in a sha256sum-style checksums file
+func checksum(checksums []byte, name string) (string, error) {
+	sc := bufio.NewScanner(bytes.NewReader(checksums))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s has no checksum for %s", ChecksumsFile, name)
+}
+
+// extract returns the binary inside a release archive
+func extract(name string, archive []byte) ([]byte, error) {
+	want := strings.TrimSuffix(strings.TrimSuffix(name, ".tar.gz"), ".zip")
+	if strings.HasSuffix(name, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if strings.TrimSuffix(path.Base(f.Name), ".exe") == want {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(rc)
+			}
+		}
+		return nil, fmt.Errorf("%s has no %s", name, want)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s has no %s", name, want)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag == tar.TypeReg && path.Base(h.Name) == want {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// replace swaps exe for binary: written next to it first, so a failed
+// download never leaves a half-written binary behind
+func replace(exe string, binary []byte) error {
+	tmp := exe + ".new"
+	if err := os.WriteFile(tmp, binary, 0755); err != nil {
+		return fmt.Errorf("cannot write next to %s: %w", exe, err)
+	}
+	if runtime.GOOS == "windows" {
+		// A running executable can be renamed but not overwritten
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := os.Rename(tmp, exe); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// get fetches target, failing on anything but 200 OK
+func get(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "contextpilot-self-update")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", target, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Newer reports whether version a is newer than b, comparing them as
+// semantic versions with or without a leading v
+func Newer(a, b string) bool {
+	return compare(a, b) > 0
+}
+
+func compare(a, b string) int {
+	a, b = strings.TrimPrefix(a, "v"), strings.TrimPrefix(b, "v")
+	coreA, preA, _ := strings.Cut(a, "-")
+	coreB, preB, _ := strings.Cut(b, "-")
+	partsA, partsB := strings.Split(coreA, "."), strings.Split(coreB, ".")
+	for i := 0; i < 3; i++ {
+		if c := number(partsA, i) - number(partsB, i); c != 0 {
+			return c
+		}
+	}
+	// A release is newer than its pre-releases
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+	return comparePrerelease(preA, preB)
+}
+
+// comparePrerelease orders beta.2 before beta.10
+func comparePrerelease(a, b string) int {
+	fa, fb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(fa) && i < len(fb); i++ {
+		na, errA := strconv.Atoi(fa[i])
+		nb, errB := strconv.Atoi(fb[i])
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			return na - nb
+		case (errA != nil || errB != nil) && fa[i] != fb[i]:
+			return strings.Compare(fa[i], fb[i])
+		}
+	}
+	return len(fa) - len(fb)
+}
+
+func number(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[i])
+	return n
+}
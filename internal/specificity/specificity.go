@@ -0,0 +1,127 @@
+// Package specificity measures how project-specific a context file is.
+// Mentions of the project's own names (dependencies, folders, commands)
+// count for it; stock phrases that fit any project count against it.
+package specificity
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+)
+
+// Boilerplate lists phrases that tell an assistant nothing about this
+// project, including placeholders left in generated files
+var Boilerplate = []string{
+	"follow best practices",
+	"follow existing patterns",
+	"follow the existing code style",
+	"maintain existing code style",
+	"the project's testing framework",
+	"use the detected tech stack",
+	"in the appropriate directories",
+	"write clean code",
+	"clean and maintainable",
+	"use meaningful variable names",
+	"keep functions small",
+	"handle errors appropriately",
+	"update based on your project",
+}
+
+// enoughTerms is how many distinct project terms earn full coverage
+const enoughTerms = 10
+
+// maxPenalty caps how much boilerplate can take off the score
+const maxPenalty = 0.5
+
+// Report is the specificity of one context file
+type Report struct {
+	Path        string   `json:"path"`
+	Score       int      `json:"score"` // 0-100
+	Terms       []string `json:"terms"`
+	Missing     []string `json:"missing"`
+	Boilerplate []string `json:"boilerplate"`
+}
+
+// Terms returns names specific to the analyzed project: framework and
+// tools, dependencies, top-level folders and commands
+func Terms(a *analyzer.Analysis) []string {
+	seen := map[string]bool{}
+	var terms []string
+	add := func(t string) {
+		t = strings.TrimSpace(t)
+		if len(t) < 3 || seen[strings.ToLower(t)] {
+			return
+		}
+		seen[strings.ToLower(t)] = true
+		terms = append(terms, t)
+	}
+
+	if a.Framework != nil {
+		add(a.Framework.Name)
+	}
+	for _, f := range a.Frameworks {
+		add(f.Name)
+	}
+	p := a.Patterns
+	for _, t := range []string{p.TestFramework, p.Linter, p.Formatter, p.ORM, p.StateManagement, p.Styling} {
+		add(t)
+	}
+	for _, c := range generator.Commands(a) {
+		add(c.Run)
+	}
+	for _, f := range a.Structure.Folders {
+		add(f + "/")
+	}
+	add(a.Structure.EntryPoint)
+
+	var deps []string
+	for d := range a.Packages.Dependencies {
+		deps = append(deps, d)
+	}
+	for d := range a.Packages.DevDeps {
+		deps = append(deps, d)
+	}
+	sort.Strings(deps)
+	for _, d := range deps {
+		add(d)
+	}
+	return terms
+}
+
+// Analyze scores content against the project's terms. Coverage of up to
+// enoughTerms terms sets the score; each distinct boilerplate phrase takes
+// off 10%, up to half.
+func Analyze(path, content string, terms []string) Report {
+	r := Report{Path: path, Terms: []string{}, Missing: []string{}, Boilerplate: []string{}}
+	lower := strings.ToLower(content)
+
+	for _, t := range terms {
+		if mentions(content, t) {
+			r.Terms = append(r.Terms, t)
+		} else {
+			r.Missing = append(r.Missing, t)
+		}
+	}
+	for _, phrase := range Boilerplate {
+		if strings.Contains(lower, phrase) {
+			r.Boilerplate = append(r.Boilerplate, phrase)
+		}
+	}
+
+	coverage := 1.0
+	if target := min(len(terms), enoughTerms); target > 0 {
+		coverage = min(1, float64(len(r.Terms))/float64(target))
+	}
+	penalty := min(maxPenalty, 0.1*float64(len(r.Boilerplate)))
+	r.Score = int(100*coverage*(1-penalty) + 0.5)
+	return r
+}
+
+// mentions reports whether content contains term as a whole word
+func mentions(content, term string) bool {
+	re, err := regexp.Compile(`(?i)(^|[^\w-])` + regexp.QuoteMeta(term) + `($|[^\w-])`)
+	return err == nil && re.MatchString(content)
+}
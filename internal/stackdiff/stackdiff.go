@@ -0,0 +1,308 @@
+// Package stackdiff compares two analyses of a project, e.g. at two git
+// refs, and reports what changed structurally: workspace packages,
+// dependencies and their versions, languages, frameworks, tooling and
+// top-level folders. Unlike drift it compares full analyses, versions and
+// file shares included, and doesn't look at the context files.
+package stackdiff
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// Categories, in the order changes are reported
+const (
+	Packages     = "packages"
+	Dependencies = "dependencies"
+	Frameworks   = "frameworks"
+	Languages    = "languages"
+	Tooling      = "tooling"
+	Folders      = "folders"
+)
+
+// Categories lists every category in report order
+var Categories = []string{Packages, Dependencies, Frameworks, Languages, Tooling, Folders}
+
+// Kinds of change
+const (
+	Added      = "added"
+	Removed    = "removed"
+	Upgraded   = "upgraded"
+	Downgraded = "downgraded"
+	Changed    = "changed"
+)
+
+// languageShift is how many percentage points a language's share of the
+// files must move to be reported
+const languageShift = 5.0
+
+// Change is one structural difference
+type Change struct {
+	Category string `json:"category"`
+	Kind     string `json:"kind"`
+	Subject  string `json:"subject"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (c Change) String() string {
+	return c.Message
+}
+
+// Compare lists what changed from old to cur, by category
+func Compare(old, cur *analyzer.Analysis) []Change {
+	var changes []Change
+	changes = append(changes, packages(old, cur)...)
+	changes = append(changes, dependencies(old, cur)...)
+	changes = append(changes, frameworks(old, cur)...)
+	changes = append(changes, languages(old, cur)...)
+	changes = append(changes, tooling(old, cur)...)
+	changes = append(changes, folders(old, cur)...)
+	return changes
+}
+
+func packages(old, cur *analyzer.Analysis) []Change {
+	was, now := map[string]string{}, map[string]string{}
+	for _, w := range old.Packages.Workspace {
+		was[w.Path] = w.Name
+	}
+	for _, w := range cur.Packages.Workspace {
+		now[w.Path] = w.Name
+	}
+	var changes []Change
+	for _, p := range sortedKeys(now) {
+		if _, ok := was[p]; !ok {
+			changes = append(changes, Change{Packages, Added, p, "", now[p], fmt.Sprintf("New package %s (%s)", p, now[p])})
+		}
+	}
+	for _, p := range sortedKeys(was) {
+		if _, ok := now[p]; !ok {
+			changes = append(changes, Change{Packages, Removed, p, was[p], "", fmt.Sprintf("Removed package %s (%s)", p, was[p])})
+		}
+	}
+	return changes
+}
+
+func dependencies(old, cur *analyzer.Analysis) []Change {
+	was, now := deps(old), deps(cur)
+	var changes []Change
+	for _, d := range sortedKeys(now) {
+		from, ok := was[d]
+		to := now[d]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Dependencies, Added, d, "", to, strings.TrimSpace("New dependency " + d + " " + to)})
+		case from != to:
+			kind := Changed
+			switch c := compareVersions(from, to); {
+			case c < 0:
+				kind = Upgraded
+			case c > 0:
+				kind = Downgraded
+			}
+			changes = append(changes, Change{Dependencies, kind, d, from, to, fmt.Sprintf("%s %s from %s to %s", d, kind, from, to)})
+		}
+	}
+	for _, d := range sortedKeys(was) {
+		if _, ok := now[d]; !ok {
+			changes = append(changes, Change{Dependencies, Removed, d, was[d], "", "Removed dependency " + d})
+		}
+	}
+	return changes
+}
+
+// deps returns the dependencies and dev dependencies with their versions
+func deps(a *analyzer.Analysis) map[string]string {
+	all := map[string]string{}
+	for name, v := range a.Packages.DevDeps {
+		all[name] = v
+	}
+	for name, v := range a.Packages.Dependencies {
+		all[name] = v
+	}
+	return all
+}
+
+func frameworks(old, cur *analyzer.Analysis) []Change {
+	key := func(f analyzer.Framework) string {
+		if f.Where == "" {
+			return f.Name
+		}
+		return f.Name + " (" + f.Where + ")"
+	}
+	was, now := map[string]string{}, map[string]string{}
+	for _, f := range old.Frameworks {
+		was[key(f)] = f.Version
+	}
+	for _, f := range cur.Frameworks {
+		now[key(f)] = f.Version
+	}
+	var changes []Change
+	for _, f := range sortedKeys(now) {
+		from, ok := was[f]
+		to := now[f]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Frameworks, Added, f, "", to, "Framework " + f + " is now used"})
+		case from != to && from != "" && to != "":
+			kind := Changed
+			switch c := compareVersions(from, to); {
+			case c < 0:
+				kind = Upgraded
+			case c > 0:
+				kind = Downgraded
+			}
+			changes = append(changes, Change{Frameworks, kind, f, from, to, fmt.Sprintf("%s %s from %s to %s", f, kind, from, to)})
+		}
+	}
+	for _, f := range sortedKeys(was) {
+		if _, ok := now[f]; !ok {
+			changes = append(changes, Change{Frameworks, Removed, f, was[f], "", "Framework " + f + " is no longer used"})
+		}
+	}
+	return changes
+}
+
+func languages(old, cur *analyzer.Analysis) []Change {
+	was, now := map[string]float64{}, map[string]float64{}
+	for _, l := range old.Languages {
+		was[l.Name] = l.Percentage
+	}
+	for _, l := range cur.Languages {
+		now[l.Name] = l.Percentage
+	}
+	var changes []Change
+	for _, l := range sortedKeys(now) {
+		from, ok := was[l]
+		to := now[l]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Languages, Added, l, "", percent(to), fmt.Sprintf("%s is new (%s of files)", l, percent(to))})
+		case math.Abs(to-from) >= languageShift:
+			changes = append(changes, Change{Languages, Changed, l, percent(from), percent(to), fmt.Sprintf("%s went from %s to %s of files", l, percent(from), percent(to))})
+		}
+	}
+	for _, l := range sortedKeys(was) {
+		if _, ok := now[l]; !ok {
+			changes = append(changes, Change{Languages, Removed, l, percent(was[l]), "", l + " is no longer used"})
+		}
+	}
+	return changes
+}
+
+func percent(p float64) string {
+	return strconv.FormatFloat(p, 'f', 1, 64) + "%"
+}
+
+func tooling(old, cur *analyzer.Analysis) []Change {
+	tools := func(a *analyzer.Analysis) map[string]string {
+		p := a.Patterns
+		return map[string]string{
+			"package manager":  a.Packages.Manager,
+			"runtime":          a.Packages.Runtime,
+			"test framework":   p.TestFramework,
+			"test runner":      p.TestRunner,
+			"type checker":     p.TypeChecker,
+			"linter":           p.Linter,
+			"formatter":        p.Formatter,
+			"ORM":              p.ORM,
+			"state management": p.StateManagement,
+			"styling":          p.Styling,
+		}
+	}
+	was, now := tools(old), tools(cur)
+	var changes []Change
+	for _, name := range sortedKeys(now) {
+		from, to := was[name], now[name]
+		switch {
+		case from == to:
+		case from == "":
+			changes = append(changes, Change{Tooling, Added, name, "", to, fmt.Sprintf("%s %s is now used", upperFirst(name), to)})
+		case to == "":
+			changes = append(changes, Change{Tooling, Removed, name, from, "", fmt.Sprintf("%s %s is no longer used", upperFirst(name), from)})
+		default:
+			changes = append(changes, Change{Tooling, Changed, name, from, to, fmt.Sprintf("%s changed from %s to %s", upperFirst(name), from, to)})
+		}
+	}
+	return changes
+}
+
+func folders(old, cur *analyzer.Analysis) []Change {
+	was, now := map[string]bool{}, map[string]bool{}
+	for _, f := range old.Structure.Folders {
+		was[f] = true
+	}
+	for _, f := range cur.Structure.Folders {
+		now[f] = true
+	}
+	var changes []Change
+	for _, f := range sortedKeys(now) {
+		if !was[f] {
+			changes = append(changes, Change{Folders, Added, f, "", "", "New top-level folder " + f + "/"})
+		}
+	}
+	for _, f := range sortedKeys(was) {
+		if !now[f] {
+			changes = append(changes, Change{Folders, Removed, f, "", "", "Removed top-level folder " + f + "/"})
+		}
+	}
+	return changes
+}
+
+// compareVersions compares two version constraints such as ^18.2.0 and
+// v1.9.0 by their numbers, ignoring range operators: negative when a is
+// older, positive when it is newer, 0 when they can't be told apart
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+// versionParts returns the numbers of a version, up to the first part
+// that isn't one (a pre-release, a wildcard)
+func versionParts(v string) []int {
+	v = strings.TrimLeft(strings.TrimSpace(v), "^~>=<v ")
+	v, _, _ = strings.Cut(v, "-")
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
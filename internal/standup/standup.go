@@ -0,0 +1,181 @@
+// Package standup compiles a day's work from session history and the
+// decision log into a short summary to paste into a standup channel.
+package standup
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+)
+
+// Task is a task saved during the day, with the state last saved for it
+type Task struct {
+	Task  string `json:"task"`
+	State string `json:"state,omitempty"`
+}
+
+// Branch is the day's work on one branch
+type Branch struct {
+	Branch string `json:"branch"`
+	Issue  string `json:"issue,omitempty"`
+	PR     string `json:"pr,omitempty"`
+	Tasks  []Task `json:"tasks"`
+	// Done are the next steps checked off during the day
+	Done []string `json:"done"`
+	// Next are the next steps still open, as last saved
+	Next []string `json:"next"`
+}
+
+// Standup is the work of one day
+type Standup struct {
+	// Day is the date summarized, YYYY-MM-DD
+	Day       string               `json:"day"`
+	Branches  []Branch             `json:"branches"`
+	Decisions []decisions.Decision `json:"decisions"`
+}
+
+// Empty reports whether nothing was saved or decided that day
+func (s Standup) Empty() bool {
+	return len(s.Branches) == 0 && len(s.Decisions) == 0
+}
+
+// LastWorkday returns the start of the weekday before now's: yesterday,
+// or Friday from Saturday to Monday
+func LastWorkday(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// Build compiles the standup for the day starting at day from history
+// (see session.Manager.History, oldest first) and the decision log.
+// Branches are in the order work on them started that day.
+func Build(day time.Time, history []session.Session, decs []decisions.Decision) Standup {
+	end := day.AddDate(0, 0, 1)
+	s := Standup{Day: day.Format("2006-01-02"), Branches: []Branch{}, Decisions: []decisions.Decision{}}
+
+	// A session's last version before the day tells which of its steps
+	// were already done; its last version overall which are still open
+	before := map[string]session.Session{}
+	latest := map[string]session.Session{}
+	var during []session.Session
+	for _, v := range history {
+		k := key(v)
+		switch {
+		case v.UpdatedAt.Before(day):
+			before[k] = v
+		case v.UpdatedAt.Before(end):
+			during = append(during, v)
+		}
+		latest[k] = v
+	}
+
+	byBranch := map[string]int{}
+	counted := map[string]bool{}
+	for _, v := range during {
+		k := key(v)
+		i, ok := byBranch[v.Branch]
+		if !ok {
+			i = len(s.Branches)
+			byBranch[v.Branch] = i
+			s.Branches = append(s.Branches, Branch{Branch: v.Branch, Tasks: []Task{}, Done: []string{}, Next: []string{}})
+		}
+		b := &s.Branches[i]
+
+		if t := slices.IndexFunc(b.Tasks, func(t Task) bool { return t.Task == v.Task }); t >= 0 {
+			b.Tasks[t].State = v.State
+		} else if v.Task != "" {
+			b.Tasks = append(b.Tasks, Task{Task: v.Task, State: v.State})
+		}
+		for _, step := range v.Completed {
+			if !slices.Contains(before[k].Completed, step) {
+				b.Done = session.AddUnique(b.Done, step)
+			}
+		}
+
+		if counted[k] {
+			continue
+		}
+		counted[k] = true
+		last := latest[k]
+		b.Next = session.AddUnique(b.Next, last.NextSteps...)
+		if last.Issue != "" {
+			b.Issue = last.Issue
+		}
+		if last.PR != "" {
+			b.PR = last.PR
+		}
+	}
+	// A step done later the same day isn't still open
+	for i := range s.Branches {
+		b := &s.Branches[i]
+		b.Next = slices.DeleteFunc(b.Next, func(step string) bool { return slices.Contains(b.Done, step) })
+	}
+
+	for _, d := range decs {
+		if d.Date == s.Day {
+			s.Decisions = append(s.Decisions, d)
+		}
+	}
+	return s
+}
+
+// key identifies a session across its saved versions
+func key(s session.Session) string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return s.Branch + "/" + s.Name
+}
+
+// Markdown renders s for pasting: a heading with the day, then a short
+// block per branch and the decisions logged
+func (s Standup) Markdown() string {
+	day, err := time.Parse("2006-01-02", s.Day)
+	title := s.Day
+	if err == nil {
+		title = day.Format("Monday, Jan 2")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Standup: %s**\n", title)
+	for _, br := range s.Branches {
+		b.WriteString("\n**" + br.Branch + "**")
+		var links []string
+		for _, l := range []string{br.Issue, br.PR} {
+			if l != "" {
+				links = append(links, l)
+			}
+		}
+		if len(links) > 0 {
+			b.WriteString(" (" + strings.Join(links, ", ") + ")")
+		}
+		b.WriteString("\n")
+		for _, t := range br.Tasks {
+			if t.State != "" {
+				fmt.Fprintf(&b, "- Worked on: %s (%s)\n", t.Task, t.State)
+			} else {
+				fmt.Fprintf(&b, "- Worked on: %s\n", t.Task)
+			}
+		}
+		if len(br.Done) > 0 {
+			b.WriteString("- Done: " + strings.Join(br.Done, "; ") + "\n")
+		}
+		if len(br.Next) > 0 {
+			b.WriteString("- Next: " + strings.Join(br.Next, "; ") + "\n")
+		}
+	}
+	if len(s.Decisions) > 0 {
+		b.WriteString("\n**Decisions**\n")
+		for _, d := range s.Decisions {
+			b.WriteString("- " + d.Text + "\n")
+		}
+	}
+	return b.String()
+}
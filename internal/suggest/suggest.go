@@ -0,0 +1,125 @@
+// Package suggest finds areas of a project that changed a lot recently but
+// have no recorded decisions, to nudge teams into documenting choices while
+// they're fresh.
+package suggest
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+)
+
+// Defaults used by score and 'contextpilot suggest'
+const (
+	DefaultDays       = 30
+	DefaultMinCommits = 5
+)
+
+// containerDirs hold code rather than name an area, so areas below them
+// include the next path segment (src/payments rather than src)
+var containerDirs = map[string]bool{
+	"src": true, "app": true, "lib": true, "internal": true, "pkg": true,
+	"cmd": true, "packages": true, "apps": true, "services": true, "modules": true,
+}
+
+// Hotspot is an area with heavy recent churn and no decisions covering it
+type Hotspot struct {
+	Area    string
+	Commits int
+	Files   int
+}
+
+// Glob returns the --files pattern that links a decision to the area
+func (h Hotspot) Glob() string {
+	return h.Area + "/*"
+}
+
+// String describes the hotspot, e.g. "12 commits to src/payments in the
+// last 30 days, no decisions recorded"
+func (h Hotspot) String(days int) string {
+	return fmt.Sprintf("%d commits to %s in the last %d days, no decisions recorded", h.Commits, h.Area, days)
+}
+
+// Hotspots returns areas under root with at least minCommits commits in the
+// last days days that no decision covers, busiest first. A decision covers
+// an area when it links files inside it, or when it was logged during the
+// window and mentions the area by name.
+func Hotspots(root string, days, minCommits int, decs []decisions.Decision) ([]Hotspot, error) {
+	commits, err := git.LogFiles(root, fmt.Sprintf("%d.days.ago", days))
+	if err != nil {
+		return nil, err
+	}
+
+	commitCounts := map[string]int{}
+	fileSets := map[string]map[string]bool{}
+	for _, files := range commits {
+		touched := map[string]bool{}
+		for _, f := range files {
+			area := Area(f)
+			if area == "" {
+				continue
+			}
+			touched[area] = true
+			if fileSets[area] == nil {
+				fileSets[area] = map[string]bool{}
+			}
+			fileSets[area][f] = true
+		}
+		for area := range touched {
+			commitCounts[area]++
+		}
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	var hotspots []Hotspot
+	for area, n := range commitCounts {
+		if n < minCommits || covered(area, cutoff, decs) {
+			continue
+		}
+		hotspots = append(hotspots, Hotspot{Area: area, Commits: n, Files: len(fileSets[area])})
+	}
+
+	sort.Slice(hotspots, func(i, j int) bool {
+		if hotspots[i].Commits != hotspots[j].Commits {
+			return hotspots[i].Commits > hotspots[j].Commits
+		}
+		return hotspots[i].Area < hotspots[j].Area
+	})
+	return hotspots, nil
+}
+
+// Area maps a file path to the area it belongs to: its top-level directory,
+// or the first two segments below a container such as src/. Root files and
+// hidden directories have no area.
+func Area(file string) string {
+	parts := strings.Split(path.Clean(file), "/")
+	if len(parts) < 2 || strings.HasPrefix(parts[0], ".") {
+		return ""
+	}
+	if containerDirs[parts[0]] && len(parts) > 2 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
+
+func covered(area, cutoff string, decs []decisions.Decision) bool {
+	name := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(path.Base(area)) + `\b`)
+	for _, d := range decs {
+		for _, f := range d.Files {
+			f = strings.TrimSuffix(strings.TrimSuffix(path.Clean(f), "/*"), "/**")
+			if f == area || strings.HasPrefix(f, area+"/") || strings.HasPrefix(area, f+"/") {
+				return true
+			}
+		}
+		if d.Date >= cutoff && (name.MatchString(d.Text) || name.MatchString(d.Context)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+// Package watch reports debounced filesystem changes under a project so
+// context files can be regenerated as the code changes
+package watch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long the tree must be quiet before a batch of
+// changes is reported
+const DefaultDebounce = time.Second
+
+// contentFiles are files whose contents (not just presence) feed the
+// analysis, so editing them calls for a re-run
+var contentFiles = map[string]bool{
+	"package.json":        true,
+	"go.mod":              true,
+	"pyproject.toml":      true,
+	"requirements.txt":    true,
+	"Cargo.toml":          true,
+	"Makefile":            true,
+	"pnpm-workspace.yaml": true,
+	"turbo.json":          true,
+	"lerna.json":          true,
+}
+
+// Options configure a Watcher
+type Options struct {
+	// Debounce is the quiet period before a batch is reported
+	Debounce time.Duration
+	// SkipDir reports whether a directory (relative, slash-separated) is
+	// left unwatched, e.g. node_modules
+	SkipDir func(rel string) bool
+	// Ignore lists files whose events never count, such as the generated
+	// context files themselves
+	Ignore []string
+	// ContentDirs are directories whose file contents feed the context,
+	// e.g. the ADR directory; .contextpilot is always included
+	ContentDirs []string
+}
+
+// Change is one debounced batch of filesystem events
+type Change struct {
+	// Paths changed, relative to the root, slash-separated and sorted
+	Paths []string
+	// NeedsAnalysis is set when files were added, removed or renamed, or a
+	// file the analysis reads was edited. Edits to other files can't change
+	// the generated context.
+	NeedsAnalysis bool
+}
+
+// Watcher watches a project tree recursively
+type Watcher struct {
+	root string
+	opts Options
+	fs   *fsnotify.Watcher
+}
+
+// New starts watching every directory under root not skipped by opts
+func New(root string, opts Options) (*Watcher, error) {
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+	opts.ContentDirs = append(opts.ContentDirs, ".contextpilot")
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	w := &Watcher{root: root, opts: opts, fs: fsw}
+	if err := w.addTree(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Close stops watching
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
+
+// Dirs returns the number of directories being watched
+func (w *Watcher) Dirs() int {
+	return len(w.fs.WatchList())
+}
+
+// addTree watches dir and every directory below it
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != w.root && w.skipDir(w.rel(path)) {
+			return filepath.SkipDir
+		}
+		if err := w.fs.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) skipDir(rel string) bool {
+	if filepath.Base(rel) == ".git" {
+		return true
+	}
+	return w.opts.SkipDir != nil && w.opts.SkipDir(rel)
+}
+
+func (w *Watcher) rel(path string) string {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Run calls onChange with each debounced batch of changes until stop is
+// closed. onChange runs on the watcher's goroutine, so events that arrive
+// meanwhile are batched for the next call.
+func (w *Watcher) Run(stop <-chan struct{}, onChange func(Change)) error {
+	pending := map[string]bool{}
+	created := map[string]bool{}
+	needsAnalysis := false
+
+	timer := time.NewTimer(w.opts.Debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("file watcher failed: %w", err)
+		case ev, ok := <-w.fs.Events:
+			if !ok {
+				return nil
+			}
+			rel := w.rel(ev.Name)
+			if !w.relevant(rel) {
+				continue
+			}
+			if ev.Has(fsnotify.Create) {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					if w.skipDir(rel) {
+						continue
+					}
+					w.addTree(ev.Name)
+				}
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			if ev.Has(fsnotify.Create) && !pending[rel] {
+				created[rel] = true
+			}
+			pending[rel] = true
+			if ev.Has(fsnotify.Create) || ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename) || w.isContent(rel) {
+				needsAnalysis = true
+			}
+			timer.Reset(w.opts.Debounce)
+		case <-timer.C:
+			change := Change{NeedsAnalysis: needsAnalysis}
+			for p := range pending {
+				// Skip temp files that came and went within the batch,
+				// such as those behind atomic writes
+				if created[p] {
+					if _, err := os.Lstat(filepath.Join(w.root, p)); err != nil {
+						continue
+					}
+				}
+				change.Paths = append(change.Paths, p)
+			}
+			sort.Strings(change.Paths)
+			pending = map[string]bool{}
+			created = map[string]bool{}
+			needsAnalysis = false
+			onChange(change)
+		}
+	}
+}
+
+// relevant reports whether an event on rel counts at all
+func (w *Watcher) relevant(rel string) bool {
+	for _, ignored := range w.opts.Ignore {
+		if rel == ignored {
+			return false
+		}
+	}
+	// Editor swap and backup files
+	base := filepath.Base(rel)
+	return !strings.HasSuffix(base, "~") && !strings.HasSuffix(base, ".swp") && !strings.HasPrefix(base, ".#")
+}
+
+// isContent reports whether the contents of rel feed the analysis
+func (w *Watcher) isContent(rel string) bool {
+	if contentFiles[filepath.Base(rel)] {
+		return true
+	}
+	for _, dir := range w.opts.ContentDirs {
+		if strings.HasPrefix(rel, strings.TrimSuffix(dir, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
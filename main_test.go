@@ -0,0 +1,367 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/cmd"
+	"github.com/jitin-nhz/contextpilot/internal/selfupdate"
+	"github.com/rogpeppe/go-internal/testscript"
+)
+
+// releaseKey signs the releases releaseHandler serves
+var releaseKey = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+
+func TestMain(m *testing.M) {
+	// self-update trusts the test key and replaces $TEST_EXECUTABLE, not
+	// the test binary
+	selfupdate.PublicKey = base64.StdEncoding.EncodeToString(releaseKey.Public().(ed25519.PublicKey))
+	selfupdate.Executable = func() (string, error) {
+		if exe := os.Getenv("TEST_EXECUTABLE"); exe != "" {
+			return exe, nil
+		}
+		return os.Executable()
+	}
+	os.Exit(testscript.RunMain(m, map[string]func() int{
+		"contextpilot": func() int {
+			cmd.Execute()
+			return 0
+		},
+	}))
+}
+
+// TestScripts runs the end-to-end CLI scenarios in testdata/script.
+// Each .txtar file is a fixture repo plus the commands to run against it.
+func TestScripts(t *testing.T) {
+	testscript.Run(t, testscript.Params{
+		Dir: "testdata/script",
+		Setup: func(env *testscript.Env) error {
+			srv := httptest.NewServer(davHandler(filepath.Join(env.WorkDir, "dav")))
+			env.Defer(srv.Close)
+			env.Setenv("DAV_URL", srv.URL)
+
+			llm := httptest.NewServer(llmHandler(filepath.Join(env.WorkDir, "llm-request.json")))
+			env.Defer(llm.Close)
+			env.Setenv("LLM_URL", llm.URL)
+
+			releases := httptest.NewServer(releaseHandler(releaseKey))
+			env.Defer(releases.Close)
+			env.Setenv("RELEASES_URL", releases.URL)
+			_, otherKey, _ := ed25519.GenerateKey(nil)
+			forged := httptest.NewServer(releaseHandler(otherKey))
+			env.Defer(forged.Close)
+			env.Setenv("FORGED_RELEASES_URL", forged.URL)
+
+			gh := httptest.NewServer(githubHandler())
+			env.Defer(gh.Close)
+			env.Setenv("GITHUB_URL", gh.URL)
+
+			// The contextpilot command, for scripts that link it elsewhere
+			bin, err := exec.LookPath("contextpilot")
+			if err != nil {
+				return err
+			}
+			env.Setenv("CONTEXTPILOT_BIN", bin)
+
+			// A free port for scripts that start 'contextpilot serve'
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				return err
+			}
+			env.Setenv("FREE_PORT", strconv.Itoa(ln.Addr().(*net.TCPAddr).Port))
+			return ln.Close()
+		},
+		Cmds: map[string]func(ts *testscript.TestScript, neg bool, args []string){
+			"fetch": fetch,
+		},
+	})
+}
+
+// fetch [-X method] [-H header]... url [body] requests url, retrying while
+// the server is starting, and writes "status code" and the response body
+// to stdout. It fails on a non-2xx status unless negated.
+func fetch(ts *testscript.TestScript, neg bool, args []string) {
+	method := http.MethodGet
+	var headers []string
+	for len(args) > 1 && (args[0] == "-X" || args[0] == "-H") {
+		if args[0] == "-X" {
+			method = args[1]
+		} else {
+			headers = append(headers, args[1])
+		}
+		args = args[2:]
+	}
+	if len(args) < 1 || len(args) > 2 {
+		ts.Fatalf("usage: fetch [-X method] [-H header]... url [body]")
+	}
+	body := ""
+	if len(args) == 2 {
+		body = args[1]
+	}
+
+	var resp *http.Response
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(50 * time.Millisecond) {
+		req, err := http.NewRequest(method, args[0], strings.NewReader(body))
+		ts.Check(err)
+		for _, h := range headers {
+			name, value, _ := strings.Cut(h, ":")
+			req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		resp, err = http.DefaultClient.Do(req)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			ts.Fatalf("fetch %s: %v", args[0], err)
+		}
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	ts.Check(err)
+	fmt.Fprintf(ts.Stdout(), "status %d\n%s", resp.StatusCode, data)
+
+	ok := resp.StatusCode >= 200 && resp.StatusCode <= 299
+	if ok == neg {
+		ts.Fatalf("fetch %s: unexpected status %d", args[0], resp.StatusCode)
+	}
+}
+
+// davHandler is a minimal WebDAV server over dir for the sessions remote:
+// GET, PUT (409 until the parent collection exists) and MKCOL, behind
+// basic auth as me:secret
+func davHandler(dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me" || pass != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		path := filepath.Join(dir, filepath.FromSlash(r.URL.Path))
+		switch r.Method {
+		case http.MethodGet:
+			http.ServeFile(w, r, path)
+		case http.MethodPut:
+			if _, err := os.Stat(filepath.Dir(path)); err != nil {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		case "MKCOL":
+			if _, err := os.Stat(path); err == nil {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			if err := os.MkdirAll(path, 0755); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// githubHandler fakes the issue comments API of the repository o/r,
+// behind the token gh-test, keeping the comments in memory
+func githubHandler() http.Handler {
+	type comment struct {
+		ID    int64  `json:"id"`
+		Body  string `json:"body"`
+		URL   string `json:"html_url"`
+		issue string
+	}
+	var mu sync.Mutex
+	var comments []*comment
+	list := regexp.MustCompile(`^/repos/o/r/issues/(\d+)/comments$`)
+	edit := regexp.MustCompile(`^/repos/o/r/issues/comments/(\d+)$`)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-test" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		var in struct {
+			Body string `json:"body"`
+		}
+		json.NewDecoder(r.Body).Decode(&in)
+		if m := list.FindStringSubmatch(r.URL.Path); m != nil {
+			switch r.Method {
+			case http.MethodGet:
+				out := []*comment{}
+				for _, c := range comments {
+					if c.issue == m[1] {
+						out = append(out, c)
+					}
+				}
+				json.NewEncoder(w).Encode(out)
+			case http.MethodPost:
+				id := int64(len(comments) + 1)
+				c := &comment{ID: id, Body: in.Body, URL: fmt.Sprintf("https://github.com/o/r/pull/%s#issuecomment-%d", m[1], id), issue: m[1]}
+				comments = append(comments, c)
+				w.WriteHeader(http.StatusCreated)
+				json.NewEncoder(w).Encode(c)
+			default:
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+			return
+		}
+		if m := edit.FindStringSubmatch(r.URL.Path); m != nil && r.Method == http.MethodPatch {
+			id, _ := strconv.ParseInt(m[1], 10, 64)
+			if id < 1 || int(id) > len(comments) {
+				http.NotFound(w, r)
+				return
+			}
+			comments[id-1].Body = in.Body
+			json.NewEncoder(w).Encode(comments[id-1])
+			return
+		}
+		http.NotFound(w, r)
+	})
+}
+
+// llmReply is the draft every provider returns from llmHandler
+const llmReply = `Here is the draft:
+
+## Architecture Overview
+
+- Model %s was asked
+
+## Conventions in Detail
+
+- Keep handlers thin`
+
+// llmHandler fakes the OpenAI, Anthropic and Ollama chat APIs. It saves
+// each request body to record and replies with llmReply naming the model,
+// or with a decision about the first commit listed when asked to mine.
+func llmHandler(record string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		os.WriteFile(record, data, 0644)
+		var req struct {
+			Model string `json:"model"`
+		}
+		json.Unmarshal(data, &req)
+		text := fmt.Sprintf(llmReply, req.Model)
+		// Decision mining asks for DECISION lines about the listed commits
+		if bytes.Contains(data, []byte("DECISION:")) {
+			sha := regexp.MustCompile(`\\n([0-9a-f]{7,}) `).FindSubmatch(data)
+			if sha == nil {
+				text = "NONE"
+			} else {
+				text = "- DECISION: Keep all dates in UTC | COMMITS: " + string(sha[1])
+			}
+		}
+
+		var resp interface{}
+		switch r.URL.Path {
+		case "/chat/completions":
+			if r.Header.Get("Authorization") != "Bearer sk-test" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			resp = map[string]interface{}{"choices": []interface{}{map[string]interface{}{"message": map[string]string{"role": "assistant", "content": text}}}}
+		case "/v1/messages":
+			if r.Header.Get("x-api-key") != "sk-test" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			resp = map[string]interface{}{"content": []interface{}{map[string]string{"type": "text", "text": text}}}
+		case "/api/chat":
+			resp = map[string]interface{}{"message": map[string]string{"role": "assistant", "content": text}}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// releaseHandler serves a GitHub-style release list for self-update:
+// v1.0.0 and v1.1.0 stable and v1.2.0-beta.1, each with an archive for
+// this platform holding "binary <version>" and checksums signed by key
+func releaseHandler(key ed25519.PrivateKey) http.Handler {
+	versions := []string{"v1.0.0", "v1.1.0", "v1.2.0-beta.1"}
+	asset := selfupdate.AssetName()
+	files := map[string][]byte{}
+	for _, v := range versions {
+		archive := releaseArchive(asset, []byte("binary "+v+"\n"))
+		sum := sha256.Sum256(archive)
+		checksums := []byte(fmt.Sprintf("%x  %s\n", sum, asset))
+		files[v+"/"+asset] = archive
+		files[v+"/"+selfupdate.ChecksumsFile] = checksums
+		files[v+"/"+selfupdate.SignatureFile] = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, checksums)))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/releases" {
+			data, ok := files[strings.TrimPrefix(r.URL.Path, "/download/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(data)
+			return
+		}
+		var list []map[string]interface{}
+		for _, v := range versions {
+			var assets []map[string]string
+			for _, name := range []string{asset, selfupdate.ChecksumsFile, selfupdate.SignatureFile} {
+				assets = append(assets, map[string]string{
+					"name":                 name,
+					"browser_download_url": "http://" + r.Host + "/download/" + v + "/" + name,
+				})
+			}
+			list = append(list, map[string]interface{}{
+				"tag_name":   v,
+				"prerelease": strings.Contains(v, "-"),
+				"assets":     assets,
+			})
+		}
+		json.NewEncoder(w).Encode(list)
+	})
+}
+
+// releaseArchive packs binary as a release archive named name
+func releaseArchive(name string, binary []byte) []byte {
+	var buf bytes.Buffer
+	if base, ok := strings.CutSuffix(name, ".zip"); ok {
+		zw := zip.NewWriter(&buf)
+		f, _ := zw.Create(base + ".exe")
+		f.Write(binary)
+		zw.Close()
+		return buf.Bytes()
+	}
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: strings.TrimSuffix(name, ".tar.gz"), Mode: 0755, Size: int64(len(binary)), Typeflag: tar.TypeReg})
+	tw.Write(binary)
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,359 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/jsonc"
+)
+
+// ImportAlias is an import specifier that resolves to a path in the
+// project, e.g. @/ to src/. A trailing / on both sides stands for
+// everything below.
+type ImportAlias struct {
+	Alias  string `json:"alias"`
+	Target string `json:"target"` // relative to the project root
+	// Source is the file that defines it, e.g. tsconfig.json or
+	// packages/ui/package.json
+	Source string `json:"source"`
+}
+
+// Config files aliases are read from, in the order they are reported
+var (
+	tsConfigs      = []string{"tsconfig.json", "jsconfig.json"}
+	bundlerConfigs = []string{
+		"vite.config.ts", "vite.config.mts", "vite.config.js", "vite.config.mjs",
+		"webpack.config.js", "webpack.config.cjs", "webpack.config.mjs", "webpack.config.ts",
+	}
+)
+
+// maxExtends is how many tsconfig extends are followed
+const maxExtends = 3
+
+// detectAliases finds the import aliases of the root and every workspace
+// package: tsconfig paths, package.json imports, the subpath exports of
+// workspace packages and the resolve.alias of Vite and webpack configs
+func (a *Analyzer) detectAliases(analysis *Analysis) {
+	dirs := []string{"."}
+	names := map[string]string{}
+	for _, w := range analysis.Packages.Workspace {
+		dirs = append(dirs, w.Path)
+		names[w.Path] = w.Name
+	}
+
+	var aliases []ImportAlias
+	seen := map[string]bool{}
+	add := func(alias ImportAlias) {
+		key := alias.Alias + "\x00" + alias.Target
+		if alias.Alias == "" || alias.Target == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		aliases = append(aliases, alias)
+	}
+	for _, dir := range dirs {
+		for _, name := range tsConfigs {
+			for _, alias := range a.tsconfigPaths(path.Join(dir, name), 0) {
+				add(alias)
+			}
+		}
+		for _, alias := range a.packageAliases(dir, names[dir]) {
+			add(alias)
+		}
+		for _, name := range bundlerConfigs {
+			file := path.Join(dir, name)
+			data, err := fs.ReadFile(a.files, file)
+			if err != nil {
+				continue
+			}
+			for _, alias := range bundlerAliases(string(data)) {
+				target, ok := projectPath(dir, alias[1])
+				if ok {
+					add(dirAlias(alias[0], target, file))
+				}
+			}
+		}
+	}
+	analysis.Aliases = aliases
+}
+
+// tsconfigPaths reads compilerOptions.paths from a tsconfig, resolved
+// against its baseUrl, following a relative extends when it sets none
+func (a *Analyzer) tsconfigPaths(file string, depth int) []ImportAlias {
+	data, err := fs.ReadFile(a.files, file)
+	if err != nil {
+		return nil
+	}
+	plain, _ := jsonc.Strip(data)
+	var cfg struct {
+		Extends         json.RawMessage `json:"extends"`
+		CompilerOptions struct {
+			BaseURL string              `json:"baseUrl"`
+			Paths   map[string][]string `json:"paths"`
+		} `json:"compilerOptions"`
+	}
+	if json.Unmarshal(plain, &cfg) != nil {
+		return nil
+	}
+	dir := path.Dir(file)
+	if cfg.CompilerOptions.Paths == nil {
+		var parent string
+		if json.Unmarshal(cfg.Extends, &parent) == nil && strings.HasPrefix(parent, ".") && depth < maxExtends {
+			if !strings.HasSuffix(parent, ".json") {
+				parent += ".json"
+			}
+			return a.tsconfigPaths(path.Join(dir, parent), depth+1)
+		}
+		return nil
+	}
+
+	base := path.Join(dir, cfg.CompilerOptions.BaseURL)
+	var aliases []ImportAlias
+	for _, pattern := range sortedKeys(keySet(cfg.CompilerOptions.Paths)) {
+		targets := cfg.CompilerOptions.Paths[pattern]
+		if len(targets) == 0 {
+			continue
+		}
+		// Targets are always paths, relative to baseUrl
+		target := targets[0]
+		if !strings.HasPrefix(target, ".") && !strings.HasPrefix(target, "/") {
+			target = "./" + target
+		}
+		if target, ok := projectPath(base, target); ok {
+			aliases = append(aliases, starAlias(pattern, target, file))
+		}
+	}
+	return aliases
+}
+
+// packageAliases reads the imports of dir's package.json and, for a
+// workspace package named name, the subpaths its exports offer
+func (a *Analyzer) packageAliases(dir, name string) []ImportAlias {
+	file := path.Join(dir, "package.json")
+	data, err := fs.ReadFile(a.files, file)
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Imports map[string]json.RawMessage `json:"imports"`
+		Exports json.RawMessage            `json:"exports"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return nil
+	}
+
+	var aliases []ImportAlias
+	for _, spec := range sortedKeys(keySet(pkg.Imports)) {
+		if target, ok := projectPath(dir, conditionTarget(pkg.Imports[spec])); ok {
+			aliases = append(aliases, starAlias(spec, target, file))
+		}
+	}
+	if name == "" {
+		return aliases
+	}
+	// Only a map of subpaths ("./button": ...) names more than the
+	// package itself
+	var exports map[string]json.RawMessage
+	if json.Unmarshal(pkg.Exports, &exports) != nil {
+		return aliases
+	}
+	for _, sub := range sortedKeys(keySet(exports)) {
+		if !strings.HasPrefix(sub, "./") || sub == "./package.json" {
+			continue
+		}
+		if target, ok := projectPath(dir, conditionTarget(exports[sub])); ok {
+			aliases = append(aliases, starAlias(name+"/"+strings.TrimPrefix(sub, "./"), target, file))
+		}
+	}
+	return aliases
+}
+
+// conditionTarget returns the path an imports or exports entry resolves
+// to: the string itself, or the first of its import, default, node and
+// require conditions, nested conditions included
+func conditionTarget(raw json.RawMessage) string {
+	var target string
+	if json.Unmarshal(raw, &target) == nil {
+		return target
+	}
+	var conditions map[string]json.RawMessage
+	if json.Unmarshal(raw, &conditions) != nil {
+		return ""
+	}
+	for _, c := range []string{"import", "default", "node", "require", "types"} {
+		if t := conditionTarget(conditions[c]); t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// projectPath resolves target, relative to dir, to a path inside the
+// project; false for packages and paths outside it
+func projectPath(dir, target string) (string, bool) {
+	target = strings.TrimSpace(target)
+	// A bare specifier such as "lodash-es" or "vue/dist/vue.js" is a
+	// package
+	if !strings.HasPrefix(target, ".") && !strings.HasPrefix(target, "/") || strings.Contains(target, "node_modules") {
+		return "", false
+	}
+	trailing := strings.HasSuffix(target, "/")
+	p := path.Join(dir, strings.TrimPrefix(target, "/"))
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", false
+	}
+	if trailing && p != "." {
+		p += "/"
+	}
+	return p, true
+}
+
+// starAlias turns a tsconfig or package.json pattern such as "@/*" to
+// "src/*" into "@/" to "src/"; other patterns keep their *
+func starAlias(pattern, target, source string) ImportAlias {
+	if strings.HasSuffix(pattern, "/*") && strings.HasSuffix(target, "/*") {
+		pattern, target = strings.TrimSuffix(pattern, "*"), strings.TrimSuffix(target, "*")
+	}
+	return ImportAlias{Alias: pattern, Target: target, Source: source}
+}
+
+// dirAlias is a bundler alias: "@" to src also resolves @/x to src/x, so a
+// directory target gets a trailing / on both sides
+func dirAlias(alias, target, source string) ImportAlias {
+	if path.Ext(target) == "" && !strings.HasSuffix(alias, "/") {
+		alias += "/"
+		target = strings.TrimSuffix(target, "/") + "/"
+	}
+	return ImportAlias{Alias: alias, Target: target, Source: source}
+}
+
+var (
+	// aliasBlock finds where a resolve.alias object or array starts
+	aliasBlock = regexp.MustCompile(`\balias\s*:\s*([{\[])`)
+	// quoted matches a string literal
+	quoted = regexp.MustCompile("['\"`]([^'\"`]*)['\"`]")
+	// findReplacement matches the entries of Vite's array form
+	findReplacement = regexp.MustCompile(`find\s*:\s*['"]([^'"]+)['"]\s*,\s*replacement\s*:\s*([^}]+)`)
+)
+
+// bundlerAliases reads resolve.alias from a Vite or webpack config, in
+// either the object form, { '@': path.resolve(__dirname, 'src') }, or
+// Vite's array form, [{ find: '@', replacement: ... }], as alias and
+// target pairs with the target as written
+func bundlerAliases(config string) [][2]string {
+	var pairs [][2]string
+	for _, m := range aliasBlock.FindAllStringSubmatchIndex(config, -1) {
+		block := bracketed(config[m[2]:])
+		if config[m[2]] == '[' {
+			for _, f := range findReplacement.FindAllStringSubmatch(block, -1) {
+				if target := literalPath(f[2]); target != "" {
+					pairs = append(pairs, [2]string{f[1], target})
+				}
+			}
+			continue
+		}
+		for _, entry := range splitTopLevel(block[1 : len(block)-1]) {
+			key, value, ok := strings.Cut(entry, ":")
+			if !ok {
+				continue
+			}
+			key = strings.Trim(strings.TrimSpace(key), `'"`+"`")
+			if target := literalPath(value); key != "" && target != "" {
+				pairs = append(pairs, [2]string{key, target})
+			}
+		}
+	}
+	return pairs
+}
+
+// relativeTo matches the expressions a config builds a path from
+var relativeTo = regexp.MustCompile(`__dirname|import\.meta|process\.cwd\(\)`)
+
+// literalPath returns the path a JavaScript expression gives: a string
+// literal, or the literals joined in path.resolve(__dirname, 'src') or
+// new URL('./src', import.meta.url), made relative
+func literalPath(expr string) string {
+	var parts []string
+	for _, m := range quoted.FindAllStringSubmatch(expr, -1) {
+		parts = append(parts, m[1])
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	if !relativeTo.MatchString(expr) {
+		return parts[len(parts)-1]
+	}
+	p := path.Join(parts...)
+	if strings.HasPrefix(p, "/") || strings.HasPrefix(p, ".") {
+		return p
+	}
+	return "./" + p
+}
+
+// bracketed returns the text from the bracket s starts with to the one
+// that closes it, or all of s if none does
+func bracketed(s string) string {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '{' || c == '[' || c == '(':
+			depth++
+		case c == '}' || c == ']' || c == ')':
+			depth--
+			if depth == 0 {
+				return s[:i+1]
+			}
+		}
+	}
+	return s
+}
+
+// splitTopLevel splits s at the commas outside brackets and strings
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '{' || c == '[' || c == '(':
+			depth++
+		case c == '}' || c == ']' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// keySet returns the keys of m as a set
+func keySet[V any](m map[string]V) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for k := range m {
+		set[k] = true
+	}
+	return set
+}
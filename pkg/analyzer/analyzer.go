@@ -0,0 +1,832 @@
+// Package analyzer detects a project's languages, framework, structure and
+// conventions. It is part of ContextPilot's public Go API; most programs
+// start from package contextpilot instead.
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// Analysis represents the result of analyzing a codebase
+type Analysis struct {
+	RootPath  string     `json:"rootPath"`
+	Languages []Language `json:"languages"`
+	Framework *Framework `json:"framework,omitempty"`
+	// Frameworks are all the frameworks detected; the first is Framework
+	Frameworks []Framework `json:"frameworks,omitempty"`
+	Structure  Structure   `json:"structure"`
+	Packages   PackageInfo `json:"packages"`
+	Patterns   Patterns    `json:"patterns"`
+	Decisions  []Decision  `json:"decisions"`
+	// Aliases are the import aliases that resolve to project paths
+	Aliases []ImportAlias `json:"aliases,omitempty"`
+	// Totals describe the walk itself rather than the project
+	Totals Totals `json:"totals"`
+}
+
+// Totals count what the walk covered and skipped, so a directory ignored
+// by mistake shows up
+type Totals struct {
+	// FilesScanned are all the files walked, CodeFiles those in a
+	// language the analyzer knows
+	FilesScanned int `json:"filesScanned"`
+	CodeFiles    int `json:"codeFiles"`
+	// FilesIgnored are the files in the Ignored directories that were
+	// counted (see IgnoredDir.Files)
+	FilesIgnored int          `json:"filesIgnored"`
+	Ignored      []IgnoredDir `json:"ignored"`
+	// WalkMillis is how long the walk took, in milliseconds
+	WalkMillis int64 `json:"walkMs"`
+}
+
+// Reasons a directory is ignored
+const (
+	// IgnoreDefault is one of the directories always skipped, such as
+	// node_modules
+	IgnoreDefault = "default"
+	// IgnoreConfig is listed under ignore in config.yaml
+	IgnoreConfig = "config"
+	// IgnoreOption is in Options.Ignore
+	IgnoreOption = "option"
+)
+
+// IgnoredDir is a directory the walk skipped
+type IgnoredDir struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+	// Files it holds, counted only for directories ignored by config or
+	// option: the defaults aren't walked at all, node_modules being why
+	// they are skipped
+	Files int `json:"files,omitempty"`
+}
+
+// Language detected in the codebase
+type Language struct {
+	Name       string  `json:"name"`
+	Extension  string  `json:"extension"`
+	FileCount  int     `json:"fileCount"`
+	Percentage float64 `json:"percentage"`
+	// TestFiles is how many of FileCount are tests
+	TestFiles int `json:"testFiles,omitempty"`
+	// Dirs are the top-level folders its files are in, sorted; "." stands
+	// for files at the root
+	Dirs []string `json:"dirs,omitempty"`
+}
+
+// Framework detected (Next.js, Express, FastAPI, etc.)
+type Framework struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Where is the monorepo package using it, e.g. apps/web; empty for
+	// the project root
+	Where string `json:"where,omitempty"`
+}
+
+// ORM detected (Prisma, Drizzle, etc.)
+type ORM struct {
+	Name string `json:"name"`
+	// Where is the monorepo package using it; empty for the project root
+	Where string `json:"where,omitempty"`
+}
+
+// Structure represents project directory structure
+type Structure struct {
+	Type       string   `json:"type"` // "monorepo", "standard", "flat"
+	SrcDir     string   `json:"srcDir,omitempty"`
+	Folders    []string `json:"folders"`
+	EntryPoint string   `json:"entryPoint,omitempty"`
+}
+
+// PackageInfo from package.json, go.mod, etc.
+type PackageInfo struct {
+	Manager      string            `json:"manager"` // npm, yarn, pnpm, bun, deno, go, pip
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+	DevDeps      map[string]string `json:"devDependencies,omitempty"`
+	// Runtime is the JavaScript runtime when it isn't Node.js: Bun or Deno
+	Runtime string `json:"runtime,omitempty"`
+	// Scripts are the package.json scripts, or the deno.json tasks
+	Scripts map[string]string `json:"scripts,omitempty"`
+	// Workspace are the monorepo's own packages; they are left out of
+	// Dependencies and DevDeps, which also hold their external dependencies
+	Workspace []WorkspacePackage `json:"workspace,omitempty"`
+	// PrivateScopes are npm scopes installed from a private registry,
+	// e.g. @acme
+	PrivateScopes []string `json:"privateScopes,omitempty"`
+}
+
+// Patterns detected in code
+type Patterns struct {
+	NamingConvention string `json:"namingConvention"` // camelCase, snake_case, etc.
+	ExportStyle      string `json:"exportStyle"`      // named, default, mixed
+	TestFramework    string `json:"testFramework,omitempty"`
+	// TestRunner runs the tests in their environments, e.g. tox
+	TestRunner string `json:"testRunner,omitempty"`
+	// TypeChecker is a separate type checker, e.g. mypy
+	TypeChecker string `json:"typeChecker,omitempty"`
+	Linter      string `json:"linter,omitempty"`
+	Formatter   string `json:"formatter,omitempty"`
+	ORM         string `json:"orm,omitempty"`
+	// ORMs are all the ORMs detected; the first is ORM
+	ORMs            []ORM  `json:"orms,omitempty"`
+	StateManagement string `json:"stateManagement,omitempty"`
+	Styling         string `json:"styling,omitempty"`
+	// Styles are all the styling approaches detected, the main one
+	// first; Styling names them
+	Styles []StyleSystem `json:"styles,omitempty"`
+	// Conventions are extra rules reported by plugins
+	Conventions []string `json:"conventions,omitempty"`
+	// Commits is the commit message convention, if the project has one
+	Commits *CommitConvention `json:"commits,omitempty"`
+	// Branches is the branch naming scheme, if the project has one
+	Branches *BranchNaming `json:"branches,omitempty"`
+}
+
+// Decision represents an architectural decision
+type Decision struct {
+	Date    string `json:"date"`
+	Text    string `json:"text"`
+	Context string `json:"context,omitempty"`
+}
+
+// Profile records where time went during the last Analyze call
+type Profile struct {
+	Phases []PhaseTiming
+	Dirs   []DirTiming
+}
+
+// PhaseTiming is the duration of one analysis phase
+type PhaseTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+// DirTiming is the walk cost attributed to one top-level directory
+type DirTiming struct {
+	Path      string
+	Duration  time.Duration
+	Entries   int
+	CodeFiles int
+	TestFiles int
+}
+
+// ProgressFunc is called as analysis moves through its phases ("walk",
+// "framework", "structure", "patterns") with the number of files walked
+type ProgressFunc func(phase string, files int)
+
+// progressEvery is how many walked entries pass between walk progress reports
+const progressEvery = 500
+
+// Analyzer performs codebase analysis
+type Analyzer struct {
+	rootPath  string
+	files     fs.FS
+	gitIgnore []string
+	structure string
+	profile   Profile
+	progress  ProgressFunc
+	log       *slog.Logger
+	plugins   []string
+	// ignoreReason says why each gitIgnore entry is there
+	ignoreReason map[string]string
+	// styleFiles counts the stylesheets of each kind the last walk found
+	styleFiles map[string]int
+}
+
+// Options configures an Analyzer beyond what the project's
+// .contextpilot/config.yaml says
+type Options struct {
+	// Root is the project directory
+	Root string
+	// FS is read instead of the directory at Root when set, e.g. an
+	// archive from fsys.OpenArchive or an in-memory fsys.Mem; Root then
+	// only names the project
+	FS fs.FS
+	// Ignore lists directory names or root-relative paths to skip, in
+	// addition to the defaults and the config's ignore list
+	Ignore []string
+	// Structure overrides monorepo detection ("monorepo" or "single")
+	Structure string
+	// OnProgress is told how far analysis has got
+	OnProgress ProgressFunc
+	// Logger receives phase timings and what was detected; nil means
+	// slog.Default()
+	Logger *slog.Logger
+}
+
+// New creates a new Analyzer for the given path. Entries from the ignore
+// list in .contextpilot/config.yaml are skipped in addition to the defaults,
+// and its structure setting overrides monorepo detection.
+func New(rootPath string) *Analyzer {
+	return NewWithOptions(Options{Root: rootPath})
+}
+
+// NewWithOptions creates an Analyzer for opts.Root configured by opts
+func NewWithOptions(opts Options) *Analyzer {
+	a := &Analyzer{
+		rootPath: opts.Root,
+		files:    opts.FS,
+		gitIgnore: []string{
+			"node_modules", "vendor", ".git", "dist", "build",
+			".next", "__pycache__", ".venv", "venv", ".idea",
+			".vscode", "coverage", ".nyc_output",
+		},
+		ignoreReason: map[string]string{},
+		progress:     opts.OnProgress,
+		log:          opts.Logger,
+	}
+	for _, ignored := range a.gitIgnore {
+		a.ignoreReason[ignored] = IgnoreDefault
+	}
+	if a.files == nil {
+		a.files = fsys.OS(opts.Root)
+	}
+	if a.log == nil {
+		a.log = slog.Default()
+	}
+	var ignore []string
+	if cfg, err := config.LoadFS(a.files); err == nil {
+		ignore = cfg.Ignore
+		a.structure = cfg.Structure
+		a.plugins = cfg.Plugins
+	}
+	for i, ignored := range append(ignore, opts.Ignore...) {
+		if !contains(a.gitIgnore, ignored) {
+			a.gitIgnore = append(a.gitIgnore, ignored)
+			a.ignoreReason[ignored] = IgnoreConfig
+			if i >= len(ignore) {
+				a.ignoreReason[ignored] = IgnoreOption
+			}
+		}
+	}
+	if opts.Structure != "" {
+		a.structure = opts.Structure
+	}
+	return a
+}
+
+// OnProgress registers fn to be told how far Analyze has got
+func (a *Analyzer) OnProgress(fn ProgressFunc) {
+	a.progress = fn
+}
+
+// report calls the progress callback, if any
+func (a *Analyzer) report(phase string, files int) {
+	if a.progress != nil {
+		a.progress(phase, files)
+	}
+}
+
+// Ignores reports whether the directory at rel (relative to the root) is
+// skipped during analysis, by name or by path
+func (a *Analyzer) Ignores(rel string) bool {
+	return a.ignoredBy(rel) != ""
+}
+
+// ignoredBy returns the gitIgnore entry that skips the directory at rel,
+// or "" if none does
+func (a *Analyzer) ignoredBy(rel string) string {
+	rel = filepath.ToSlash(rel)
+	name := filepath.Base(rel)
+	for _, ignored := range a.gitIgnore {
+		if name == ignored || rel == ignored {
+			return ignored
+		}
+	}
+	return ""
+}
+
+// Profile returns phase and directory timings from the last Analyze call
+func (a *Analyzer) Profile() Profile {
+	return a.profile
+}
+
+// Analyze performs full codebase analysis
+func (a *Analyzer) Analyze() (*Analysis, error) {
+	return a.AnalyzeContext(context.Background())
+}
+
+// AnalyzeContext is Analyze, stopping early with ctx's error once ctx is
+// done. The analysis so far comes back with that error: the languages of
+// the files walked until then and what the finished phases detected.
+func (a *Analyzer) AnalyzeContext(ctx context.Context) (*Analysis, error) {
+	analysis := &Analysis{
+		RootPath:  a.rootPath,
+		Languages: []Language{},
+		Packages:  PackageInfo{Dependencies: make(map[string]string)},
+		Patterns:  Patterns{},
+		Decisions: []Decision{},
+		Totals:    Totals{Ignored: []IgnoredDir{}},
+	}
+
+	a.profile = Profile{}
+	a.styleFiles = map[string]int{}
+	start := time.Now()
+	phaseStart := start
+	phase := func(name string) {
+		elapsed := time.Since(phaseStart)
+		a.profile.Phases = append(a.profile.Phases, PhaseTiming{Name: name, Duration: elapsed})
+		a.log.Debug("analysis phase", "phase", name, "duration", elapsed)
+		phaseStart = time.Now()
+	}
+
+	// Count files by extension
+	extCount := make(map[string]int)
+	testCount := make(map[string]int)
+	extDirs := make(map[string]map[string]bool)
+	totalFiles := 0
+
+	// Attribute walk time to top-level directories
+	dirStats := make(map[string]*DirTiming)
+	lastVisit := time.Now()
+
+	walked := 0
+	a.report("walk", 0)
+	err := fs.WalkDir(a.files, ".", func(name string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return nil // Skip errors
+		}
+
+		// Skip ignored directories (never the root itself)
+		if d.IsDir() && name != "." {
+			if by := a.ignoredBy(name); by != "" {
+				a.log.Debug("skipping ignored directory", "dir", name)
+				dir := IgnoredDir{Path: name, Reason: a.ignoreReason[by]}
+				if dir.Reason != IgnoreDefault {
+					dir.Files = countFiles(ctx, a.files, name)
+					analysis.Totals.FilesIgnored += dir.Files
+				}
+				analysis.Totals.Ignored = append(analysis.Totals.Ignored, dir)
+				return fs.SkipDir
+			}
+		}
+
+		now := time.Now()
+		stat := a.dirStat(dirStats, name, d.IsDir())
+		if stat != nil {
+			stat.Duration += now.Sub(lastVisit)
+			stat.Entries++
+		}
+		lastVisit = now
+
+		if d.IsDir() {
+			return nil
+		}
+
+		walked++
+		if walked%progressEvery == 0 {
+			a.report("walk", walked)
+		}
+		for _, kind := range styleFileKinds(name) {
+			a.styleFiles[kind]++
+		}
+
+		// Count by extension
+		ext := strings.ToLower(path.Ext(name))
+		if ext != "" && isCodeFile(ext) {
+			extCount[ext]++
+			totalFiles++
+			top, _, nested := strings.Cut(name, "/")
+			if !nested {
+				top = "."
+			}
+			if extDirs[ext] == nil {
+				extDirs[ext] = map[string]bool{}
+			}
+			extDirs[ext][top] = true
+			test := isTestFile(name)
+			if test {
+				testCount[ext]++
+			}
+			if stat != nil {
+				stat.CodeFiles++
+				if test {
+					stat.TestFiles++
+				}
+			}
+		}
+
+		return nil
+	})
+
+	// The walk only fails once ctx is done; what it saw still counts
+	walkErr := err
+
+	analysis.Totals.FilesScanned = walked
+	analysis.Totals.CodeFiles = totalFiles
+	analysis.Totals.WalkMillis = time.Since(start).Milliseconds()
+
+	for _, stat := range dirStats {
+		a.profile.Dirs = append(a.profile.Dirs, *stat)
+	}
+	sort.Slice(a.profile.Dirs, func(i, j int) bool {
+		return a.profile.Dirs[i].Duration > a.profile.Dirs[j].Duration
+	})
+	phase("walk")
+
+	// Convert to Language structs
+	for ext, count := range extCount {
+		lang := extensionToLanguage(ext)
+		if lang != "" {
+			pct := float64(count) / float64(totalFiles) * 100
+			analysis.Languages = append(analysis.Languages, Language{
+				Name:       lang,
+				Extension:  ext,
+				FileCount:  count,
+				Percentage: pct,
+				TestFiles:  testCount[ext],
+				Dirs:       sortedKeys(extDirs[ext]),
+			})
+		}
+	}
+
+	if walkErr != nil {
+		return analysis, walkErr
+	}
+
+	// Detect framework from package files
+	if err := ctx.Err(); err != nil {
+		return analysis, err
+	}
+	a.report("framework", walked)
+	a.detectFramework(analysis)
+	phase("framework")
+
+	// Analyze structure
+	if err := ctx.Err(); err != nil {
+		return analysis, err
+	}
+	a.report("structure", walked)
+	a.analyzeStructure(analysis)
+	phase("structure")
+
+	// Detect patterns
+	if err := ctx.Err(); err != nil {
+		return analysis, err
+	}
+	a.report("patterns", walked)
+	a.detectPatterns(analysis)
+	a.detectGitConventions(analysis)
+	phase("patterns")
+
+	if len(a.plugins) > 0 {
+		if err := ctx.Err(); err != nil {
+			return analysis, err
+		}
+		a.report("plugins", walked)
+		if err := a.runPlugins(ctx, analysis); err != nil {
+			if ctx.Err() != nil {
+				return analysis, err
+			}
+			return nil, err
+		}
+		phase("plugins")
+	}
+
+	framework := ""
+	if analysis.Framework != nil {
+		framework = analysis.Framework.Name
+	}
+	a.log.Info("analyzed project",
+		"root", a.rootPath,
+		"files", walked,
+		"codeFiles", totalFiles,
+		"framework", framework,
+		"structure", analysis.Structure.Type,
+		"duration", time.Since(start))
+	return analysis, nil
+}
+
+// countFiles returns how many files are under dir, stopping once ctx is
+// done
+func countFiles(ctx context.Context, files fs.FS, dir string) int {
+	n := 0
+	fs.WalkDir(files, dir, func(_ string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return fs.SkipAll
+		}
+		if err == nil && !d.IsDir() {
+			n++
+		}
+		return nil
+	})
+	return n
+}
+
+// dirStat returns the timing bucket for the top-level directory containing
+// name, or nil for the root and files directly in it
+func (a *Analyzer) dirStat(stats map[string]*DirTiming, name string, isDir bool) *DirTiming {
+	if name == "." {
+		return nil
+	}
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 1 && !isDir {
+		return nil
+	}
+	top := parts[0]
+	if stats[top] == nil {
+		stats[top] = &DirTiming{Path: top}
+	}
+	return stats[top]
+}
+
+func (a *Analyzer) detectFramework(analysis *Analysis) {
+	// Check package.json
+	if data, err := fs.ReadFile(a.files, "package.json"); err == nil {
+		var pkg struct {
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+			Scripts         map[string]string `json:"scripts"`
+			PackageManager  string            `json:"packageManager"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			analysis.Packages.Manager = a.jsManager(pkg.PackageManager)
+			analysis.Packages.Dependencies = pkg.Dependencies
+			analysis.Packages.DevDeps = pkg.DevDependencies
+			analysis.Packages.Scripts = pkg.Scripts
+			if analysis.Packages.Manager == "bun" {
+				analysis.Packages.Runtime = "Bun"
+			}
+
+			// Detect frameworks
+			setFrameworks(analysis, a.detectJSFrameworks("", pkg.Dependencies, pkg.DevDependencies))
+
+			// Detect ORMs
+			setORMs(analysis, detectJSORMs("", pkg.Dependencies, pkg.DevDependencies))
+
+			// Detect testing
+			if _, ok := pkg.DevDependencies["vitest"]; ok {
+				analysis.Patterns.TestFramework = "Vitest"
+			} else if _, ok := pkg.DevDependencies["jest"]; ok {
+				analysis.Patterns.TestFramework = "Jest"
+			} else if _, ok := pkg.DevDependencies["mocha"]; ok {
+				analysis.Patterns.TestFramework = "Mocha"
+			}
+
+			// Detect state management
+			if _, ok := pkg.Dependencies["zustand"]; ok {
+				analysis.Patterns.StateManagement = "Zustand"
+			} else if _, ok := pkg.Dependencies["@reduxjs/toolkit"]; ok {
+				analysis.Patterns.StateManagement = "Redux Toolkit"
+			} else if _, ok := pkg.Dependencies["jotai"]; ok {
+				analysis.Patterns.StateManagement = "Jotai"
+			} else if _, ok := pkg.Dependencies["recoil"]; ok {
+				analysis.Patterns.StateManagement = "Recoil"
+			}
+
+			// Detect linter/formatter
+			if _, ok := pkg.DevDependencies["eslint"]; ok {
+				analysis.Patterns.Linter = "ESLint"
+			}
+			if _, ok := pkg.DevDependencies["prettier"]; ok {
+				analysis.Patterns.Formatter = "Prettier"
+			} else if _, ok := pkg.DevDependencies["biome"]; ok {
+				analysis.Patterns.Formatter = "Biome"
+			}
+
+			// Bun ships its own test runner
+			if analysis.Packages.Runtime == "Bun" && analysis.Patterns.TestFramework == "" {
+				analysis.Patterns.TestFramework = "bun:test"
+			}
+		}
+	}
+
+	// Frameworks and ORMs of monorepo packages
+	a.detectPackages(analysis)
+
+	// Styling, from the dependencies of every package
+	a.detectStyling(analysis)
+
+	// Import aliases of every package
+	a.detectAliases(analysis)
+
+	// Check deno.json
+	a.detectDeno(analysis)
+
+	// Check go.mod
+	if fsys.Exists(a.files, "go.mod") {
+		analysis.Packages.Manager = "go"
+		// Could parse go.mod for dependencies
+		a.detectGoTools(analysis)
+	}
+
+	// Check pyproject.toml / requirements.txt
+	if fsys.Exists(a.files, "pyproject.toml") {
+		analysis.Packages.Manager = "poetry/pip"
+	} else if fsys.Exists(a.files, "requirements.txt") || fsys.Exists(a.files, "setup.py") || fsys.Exists(a.files, "setup.cfg") {
+		analysis.Packages.Manager = "pip"
+	}
+	a.detectPythonTools(analysis)
+}
+
+// jsLockfiles maps lockfiles and config files to the package manager
+// they belong to, in order of precedence
+var jsLockfiles = []struct{ file, manager string }{
+	{"bun.lockb", "bun"},
+	{"bun.lock", "bun"},
+	{"bunfig.toml", "bun"},
+	{"pnpm-lock.yaml", "pnpm"},
+	{"yarn.lock", "yarn"},
+	{".yarnrc.yml", "yarn"},
+	{"package-lock.json", "npm"},
+}
+
+// jsManager returns the package manager of a package.json project: the
+// one its packageManager field names, such as pnpm@9.1.0, else the one
+// whose lockfile is present, else npm
+func (a *Analyzer) jsManager(packageManager string) string {
+	name, _, _ := strings.Cut(packageManager, "@")
+	switch name {
+	case "npm", "yarn", "pnpm", "bun":
+		return name
+	}
+	for _, l := range jsLockfiles {
+		if fsys.Exists(a.files, l.file) {
+			return l.manager
+		}
+	}
+	return "npm"
+}
+
+func (a *Analyzer) analyzeStructure(analysis *Analysis) {
+	analysis.Structure.Type = "standard"
+
+	// Check for common directories
+	commonDirs := []string{"src", "app", "lib", "components", "pages", "api", "utils", "hooks", "services", "models", "types"}
+	foundDirs := []string{}
+
+	for _, dir := range commonDirs {
+		if fsys.IsDir(a.files, dir) {
+			foundDirs = append(foundDirs, dir)
+		}
+	}
+
+	analysis.Structure.Folders = foundDirs
+
+	// Detect src directory
+	if contains(foundDirs, "src") {
+		analysis.Structure.SrcDir = "src"
+	} else if contains(foundDirs, "app") {
+		analysis.Structure.SrcDir = "app"
+	}
+
+	// Check for monorepo indicators
+	for _, marker := range []string{"packages", "apps", "pnpm-workspace.yaml", "lerna.json", "turbo.json"} {
+		if fsys.Exists(a.files, marker) {
+			analysis.Structure.Type = "monorepo"
+			break
+		}
+	}
+
+	// config.yaml can override detection
+	switch a.structure {
+	case "monorepo":
+		analysis.Structure.Type = "monorepo"
+	case "single":
+		if analysis.Structure.Type == "monorepo" {
+			analysis.Structure.Type = "standard"
+		}
+	}
+
+	// Detect entry point
+	entryPoints := []string{"index.ts", "index.js", "main.ts", "main.js", "main.go", "main.py", "app.py"}
+	for _, entry := range entryPoints {
+		if fsys.Exists(a.files, entry) {
+			analysis.Structure.EntryPoint = entry
+			break
+		}
+		if analysis.Structure.SrcDir != "" {
+			if fsys.Exists(a.files, path.Join(analysis.Structure.SrcDir, entry)) {
+				analysis.Structure.EntryPoint = filepath.Join(analysis.Structure.SrcDir, entry)
+				break
+			}
+		}
+	}
+}
+
+func (a *Analyzer) detectPatterns(analysis *Analysis) {
+	// Default patterns
+	if analysis.Patterns.NamingConvention == "" {
+		// Check primary language
+		for _, lang := range analysis.Languages {
+			if lang.Name == "Go" {
+				analysis.Patterns.NamingConvention = "camelCase/PascalCase"
+				analysis.Patterns.ExportStyle = "named (capitalized)"
+				break
+			} else if lang.Name == "Python" {
+				analysis.Patterns.NamingConvention = "snake_case"
+				break
+			} else if lang.Name == "TypeScript" || lang.Name == "JavaScript" {
+				analysis.Patterns.NamingConvention = "camelCase"
+				analysis.Patterns.ExportStyle = "mixed"
+				break
+			}
+		}
+	}
+}
+
+// Helper functions
+
+func isCodeFile(ext string) bool {
+	codeExts := map[string]bool{
+		".js": true, ".ts": true, ".jsx": true, ".tsx": true,
+		".go": true, ".py": true, ".rb": true, ".rs": true,
+		".java": true, ".kt": true, ".swift": true, ".c": true,
+		".cpp": true, ".h": true, ".cs": true, ".php": true,
+		".vue": true, ".svelte": true,
+	}
+	return codeExts[ext]
+}
+
+// LanguageOf returns the language of the code file at name, or "" when
+// the analyzer doesn't count it as code
+func LanguageOf(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if ext == "" || !isCodeFile(ext) {
+		return ""
+	}
+	return extensionToLanguage(ext)
+}
+
+// IsTestFile reports whether the code file at name is a test (see
+// isTestFile)
+func IsTestFile(name string) bool {
+	return isTestFile(name)
+}
+
+// isTestFile reports whether the code file at name (slash-separated,
+// relative to the root) is a test, by the usual naming conventions or by
+// living in a test directory
+func isTestFile(name string) bool {
+	base := path.Base(name)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	switch {
+	case strings.HasSuffix(stem, "_test"), strings.HasSuffix(stem, "_spec"),
+		strings.HasPrefix(stem, "test_"),
+		strings.HasSuffix(stem, ".test"), strings.HasSuffix(stem, ".spec"),
+		strings.HasSuffix(stem, "Test"), strings.HasSuffix(stem, "Tests"):
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(name), "/") {
+		switch dir {
+		case "test", "tests", "__tests__", "spec", "specs":
+			return true
+		}
+	}
+	return false
+}
+
+func extensionToLanguage(ext string) string {
+	langMap := map[string]string{
+		".js":     "JavaScript",
+		".ts":     "TypeScript",
+		".jsx":    "JavaScript (JSX)",
+		".tsx":    "TypeScript (TSX)",
+		".go":     "Go",
+		".py":     "Python",
+		".rb":     "Ruby",
+		".rs":     "Rust",
+		".java":   "Java",
+		".kt":     "Kotlin",
+		".swift":  "Swift",
+		".c":      "C",
+		".cpp":    "C++",
+		".h":      "C/C++ Header",
+		".cs":     "C#",
+		".php":    "PHP",
+		".vue":    "Vue",
+		".svelte": "Svelte",
+	}
+	return langMap[ext]
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
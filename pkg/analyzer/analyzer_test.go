@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+var quiet = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestAnalyzeMem(t *testing.T) {
+	files := fsys.NewMem(map[string]string{
+		"go.mod":                         "module example.com/shop\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.1\n",
+		"main.go":                        "package main\n\nfunc main() {}\n",
+		"internal/orders/orders.go":      "package orders\n",
+		"internal/orders/orders_test.go": "package orders\n",
+		"vendor/lib/lib.go":              "package lib\n",
+		"generated/api.go":               "package api\n",
+		".contextpilot/config.yaml":      "ignore:\n  - generated\n",
+	})
+
+	analysis, err := NewWithOptions(Options{Root: "shop", FS: files, Logger: quiet}).Analyze()
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+
+	if len(analysis.Languages) != 1 || analysis.Languages[0].Name != "Go" {
+		t.Fatalf("Languages = %+v, want only Go", analysis.Languages)
+	}
+	// vendor is skipped by default, generated by the config.yaml in files
+	if got := analysis.Languages[0].FileCount; got != 3 {
+		t.Errorf("Go FileCount = %d, want 3", got)
+	}
+	if got := analysis.Languages[0].TestFiles; got != 1 {
+		t.Errorf("Go TestFiles = %d, want 1", got)
+	}
+	if analysis.Packages.Manager != "go" {
+		t.Errorf("Packages.Manager = %q, want go", analysis.Packages.Manager)
+	}
+}
+
+func TestAnalyzeReadOnly(t *testing.T) {
+	mem := fsys.NewMem(map[string]string{
+		"package.json": `{"name": "web", "dependencies": {"react": "^18.2.0"}}`,
+		"src/App.tsx":  "export const App = () => null;\n",
+	})
+
+	analysis, err := NewWithOptions(Options{Root: "web", FS: fsys.ReadOnly(mem), Logger: quiet}).Analyze()
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if analysis.Framework == nil || analysis.Framework.Name != "React" {
+		t.Errorf("Framework = %+v, want React", analysis.Framework)
+	}
+	if got := mem.Files(); len(got) != 2 {
+		t.Errorf("analysis wrote to the project: %v", got)
+	}
+}
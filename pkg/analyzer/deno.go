@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/jsonc"
+)
+
+// denoConfig is the part of deno.json(c) the analyzer reads
+type denoConfig struct {
+	Imports   map[string]string          `json:"imports"`
+	ImportMap string                     `json:"importMap"`
+	Tasks     map[string]json.RawMessage `json:"tasks"`
+}
+
+// denoFrameworks maps import names to the framework they indicate, in
+// order of precedence
+var denoFrameworks = []struct{ name, framework string }{
+	{"@fresh/core", "Fresh"},
+	{"fresh", "Fresh"},
+	{"@hono/hono", "Hono"},
+	{"hono", "Hono"},
+	{"@oak/oak", "Oak"},
+	{"oak", "Oak"},
+}
+
+// detectDeno reads deno.json or deno.jsonc: its import map becomes the
+// dependencies and its tasks the scripts
+func (a *Analyzer) detectDeno(analysis *Analysis) {
+	var data []byte
+	for _, name := range []string{"deno.json", "deno.jsonc"} {
+		if b, err := fs.ReadFile(a.files, name); err == nil {
+			data = b
+			break
+		}
+	}
+	if data == nil {
+		return
+	}
+	plain, _ := jsonc.Strip(data)
+	var cfg denoConfig
+	if json.Unmarshal(plain, &cfg) != nil {
+		return
+	}
+
+	pkgs := &analysis.Packages
+	pkgs.Manager = "deno"
+	pkgs.Runtime = "Deno"
+
+	imports := cfg.Imports
+	if len(imports) == 0 && cfg.ImportMap != "" && !strings.Contains(cfg.ImportMap, "://") {
+		var m struct {
+			Imports map[string]string `json:"imports"`
+		}
+		if b, err := fs.ReadFile(a.files, path.Clean(strings.TrimPrefix(cfg.ImportMap, "./"))); err == nil && json.Unmarshal(b, &m) == nil {
+			imports = m.Imports
+		}
+	}
+	if len(imports) > 0 && pkgs.Dependencies == nil {
+		pkgs.Dependencies = map[string]string{}
+	}
+	for key, spec := range imports {
+		name, version := denoDependency(key, spec)
+		if name != "" {
+			pkgs.Dependencies[name] = version
+		}
+	}
+
+	for name, raw := range cfg.Tasks {
+		// A task is a command, or since Deno 2 an object with one
+		var task struct {
+			Command string `json:"command"`
+		}
+		if json.Unmarshal(raw, &task.Command) != nil {
+			json.Unmarshal(raw, &task)
+		}
+		if pkgs.Scripts == nil {
+			pkgs.Scripts = map[string]string{}
+		}
+		pkgs.Scripts[name] = task.Command
+	}
+
+	if analysis.Framework == nil {
+		for _, f := range denoFrameworks {
+			if version, ok := pkgs.Dependencies[f.name]; ok {
+				setFrameworks(analysis, []Framework{{Name: f.framework, Version: version}})
+				break
+			}
+		}
+	}
+
+	// Deno ships its own test runner, linter and formatter
+	if analysis.Patterns.TestFramework == "" {
+		analysis.Patterns.TestFramework = "Deno.test"
+	}
+	if analysis.Patterns.Linter == "" {
+		analysis.Patterns.Linter = "deno lint"
+	}
+	if analysis.Patterns.Formatter == "" {
+		analysis.Patterns.Formatter = "deno fmt"
+	}
+}
+
+// urlVersion finds the version in URLs such as
+// https://deno.land/x/fresh@1.6.8/
+var urlVersion = regexp.MustCompile(`@(v?\d[^/]*)`)
+
+// denoDependency returns the package name and version an import map entry
+// refers to: jsr:@std/assert@^1.0.0 is @std/assert ^1.0.0, and a URL is
+// named by its key without $ and slashes
+func denoDependency(key, spec string) (string, string) {
+	for _, scheme := range []string{"jsr:", "npm:"} {
+		if rest, ok := strings.CutPrefix(spec, scheme); ok {
+			rest = strings.TrimPrefix(rest, "/")
+			// The version follows the last @ that doesn't start a scope
+			if i := strings.LastIndex(rest, "@"); i > 0 {
+				name, version := rest[:i], rest[i+1:]
+				return name, strings.SplitN(version, "/", 2)[0]
+			}
+			return strings.TrimSuffix(rest, "/"), ""
+		}
+	}
+	name := strings.Trim(key, "$/")
+	version := ""
+	if m := urlVersion.FindStringSubmatch(spec); m != nil {
+		version = m[1]
+	}
+	return name, version
+}
@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path"
+	"sort"
+
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// jsFramework is a JavaScript framework and how to recognize it
+type jsFramework struct {
+	name string
+	// packages are the dependencies that indicate it; the first one found
+	// gives the version
+	packages []string
+	// configs are config files that indicate it
+	configs []string
+	// implies are frameworks it builds on, which aren't reported
+	// separately when it is detected
+	implies []string
+}
+
+// jsFrameworks are checked in this order, so meta-frameworks come before
+// the libraries they build on and the first one found is the main one
+var jsFrameworks = []jsFramework{
+	{"Next.js", []string{"next"}, []string{"next.config.js", "next.config.mjs", "next.config.ts"}, []string{"React"}},
+	{"Nuxt", []string{"nuxt"}, []string{"nuxt.config.ts", "nuxt.config.js"}, []string{"Vue.js", "Vite"}},
+	{"Remix", []string{"@remix-run/react", "@remix-run/node", "@remix-run/dev"}, []string{"remix.config.js"}, []string{"React", "Vite"}},
+	{"SvelteKit", []string{"@sveltejs/kit"}, nil, []string{"Svelte", "Vite"}},
+	{"Astro", []string{"astro"}, []string{"astro.config.mjs", "astro.config.ts", "astro.config.js"}, []string{"Vite"}},
+	{"Angular", []string{"@angular/core"}, []string{"angular.json"}, nil},
+	{"NestJS", []string{"@nestjs/core"}, []string{"nest-cli.json"}, []string{"Express"}},
+	{"Express", []string{"express"}, nil, nil},
+	{"React", []string{"react"}, nil, nil},
+	{"Vue.js", []string{"vue"}, nil, nil},
+	{"Svelte", []string{"svelte"}, nil, nil},
+	{"Vite", []string{"vite"}, []string{"vite.config.ts", "vite.config.js", "vite.config.mjs"}, nil},
+}
+
+// FrameworkPackages returns the dependencies that indicate the framework
+// name, e.g. next for Next.js; the first one gives its version
+func FrameworkPackages(name string) []string {
+	for _, f := range jsFrameworks {
+		if f.name == name {
+			return f.packages
+		}
+	}
+	return nil
+}
+
+// detectJSFrameworks returns every framework in deps, devDeps or the
+// config files of the package at dir, main one first
+func (a *Analyzer) detectJSFrameworks(dir string, deps, devDeps map[string]string) []Framework {
+	var found []Framework
+	implied := map[string]bool{}
+	for _, f := range jsFrameworks {
+		version, ok := "", false
+		for _, p := range f.packages {
+			if version, ok = deps[p]; ok {
+				break
+			}
+			if version, ok = devDeps[p]; ok {
+				break
+			}
+		}
+		for _, c := range f.configs {
+			if ok {
+				break
+			}
+			ok = fsys.Exists(a.files, path.Join(dir, c))
+		}
+		if !ok || implied[f.name] {
+			continue
+		}
+		found = append(found, Framework{Name: f.name, Version: version, Where: where(dir)})
+		for _, name := range f.implies {
+			implied[name] = true
+		}
+	}
+	return found
+}
+
+// setFrameworks records frameworks, the first being the main one
+func setFrameworks(analysis *Analysis, frameworks []Framework) {
+	analysis.Frameworks = frameworks
+	analysis.Framework = nil
+	if len(frameworks) > 0 {
+		main := frameworks[0]
+		analysis.Framework = &main
+	}
+}
+
+// jsORMs maps packages to the ORM they indicate, in order of precedence
+var jsORMs = []struct{ pkg, name string }{
+	{"prisma", "Prisma"},
+	{"@prisma/client", "Prisma"},
+	{"drizzle-orm", "Drizzle"},
+	{"typeorm", "TypeORM"},
+	{"mongoose", "Mongoose"},
+	{"sequelize", "Sequelize"},
+	{"@mikro-orm/core", "MikroORM"},
+	{"kysely", "Kysely"},
+}
+
+// detectJSORMs returns every ORM in deps or devDeps of the package at dir
+func detectJSORMs(dir string, deps, devDeps map[string]string) []ORM {
+	var found []ORM
+	seen := map[string]bool{}
+	for _, o := range jsORMs {
+		_, inDeps := deps[o.pkg]
+		_, inDevDeps := devDeps[o.pkg]
+		if (inDeps || inDevDeps) && !seen[o.name] {
+			seen[o.name] = true
+			found = append(found, ORM{Name: o.name, Where: where(dir)})
+		}
+	}
+	return found
+}
+
+// setORMs records orms, the first being the main one
+func setORMs(analysis *Analysis, orms []ORM) {
+	analysis.Patterns.ORMs = orms
+	analysis.Patterns.ORM = ""
+	if len(orms) > 0 {
+		analysis.Patterns.ORM = orms[0].Name
+	}
+}
+
+// where turns a package directory into a Where value
+func where(dir string) string {
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// detectPackages adds the frameworks and ORMs of each monorepo package,
+// with the package as their Where, and merges the packages' external
+// dependencies into the project's
+func (a *Analyzer) detectPackages(analysis *Analysis) {
+	var frameworks []Framework
+	orms := analysis.Patterns.ORMs
+	pkgs := a.workspacePackages()
+	var manifests []packageJSON
+	for _, dir := range pkgs {
+		data, err := fs.ReadFile(a.files, path.Join(dir, "package.json"))
+		if err != nil {
+			continue
+		}
+		var pkg packageJSON
+		if json.Unmarshal(data, &pkg) != nil {
+			continue
+		}
+		manifests = append(manifests, pkg)
+		frameworks = append(frameworks, a.detectJSFrameworks(dir, pkg.Dependencies, pkg.DevDependencies)...)
+		orms = append(orms, detectJSORMs(dir, pkg.Dependencies, pkg.DevDependencies)...)
+		if pkg.Name != "" {
+			analysis.Packages.Workspace = append(analysis.Packages.Workspace, WorkspacePackage{Name: pkg.Name, Path: dir})
+		}
+	}
+	mergeDependencies(analysis, manifests)
+	analysis.Packages.PrivateScopes = a.privateScopes()
+
+	// The root's frameworks come first, then the packages' by precedence
+	rank := func(name string) int {
+		for i, f := range jsFrameworks {
+			if f.name == name {
+				return i
+			}
+		}
+		return len(jsFrameworks)
+	}
+	sort.SliceStable(frameworks, func(i, j int) bool {
+		return rank(frameworks[i].Name) < rank(frameworks[j].Name)
+	})
+	setFrameworks(analysis, append(analysis.Frameworks, frameworks...))
+	setORMs(analysis, orms)
+}
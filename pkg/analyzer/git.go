@@ -0,0 +1,248 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// CommitConvention is how a project writes commit messages
+type CommitConvention struct {
+	// Style names the convention, e.g. "Conventional Commits"
+	Style string `json:"style"`
+	// Types are the commit types in use, most used first
+	Types []string `json:"types,omitempty"`
+	// Scoped is set when most commits name a scope, as in fix(api): ...
+	Scoped bool `json:"scoped,omitempty"`
+	// Enforcer is the tool that checks messages, e.g. "commitlint"
+	Enforcer string `json:"enforcer,omitempty"`
+}
+
+// String describes the convention for the context files, e.g.
+// "Conventional Commits (`type(scope): summary`; types: feat, fix), checked by commitlint"
+func (c *CommitConvention) String() string {
+	format := "`type: summary`"
+	if c.Scoped {
+		format = "`type(scope): summary`"
+	}
+	s := c.Style + " (" + format
+	if len(c.Types) > 0 {
+		s += "; types: " + strings.Join(c.Types, ", ")
+	}
+	s += ")"
+	if c.Enforcer != "" {
+		s += ", checked by " + c.Enforcer
+	}
+	return s
+}
+
+// BranchNaming is how a project names its branches
+type BranchNaming struct {
+	// Prefixes are the branch prefixes in use, most used first
+	Prefixes []string `json:"prefixes"`
+	// Ticket is an example issue key when branch names start with one
+	// after the prefix, e.g. "PROJ-123"
+	Ticket string `json:"ticket,omitempty"`
+}
+
+// String describes the scheme for the context files, e.g.
+// "`feat/<description>`, `fix/<description>`"
+func (b *BranchNaming) String() string {
+	rest := "<description>"
+	if b.Ticket != "" {
+		rest = b.Ticket + "-<description>"
+	}
+	forms := make([]string, 0, len(b.Prefixes))
+	for _, p := range b.Prefixes {
+		forms = append(forms, "`"+p+"/"+rest+"`")
+	}
+	return strings.Join(forms, ", ")
+}
+
+// How much history is sampled, and how much of it has to agree
+const (
+	commitSample      = 50
+	minCommits        = 5
+	minBranches       = 3
+	conventionalShare = 0.6
+	prefixedShare     = 0.6
+	maxCommitTypes    = 6
+	maxBranchPrefixes = 4
+	minPrefixUses     = 2
+	ticketBranchShare = 0.5
+)
+
+var (
+	conventionalSubject = regexp.MustCompile(`^(feat|fix|docs|style|refactor|perf|test|tests|build|ci|chore|revert)(\([^)]+\))?!?: \S`)
+	branchPrefix        = regexp.MustCompile(`^([a-z][a-z0-9-]*)/(.+)$`)
+	ticketKey           = regexp.MustCompile(`(?i)^([a-z][a-z0-9]*-\d+)\b`)
+)
+
+// longLivedBranches aren't named by a scheme
+var longLivedBranches = map[string]bool{
+	"main": true, "master": true, "develop": true, "development": true,
+	"dev": true, "trunk": true, "staging": true, "production": true,
+	"gh-pages": true,
+}
+
+// commitlintConfigs are the files commitlint reads its config from
+var commitlintConfigs = []string{
+	"commitlint.config.js", "commitlint.config.cjs", "commitlint.config.mjs",
+	"commitlint.config.ts", ".commitlintrc", ".commitlintrc.json",
+	".commitlintrc.yml", ".commitlintrc.yaml", ".commitlintrc.js",
+	".commitlintrc.cjs",
+}
+
+// detectGitConventions finds the commit message convention, from tool
+// config and a sample of history, and the branch naming scheme. History
+// and branches come from git, so they're only read when Root is a
+// directory on disk.
+func (a *Analyzer) detectGitConventions(analysis *Analysis) {
+	enforcer := a.commitEnforcer()
+	var subjects, branches []string
+	if info, err := os.Stat(a.rootPath); err == nil && info.IsDir() && git.IsRepo(a.rootPath) {
+		subjects = git.Subjects(a.rootPath, commitSample)
+		branches = git.Branches(a.rootPath)
+	}
+
+	if c := commitConvention(subjects); c != nil || enforcer != "" {
+		if c == nil {
+			c = &CommitConvention{Style: "Conventional Commits"}
+		}
+		c.Enforcer = enforcer
+		analysis.Patterns.Commits = c
+	}
+	analysis.Patterns.Branches = branchNaming(branches)
+}
+
+// commitEnforcer returns the tool configured to check commit messages
+func (a *Analyzer) commitEnforcer() string {
+	commitlint := false
+	for _, name := range commitlintConfigs {
+		if fsys.Exists(a.files, name) {
+			commitlint = true
+			break
+		}
+	}
+	commitizen := fsys.Exists(a.files, ".czrc") || fsys.Exists(a.files, ".cz.json") || fsys.Exists(a.files, ".cz.toml")
+	if data, err := fs.ReadFile(a.files, "package.json"); err == nil {
+		var pkg struct {
+			Commitlint      json.RawMessage   `json:"commitlint"`
+			DevDependencies map[string]string `json:"devDependencies"`
+			Config          struct {
+				Commitizen json.RawMessage `json:"commitizen"`
+			} `json:"config"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			_, cli := pkg.DevDependencies["@commitlint/cli"]
+			commitlint = commitlint || pkg.Commitlint != nil || cli
+			commitizen = commitizen || pkg.Config.Commitizen != nil
+		}
+	}
+	if data, err := fs.ReadFile(a.files, "pyproject.toml"); err == nil && strings.Contains(string(data), "[tool.commitizen]") {
+		commitizen = true
+	}
+
+	switch {
+	case commitlint:
+		if hook, err := fs.ReadFile(a.files, ".husky/commit-msg"); err == nil && strings.Contains(string(hook), "commitlint") {
+			return "commitlint in a husky commit-msg hook"
+		}
+		return "commitlint"
+	case commitizen:
+		return "commitizen"
+	}
+	return ""
+}
+
+// commitConvention returns Conventional Commits if most of subjects
+// follow it
+func commitConvention(subjects []string) *CommitConvention {
+	if len(subjects) < minCommits {
+		return nil
+	}
+	types := map[string]int{}
+	matched, scoped := 0, 0
+	for _, s := range subjects {
+		m := conventionalSubject.FindStringSubmatch(s)
+		if m == nil {
+			continue
+		}
+		matched++
+		types[m[1]]++
+		if m[2] != "" {
+			scoped++
+		}
+	}
+	if float64(matched) < conventionalShare*float64(len(subjects)) {
+		return nil
+	}
+	return &CommitConvention{
+		Style:  "Conventional Commits",
+		Types:  mostUsed(types, 1, maxCommitTypes),
+		Scoped: scoped*2 > matched,
+	}
+}
+
+// branchNaming returns the prefix scheme most short-lived branches follow
+func branchNaming(branches []string) *BranchNaming {
+	prefixes := map[string]int{}
+	total, prefixed, tickets := 0, 0, 0
+	ticket := ""
+	for _, b := range branches {
+		if longLivedBranches[b] {
+			continue
+		}
+		total++
+		m := branchPrefix.FindStringSubmatch(b)
+		if m == nil {
+			continue
+		}
+		prefixed++
+		prefixes[m[1]]++
+		if t := ticketKey.FindStringSubmatch(m[2]); t != nil {
+			tickets++
+			if ticket == "" {
+				ticket = strings.ToUpper(t[1])
+			}
+		}
+	}
+	if total < minBranches || float64(prefixed) < prefixedShare*float64(total) {
+		return nil
+	}
+	naming := &BranchNaming{Prefixes: mostUsed(prefixes, minPrefixUses, maxBranchPrefixes)}
+	if len(naming.Prefixes) == 0 {
+		return nil
+	}
+	if float64(tickets) >= ticketBranchShare*float64(prefixed) {
+		naming.Ticket = ticket
+	}
+	return naming
+}
+
+// mostUsed returns the keys of counts used at least minUses times, most
+// used first and at most limit of them
+func mostUsed(counts map[string]int, minUses, limit int) []string {
+	var keys []string
+	for k, n := range counts {
+		if n >= minUses {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys
+}
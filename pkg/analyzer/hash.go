@@ -0,0 +1,25 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// Hash returns a digest of everything the analysis found, so two runs can
+// be compared without keeping the whole result. It doesn't depend on where
+// the project is, the order of the languages or the walk's Totals.
+func (a *Analysis) Hash() string {
+	c := *a
+	c.RootPath = ""
+	c.Totals = Totals{}
+	c.Languages = append([]Language(nil), a.Languages...)
+	sort.Slice(c.Languages, func(i, j int) bool { return c.Languages[i].Name < c.Languages[j].Name })
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"context"
+	"os"
+
+	"github.com/jitin-nhz/contextpilot/pkg/plugin"
+)
+
+// runPlugins lets the plugins configured in config.yaml add to analysis.
+// Plugins read the project from disk, so they only run when Root is a
+// directory there.
+func (a *Analyzer) runPlugins(ctx context.Context, analysis *Analysis) error {
+	if info, err := os.Stat(a.rootPath); err != nil || !info.IsDir() {
+		return nil
+	}
+	plugins, err := plugin.ResolveAll(a.plugins)
+	if err != nil {
+		return err
+	}
+	for _, p := range plugins {
+		resp, err := p.Call(ctx, plugin.HookDetect, a.rootPath, analysis)
+		if err != nil {
+			return err
+		}
+		if resp.Detect == nil {
+			continue
+		}
+		mergeDetection(analysis, resp.Detect)
+		a.log.Info("plugin detection", "plugin", p.Name, "detect", resp.Detect)
+	}
+	return nil
+}
+
+// mergeDetection applies what a plugin detected over the built-in results
+func mergeDetection(analysis *Analysis, d *plugin.Detection) {
+	if d.Framework != nil && d.Framework.Name != "" {
+		frameworks := []Framework{{Name: d.Framework.Name, Version: d.Framework.Version}}
+		for _, f := range analysis.Frameworks {
+			if f.Name != d.Framework.Name || f.Where != "" {
+				frameworks = append(frameworks, f)
+			}
+		}
+		setFrameworks(analysis, frameworks)
+	}
+	set := func(field *string, value string) {
+		if value != "" {
+			*field = value
+		}
+	}
+	set(&analysis.Patterns.TestFramework, d.TestFramework)
+	set(&analysis.Patterns.Linter, d.Linter)
+	set(&analysis.Patterns.Formatter, d.Formatter)
+	if d.ORM != "" {
+		orms := []ORM{{Name: d.ORM}}
+		for _, o := range analysis.Patterns.ORMs {
+			if o.Name != d.ORM || o.Where != "" {
+				orms = append(orms, o)
+			}
+		}
+		setORMs(analysis, orms)
+	}
+	set(&analysis.Patterns.StateManagement, d.StateManagement)
+	set(&analysis.Patterns.Styling, d.Styling)
+	for _, c := range d.Conventions {
+		if c != "" && !contains(analysis.Patterns.Conventions, c) {
+			analysis.Patterns.Conventions = append(analysis.Patterns.Conventions, c)
+		}
+	}
+}
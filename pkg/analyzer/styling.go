@@ -0,0 +1,223 @@
+package analyzer
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// StyleSystem is one way the project styles its UI
+type StyleSystem struct {
+	Name string `json:"name"`
+	// Version is the major version when it changes how styles are
+	// written, e.g. "4" for Tailwind CSS
+	Version string `json:"version,omitempty"`
+	// Evidence is what it was detected from, e.g. tailwind.config.ts or
+	// "12 *.module.* files"
+	Evidence []string `json:"evidence"`
+}
+
+// String is the name with its version, e.g. "Tailwind CSS v4"
+func (s StyleSystem) String() string {
+	if s.Version != "" {
+		return s.Name + " v" + s.Version
+	}
+	return s.Name
+}
+
+// Kinds of stylesheet counted during the walk
+const (
+	styleModule         = "module"      // *.module.css, .scss, .sass or .less
+	styleSassModule     = "module.scss" // *.module.scss and .sass
+	styleSass           = "sass"        // *.scss and *.sass
+	styleLess           = "less"        // *.less
+	styleVanillaExtract = "css.ts"      // *.css.ts and *.css.js
+)
+
+// styleFileKinds returns the kinds of stylesheet name is, if any
+func styleFileKinds(name string) []string {
+	lower := strings.ToLower(path.Base(name))
+	var kinds []string
+	switch path.Ext(lower) {
+	case ".scss", ".sass":
+		kinds = append(kinds, styleSass)
+	case ".less":
+		kinds = append(kinds, styleLess)
+	case ".ts", ".js":
+		if strings.HasSuffix(lower, ".css.ts") || strings.HasSuffix(lower, ".css.js") {
+			kinds = append(kinds, styleVanillaExtract)
+		}
+	}
+	for _, ext := range []string{".module.css", ".module.scss", ".module.sass", ".module.less"} {
+		if strings.HasSuffix(lower, ext) {
+			kinds = append(kinds, styleModule)
+			if ext == ".module.scss" || ext == ".module.sass" {
+				kinds = append(kinds, styleSassModule)
+			}
+		}
+	}
+	return kinds
+}
+
+// tailwindConfigs and postcssConfigs are the config files looked for in
+// the root and each workspace package
+var (
+	tailwindConfigs = []string{"tailwind.config.js", "tailwind.config.cjs", "tailwind.config.mjs", "tailwind.config.ts", "tailwind.config.cts", "tailwind.config.mts"}
+	postcssConfigs  = []string{"postcss.config.js", "postcss.config.cjs", "postcss.config.mjs", "postcss.config.ts", ".postcssrc", ".postcssrc.json", ".postcssrc.js"}
+)
+
+// cssInJS are CSS-in-JS and build-time styling libraries by the package
+// that gives each away, in the order they are reported
+var cssInJS = []struct{ name, pkg string }{
+	{"UnoCSS", "unocss"},
+	{"Panda CSS", "@pandacss/dev"},
+	{"vanilla-extract", "@vanilla-extract/css"},
+	{"Styled Components", "styled-components"},
+	{"Emotion", "@emotion/react"},
+	{"Emotion", "@emotion/styled"},
+	{"Stitches", "@stitches/react"},
+	{"Linaria", "@linaria/core"},
+	{"Linaria", "@linaria/react"},
+	{"Griffel", "@griffel/react"},
+}
+
+// detectStyling finds every styling approach in use, from the
+// dependencies, the Tailwind and PostCSS configs and the stylesheets the
+// walk counted, and names them in Patterns.Styling, the main one first
+func (a *Analyzer) detectStyling(analysis *Analysis) {
+	deps := func(name string) (string, bool) {
+		if v, ok := analysis.Packages.Dependencies[name]; ok {
+			return v, true
+		}
+		v, ok := analysis.Packages.DevDeps[name]
+		return v, ok
+	}
+	dirs := []string{"."}
+	for _, w := range analysis.Packages.Workspace {
+		dirs = append(dirs, w.Path)
+	}
+	var styles []StyleSystem
+
+	if tw := a.detectTailwind(deps, dirs); tw != nil {
+		styles = append(styles, *tw)
+	}
+	for _, lib := range cssInJS {
+		if _, ok := deps(lib.pkg); !ok {
+			continue
+		}
+		if n := len(styles); n > 0 && styles[n-1].Name == lib.name {
+			styles[n-1].Evidence = append(styles[n-1].Evidence, lib.pkg)
+			continue
+		}
+		s := StyleSystem{Name: lib.name, Evidence: []string{lib.pkg}}
+		if lib.name == "vanilla-extract" && a.styleFiles[styleVanillaExtract] > 0 {
+			s.Evidence = append(s.Evidence, countOf(a.styleFiles[styleVanillaExtract], "*.css.ts"))
+		}
+		styles = append(styles, s)
+	}
+	if n := a.styleFiles[styleModule]; n > 0 {
+		files := "*.module.*"
+		switch a.styleFiles[styleSassModule] {
+		case 0:
+			files = "*.module.css"
+		case n:
+			files = "*.module.scss"
+		}
+		styles = append(styles, StyleSystem{Name: "CSS Modules", Evidence: []string{countOf(n, files)}})
+	}
+	for _, pre := range []struct{ name, kind, files string }{
+		{"Sass", styleSass, "*.scss"},
+		{"Less", styleLess, "*.less"},
+	} {
+		var evidence []string
+		if _, ok := deps(strings.ToLower(pre.name)); ok {
+			evidence = append(evidence, strings.ToLower(pre.name))
+		} else if pre.name == "Sass" {
+			if _, ok := deps("node-sass"); ok {
+				evidence = append(evidence, "node-sass")
+			}
+		}
+		if n := a.styleFiles[pre.kind]; n > 0 {
+			evidence = append(evidence, countOf(n, pre.files))
+		}
+		if len(evidence) > 0 {
+			styles = append(styles, StyleSystem{Name: pre.name, Evidence: evidence})
+		}
+	}
+
+	if len(styles) == 0 {
+		return
+	}
+	analysis.Patterns.Styles = styles
+	names := make([]string, len(styles))
+	for i, s := range styles {
+		names[i] = s.String()
+	}
+	analysis.Patterns.Styling = strings.Join(names, ", ")
+}
+
+// detectTailwind finds Tailwind CSS and its major version: 4 when a v4
+// package (@tailwindcss/postcss, /vite or /cli) is installed or the
+// tailwindcss range says so, otherwise the range's major
+func (a *Analyzer) detectTailwind(deps func(string) (string, bool), dirs []string) *StyleSystem {
+	tw := StyleSystem{Name: "Tailwind CSS"}
+	if v, ok := deps("tailwindcss"); ok {
+		tw.Evidence = append(tw.Evidence, "tailwindcss "+v)
+		tw.Version = majorVersion(v)
+	}
+	for _, pkg := range []string{"@tailwindcss/postcss", "@tailwindcss/vite", "@tailwindcss/cli"} {
+		if _, ok := deps(pkg); ok {
+			tw.Evidence = append(tw.Evidence, pkg)
+			tw.Version = "4"
+		}
+	}
+	for _, dir := range dirs {
+		for _, name := range tailwindConfigs {
+			if p := path.Join(dir, name); fsys.Exists(a.files, p) {
+				tw.Evidence = append(tw.Evidence, p)
+			}
+		}
+		for _, name := range postcssConfigs {
+			p := path.Join(dir, name)
+			data, err := fs.ReadFile(a.files, p)
+			if err != nil {
+				continue
+			}
+			switch text := string(data); {
+			case strings.Contains(text, "@tailwindcss/postcss"):
+				tw.Evidence = append(tw.Evidence, p)
+				tw.Version = "4"
+			case strings.Contains(text, "tailwindcss"):
+				tw.Evidence = append(tw.Evidence, p)
+			}
+		}
+	}
+	if len(tw.Evidence) == 0 {
+		return nil
+	}
+	return &tw
+}
+
+// majorVersion returns the major version of a range such as ^4.1.0 or
+// ~3.4, or "" when it doesn't start with one
+func majorVersion(v string) string {
+	v = strings.TrimLeft(strings.TrimSpace(v), "^~>=v ")
+	major, _, _ := strings.Cut(v, ".")
+	for _, c := range major {
+		if c < '0' || c > '9' {
+			return ""
+		}
+	}
+	return major
+}
+
+// countOf describes n files matching pattern
+func countOf(n int, pattern string) string {
+	if n == 1 {
+		return "1 " + pattern + " file"
+	}
+	return fmt.Sprintf("%d %s files", n, pattern)
+}
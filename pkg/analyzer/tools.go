@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"io/fs"
+	"regexp"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// pythonConfigs are the files Python tools are configured in
+var pythonConfigs = []string{
+	"pyproject.toml", "setup.cfg", "tox.ini", "pytest.ini", ".flake8", "mypy.ini",
+	"ruff.toml", ".ruff.toml", "requirements.txt", "requirements-dev.txt", "dev-requirements.txt",
+}
+
+// pythonTools says how each Python tool shows up in pythonConfigs: as a
+// config section such as [tool.ruff] or [flake8], or as a dependency
+var pythonTools = map[string]*regexp.Regexp{
+	"pytest": toolPattern(`tool\.pytest[\w.]*|tool:pytest|pytest`, "pytest"),
+	"tox":    toolPattern(`tool\.tox|tox`, "tox"),
+	"ruff":   toolPattern(`tool\.ruff[\w.]*`, "ruff"),
+	"flake8": toolPattern(`flake8`, "flake8"),
+	"black":  toolPattern(`tool\.black`, "black"),
+	"mypy":   toolPattern(`tool\.mypy[\w.-]*|mypy[\w.-]*`, "mypy"),
+}
+
+// toolPattern matches a [section] header, or the package as a dependency:
+// at the start of a requirements or Poetry line, or quoted in a list
+func toolPattern(sections, pkg string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^\[(` + sections + `)\]|^\s*["']?` + pkg + `\b|[\[,]\s*["']` + pkg + `\b`)
+}
+
+// detectPythonTools finds the test runner, linter, formatter and type
+// checker of a Python project from its config files
+func (a *Analyzer) detectPythonTools(analysis *Analysis) {
+	found := map[string]bool{}
+	configured := false
+	for _, name := range pythonConfigs {
+		data, err := fs.ReadFile(a.files, name)
+		if err != nil {
+			continue
+		}
+		configured = true
+		text := string(data)
+		if name == "ruff.toml" || name == ".ruff.toml" {
+			found["ruff"] = true
+		}
+		if name == "tox.ini" {
+			found["tox"] = true
+		}
+		if name == "pytest.ini" {
+			found["pytest"] = true
+		}
+		if name == "mypy.ini" {
+			found["mypy"] = true
+		}
+		if name == ".flake8" {
+			found["flake8"] = true
+		}
+		for tool, pattern := range pythonTools {
+			if pattern.MatchString(text) {
+				found[tool] = true
+			}
+		}
+	}
+	if !configured {
+		return
+	}
+	if fsys.Exists(a.files, "conftest.py") {
+		found["pytest"] = true
+	}
+
+	p := &analysis.Patterns
+	switch {
+	case p.TestFramework != "":
+	case found["pytest"]:
+		p.TestFramework = "pytest"
+	case hasTests(analysis, "Python"):
+		p.TestFramework = "unittest"
+	}
+	if found["tox"] && p.TestRunner == "" {
+		p.TestRunner = "tox"
+	}
+	switch {
+	case p.Linter != "":
+	case found["ruff"]:
+		p.Linter = "Ruff"
+	case found["flake8"]:
+		p.Linter = "flake8"
+	}
+	switch {
+	case p.Formatter != "":
+	case found["black"]:
+		p.Formatter = "Black"
+	case found["ruff"]:
+		p.Formatter = "Ruff"
+	}
+	if found["mypy"] && p.TypeChecker == "" {
+		p.TypeChecker = "mypy"
+	}
+}
+
+// golangciConfigs are golangci-lint's config files
+var golangciConfigs = []string{".golangci.yml", ".golangci.yaml", ".golangci.toml", ".golangci.json"}
+
+// detectGoTools finds the linter and formatter of a Go module: golangci-lint
+// when it is configured, else go vet, and gofumpt when golangci-lint or
+// go.mod mention it, else gofmt
+func (a *Analyzer) detectGoTools(analysis *Analysis) {
+	p := &analysis.Patterns
+	if p.TestFramework == "" {
+		p.TestFramework = "go test"
+	}
+
+	gofumpt := false
+	if data, err := fs.ReadFile(a.files, "go.mod"); err == nil && strings.Contains(string(data), "mvdan.cc/gofumpt") {
+		gofumpt = true
+	}
+	linter := "go vet"
+	for _, name := range golangciConfigs {
+		if data, err := fs.ReadFile(a.files, name); err == nil {
+			linter = "golangci-lint"
+			if strings.Contains(string(data), "gofumpt") {
+				gofumpt = true
+			}
+			break
+		}
+	}
+	if p.Linter == "" {
+		p.Linter = linter
+	}
+	if p.Formatter == "" {
+		p.Formatter = "gofmt"
+		if gofumpt {
+			p.Formatter = "gofumpt"
+		}
+	}
+}
+
+// hasTests reports whether the language has any test files
+func hasTests(analysis *Analysis, language string) bool {
+	for _, lang := range analysis.Languages {
+		if lang.Name == language && lang.TestFiles > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkspacePackage is a package of the monorepo itself, which other
+// packages depend on like any library
+type WorkspacePackage struct {
+	// Name is its package.json name, e.g. @acme/ui
+	Name string `json:"name"`
+	// Path is its directory, e.g. packages/ui
+	Path string `json:"path"`
+}
+
+// packageJSON is the part of a package.json the analyzer reads
+type packageJSON struct {
+	Name            string            `json:"name"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+	// Workspaces is a list of globs, or {"packages": [...]} in Yarn 1
+	Workspaces json.RawMessage `json:"workspaces"`
+}
+
+// packageDirs hold the packages of a monorepo that doesn't declare its
+// workspaces
+var packageDirs = []string{"apps", "packages", "services", "libs", "modules"}
+
+// workspacePackages returns the paths of the monorepo's packages, sorted:
+// the directories matching the workspaces of package.json or
+// pnpm-workspace.yaml that have a package.json, else those under the
+// usual monorepo folders
+func (a *Analyzer) workspacePackages() []string {
+	globs := a.workspaceGlobs()
+	if len(globs) == 0 {
+		for _, dir := range packageDirs {
+			globs = append(globs, dir+"/*")
+		}
+	}
+
+	seen := map[string]bool{}
+	var pkgs []string
+	for _, g := range globs {
+		if strings.HasPrefix(g, "!") {
+			continue
+		}
+		// fs.Glob has no **, so packages/** means packages/*
+		g = strings.ReplaceAll(path.Clean(strings.TrimPrefix(g, "./")), "**", "*")
+		matches, err := fs.Glob(a.files, path.Join(g, "package.json"))
+		if err != nil {
+			continue
+		}
+		for _, m := range matches {
+			dir := path.Dir(m)
+			if dir != "." && !seen[dir] && !excluded(dir, globs) {
+				seen[dir] = true
+				pkgs = append(pkgs, dir)
+			}
+		}
+	}
+	sort.Strings(pkgs)
+	return pkgs
+}
+
+// excluded reports whether a !pattern in globs excludes dir
+func excluded(dir string, globs []string) bool {
+	for _, g := range globs {
+		if rest, ok := strings.CutPrefix(g, "!"); ok {
+			if match, _ := path.Match(strings.TrimPrefix(rest, "./"), dir); match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// workspaceGlobs returns the workspace patterns the project declares, in
+// package.json or pnpm-workspace.yaml
+func (a *Analyzer) workspaceGlobs() []string {
+	if data, err := fs.ReadFile(a.files, "pnpm-workspace.yaml"); err == nil {
+		var ws struct {
+			Packages []string `yaml:"packages"`
+		}
+		if yaml.Unmarshal(data, &ws) == nil && len(ws.Packages) > 0 {
+			return ws.Packages
+		}
+	}
+	data, err := fs.ReadFile(a.files, "package.json")
+	if err != nil {
+		return nil
+	}
+	var pkg packageJSON
+	if json.Unmarshal(data, &pkg) != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+	var globs []string
+	if json.Unmarshal(pkg.Workspaces, &globs) == nil {
+		return globs
+	}
+	var yarn1 struct {
+		Packages []string `json:"packages"`
+	}
+	json.Unmarshal(pkg.Workspaces, &yarn1)
+	return yarn1.Packages
+}
+
+// mergeDependencies adds the external dependencies of the workspace
+// packages to the project's, keeping the first version seen, and leaves
+// out the workspace packages themselves
+func mergeDependencies(analysis *Analysis, manifests []packageJSON) {
+	pkgs := &analysis.Packages
+	internal := map[string]bool{}
+	for _, w := range pkgs.Workspace {
+		internal[w.Name] = true
+	}
+	merge := func(into *map[string]string, from map[string]string) {
+		for name, version := range from {
+			if internal[name] {
+				continue
+			}
+			if *into == nil {
+				*into = map[string]string{}
+			}
+			if _, ok := (*into)[name]; !ok {
+				(*into)[name] = version
+			}
+		}
+	}
+	for name := range internal {
+		delete(pkgs.Dependencies, name)
+		delete(pkgs.DevDeps, name)
+	}
+	for _, m := range manifests {
+		merge(&pkgs.Dependencies, m.Dependencies)
+		merge(&pkgs.DevDeps, m.DevDependencies)
+	}
+}
+
+// privateScopes returns the npm scopes the project installs from a
+// private registry, e.g. @acme, as configured in .npmrc or .yarnrc.yml
+func (a *Analyzer) privateScopes() []string {
+	var scopes []string
+	add := func(scope string) {
+		scope = strings.TrimSpace(scope)
+		if strings.HasPrefix(scope, "@") && !contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	// .npmrc: @acme:registry=https://npm.acme.dev/
+	if data, err := fs.ReadFile(a.files, ".npmrc"); err == nil {
+		sc := bufio.NewScanner(strings.NewReader(string(data)))
+		for sc.Scan() {
+			key, value, ok := strings.Cut(sc.Text(), "=")
+			scope, isRegistry := strings.CutSuffix(strings.TrimSpace(key), ":registry")
+			if ok && isRegistry && !strings.Contains(value, "registry.npmjs.org") {
+				add(scope)
+			}
+		}
+	}
+
+	// .yarnrc.yml: npmScopes: {acme: {npmRegistryServer: ...}}
+	if fsys.Exists(a.files, ".yarnrc.yml") {
+		data, _ := fs.ReadFile(a.files, ".yarnrc.yml")
+		var rc struct {
+			NpmScopes map[string]interface{} `yaml:"npmScopes"`
+		}
+		if yaml.Unmarshal(data, &rc) == nil {
+			for scope := range rc.NpmScopes {
+				add("@" + strings.TrimPrefix(scope, "@"))
+			}
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
@@ -0,0 +1,161 @@
+// Package contextpilot embeds ContextPilot in other Go programs: analyze a
+// project, generate its context files, and read and write its sessions and
+// decisions, as the CLI does.
+//
+//	p, err := contextpilot.Open(contextpilot.Options{Root: "."})
+//	if err != nil {
+//		return err
+//	}
+//	analysis, err := p.Sync(ctx)
+//
+// The packages it returns (analyzer, generator, session and decisions) are
+// public too, for finer control. Their exported API only grows within a
+// major version.
+package contextpilot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+	"github.com/jitin-nhz/contextpilot/pkg/generator"
+	"github.com/jitin-nhz/contextpilot/pkg/session"
+)
+
+// Options configures a Project. Zero values mean "as the project's
+// .contextpilot/config.yaml says".
+type Options struct {
+	// Root is the project directory (required)
+	Root string
+	// FS, when set, is read and written instead of the directory at Root:
+	// an in-memory fsys.Mem for tests, or fsys.ReadOnly over an archive
+	// from fsys.OpenArchive to analyze it without unpacking. Root then
+	// only names the project and is where git is asked for the branch.
+	FS fsys.WriteFS
+	// Outputs are the context files Sync writes, e.g. "CLAUDE.md"
+	Outputs []string
+	// Ignore lists extra directories analysis skips
+	Ignore []string
+	// Branch pins the branch sessions are saved for instead of the
+	// checked-out one
+	Branch string
+	// OnProgress is told how far analysis has got
+	OnProgress analyzer.ProgressFunc
+	// Logger receives what analysis found and which files were written;
+	// nil means slog.Default()
+	Logger *slog.Logger
+}
+
+// Project is a ContextPilot project
+type Project struct {
+	opts      Options
+	sessions  *session.Manager
+	decisions *decisions.Manager
+}
+
+// Open returns the project at opts.Root, which need not be initialized yet
+func Open(opts Options) (*Project, error) {
+	if opts.Root == "" {
+		return nil, fmt.Errorf("contextpilot: Options.Root is required")
+	}
+	root, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", opts.Root, err)
+	}
+	if opts.FS == nil {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open project: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("failed to open project: %s is not a directory", root)
+		}
+		opts.FS = fsys.OS(root)
+	}
+	opts.Root = root
+
+	return &Project{
+		opts:      opts,
+		sessions:  session.NewWithOptions(session.Options{Root: root, FS: opts.FS, Branch: opts.Branch}),
+		decisions: decisions.NewWithOptions(decisions.Options{Root: root, FS: opts.FS}),
+	}, nil
+}
+
+// Root returns the project's absolute path
+func (p *Project) Root() string {
+	return p.opts.Root
+}
+
+// Analyze inspects the project's code without writing anything
+func (p *Project) Analyze(ctx context.Context) (*analyzer.Analysis, error) {
+	a := analyzer.NewWithOptions(analyzer.Options{
+		Root:       p.opts.Root,
+		FS:         p.opts.FS,
+		Ignore:     p.opts.Ignore,
+		OnProgress: p.opts.OnProgress,
+		Logger:     p.opts.Logger,
+	})
+	return a.AnalyzeContext(ctx)
+}
+
+// Sync analyzes the project and regenerates its context files, like
+// 'contextpilot sync'
+func (p *Project) Sync(ctx context.Context) (*analyzer.Analysis, error) {
+	analysis, err := p.Analyze(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gen := generator.NewWithOptions(analysis, generator.Options{Root: p.opts.Root, FS: p.opts.FS, Outputs: p.opts.Outputs, Logger: p.opts.Logger})
+	if err := gen.GenerateAllContext(ctx); err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+// Sessions returns the project's session manager
+func (p *Project) Sessions() *session.Manager {
+	return p.sessions
+}
+
+// Decisions returns the project's decision manager
+func (p *Project) Decisions() *decisions.Manager {
+	return p.decisions
+}
+
+// SaveSession saves s for the project's branch
+func (p *Project) SaveSession(ctx context.Context, s *session.Session) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return p.sessions.Save(s)
+}
+
+// LoadSession returns the named session of the project's branch ("" for
+// the default one), or nil if there is none
+func (p *Project) LoadSession(ctx context.Context, name string) (*session.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.sessions.LoadNamed(name)
+}
+
+// AddDecision logs d, assigning its ID and date
+func (p *Project) AddDecision(ctx context.Context, d decisions.Decision) (*decisions.Decision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.decisions.AddDecision(d)
+}
+
+// ListDecisions returns every logged decision
+func (p *Project) ListDecisions(ctx context.Context) ([]decisions.Decision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.decisions.List()
+}
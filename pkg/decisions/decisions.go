@@ -0,0 +1,327 @@
+// Package decisions records architectural decisions in the project, as a
+// single decisions.md, as MADR files or in a SQLite database. It is part of
+// ContextPilot's public Go API.
+package decisions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/lock"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// Decision represents an architectural decision
+type Decision struct {
+	ID      int      `json:"id"`
+	Date    string   `json:"date"`
+	Text    string   `json:"text"`
+	Context string   `json:"context,omitempty"`
+	Commit  string   `json:"commit,omitempty"` // abbreviated SHA of the commit the decision shaped
+	Files   []string `json:"files,omitempty"`  // paths or globs the decision affects
+	Status  string   `json:"status,omitempty"` // e.g. accepted or superseded; empty means accepted
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// Active reports whether the decision still applies, i.e. it hasn't been
+// rejected, deprecated or superseded
+func (d Decision) Active() bool {
+	status := strings.ToLower(d.Status)
+	for _, inactive := range []string{"rejected", "deprecated", "superseded"} {
+		if strings.HasPrefix(status, inactive) {
+			return false
+		}
+	}
+	return true
+}
+
+// Links describes the decision's commit and files for context files, e.g.
+// "commit abc1234; files: src/auth/*"
+func (d Decision) Links() string {
+	var parts []string
+	if d.Commit != "" {
+		parts = append(parts, "commit "+d.Commit)
+	}
+	if len(d.Files) > 0 {
+		parts = append(parts, "files: "+strings.Join(d.Files, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// backend persists decisions in a particular on-disk layout
+type backend interface {
+	// list returns all decisions ordered by ID
+	list() ([]Decision, error)
+	// add stores a new decision whose ID has already been assigned
+	add(d *Decision) error
+	// remove deletes the decision with the given ID
+	remove(id int) error
+	// update replaces the text and context of an existing decision
+	update(d Decision) error
+}
+
+// Manager handles decision operations
+type Manager struct {
+	rootPath string
+	files    fsys.WriteFS
+	backend  backend
+}
+
+// Options configures a Manager. Empty fields take their value from the
+// decisions section of .contextpilot/config.yaml.
+type Options struct {
+	// Root is the project directory
+	Root string
+	// FS is used instead of the directory at Root when set
+	FS fsys.WriteFS
+	// Backend is "markdown" (a single decisions.md), "madr" (one file
+	// per decision) or "sqlite" (.contextpilot/contextpilot.db)
+	Backend string
+	// Dir and Template configure the madr backend
+	Dir      string
+	Template string
+}
+
+// New creates a new decision Manager using the backend configured in
+// .contextpilot/config.yaml (markdown unless decisions.backend says otherwise)
+func New(rootPath string) *Manager {
+	return NewWithOptions(Options{Root: rootPath})
+}
+
+// NewWithOptions creates a decision Manager configured by opts
+func NewWithOptions(opts Options) *Manager {
+	files := opts.FS
+	if files == nil {
+		files = fsys.OS(opts.Root)
+	}
+	cfg, _ := config.LoadFS(files)
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	backendName, dir, tmpl := cfg.Decisions.Backend, cfg.Decisions.Dir, cfg.Decisions.Template
+	if opts.Backend != "" {
+		backendName = opts.Backend
+	}
+	if opts.Dir != "" {
+		dir = opts.Dir
+	}
+	if opts.Template != "" {
+		tmpl = opts.Template
+	}
+
+	var b backend
+	switch backendName {
+	case "madr":
+		b = newMADRBackend(files, dir, tmpl)
+	case "sqlite":
+		b = newSQLiteBackend(opts.Root)
+	default:
+		b = newMarkdownBackend(files)
+	}
+
+	return &Manager{
+		rootPath: opts.Root,
+		files:    files,
+		backend:  b,
+	}
+}
+
+// Add adds a new decision
+func (m *Manager) Add(text string, context string) (*Decision, error) {
+	return m.AddDecision(Decision{Text: text, Context: context})
+}
+
+// AddDecision adds d, assigning it the next ID and today's date
+func (m *Manager) AddDecision(d Decision) (*Decision, error) {
+	unlock, err := lock.Acquire(m.files, lock.Decisions)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// Get next ID
+	decisions, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	nextID := 1
+	for _, d := range decisions {
+		if d.ID >= nextID {
+			nextID = d.ID + 1
+		}
+	}
+
+	decision := &d
+	decision.ID = nextID
+	decision.Date = time.Now().Format("2006-01-02")
+
+	if err := m.backend.add(decision); err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+// List returns all decisions ordered by ID
+func (m *Manager) List() ([]Decision, error) {
+	decisions, err := m.backend.list()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return decisions[i].ID < decisions[j].ID
+	})
+	return decisions, nil
+}
+
+// Import merges found into the store and returns the decisions it added.
+// Decisions whose text is already present are skipped, so importing the same
+// source twice is a no-op. Incoming IDs (e.g. ADR numbers) are kept when
+// free; the rest get the next available ID.
+func (m *Manager) Import(found []Decision) ([]Decision, int, error) {
+	unlock, err := lock.Acquire(m.files, lock.Decisions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer unlock()
+
+	existing, err := m.List()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	seen := map[string]bool{}
+	used := map[int]bool{}
+	nextID := 1
+	for _, d := range existing {
+		seen[normalizeText(d.Text)] = true
+		used[d.ID] = true
+		if d.ID >= nextID {
+			nextID = d.ID + 1
+		}
+	}
+	for _, d := range found {
+		if d.ID >= nextID && !seen[normalizeText(d.Text)] {
+			nextID = d.ID + 1
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].ID < found[j].ID
+	})
+
+	var added []Decision
+	skipped := 0
+	today := time.Now().Format("2006-01-02")
+	for _, d := range found {
+		key := normalizeText(d.Text)
+		if key == "" || seen[key] {
+			skipped++
+			continue
+		}
+		if d.ID <= 0 || used[d.ID] {
+			d.ID = nextID
+			nextID++
+		}
+		if d.Date == "" {
+			d.Date = today
+		}
+		if err := m.backend.add(&d); err != nil {
+			return added, skipped, err
+		}
+		seen[key] = true
+		used[d.ID] = true
+		added = append(added, d)
+	}
+	return added, skipped, nil
+}
+
+// Delete removes a decision by ID
+func (m *Manager) Delete(id int) error {
+	unlock, err := lock.Acquire(m.files, lock.Decisions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return m.backend.remove(id)
+}
+
+// Get returns the decision with the given ID
+func (m *Manager) Get(id int) (*Decision, error) {
+	decisions, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range decisions {
+		if d.ID == id {
+			return &d, nil
+		}
+	}
+	return nil, fmt.Errorf("decision #%d not found", id)
+}
+
+// Update replaces the text and context of a decision, keeping its ID and date
+func (m *Manager) Update(d Decision) error {
+	if strings.TrimSpace(d.Text) == "" {
+		return fmt.Errorf("decision text cannot be empty")
+	}
+	unlock, err := lock.Acquire(m.files, lock.Decisions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := m.Get(d.ID); err != nil {
+		return err
+	}
+	return m.backend.update(d)
+}
+
+// GetForContext returns decisions formatted for inclusion in context files
+func (m *Manager) GetForContext() string {
+	decisions, err := m.List()
+	if err != nil || len(decisions) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, d := range decisions {
+		if !d.Active() {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("- **%s:** %s", d.Date, d.Text))
+		if links := d.Links(); links != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", links))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// normalizeText reduces text to a comparison key for duplicate detection
+func normalizeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// ensureDir creates dir in files if it doesn't exist
+func ensureDir(files fsys.WriteFS, dir string) error {
+	if err := files.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	return nil
+}
+
+// summarize truncates text to maxLen with ellipsis
+func summarize(text string, maxLen int) string {
+	// Get first line only
+	if idx := strings.Index(text, "\n"); idx != -1 {
+		text = text[:idx]
+	}
+
+	if len(text) <= maxLen {
+		return text
+	}
+	return text[:maxLen-3] + "..."
+}
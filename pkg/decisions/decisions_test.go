@@ -0,0 +1,65 @@
+package decisions
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+func TestBackendsMem(t *testing.T) {
+	tests := []struct {
+		backend string
+		file    string // where the first decision is written
+	}{
+		{"markdown", ".contextpilot/decisions.md"},
+		{"madr", "docs/adr/0001-use-postgres-for-orders.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			files := fsys.NewMem(nil)
+			m := NewWithOptions(Options{Root: "shop", FS: files, Backend: tt.backend})
+
+			if _, err := m.Add("Use Postgres for orders", "Need transactions"); err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+			d, err := m.AddDecision(Decision{Text: "Log in JSON", Files: []string{"internal/log/**"}})
+			if err != nil {
+				t.Fatalf("AddDecision: %v", err)
+			}
+			if d.ID != 2 {
+				t.Errorf("second decision got ID %d, want 2", d.ID)
+			}
+			if _, err := fs.ReadFile(files, tt.file); err != nil {
+				t.Errorf("%s not written; files are %v", tt.file, files.Files())
+			}
+
+			d.Text = "Log in JSON lines"
+			if err := m.Update(*d); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			if err := m.Delete(1); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+
+			decisions, err := m.List()
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(decisions) != 1 || decisions[0].ID != 2 || decisions[0].Text != "Log in JSON lines" {
+				t.Fatalf("List = %+v, want only #2, updated", decisions)
+			}
+			if got := decisions[0].Files; len(got) != 1 || got[0] != "internal/log/**" {
+				t.Errorf("Files = %v, want [internal/log/**]", got)
+			}
+			if !strings.Contains(m.GetForContext(), "Log in JSON lines") {
+				t.Errorf("GetForContext = %q, want the updated decision", m.GetForContext())
+			}
+
+			if err := m.Delete(1); err == nil {
+				t.Error("deleting a missing decision succeeded")
+			}
+		})
+	}
+}
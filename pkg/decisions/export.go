@@ -0,0 +1,278 @@
+package decisions
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/api"
+)
+
+// ExportFormats lists the formats supported by Export
+var ExportFormats = []string{"html", "docx", "json", "csv"}
+
+// untagged heads the group of decisions without tags
+const untagged = "Untagged"
+
+// StatusGroup holds the decisions sharing a status, split by tag
+type StatusGroup struct {
+	Status string
+	Tags   []TagGroup
+}
+
+// TagGroup holds the decisions sharing a tag. A decision with several tags
+// appears in each of their groups.
+type TagGroup struct {
+	Tag       string
+	Decisions []Decision
+}
+
+// Group arranges decisions by status (active statuses first, accepted
+// leading) and then by tag, with untagged decisions last
+func Group(decs []Decision) []StatusGroup {
+	byStatus := map[string][]Decision{}
+	for _, d := range decs {
+		status := strings.ToLower(strings.TrimSpace(d.Status))
+		if status == "" {
+			status = "accepted"
+		}
+		byStatus[status] = append(byStatus[status], d)
+	}
+
+	statuses := make([]string, 0, len(byStatus))
+	for s := range byStatus {
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		a, b := statuses[i], statuses[j]
+		activeA, activeB := (Decision{Status: a}).Active(), (Decision{Status: b}).Active()
+		if activeA != activeB {
+			return activeA
+		}
+		if (a == "accepted") != (b == "accepted") {
+			return a == "accepted"
+		}
+		return a < b
+	})
+
+	groups := make([]StatusGroup, 0, len(statuses))
+	for _, s := range statuses {
+		byTag := map[string][]Decision{}
+		for _, d := range byStatus[s] {
+			if len(d.Tags) == 0 {
+				byTag[untagged] = append(byTag[untagged], d)
+			}
+			for _, t := range d.Tags {
+				byTag[t] = append(byTag[t], d)
+			}
+		}
+		tags := make([]string, 0, len(byTag))
+		for t := range byTag {
+			tags = append(tags, t)
+		}
+		sort.Slice(tags, func(i, j int) bool {
+			if (tags[i] == untagged) != (tags[j] == untagged) {
+				return tags[j] == untagged
+			}
+			return tags[i] < tags[j]
+		})
+
+		group := StatusGroup{Status: s}
+		for _, t := range tags {
+			group.Tags = append(group.Tags, TagGroup{Tag: t, Decisions: byTag[t]})
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// Export renders decs as a standalone document in the given format
+func Export(decs []Decision, format, title string) ([]byte, error) {
+	switch format {
+	case "html":
+		return exportHTML(decs, title)
+	case "docx":
+		return exportDOCX(decs, title)
+	case "json":
+		return exportJSON(decs)
+	case "csv":
+		return exportCSV(decs)
+	}
+	return nil, fmt.Errorf("unknown format %q (use %s)", format, strings.Join(ExportFormats, ", "))
+}
+
+func exportJSON(decs []Decision) ([]byte, error) {
+	if decs == nil {
+		decs = []Decision{}
+	}
+	out := struct {
+		APIVersion int        `json:"apiVersion"`
+		Decisions  []Decision `json:"decisions"`
+	}{api.Selected(), decs}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return nil, fmt.Errorf("failed to encode decisions: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func exportCSV(decs []Decision) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"id", "date", "status", "tags", "decision", "context", "commit", "files"})
+	for _, d := range decs {
+		w.Write([]string{
+			fmt.Sprint(d.ID), d.Date, d.Status, strings.Join(d.Tags, ", "),
+			d.Text, d.Context, d.Commit, strings.Join(d.Files, ", "),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 50rem; margin: 2rem auto; padding: 0 1rem; color: #1f2328; line-height: 1.5; }
+h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .3rem; text-transform: capitalize; }
+article { margin: 1rem 0; padding: .75rem 1rem; border-left: 3px solid #0969da; background: #f6f8fa; }
+.meta { color: #59636e; font-size: .875rem; }
+.text { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Count}} decision(s) · exported {{.Date}}</p>
+{{- range .Groups}}
+<h2>{{.Status}}</h2>
+{{- range .Tags}}
+<h3>{{.Tag}}</h3>
+{{- range .Decisions}}
+<article>
+<p class="meta">#{{.ID}} · {{.Date}}{{with .Links}} · {{.}}{{end}}</p>
+<p class="text">{{.Text}}</p>
+{{- if .Context}}
+<p><strong>Context:</strong> {{.Context}}</p>
+{{- end}}
+</article>
+{{- end}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+`
+
+func exportHTML(decs []Decision, title string) ([]byte, error) {
+	tmpl, err := template.New("export").Parse(htmlTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		Title  string
+		Count  int
+		Date   string
+		Groups []StatusGroup
+	}{title, len(decs), time.Now().Format("2006-01-02"), Group(decs)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// docx parts that don't depend on the content
+const (
+	docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/></Types>`
+	docxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/></Relationships>`
+)
+
+// exportDOCX writes a minimal WordprocessingML package. Headings use direct
+// formatting rather than styles so the file needs no styles part.
+func exportDOCX(decs []Decision, title string) ([]byte, error) {
+	var body strings.Builder
+	para := func(text string, size int, bold bool, color string) {
+		props := fmt.Sprintf(`<w:sz w:val="%d"/>`, size)
+		if bold {
+			props = "<w:b/>" + props
+		}
+		if color != "" {
+			props += fmt.Sprintf(`<w:color w:val="%s"/>`, color)
+		}
+		body.WriteString("<w:p>")
+		for i, line := range strings.Split(text, "\n") {
+			if i > 0 {
+				body.WriteString(fmt.Sprintf(`<w:r><w:rPr>%s</w:rPr><w:br/></w:r>`, props))
+			}
+			body.WriteString(fmt.Sprintf(`<w:r><w:rPr>%s</w:rPr><w:t xml:space="preserve">%s</w:t></w:r>`, props, docxEscape(line)))
+		}
+		body.WriteString("</w:p>")
+	}
+
+	para(title, 40, true, "")
+	para(fmt.Sprintf("%d decision(s) · exported %s", len(decs), time.Now().Format("2006-01-02")), 18, false, "59636E")
+	for _, g := range Group(decs) {
+		para(strings.ToUpper(g.Status[:1])+g.Status[1:], 32, true, "")
+		for _, t := range g.Tags {
+			para(t.Tag, 26, true, "")
+			for _, d := range t.Decisions {
+				meta := fmt.Sprintf("#%d · %s", d.ID, d.Date)
+				if links := d.Links(); links != "" {
+					meta += " · " + links
+				}
+				para(meta, 18, false, "59636E")
+				para(d.Text, 22, false, "")
+				if d.Context != "" {
+					para("Context: "+d.Context, 20, false, "")
+				}
+			}
+		}
+	}
+
+	document := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		body.String() + `</w:body></w:document>`
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRels},
+		{"word/document.xml", document},
+	} {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write docx: %w", err)
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to write docx: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write docx: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var docxEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func docxEscape(s string) string {
+	return docxEscaper.Replace(s)
+}
@@ -0,0 +1,220 @@
+package decisions
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+var (
+	logHeadingPattern = regexp.MustCompile(`(?m)^## `)
+	isoDatePattern    = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+	// titleNumberPattern matches numbering such as "ADR-0003:", "ADR 7 -" or "12."
+	titleNumberPattern = regexp.MustCompile(`(?i)^(?:adr[-\s]?)?(\d+)\s*[:.)\-–—]\s*`)
+)
+
+// Parse reads decisions from an existing source: a directory of ADRs or
+// markdown pages (docs/adr, a Notion export), a ContextPilot decisions.json
+// or decisions.md,
+// a DECISIONS.md style log with one "## " heading per decision, or a single
+// ADR. IDs are set from ADR numbers where present and are 0 otherwise.
+func Parse(path string) ([]Decision, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return parseDir(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	content := string(data)
+
+	switch {
+	case filepath.Ext(path) == ".json":
+		return parseIndex(data)
+	case strings.Contains(content, "## [") && strings.Contains(content, "**Date:**"):
+		return parseMarkdownFile(fsys.OS(filepath.Dir(path)), filepath.Base(path))
+	case isDecisionLog(content):
+		return parseLog(content), nil
+	}
+	d := parsePage(content)
+	d.ID = numberFromName(filepath.Base(path))
+	return []Decision{d}, nil
+}
+
+// parseDir reads every markdown page below dir as one decision, skipping
+// READMEs, indexes and templates
+func parseDir(dir string) ([]Decision, error) {
+	var found []Decision
+	err := filepath.WalkDir(dir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := strings.ToLower(e.Name())
+		if e.IsDir() || filepath.Ext(name) != ".md" ||
+			name == "readme.md" || name == "index.md" || strings.Contains(name, "template") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		d := parsePage(string(data))
+		if n := numberFromName(e.Name()); n > 0 {
+			d.ID = n
+		}
+		found = append(found, d)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	return found, nil
+}
+
+// parsePage reads a single-decision page. ADR sections are used when
+// present; otherwise the page body becomes the context.
+func parsePage(content string) Decision {
+	d := parseADR(content)
+	title := pageTitle(content)
+	if d.ID == 0 {
+		d.ID = numberFromTitle(title)
+	}
+	if d.Text == title {
+		d.Text = cleanTitle(title)
+		if d.Context == "" {
+			d.Context = pageBody(content)
+		}
+	}
+	return d
+}
+
+// isDecisionLog reports whether content holds several decisions under "## "
+// headings rather than a single ADR's sections
+func isDecisionLog(content string) bool {
+	headings := 0
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "## ") {
+			continue
+		}
+		if adrSectionKind(trimmed) != "" {
+			return false
+		}
+		headings++
+	}
+	return headings > 0
+}
+
+// parseLog splits a DECISIONS.md style log into one decision per "## "
+// heading. Dates and numbers in the heading are pulled out of the title;
+// "Date:" and "Status:" lines in the body are honoured.
+func parseLog(content string) []Decision {
+	var found []Decision
+	locs := logHeadingPattern.FindAllStringIndex(content, -1)
+	for i, loc := range locs {
+		end := len(content)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		block := content[loc[1]:end]
+		heading, body, _ := strings.Cut(block, "\n")
+
+		var d Decision
+		heading = strings.TrimSpace(heading)
+		if date := isoDatePattern.FindString(heading); date != "" {
+			d.Date = date
+			heading = strings.Trim(strings.Replace(heading, date, "", 1), " :-–—|()[]")
+		}
+		d.ID = numberFromTitle(heading)
+		d.Text = cleanTitle(heading)
+
+		var rest []string
+		for _, line := range strings.Split(body, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if m := adrDatePattern.FindStringSubmatch(trimmed); m != nil && d.Date == "" {
+				d.Date = strings.TrimSpace(m[1])
+				continue
+			}
+			if m := adrStatusPattern.FindStringSubmatch(trimmed); m != nil && d.Status == "" {
+				d.Status = strings.TrimSpace(m[1])
+				continue
+			}
+			if trimmed != "" && trimmed != "---" {
+				rest = append(rest, trimmed)
+			}
+		}
+		d.Context = strings.Join(rest, " ")
+
+		if d.Text != "" {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+// pageTitle returns the first "# " heading of content
+func pageTitle(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "# ") {
+			return strings.TrimSpace(strings.TrimPrefix(trimmed, "# "))
+		}
+	}
+	return ""
+}
+
+// pageBody returns the prose of a page without front matter, headings or
+// property lines, joined into one line
+func pageBody(content string) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				lines = lines[i+1:]
+				break
+			}
+		}
+	}
+
+	var body []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") ||
+			adrDatePattern.MatchString(trimmed) || adrStatusPattern.MatchString(trimmed) {
+			continue
+		}
+		body = append(body, trimmed)
+	}
+	return strings.Join(body, " ")
+}
+
+// cleanTitle strips ADR numbering from a title
+func cleanTitle(title string) string {
+	return strings.TrimSpace(titleNumberPattern.ReplaceAllString(title, ""))
+}
+
+func numberFromTitle(title string) int {
+	if m := titleNumberPattern.FindStringSubmatch(title); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	return 0
+}
+
+func numberFromName(name string) int {
+	if m := adrFilePattern.FindStringSubmatch(name); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,352 @@
+package decisions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// DefaultADRDir is where the madr backend keeps ADR files by default
+const DefaultADRDir = "docs/adr"
+
+// defaultADRTemplate renders a new ADR in MADR 3 layout
+const defaultADRTemplate = `---
+status: {{.Status}}
+date: {{.Date}}
+{{- if .Commit}}
+commit: {{.Commit}}
+{{- end}}
+{{- if .Files}}
+files: {{.Files}}
+{{- end}}
+{{- if .Tags}}
+tags: {{.Tags}}
+{{- end}}
+---
+# {{.Title}}
+
+## Context and Problem Statement
+
+{{if .Context}}{{.Context}}{{else}}TBD{{end}}
+
+## Decision Outcome
+
+{{.Text}}
+`
+
+// adrData is the data available to ADR templates
+type adrData struct {
+	ID      int
+	Number  string // zero-padded ID, e.g. 0007
+	Title   string
+	Date    string
+	Status  string
+	Text    string
+	Context string
+	Commit  string
+	Files   string // comma-separated
+	Tags    string // comma-separated
+}
+
+var (
+	adrFilePattern   = regexp.MustCompile(`^(\d+)-.*\.md$`)
+	adrDatePattern   = regexp.MustCompile(`(?i)^(?:[*-]\s*)?date:\s*(.+)$`)
+	adrCommitPattern = regexp.MustCompile(`(?i)^(?:[*-]\s*)?commit:\s*(.+)$`)
+	adrFilesPattern  = regexp.MustCompile(`(?i)^(?:[*-]\s*)?files:\s*(.+)$`)
+	adrStatusPattern = regexp.MustCompile(`(?i)^(?:[*-]\s*)?status:\s*(.+)$`)
+	adrTagsPattern   = regexp.MustCompile(`(?i)^(?:[*-]\s*)?tags:\s*(.+)$`)
+	slugPattern      = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// madrBackend stores one MADR file per decision, e.g. docs/adr/0007-use-redis.md
+type madrBackend struct {
+	files fsys.WriteFS
+	dir   string
+
+	templateFS   fs.FS
+	templatePath string
+}
+
+// newMADRBackend keeps ADRs in dir within files. An absolute dir or
+// template path is read from disk instead.
+func newMADRBackend(files fsys.WriteFS, dir, templatePath string) *madrBackend {
+	if dir == "" {
+		dir = DefaultADRDir
+	}
+	b := &madrBackend{files: files, dir: path.Clean(filepath.ToSlash(dir)), templateFS: files, templatePath: filepath.ToSlash(templatePath)}
+	if filepath.IsAbs(dir) {
+		b.files, b.dir = fsys.OS(dir), "."
+	}
+	if filepath.IsAbs(templatePath) {
+		b.templateFS, b.templatePath = fsys.OS(filepath.Dir(templatePath)), filepath.Base(templatePath)
+	}
+	return b
+}
+
+func (b *madrBackend) list() ([]Decision, error) {
+	entries, err := fs.ReadDir(b.files, b.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []Decision{}, nil
+		}
+		return nil, fmt.Errorf("failed to read ADR directory: %w", err)
+	}
+
+	decisions := []Decision{}
+	for _, e := range entries {
+		m := adrFilePattern.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		data, err := fs.ReadFile(b.files, path.Join(b.dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", e.Name(), err)
+		}
+		d := parseADR(string(data))
+		d.ID, _ = strconv.Atoi(m[1])
+		decisions = append(decisions, d)
+	}
+
+	sort.Slice(decisions, func(i, j int) bool {
+		return decisions[i].ID < decisions[j].ID
+	})
+	return decisions, nil
+}
+
+func (b *madrBackend) add(d *Decision) error {
+	if err := ensureDir(b.files, b.dir); err != nil {
+		return err
+	}
+
+	tmplText := defaultADRTemplate
+	if b.templatePath != "" {
+		data, err := fs.ReadFile(b.templateFS, b.templatePath)
+		if err != nil {
+			return fmt.Errorf("failed to read ADR template: %w", err)
+		}
+		tmplText = string(data)
+	}
+	tmpl, err := template.New("adr").Parse(tmplText)
+	if err != nil {
+		return fmt.Errorf("failed to parse ADR template: %w", err)
+	}
+
+	status := d.Status
+	if status == "" {
+		status = "accepted"
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, adrData{
+		ID:      d.ID,
+		Number:  fmt.Sprintf("%04d", d.ID),
+		Title:   summarize(d.Text, 60),
+		Date:    d.Date,
+		Status:  status,
+		Text:    d.Text,
+		Context: d.Context,
+		Commit:  d.Commit,
+		Files:   strings.Join(d.Files, ", "),
+		Tags:    strings.Join(d.Tags, ", "),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render ADR: %w", err)
+	}
+
+	name := fmt.Sprintf("%04d-%s.md", d.ID, slugify(summarize(d.Text, 60)))
+	if err := b.files.WriteFile(path.Join(b.dir, name), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write decision: %w", err)
+	}
+	return nil
+}
+
+func (b *madrBackend) remove(id int) error {
+	name, err := b.pathFor(id)
+	if err != nil {
+		return err
+	}
+	return b.files.Remove(name)
+}
+
+func (b *madrBackend) update(d Decision) error {
+	name, err := b.pathFor(d.ID)
+	if err != nil {
+		return err
+	}
+	data, err := fs.ReadFile(b.files, name)
+	if err != nil {
+		return fmt.Errorf("failed to read decision: %w", err)
+	}
+
+	content := replaceADRSection(string(data), "decision", "Decision Outcome", d.Text)
+	content = replaceADRSection(content, "context", "Context and Problem Statement", d.Context)
+	if err := b.files.WriteFile(name, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write decision: %w", err)
+	}
+	return nil
+}
+
+// pathFor returns the ADR file holding decision id
+func (b *madrBackend) pathFor(id int) (string, error) {
+	entries, err := fs.ReadDir(b.files, b.dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to read ADR directory: %w", err)
+	}
+	for _, e := range entries {
+		m := adrFilePattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		if n, _ := strconv.Atoi(m[1]); n == id {
+			return path.Join(b.dir, e.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("decision #%d not found", id)
+}
+
+// parseADR extracts a decision from an ADR in MADR 2 or 3 layout. The
+// title is the first "# " heading; the date comes from front matter or a
+// "* Date:" line, as do the status, tags and the optional commit and files
+// links; context and decision text come from the "Context..." and "Decision
+// Outcome" (or "Decision") sections, falling back to the title. A Nygard
+// style "## Status" section also sets the status.
+func parseADR(content string) Decision {
+	var d Decision
+	var title string
+	section := ""
+	sections := map[string][]string{}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case title == "" && strings.HasPrefix(trimmed, "# "):
+			title = strings.TrimSpace(strings.TrimPrefix(trimmed, "# "))
+			continue
+		case strings.HasPrefix(trimmed, "## "):
+			section = adrSectionKind(trimmed)
+			continue
+		case strings.HasPrefix(trimmed, "### "):
+			// Subsections such as Consequences aren't part of the outcome
+			section = ""
+			continue
+		}
+
+		if d.Date == "" {
+			if m := adrDatePattern.FindStringSubmatch(trimmed); m != nil {
+				d.Date = strings.Trim(strings.TrimSpace(m[1]), `"'`)
+				continue
+			}
+		}
+		if d.Status == "" {
+			if m := adrStatusPattern.FindStringSubmatch(trimmed); m != nil {
+				d.Status = strings.Trim(strings.TrimSpace(m[1]), `"'`)
+				continue
+			}
+		}
+		if d.Commit == "" {
+			if m := adrCommitPattern.FindStringSubmatch(trimmed); m != nil {
+				d.Commit = strings.TrimSpace(m[1])
+				continue
+			}
+		}
+		if d.Files == nil {
+			if m := adrFilesPattern.FindStringSubmatch(trimmed); m != nil {
+				d.Files = splitFiles(m[1])
+				continue
+			}
+		}
+		if d.Tags == nil {
+			if m := adrTagsPattern.FindStringSubmatch(trimmed); m != nil {
+				d.Tags = splitFiles(strings.Trim(m[1], "[]"))
+				continue
+			}
+		}
+		if section != "" {
+			sections[section] = append(sections[section], line)
+		}
+	}
+
+	if d.Status == "" {
+		d.Status = strings.TrimSpace(strings.Join(sections["status"], " "))
+	}
+	d.Context = strings.TrimSpace(strings.Join(sections["context"], "\n"))
+	d.Text = strings.TrimSpace(strings.Join(sections["decision"], "\n"))
+	if d.Text == "" {
+		d.Text = title
+	}
+	return d
+}
+
+// replaceADRSection replaces the body of the section parseADR maps to kind
+// ("context" or "decision") with body, leaving subsections and every other
+// part of the file untouched. A missing section is appended as heading.
+func replaceADRSection(content, kind, heading, body string) string {
+	lines := strings.Split(content, "\n")
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if start == -1 {
+			if strings.HasPrefix(trimmed, "## ") && adrSectionKind(trimmed) == kind {
+				start = i
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "## ") || strings.HasPrefix(trimmed, "### ") {
+			end = i
+			break
+		}
+	}
+
+	if start == -1 {
+		if body == "" {
+			return content
+		}
+		return strings.TrimRight(content, "\n") + "\n\n## " + heading + "\n\n" + body + "\n"
+	}
+
+	replacement := []string{lines[start], ""}
+	if body != "" {
+		replacement = append(replacement, body, "")
+	}
+	out := append([]string{}, lines[:start]...)
+	out = append(out, replacement...)
+	out = append(out, lines[end:]...)
+	return strings.Join(out, "\n")
+}
+
+// adrSectionKind classifies a "## " heading line as "context", "decision",
+// "status" or ""
+func adrSectionKind(line string) string {
+	heading := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "## ")))
+	switch {
+	case strings.HasPrefix(heading, "context"):
+		return "context"
+	case strings.HasPrefix(heading, "decision outcome"), heading == "decision":
+		return "decision"
+	case heading == "status":
+		return "status"
+	}
+	return ""
+}
+
+// slugify turns text into a lowercase, dash-separated file name fragment
+func slugify(text string) string {
+	slug := strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(text), "-"), "-")
+	if len(slug) > 50 {
+		slug = strings.TrimRight(slug[:50], "-")
+	}
+	if slug == "" {
+		slug = "decision"
+	}
+	return slug
+}
@@ -0,0 +1,294 @@
+package decisions
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/lock"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+const markdownHeader = `# Architectural Decisions
+# Managed by ContextPilot — https://contextpilot.dev
+# Add decisions with: contextpilot decision "Your decision here"
+# Rendered from decisions.json; edit with 'contextpilot decision edit <id>'
+
+`
+
+// indexVersion is the schema version written to decisions.json
+const indexVersion = 1
+
+// decisionIndex is the on-disk layout of decisions.json
+type decisionIndex struct {
+	Version   int        `json:"version"`
+	Decisions []Decision `json:"decisions"`
+}
+
+// markdownBackend stores decisions in .contextpilot/decisions.json and
+// renders .contextpilot/decisions.md from it after every change. Projects
+// that only have a decisions.md from older versions are read from the
+// markdown until the first write creates the index.
+type markdownBackend struct {
+	files     fsys.WriteFS
+	filePath  string
+	indexPath string
+}
+
+func newMarkdownBackend(files fsys.WriteFS) *markdownBackend {
+	return &markdownBackend{
+		files:     files,
+		filePath:  ".contextpilot/decisions.md",
+		indexPath: ".contextpilot/decisions.json",
+	}
+}
+
+func (b *markdownBackend) list() ([]Decision, error) {
+	data, err := fs.ReadFile(b.files, b.indexPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return parseMarkdownFile(b.files, b.filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decisions: %w", err)
+	}
+	return parseIndex(data)
+}
+
+func (b *markdownBackend) add(decision *Decision) error {
+	decisions, err := b.list()
+	if err != nil {
+		return err
+	}
+	return b.save(append(decisions, *decision))
+}
+
+func (b *markdownBackend) remove(id int) error {
+	decisions, err := b.list()
+	if err != nil {
+		return err
+	}
+
+	// Filter out the decision
+	var remaining []Decision
+	found := false
+	for _, d := range decisions {
+		if d.ID != id {
+			remaining = append(remaining, d)
+		} else {
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("decision #%d not found", id)
+	}
+
+	return b.save(remaining)
+}
+
+func (b *markdownBackend) update(d Decision) error {
+	decisions, err := b.list()
+	if err != nil {
+		return err
+	}
+	for i := range decisions {
+		if decisions[i].ID == d.ID {
+			decisions[i].Text = d.Text
+			decisions[i].Context = d.Context
+		}
+	}
+	return b.save(decisions)
+}
+
+// save writes the index and re-renders the markdown
+func (b *markdownBackend) save(decisions []Decision) error {
+	if err := ensureDir(b.files, path.Dir(b.indexPath)); err != nil {
+		return err
+	}
+	if decisions == nil {
+		decisions = []Decision{}
+	}
+
+	data, err := json.MarshalIndent(decisionIndex{Version: indexVersion, Decisions: decisions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode decisions: %w", err)
+	}
+	if err := fsys.WriteFileAtomic(b.files, b.indexPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write decisions: %w", err)
+	}
+	if err := fsys.WriteFileAtomic(b.files, b.filePath, []byte(renderMarkdown(decisions)), 0644); err != nil {
+		return fmt.Errorf("failed to render decisions.md: %w", err)
+	}
+	return nil
+}
+
+// HasLegacyMarkdown reports whether the project only has a decisions.md
+// written before decisions.json existed
+func HasLegacyMarkdown(rootPath string) bool {
+	b := newMarkdownBackend(fsys.OS(rootPath))
+	return !fsys.Exists(b.files, b.indexPath) && fsys.Exists(b.files, b.filePath)
+}
+
+// MigrateMarkdown builds decisions.json from a legacy decisions.md and
+// re-renders the markdown from it
+func MigrateMarkdown(rootPath string) error {
+	b := newMarkdownBackend(fsys.OS(rootPath))
+	unlock, err := lock.Acquire(b.files, lock.Decisions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	decisions, err := parseMarkdownFile(b.files, b.filePath)
+	if err != nil {
+		return err
+	}
+	return b.save(decisions)
+}
+
+func parseIndex(data []byte) ([]Decision, error) {
+	var index decisionIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse decisions.json: %w", err)
+	}
+	if index.Decisions == nil {
+		return []Decision{}, nil
+	}
+	return index.Decisions, nil
+}
+
+func renderMarkdown(decisions []Decision) string {
+	var sb strings.Builder
+	sb.WriteString(markdownHeader)
+	for _, d := range decisions {
+		sb.WriteString(markdownEntry(d))
+	}
+	return sb.String()
+}
+
+func markdownEntry(d Decision) string {
+	entry := fmt.Sprintf("## [%d] %s\n**Date:** %s\n", d.ID, summarize(d.Text, 60), d.Date)
+	if d.Status != "" {
+		entry += fmt.Sprintf("**Status:** %s\n", d.Status)
+	}
+	if d.Commit != "" {
+		entry += fmt.Sprintf("**Commit:** %s\n", d.Commit)
+	}
+	if len(d.Files) > 0 {
+		entry += fmt.Sprintf("**Files:** %s\n", strings.Join(d.Files, ", "))
+	}
+	if len(d.Tags) > 0 {
+		entry += fmt.Sprintf("**Tags:** %s\n", strings.Join(d.Tags, ", "))
+	}
+	entry += fmt.Sprintf("\n%s\n", d.Text)
+	if d.Context != "" {
+		entry += fmt.Sprintf("\n**Context:** %s\n", d.Context)
+	}
+	return entry + "\n---\n\n"
+}
+
+// parseMarkdownFile reads a decisions.md written by ContextPilot before
+// decisions.json existed. A missing file yields no decisions.
+func parseMarkdownFile(files fs.FS, name string) ([]Decision, error) {
+	f, err := files.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Decision{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	var decisions []Decision
+	var current *Decision
+	var textLines []string
+
+	scanner := bufio.NewScanner(f)
+	idPattern := regexp.MustCompile(`^## \[(\d+)\]`)
+	datePattern := regexp.MustCompile(`^\*\*Date:\*\* (.+)$`)
+	commitPattern := regexp.MustCompile(`^\*\*Commit:\*\* (.+)$`)
+	filesPattern := regexp.MustCompile(`^\*\*Files:\*\* (.+)$`)
+	statusPattern := regexp.MustCompile(`^\*\*Status:\*\* (.+)$`)
+	tagsPattern := regexp.MustCompile(`^\*\*Tags:\*\* (.+)$`)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Check for new decision header
+		if matches := idPattern.FindStringSubmatch(line); matches != nil {
+			// Save previous decision
+			if current != nil {
+				current.Text = strings.TrimSpace(strings.Join(textLines, "\n"))
+				decisions = append(decisions, *current)
+			}
+
+			id, _ := strconv.Atoi(matches[1])
+			current = &Decision{ID: id}
+			textLines = []string{}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		// Parse date
+		if matches := datePattern.FindStringSubmatch(line); matches != nil {
+			current.Date = matches[1]
+			continue
+		}
+		if matches := commitPattern.FindStringSubmatch(line); matches != nil {
+			current.Commit = matches[1]
+			continue
+		}
+		if matches := filesPattern.FindStringSubmatch(line); matches != nil {
+			current.Files = splitFiles(matches[1])
+			continue
+		}
+		if matches := statusPattern.FindStringSubmatch(line); matches != nil {
+			current.Status = matches[1]
+			continue
+		}
+		if matches := tagsPattern.FindStringSubmatch(line); matches != nil {
+			current.Tags = splitFiles(matches[1])
+			continue
+		}
+
+		// Skip separators and empty lines at start
+		if line == "---" || (len(textLines) == 0 && line == "") {
+			continue
+		}
+
+		// Collect text
+		if !strings.HasPrefix(line, "**Context:**") {
+			textLines = append(textLines, line)
+		} else {
+			current.Context = strings.TrimPrefix(line, "**Context:** ")
+		}
+	}
+
+	// Don't forget last decision
+	if current != nil {
+		current.Text = strings.TrimSpace(strings.Join(textLines, "\n"))
+		decisions = append(decisions, *current)
+	}
+
+	return decisions, scanner.Err()
+}
+
+// splitFiles parses a comma-separated list such as files or tags
+func splitFiles(s string) []string {
+	var files []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
@@ -0,0 +1,96 @@
+package decisions
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jitin-nhz/contextpilot/internal/db"
+)
+
+// sqliteBackend stores decisions in the decisions table of
+// .contextpilot/contextpilot.db (see internal/db). Unlike decisions.md the
+// database is local to this machine, so it suits projects whose decisions
+// aren't shared through git.
+type sqliteBackend struct {
+	root string
+}
+
+func newSQLiteBackend(root string) *sqliteBackend {
+	return &sqliteBackend{root: root}
+}
+
+func (b *sqliteBackend) list() ([]Decision, error) {
+	conn, err := db.Open(b.root)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := conn.Query(`SELECT data FROM decisions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decisions: %w", err)
+	}
+	defer rows.Close()
+
+	decisions := []Decision{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read decisions: %w", err)
+		}
+		var d Decision
+		if err := json.Unmarshal([]byte(data), &d); err != nil {
+			return nil, fmt.Errorf("failed to parse decision: %w", err)
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions, rows.Err()
+}
+
+func (b *sqliteBackend) add(d *Decision) error {
+	return b.put(*d, `INSERT INTO decisions (id, date, text, data) VALUES (?, ?, ?, ?)`)
+}
+
+func (b *sqliteBackend) remove(id int) error {
+	conn, err := db.Open(b.root)
+	if err != nil {
+		return err
+	}
+	res, err := conn.Exec(`DELETE FROM decisions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete decision: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("decision #%d not found", id)
+	}
+	return nil
+}
+
+func (b *sqliteBackend) update(d Decision) error {
+	decisions, err := b.list()
+	if err != nil {
+		return err
+	}
+	for _, existing := range decisions {
+		if existing.ID == d.ID {
+			existing.Text = d.Text
+			existing.Context = d.Context
+			return b.put(existing, `UPDATE decisions SET date = ?2, text = ?3, data = ?4 WHERE id = ?1`)
+		}
+	}
+	return nil
+}
+
+// put runs stmt with the id, date, text and JSON of d as parameters 1 to 4
+func (b *sqliteBackend) put(d Decision, stmt string) error {
+	conn, err := db.Open(b.root)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to encode decision: %w", err)
+	}
+	if _, err := conn.Exec(stmt, d.ID, d.Date, d.Text, string(data)); err != nil {
+		return fmt.Errorf("failed to write decision: %w", err)
+	}
+	return nil
+}
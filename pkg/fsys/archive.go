@@ -0,0 +1,135 @@
+package fsys
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// maxArchiveFile caps how much of a single archive entry is kept; larger
+// files are recorded empty, as analysis only reads manifests and sources
+const maxArchiveFile = 8 << 20
+
+// OpenArchive reads a .zip, .tar, .tar.gz or .tgz file into memory for
+// read-only analysis. When every entry sits under one top-level directory,
+// as in archives of a repository, that directory becomes the root.
+func OpenArchive(name string) (fs.FS, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	mem := NewMem(nil)
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		err = readZip(mem, data)
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		var gz *gzip.Reader
+		if gz, err = gzip.NewReader(bytes.NewReader(data)); err == nil {
+			err = readTar(mem, gz)
+		}
+	case strings.HasSuffix(lower, ".tar"):
+		err = readTar(mem, bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unsupported archive %s (want .zip, .tar, .tar.gz or .tgz)", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", name, err)
+	}
+
+	if top := commonTop(mem.Files()); top != "" {
+		return fs.Sub(mem, top)
+	}
+	return ReadOnly(mem), nil
+}
+
+// ReadTar reads a tar stream, such as 'git archive' writes, into memory
+// for read-only analysis, keeping its paths as they are
+func ReadTar(r io.Reader) (fs.FS, error) {
+	mem := NewMem(nil)
+	if err := readTar(mem, r); err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	return ReadOnly(mem), nil
+}
+
+func readZip(mem *Mem, data []byte) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if err := addEntry(mem, f.Name, f.FileInfo().IsDir(), int64(f.UncompressedSize64), f.Open); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readTar(mem *Mem, r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if h.Typeflag != tar.TypeReg && h.Typeflag != tar.TypeDir {
+			continue
+		}
+		open := func() (io.ReadCloser, error) { return io.NopCloser(tr), nil }
+		if err := addEntry(mem, h.Name, h.Typeflag == tar.TypeDir, h.Size, open); err != nil {
+			return err
+		}
+	}
+}
+
+// addEntry copies one archive entry into mem, skipping names that would
+// escape the root
+func addEntry(mem *Mem, name string, isDir bool, size int64, open func() (io.ReadCloser, error)) error {
+	name = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+	if name == "" || !fs.ValidPath(name) {
+		return nil
+	}
+	if isDir {
+		return mem.MkdirAll(name, 0755)
+	}
+	var data []byte
+	if size <= maxArchiveFile {
+		rc, err := open()
+		if err != nil {
+			return err
+		}
+		data, err = io.ReadAll(io.LimitReader(rc, maxArchiveFile))
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return mem.WriteFile(name, data, 0644)
+}
+
+// commonTop returns the single top-level directory all files are under, if
+// there is one
+func commonTop(files []string) string {
+	top := ""
+	for _, f := range files {
+		dir, _, ok := strings.Cut(f, "/")
+		if !ok || (top != "" && dir != top) {
+			return ""
+		}
+		top = dir
+	}
+	return top
+}
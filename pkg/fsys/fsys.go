@@ -0,0 +1,186 @@
+// Package fsys is the filesystem ContextPilot reads and writes projects
+// through. Reads go through io/fs; writes through the small WriteFS
+// interface. Names are slash-separated and relative to the project root,
+// as io/fs requires.
+//
+// OS is the real project directory. Mem is an in-memory tree for tests,
+// ReadOnly adapts any fs.FS (a zip.Reader, an embed.FS, an archive opened
+// with OpenArchive) for analysis.
+package fsys
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// ErrReadOnly is returned by writes to a ReadOnly filesystem
+var ErrReadOnly = errors.New("read-only filesystem")
+
+// WriteFS is an fs.FS that can also be written to
+type WriteFS interface {
+	fs.FS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+	Remove(name string) error
+	Rename(oldname, newname string) error
+}
+
+// AppendFS is implemented by filesystems that can append to a file
+// without rewriting it
+type AppendFS interface {
+	AppendFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// OS returns the directory root on disk ("" is the working directory)
+func OS(root string) WriteFS {
+	if root == "" {
+		root = "."
+	}
+	return osFS{FS: os.DirFS(root), root: root}
+}
+
+type osFS struct {
+	fs.FS
+	root string
+}
+
+// Dir returns the directory on disk files reads, or "" when it wasn't
+// returned by OS
+func Dir(files fs.FS) string {
+	if f, ok := files.(osFS); ok {
+		return f.root
+	}
+	return ""
+}
+
+func (f osFS) path(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(f.root, filepath.FromSlash(name)), nil
+}
+
+func (f osFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	p, err := f.path(name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, perm)
+}
+
+func (f osFS) AppendFile(name string, data []byte, perm fs.FileMode) error {
+	p, err := f.path(name)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (f osFS) MkdirAll(name string, perm fs.FileMode) error {
+	p, err := f.path(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(p, perm)
+}
+
+func (f osFS) Remove(name string) error {
+	p, err := f.path(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
+}
+
+func (f osFS) Rename(oldname, newname string) error {
+	from, err := f.path(oldname)
+	if err != nil {
+		return err
+	}
+	to, err := f.path(newname)
+	if err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
+// ReadOnly wraps fsys so it satisfies WriteFS, failing every write with
+// ErrReadOnly
+func ReadOnly(fsys fs.FS) WriteFS {
+	return readOnly{fsys}
+}
+
+type readOnly struct {
+	fs.FS
+}
+
+func (readOnly) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return &fs.PathError{Op: "write", Path: name, Err: ErrReadOnly}
+}
+
+func (readOnly) MkdirAll(name string, perm fs.FileMode) error {
+	return &fs.PathError{Op: "mkdir", Path: name, Err: ErrReadOnly}
+}
+
+func (readOnly) Remove(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: ErrReadOnly}
+}
+
+func (readOnly) Rename(oldname, newname string) error {
+	return &fs.PathError{Op: "rename", Path: oldname, Err: ErrReadOnly}
+}
+
+// AppendFile appends data to name in fsys, creating the file if needed.
+// Filesystems that don't implement AppendFS have the file rewritten.
+func AppendFile(fsys WriteFS, name string, data []byte, perm fs.FileMode) error {
+	if a, ok := fsys.(AppendFS); ok {
+		return a.AppendFile(name, data, perm)
+	}
+	current, err := fs.ReadFile(fsys, name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return fsys.WriteFile(name, append(current, data...), perm)
+}
+
+// tmpSeq makes temporary names unique within the process
+var tmpSeq atomic.Int64
+
+// WriteFileAtomic replaces name through a temporary file and a rename, so
+// readers never see it half-written
+func WriteFileAtomic(fsys WriteFS, name string, data []byte, perm fs.FileMode) error {
+	tmp := path.Join(path.Dir(name), fmt.Sprintf(".%s.%d-%d.tmp", path.Base(name), os.Getpid(), tmpSeq.Add(1)))
+	if err := fsys.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	if err := fsys.Rename(tmp, name); err != nil {
+		fsys.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Exists reports whether name exists in fsys
+func Exists(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
+
+// IsDir reports whether name is a directory in fsys
+func IsDir(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,151 @@
+package fsys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Lock timing. A lock is only held for the few milliseconds a write takes,
+// so one older than StaleLockAge was left behind by a process that died.
+var (
+	LockTimeout  = 10 * time.Second
+	StaleLockAge = 30 * time.Second
+)
+
+// ErrLocked is returned when a lock is still held after LockTimeout
+var ErrLocked = errors.New("locked by another process")
+
+// Locker is implemented by filesystems that can hold a named lock shared
+// with other processes using them
+type Locker interface {
+	Lock(name string) (unlock func(), err error)
+}
+
+// Lock takes the lock name in fsys, waiting up to LockTimeout for another
+// holder to release it, and returns the function that releases it.
+// Filesystems that aren't Lockers, such as ReadOnly ones, need no lock.
+func Lock(fsys WriteFS, name string) (func(), error) {
+	if l, ok := fsys.(Locker); ok {
+		return l.Lock(name)
+	}
+	return func() {}, nil
+}
+
+// lockOwner is what a lock file on disk records about its holder
+type lockOwner struct {
+	PID      int       `json:"pid"`
+	Host     string    `json:"host"`
+	Acquired time.Time `json:"acquired"`
+}
+
+// Lock creates name exclusively, retrying with backoff while another
+// process holds it and removing it when its holder is gone
+func (f osFS) Lock(name string) (func(), error) {
+	p, err := f.path(name)
+	if err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	data, _ := json.Marshal(lockOwner{PID: os.Getpid(), Host: host, Acquired: time.Now()})
+
+	deadline := time.Now().Add(LockTimeout)
+	backoff := 5 * time.Millisecond
+	for {
+		file, err := os.OpenFile(p, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, werr := file.Write(data)
+			if cerr := file.Close(); werr == nil {
+				werr = cerr
+			}
+			if werr != nil {
+				os.Remove(p)
+				return nil, werr
+			}
+			return func() { os.Remove(p) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+
+		info, owner, stale := staleLock(p, host)
+		if stale {
+			// Another waiter may have cleared it and taken the lock since;
+			// only remove the file that was judged stale
+			if cur, err := os.Stat(p); err == nil && os.SameFile(cur, info) && cur.ModTime().Equal(info.ModTime()) {
+				os.Remove(p)
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s %s: %w", name, owner, ErrLocked)
+		}
+		time.Sleep(backoff)
+		if backoff < 200*time.Millisecond {
+			backoff *= 2
+		}
+	}
+}
+
+// staleLock stats the lock file at p, describes its holder and reports whether
+// it was abandoned: it is older than StaleLockAge, or its process on this
+// host has exited
+func staleLock(p, host string) (fs.FileInfo, string, bool) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, "", false
+	}
+	age := time.Since(info.ModTime())
+	if age > StaleLockAge {
+		return info, "", true
+	}
+
+	var owner lockOwner
+	data, err := os.ReadFile(p)
+	if err != nil || json.Unmarshal(data, &owner) != nil || owner.PID == 0 {
+		// Being written right now, or unreadable: let it age out
+		return info, fmt.Sprintf("(held for %s)", age.Round(time.Millisecond)), false
+	}
+	if owner.Host == host && !processAlive(owner.PID) {
+		return info, "", true
+	}
+	return info, fmt.Sprintf("(held by pid %d on %s for %s)", owner.PID, owner.Host, age.Round(time.Millisecond)), false
+}
+
+// processAlive reports whether pid is running. Where that can't be asked
+// without side effects it is assumed to be.
+func processAlive(pid int) bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// Lock holds name for as long as the returned function isn't called.
+// Mem locks are only shared by users of the same Mem.
+func (m *Mem) Lock(name string) (func(), error) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = map[string]*sync.Mutex{}
+	}
+	l, ok := m.locks[name]
+	if !ok {
+		l = &sync.Mutex{}
+		m.locks[name] = l
+	}
+	m.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock, nil
+}
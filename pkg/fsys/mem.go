@@ -0,0 +1,115 @@
+package fsys
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing/fstest"
+	"time"
+)
+
+// Mem is an in-memory WriteFS. Parent directories exist implicitly, so
+// writing "a/b/c.txt" needs no MkdirAll first. It is safe for concurrent
+// use.
+type Mem struct {
+	mu    sync.RWMutex
+	files fstest.MapFS
+	locks map[string]*sync.Mutex
+}
+
+// NewMem returns an in-memory tree holding files, keyed by slash-separated
+// name
+func NewMem(files map[string]string) *Mem {
+	m := &Mem{files: fstest.MapFS{}}
+	for name, content := range files {
+		m.files[name] = &fstest.MapFile{Data: []byte(content), Mode: 0644, ModTime: time.Now()}
+	}
+	return m
+}
+
+func (m *Mem) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files.Open(name)
+}
+
+func (m *Mem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f, ok := m.files[name]; ok && f.Mode.IsDir() {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrExist}
+	}
+	// Files are never changed in place, so open handles keep their contents
+	m.files[name] = &fstest.MapFile{Data: append([]byte(nil), data...), Mode: perm, ModTime: time.Now()}
+	return nil
+}
+
+func (m *Mem) MkdirAll(name string, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for dir := name; dir != "."; dir = path.Dir(dir) {
+		if f, ok := m.files[dir]; ok {
+			if !f.Mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: dir, Err: fs.ErrExist}
+			}
+			continue
+		}
+		m.files[dir] = &fstest.MapFile{Mode: fs.ModeDir | perm, ModTime: time.Now()}
+	}
+	return nil
+}
+
+func (m *Mem) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+func (m *Mem) Rename(oldname, newname string) error {
+	if !fs.ValidPath(newname) || newname == "." {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[oldname]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldname, Err: fs.ErrNotExist}
+	}
+	delete(m.files, oldname)
+	m.files[newname] = f
+	// A directory takes what's below it along
+	prefix := oldname + "/"
+	for name, child := range m.files {
+		if strings.HasPrefix(name, prefix) {
+			delete(m.files, name)
+			m.files[newname+"/"+strings.TrimPrefix(name, prefix)] = child
+		}
+	}
+	return nil
+}
+
+// Files returns the names of the regular files in m, sorted
+func (m *Mem) Files() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var names []string
+	for name, f := range m.files {
+		if !f.Mode.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+)
+
+// AgentsFile is the context file Codex CLI and Amp read. They merge the
+// AGENTS.md files from the project root down to the directory they work
+// in, so a monorepo package can have its own (see config.Agents).
+const AgentsFile = "AGENTS.md"
+
+// generatedMarker is in every file ContextPilot generates
+const generatedMarker = "Generated by ContextPilot"
+
+// GenerateAgentsMD creates AGENTS.md and, when agents.nested is set in
+// config.yaml, one in every monorepo package. A package AGENTS.md that
+// ContextPilot didn't write is left alone, and those it wrote are removed
+// once agents.nested is turned off.
+func (g *Generator) GenerateAgentsMD() error {
+	if err := g.write(AgentsFile, g.renderAgentsMD()); err != nil {
+		return err
+	}
+	nested := g.nestedAgents()
+	for _, w := range g.analysis.Packages.Workspace {
+		name := path.Join(w.Path, AgentsFile)
+		current, err := fs.ReadFile(g.files, name)
+		if err == nil && !strings.Contains(string(current), generatedMarker) {
+			g.log.Info("kept hand-written file", "file", name)
+			continue
+		}
+		switch {
+		case nested:
+			if err := g.write(name, g.renderPackageAgents(w)); err != nil {
+				return err
+			}
+		case err == nil:
+			if err := g.files.Remove(name); err != nil {
+				return err
+			}
+			g.log.Info("removed file", "file", name)
+		}
+	}
+	return nil
+}
+
+// nestedAgents reports whether config.yaml turns on per-package AGENTS.md
+func (g *Generator) nestedAgents() bool {
+	cfg, err := config.LoadFS(g.files)
+	return err == nil && cfg.Agents.Nested
+}
+
+// packageAgentsFiles returns the per-package AGENTS.md files generated,
+// skipping those written by hand
+func (g *Generator) packageAgentsFiles() []string {
+	if !g.nestedAgents() {
+		return nil
+	}
+	var files []string
+	for _, w := range g.analysis.Packages.Workspace {
+		name := path.Join(w.Path, AgentsFile)
+		if current, err := fs.ReadFile(g.files, name); err == nil && !strings.Contains(string(current), generatedMarker) {
+			continue
+		}
+		files = append(files, name)
+	}
+	return files
+}
+
+func (g *Generator) renderAgentsMD() string {
+	return g.executeTemplate("agents", AgentsTemplate)
+}
+
+// renderPackageAgents renders the AGENTS.md of the monorepo package w:
+// its own frameworks and ORMs, and the decisions linked to its files
+func (g *Generator) renderPackageAgents(w analyzer.WorkspacePackage) string {
+	data := struct {
+		Date       string
+		Package    analyzer.WorkspacePackage
+		Frameworks []analyzer.Framework
+		ORMs       []analyzer.ORM
+		Decisions  []decisions.Decision
+	}{
+		Date:    time.Now().Format("2006-01-02"),
+		Package: w,
+	}
+	for _, f := range frameworks(g.analysis) {
+		if f.Where == w.Path {
+			data.Frameworks = append(data.Frameworks, f)
+		}
+	}
+	for _, o := range orms(g.analysis) {
+		if o.Where == w.Path {
+			data.ORMs = append(data.ORMs, o)
+		}
+	}
+	for _, d := range g.activeDecisions() {
+		if linksDir(d, w.Path) {
+			data.Decisions = append(data.Decisions, d)
+		}
+	}
+	return g.render(PackageAgentsTemplate, data)
+}
+
+// linksDir reports whether one of d's files lies inside dir
+func linksDir(d decisions.Decision, dir string) bool {
+	for _, f := range d.Files {
+		f = path.Clean(strings.TrimPrefix(f, "./"))
+		if f == dir || strings.HasPrefix(f, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// maxAliases caps the import aliases listed, which a package exporting
+// every component could otherwise fill the file with
+const maxAliases = 20
+
+// aliasLines describes the import aliases, one per line, e.g. "`@/` maps
+// to `src/` (tsconfig.json)"
+func aliasLines(a *analyzer.Analysis) []string {
+	var lines []string
+	for i, alias := range a.Aliases {
+		if i == maxAliases {
+			lines = append(lines, fmt.Sprintf("...and %d more", len(a.Aliases)-maxAliases))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("`%s` maps to `%s` (%s)", alias.Alias, alias.Target, alias.Source))
+	}
+	return lines
+}
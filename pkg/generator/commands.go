@@ -0,0 +1,271 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// Command is a common project command surfaced in generated context
+type Command struct {
+	Name    string `json:"name"` // install, dev, test, build, run, lint, fmt, typecheck or a task
+	Run     string `json:"run"`
+	Comment string `json:"comment"`
+}
+
+// Commands returns the common commands for the project's package manager
+func Commands(analysis *analyzer.Analysis) []Command {
+	switch analysis.Packages.Manager {
+	case "npm", "yarn", "pnpm", "bun":
+		return jsCommands(analysis.Packages)
+	case "deno":
+		return denoCommands(analysis.Packages.Scripts)
+	case "go":
+		return append([]Command{
+			{"build", "go build", "Build the project"},
+			{"test", "go test ./...", "Run all tests"},
+			{"run", "go run .", "Run the project"},
+		}, toolCommands(analysis.Patterns)...)
+	case "pip", "poetry/pip":
+		return append([]Command{
+			{"install", "pip install -r requirements.txt", "Install dependencies"},
+			{"run", "python main.py", "Run the project"},
+			{"test", pythonTestCommand(analysis.Patterns), "Run tests"},
+		}, toolCommands(analysis.Patterns)...)
+	}
+	return nil
+}
+
+// checkCommands returns the test, lint, format and type check commands
+func checkCommands(analysis *analyzer.Analysis) []Command {
+	var checks []Command
+	for _, c := range Commands(analysis) {
+		switch c.Name {
+		case "test", "lint", "fmt", "typecheck":
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+// pythonTestCommand runs the tests through tox when it is configured
+func pythonTestCommand(p analyzer.Patterns) string {
+	switch {
+	case p.TestRunner == "tox":
+		return "tox"
+	case p.TestFramework == "unittest":
+		return "python -m unittest"
+	}
+	return "pytest"
+}
+
+// lintCommands, formatCommands and typeCheckCommands run the Go and
+// Python tools the analyzer detects
+var (
+	lintCommands = map[string]string{
+		"golangci-lint": "golangci-lint run",
+		"go vet":        "go vet ./...",
+		"Ruff":          "ruff check .",
+		"flake8":        "flake8",
+	}
+	formatCommands = map[string]string{
+		"gofumpt": "gofumpt -l -w .",
+		"gofmt":   "gofmt -l -w .",
+		"Ruff":    "ruff format .",
+		"Black":   "black .",
+	}
+	typeCheckCommands = map[string]string{
+		"mypy": "mypy .",
+	}
+)
+
+// toolCommands returns the lint, format and type check commands for the
+// detected tools
+func toolCommands(p analyzer.Patterns) []Command {
+	var commands []Command
+	if run, ok := lintCommands[p.Linter]; ok {
+		commands = append(commands, Command{"lint", run, "Lint with " + p.Linter})
+	}
+	if run, ok := formatCommands[p.Formatter]; ok {
+		commands = append(commands, Command{"fmt", run, "Format with " + p.Formatter})
+	}
+	if run, ok := typeCheckCommands[p.TypeChecker]; ok {
+		commands = append(commands, Command{"typecheck", run, "Type check with " + p.TypeChecker})
+	}
+	return commands
+}
+
+// jsCommands returns the package.json commands in the manager's syntax
+func jsCommands(pkgs analyzer.PackageInfo) []Command {
+	manager := pkgs.Manager
+	run := manager + " "
+	test := manager + " test"
+	switch manager {
+	case "npm":
+		run = "npm run "
+	case "bun":
+		// bun build and bun test are Bun's bundler and test runner, so
+		// scripts need bun run
+		run = "bun run "
+		if _, ok := pkgs.Scripts["test"]; ok {
+			test = "bun run test"
+		}
+	}
+	return []Command{
+		{"install", manager + " install", "Install dependencies"},
+		{"dev", run + "dev", "Start development server"},
+		{"test", test, "Run tests"},
+		{"build", run + "build", "Build for production"},
+	}
+}
+
+// denoCommands runs each deno.json task with deno task, and falls back to
+// Deno's built-in test runner, linter and formatter
+func denoCommands(tasks map[string]string) []Command {
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	commands := []Command{{"install", "deno install", "Install dependencies"}}
+	for _, name := range names {
+		comment := "Run the " + name + " task"
+		if tasks[name] != "" {
+			comment += ": " + tasks[name]
+		}
+		commands = append(commands, Command{name, "deno task " + name, comment})
+	}
+	for _, c := range []Command{
+		{"test", "deno test", "Run tests"},
+		{"lint", "deno lint", "Lint"},
+		{"fmt", "deno fmt", "Format"},
+	} {
+		if _, ok := tasks[c.Name]; !ok {
+			commands = append(commands, c)
+		}
+	}
+	return commands
+}
+
+// CommandFor returns the command with the given name, or ""
+func CommandFor(analysis *analyzer.Analysis, name string) string {
+	for _, c := range Commands(analysis) {
+		if c.Name == name {
+			return c.Run
+		}
+	}
+	return ""
+}
+
+// Conventions returns the detected coding conventions as short phrases
+func Conventions(analysis *analyzer.Analysis) []string {
+	p := analysis.Patterns
+	var conventions []string
+	if p.NamingConvention != "" {
+		conventions = append(conventions, p.NamingConvention+" naming")
+	}
+	if p.ExportStyle != "" {
+		conventions = append(conventions, p.ExportStyle+" exports")
+	}
+	if p.Styling != "" {
+		conventions = append(conventions, "style with "+p.Styling)
+	}
+	if orms := ormsList(analysis); orms != "" {
+		conventions = append(conventions, "database access via "+orms)
+	}
+	if p.StateManagement != "" {
+		conventions = append(conventions, "state via "+p.StateManagement)
+	}
+	if p.TestFramework != "" {
+		conventions = append(conventions, "tests with "+p.TestFramework)
+	}
+	if p.Linter != "" {
+		conventions = append(conventions, "lint with "+p.Linter)
+	}
+	if p.Formatter != "" {
+		conventions = append(conventions, "format with "+p.Formatter)
+	}
+	if p.TypeChecker != "" {
+		conventions = append(conventions, "type check with "+p.TypeChecker)
+	}
+	return append(conventions, p.Conventions...)
+}
+
+// Stack returns a one-line description of the framework and languages
+func Stack(analysis *analyzer.Analysis) string {
+	var parts []string
+	if list := frameworksList(analysis); list != "" {
+		parts = append(parts, list)
+	}
+	for _, lang := range analysis.Languages {
+		parts = append(parts, lang.Name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// commandLines renders commands as aligned "cmd  # comment" lines
+func commandLines(commands []Command) string {
+	width := 0
+	for _, c := range commands {
+		if len(c.Run) > width {
+			width = len(c.Run)
+		}
+	}
+
+	lines := make([]string, 0, len(commands))
+	for _, c := range commands {
+		lines = append(lines, fmt.Sprintf("%-*s# %s", width+2, c.Run, c.Comment))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Header returns an ultra-compact project header of at most three lines for
+// prepending to ad-hoc prompts. Languages are expected sorted by file count.
+func Header(analysis *analyzer.Analysis) string {
+	var stack []string
+	for _, f := range frameworks(analysis) {
+		if !contains(stack, f.Name) {
+			stack = append(stack, f.Name)
+		}
+	}
+	for i, lang := range analysis.Languages {
+		if i == 2 {
+			break
+		}
+		stack = append(stack, lang.Name)
+	}
+
+	lines := []string{}
+	if len(stack) > 0 {
+		lines = append(lines, "Stack: "+strings.Join(stack, ", "))
+	} else {
+		lines = append(lines, "Stack: unknown")
+	}
+
+	tooling := []string{}
+	if analysis.Packages.Runtime != "" {
+		tooling = append(tooling, "runtime "+analysis.Packages.Runtime)
+	}
+	if analysis.Packages.Manager != "" {
+		tooling = append(tooling, "package manager "+analysis.Packages.Manager)
+	}
+	if test := CommandFor(analysis, "test"); test != "" {
+		tooling = append(tooling, "test with `"+test+"`")
+	}
+	if len(tooling) > 0 {
+		lines = append(lines, "Tooling: "+strings.Join(tooling, ", "))
+	}
+
+	conventions := Conventions(analysis)
+	if len(conventions) > 4 {
+		conventions = conventions[:4]
+	}
+	if len(conventions) > 0 {
+		lines = append(lines, "Conventions: "+strings.Join(conventions, "; "))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// EnrichmentFile holds the prose 'contextpilot enrich' drafted with an
+// LLM. It is reviewed and edited by hand; every sync copies it into the
+// context files between EnrichmentBegin and EnrichmentEnd.
+const EnrichmentFile = ".contextpilot/enrichment.md"
+
+// Markers around the enrichment region of a context file
+const (
+	EnrichmentBegin = "<!-- contextpilot:enrichment:begin (edit " + EnrichmentFile + ", not this region) -->"
+	EnrichmentEnd   = "<!-- contextpilot:enrichment:end -->"
+)
+
+// enrichment returns EnrichmentFile wrapped in the region markers, without
+// the file's leading comments, or "" if there is none
+func (g *Generator) enrichment() string {
+	data, err := fs.ReadFile(g.files, EnrichmentFile)
+	if err != nil {
+		return ""
+	}
+	body := strings.TrimSpace(string(data))
+	for strings.HasPrefix(body, "<!--") {
+		end := strings.Index(body, "-->")
+		if end == -1 {
+			break
+		}
+		body = strings.TrimSpace(body[end+len("-->"):])
+	}
+	if body == "" {
+		return ""
+	}
+	return EnrichmentBegin + "\n" + body + "\n" + EnrichmentEnd
+}
@@ -0,0 +1,564 @@
+// Package generator writes context files (.cursorrules, CLAUDE.md,
+// copilot-instructions.md, GEMINI.md, AGENTS.md) from an analysis. It is part of ContextPilot's
+// public Go API.
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/drift"
+	"github.com/jitin-nhz/contextpilot/internal/redact"
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+	"github.com/jitin-nhz/contextpilot/pkg/decisions"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// Generator creates context files from analysis
+type Generator struct {
+	analysis *analyzer.Analysis
+	rootPath string
+	files    fsys.WriteFS
+	outputs  []string
+	recent   int
+	log      *slog.Logger
+	// written are the files written so far, in order
+	written []string
+}
+
+// Options configures a Generator
+type Options struct {
+	// Root is the project directory files are written to
+	Root string
+	// FS is written instead of the directory at Root when set
+	FS fsys.WriteFS
+	// Outputs are the context files to write (see ContextFiles); nil means
+	// those configured in .contextpilot/config.yaml
+	Outputs []string
+	// RecentChanges is how many recent commits to summarize in the context
+	// files; 0 means what config.yaml's recentChanges says, a negative
+	// number none
+	RecentChanges int
+	// Logger records each file written; nil means slog.Default()
+	Logger *slog.Logger
+}
+
+// New creates a new Generator
+func New(analysis *analyzer.Analysis, rootPath string) *Generator {
+	return NewWithOptions(analysis, Options{Root: rootPath})
+}
+
+// NewWithOptions creates a Generator for analysis configured by opts
+func NewWithOptions(analysis *analyzer.Analysis, opts Options) *Generator {
+	g := &Generator{
+		analysis: analysis,
+		rootPath: opts.Root,
+		files:    opts.FS,
+		outputs:  opts.Outputs,
+		recent:   opts.RecentChanges,
+		log:      opts.Logger,
+	}
+	if g.files == nil {
+		g.files = fsys.OS(opts.Root)
+	}
+	if g.log == nil {
+		g.log = slog.Default()
+	}
+	return g
+}
+
+// SetOutputs overrides the context files to generate, e.g. with the
+// answers from 'init --interactive'
+func (g *Generator) SetOutputs(outputs []string) {
+	g.outputs = outputs
+}
+
+// Outputs returns the context files this generator writes
+func (g *Generator) Outputs() []string {
+	if g.outputs != nil {
+		return g.outputs
+	}
+	return configuredOutputs(config.LoadFS(g.files))
+}
+
+// Outputs returns the context files a project generates: the outputs
+// listed in config.yaml, or DefaultOutputs if it lists none
+func Outputs(rootPath string) []string {
+	return configuredOutputs(config.Load(rootPath))
+}
+
+func configuredOutputs(cfg *config.Config, err error) []string {
+	if err != nil || len(cfg.Outputs) == 0 {
+		return DefaultOutputs
+	}
+	var outputs []string
+	for _, f := range ContextFiles {
+		for _, o := range cfg.Outputs {
+			if filepath.ToSlash(filepath.Clean(o)) == f {
+				outputs = append(outputs, f)
+				break
+			}
+		}
+	}
+	return outputs
+}
+
+// GenerateAll creates the configured context files, the files plugins
+// generate and config.yaml, and records the analysis as the baseline for
+// drift detection
+func (g *Generator) GenerateAll() error {
+	return g.GenerateAllContext(context.Background())
+}
+
+// GenerateAllContext is GenerateAll, stopping before the next file once ctx
+// is done
+func (g *Generator) GenerateAllContext(ctx context.Context) error {
+	generate := map[string]func() error{
+		".cursorrules":                    g.GenerateCursorRules,
+		"CLAUDE.md":                       g.GenerateClaudeMD,
+		"GEMINI.md":                       g.GenerateGeminiMD,
+		".github/copilot-instructions.md": g.GenerateCopilotInstructions,
+		InstructionsDir:                   g.GenerateScopedInstructions,
+		GeminiStyleguide:                  g.GenerateGeminiStyleguide,
+		AgentsFile:                        g.GenerateAgentsMD,
+	}
+	for _, f := range g.Outputs() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := generate[f](); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", f, err)
+		}
+	}
+
+	if _, err := g.GeneratePluginFiles(ctx); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := g.GenerateConfig(); err != nil {
+		return fmt.Errorf("failed to generate config: %w", err)
+	}
+
+	if err := drift.SaveFS(g.files, g.analysis); err != nil {
+		return fmt.Errorf("failed to save analysis snapshot: %w", err)
+	}
+	g.log.Debug("saved analysis snapshot")
+
+	return nil
+}
+
+// GenerateCursorRules creates .cursorrules file and, when the project mixes
+// stacks, the scoped rules in .cursor/rules (see Scopes)
+func (g *Generator) GenerateCursorRules() error {
+	if err := g.write(".cursorrules", g.renderCursorRules()); err != nil {
+		return err
+	}
+	return g.writeScopes(cursorScopes)
+}
+
+// GenerateScopedInstructions creates the Copilot instructions in
+// .github/instructions, one per scope with its applyTo globs
+func (g *Generator) GenerateScopedInstructions() error {
+	if err := g.files.MkdirAll(InstructionsDir, 0755); err != nil {
+		return err
+	}
+	return g.writeScopes(copilotScopes)
+}
+
+// writeScopes writes a file per scope for the tool, removing those left
+// from scopes that are gone
+func (g *Generator) writeScopes(t scopedTarget) error {
+	keep := map[string]bool{}
+	for _, s := range Scopes(g.analysis) {
+		if err := g.files.MkdirAll(t.dir, 0755); err != nil {
+			return err
+		}
+		if err := g.write(t.file(s), g.render(t.template, s)); err != nil {
+			return err
+		}
+		keep[t.file(s)] = true
+	}
+	for _, f := range g.scopedFiles(t) {
+		if keep[f] {
+			continue
+		}
+		if err := g.files.Remove(f); err != nil {
+			return err
+		}
+		g.log.Info("removed file", "file", f)
+	}
+	return nil
+}
+
+// scopedFiles returns the scoped files ContextPilot wrote before for the
+// tool
+func (g *Generator) scopedFiles(t scopedTarget) []string {
+	files, _ := fs.Glob(g.files, path.Join(t.dir, scopedRulesPrefix+"*"+t.suffix))
+	return files
+}
+
+// GenerateClaudeMD creates CLAUDE.md file
+func (g *Generator) GenerateClaudeMD() error {
+	return g.write("CLAUDE.md", g.renderClaudeMD())
+}
+
+// GenerateGeminiMD creates GEMINI.md, which Gemini CLI reads
+func (g *Generator) GenerateGeminiMD() error {
+	return g.write("GEMINI.md", g.renderGeminiMD())
+}
+
+// GenerateGeminiStyleguide creates .gemini/styleguide.md, which Gemini
+// Code Assist reviews pull requests against
+func (g *Generator) GenerateGeminiStyleguide() error {
+	if err := g.files.MkdirAll(path.Dir(GeminiStyleguide), 0755); err != nil {
+		return err
+	}
+	return g.write(GeminiStyleguide, g.renderGeminiStyleguide())
+}
+
+// GenerateCopilotInstructions creates .github/copilot-instructions.md
+func (g *Generator) GenerateCopilotInstructions() error {
+	if err := g.files.MkdirAll(".github", 0755); err != nil {
+		return err
+	}
+	return g.write(".github/copilot-instructions.md", g.renderCopilotInstructions())
+}
+
+// GenerateConfig creates .contextpilot/config.yaml. An existing config is
+// preserved and only its lastSync timestamp and analysis hash are updated.
+func (g *Generator) GenerateConfig() error {
+	if fsys.Exists(g.files, config.File) {
+		g.log.Debug("updating lastSync", "file", config.File)
+		return config.TouchLastSyncFS(g.files, time.Now(), g.analysis.Hash())
+	}
+
+	if err := g.files.MkdirAll(path.Dir(config.File), 0755); err != nil {
+		return err
+	}
+	return g.write(config.File, g.renderConfig())
+}
+
+// write replaces name with content, logging what was there before so an
+// unexpected rewrite can be traced
+func (g *Generator) write(name, content string) error {
+	previous, readErr := fs.ReadFile(g.files, name)
+	if err := g.files.WriteFile(name, []byte(content), 0644); err != nil {
+		return err
+	}
+	g.written = append(g.written, name)
+	g.log.Info("wrote file",
+		"file", name,
+		"bytes", len(content),
+		"previousBytes", len(previous),
+		"created", readErr != nil,
+		"changed", withoutDate(string(previous)) != withoutDate(content))
+	return nil
+}
+
+// Written returns the files the generator has written, in order, e.g. to
+// say how far GenerateAllContext got before ctx was done
+func (g *Generator) Written() []string {
+	return g.written
+}
+
+// GeminiStyleguide is the style guide Gemini Code Assist reviews with
+const GeminiStyleguide = ".gemini/styleguide.md"
+
+// ContextFiles are the context files that can be generated, relative to
+// the project root. InstructionsDir stands for the scoped Copilot
+// instructions in it.
+var ContextFiles = []string{".cursorrules", "CLAUDE.md", "GEMINI.md", AgentsFile, ".github/copilot-instructions.md", InstructionsDir, GeminiStyleguide}
+
+// DefaultOutputs are the context files generated when config.yaml lists
+// none
+var DefaultOutputs = []string{".cursorrules", "CLAUDE.md", ".github/copilot-instructions.md"}
+
+// Files returns every context file this generator writes: its Outputs,
+// with the scoped rules after .cursorrules, the package AGENTS.md files
+// after the root one, and InstructionsDir replaced by the instructions in
+// it
+func (g *Generator) Files() []string {
+	var files []string
+	for _, f := range g.Outputs() {
+		if f != InstructionsDir {
+			files = append(files, f)
+		}
+		if f == AgentsFile {
+			files = append(files, g.packageAgentsFiles()...)
+		}
+		for _, s := range Scopes(g.analysis) {
+			switch f {
+			case ".cursorrules":
+				files = append(files, s.File())
+			case InstructionsDir:
+				files = append(files, s.InstructionsFile())
+			}
+		}
+	}
+	return files
+}
+
+// Stale returns the context files whose content on disk differs from what
+// would be generated now, ignoring the "Last updated" line, and the scoped
+// rules and package AGENTS.md files that would be removed
+func (g *Generator) Stale() []string {
+	preview := g.Preview()
+	var stale []string
+	for _, f := range g.Files() {
+		current, err := fs.ReadFile(g.files, f)
+		if err != nil || withoutDate(string(current)) != withoutDate(preview[f]) {
+			stale = append(stale, f)
+		}
+	}
+	for _, t := range []scopedTarget{cursorScopes, copilotScopes} {
+		if !slices.Contains(g.Outputs(), t.output) {
+			continue
+		}
+		for _, f := range g.scopedFiles(t) {
+			if _, ok := preview[f]; !ok {
+				stale = append(stale, f)
+			}
+		}
+	}
+	if slices.Contains(g.Outputs(), AgentsFile) && !g.nestedAgents() {
+		for _, w := range g.analysis.Packages.Workspace {
+			name := path.Join(w.Path, AgentsFile)
+			if current, err := fs.ReadFile(g.files, name); err == nil && strings.Contains(string(current), generatedMarker) {
+				stale = append(stale, name)
+			}
+		}
+	}
+	return stale
+}
+
+// withoutDate drops the "# Last updated:" header line
+func withoutDate(content string) string {
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "# Last updated:") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// Preview returns all generated content without writing files
+func (g *Generator) Preview() map[string]string {
+	preview := map[string]string{
+		".cursorrules":                    g.renderCursorRules(),
+		"CLAUDE.md":                       g.renderClaudeMD(),
+		"GEMINI.md":                       g.renderGeminiMD(),
+		".github/copilot-instructions.md": g.renderCopilotInstructions(),
+		GeminiStyleguide:                  g.renderGeminiStyleguide(),
+		AgentsFile:                        g.renderAgentsMD(),
+		".contextpilot/config.yaml":       g.renderConfig(),
+	}
+	for _, f := range g.packageAgentsFiles() {
+		for _, w := range g.analysis.Packages.Workspace {
+			if path.Join(w.Path, AgentsFile) == f {
+				preview[f] = g.renderPackageAgents(w)
+			}
+		}
+	}
+	for _, s := range Scopes(g.analysis) {
+		preview[s.File()] = g.render(CursorScopeTemplate, s)
+		preview[s.InstructionsFile()] = g.render(CopilotScopeTemplate, s)
+	}
+	return preview
+}
+
+func (g *Generator) renderCursorRules() string {
+	return g.executeTemplate("cursor", CursorTemplate)
+}
+
+func (g *Generator) renderClaudeMD() string {
+	return g.executeTemplate("claude", ClaudeTemplate)
+}
+
+func (g *Generator) renderGeminiMD() string {
+	return g.executeTemplate("gemini", GeminiTemplate)
+}
+
+func (g *Generator) renderGeminiStyleguide() string {
+	return g.executeTemplate("gemini", StyleguideTemplate)
+}
+
+func (g *Generator) renderCopilotInstructions() string {
+	return g.executeTemplate("copilot", CopilotTemplate)
+}
+
+// renderConfig renders config.yaml, or for a project below another one
+// the short config.yaml that only overrides its settings
+func (g *Generator) renderConfig() string {
+	data := struct {
+		Date         string
+		Version      int
+		LastSync     string
+		AnalysisHash string
+		Outputs      []string
+		// Parent is the config.yaml inherited from, relative to the
+		// project
+		Parent string
+	}{
+		Date:         time.Now().Format("2006-01-02"),
+		Version:      config.CurrentVersion,
+		LastSync:     time.Now().Format(time.RFC3339),
+		AnalysisHash: g.analysis.Hash(),
+		Outputs:      g.Outputs(),
+	}
+	if root := fsys.Dir(g.files); root != "" {
+		if parent := config.Parent(root); parent != "" {
+			abs, _ := filepath.Abs(root)
+			if rel, err := filepath.Rel(abs, config.Path(parent)); err == nil {
+				data.Parent = filepath.ToSlash(rel)
+				return g.render(PackageConfigTemplate, data)
+			}
+		}
+	}
+	return g.render(ConfigTemplate, data)
+}
+
+// instructions returns the rules config.yaml adds to tool's context file
+func (g *Generator) instructions(tool string) []string {
+	cfg, err := config.LoadFS(g.files)
+	if err != nil {
+		return nil
+	}
+	return cfg.InstructionsFor(tool)
+}
+
+// activeDecisions returns the project's decisions that are in force
+func (g *Generator) activeDecisions() []decisions.Decision {
+	decMgr := decisions.NewWithOptions(decisions.Options{Root: g.rootPath, FS: g.files})
+	allDecisions, _ := decMgr.List()
+	var active []decisions.Decision
+	for _, d := range allDecisions {
+		if d.Active() {
+			active = append(active, d)
+		}
+	}
+	return active
+}
+
+// contextData is what the context file templates are rendered with
+type contextData struct {
+	*analyzer.Analysis
+	Date            string
+	LanguagesList   string
+	FrameworksList  string
+	OtherFrameworks []analyzer.Framework
+	ORMsList        string
+	StylingNotes    []string
+	StackTable      []stackRow
+	Scopes          []Scope
+	ScopesList      string
+	FoldersList     string
+	ImportAliases   []string
+	PrimaryLanguage string
+	CommandLines    string
+	CheckLines      string
+	Decisions       []decisions.Decision
+	HasDecisions    bool
+	Instructions    []string
+	RecentChanges   []changeGroup
+	Enrichment      string
+}
+
+// contextData returns the template data for tool's context file
+func (g *Generator) contextData(tool string) contextData {
+	decisionsList := g.activeDecisions()
+	return contextData{
+		Analysis:        g.analysis,
+		Date:            time.Now().Format("2006-01-02"),
+		LanguagesList:   g.languagesList(),
+		FrameworksList:  frameworksList(g.analysis),
+		OtherFrameworks: otherFrameworks(g.analysis),
+		ORMsList:        ormsList(g.analysis),
+		StylingNotes:    stylingNotes(g.analysis),
+		StackTable:      stackTable(g.analysis),
+		Scopes:          Scopes(g.analysis),
+		ScopesList:      scopesList(g.analysis),
+		FoldersList:     strings.Join(g.analysis.Structure.Folders, ", "),
+		ImportAliases:   aliasLines(g.analysis),
+		PrimaryLanguage: g.primaryLanguage(),
+		CommandLines:    commandLines(Commands(g.analysis)),
+		CheckLines:      commandLines(checkCommands(g.analysis)),
+		Decisions:       decisionsList,
+		HasDecisions:    len(decisionsList) > 0,
+		Instructions:    g.instructions(tool),
+		RecentChanges:   g.recentChanges(),
+		Enrichment:      g.enrichment(),
+	}
+}
+
+// executeTemplate renders the template name for tool's context file
+func (g *Generator) executeTemplate(tool, name string) string {
+	return g.render(name, g.contextData(tool))
+}
+
+// render executes the template name, as the override chain resolves it,
+// with data
+func (g *Generator) render(name string, data interface{}) string {
+	text, _, err := g.Template(name)
+	if err != nil {
+		return fmt.Sprintf("Template error: %v", err)
+	}
+	tmpl, err := template.New(name).Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return fmt.Sprintf("Template error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("Template execution error: %v", err)
+	}
+
+	if name == ConfigTemplate || name == PackageConfigTemplate {
+		return buf.String()
+	}
+	return g.redact(buf.String())
+}
+
+// redact applies the redaction policy in config.yaml to content written
+// for AI tools. A policy that can't be applied replaces the content, so
+// nothing it should have removed gets out.
+func (g *Generator) redact(content string) string {
+	cfg, err := config.LoadFS(g.files)
+	if err != nil || !cfg.Redact.Enabled() {
+		return content
+	}
+	policy, err := redact.New(cfg.Redact, g.analysis)
+	if err != nil {
+		return fmt.Sprintf("Redaction error: %v", err)
+	}
+	return policy.Apply(content)
+}
+
+func (g *Generator) languagesList() string {
+	names := make([]string, 0, len(g.analysis.Languages))
+	for _, lang := range g.analysis.Languages {
+		names = append(names, lang.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (g *Generator) primaryLanguage() string {
+	if len(g.analysis.Languages) > 0 {
+		return g.analysis.Languages[0].Name
+	}
+	return "Unknown"
+}
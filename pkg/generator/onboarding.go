@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/suggest"
+)
+
+// Where to start looking: the areas with the most commits in the last
+// onboardingDays days, up to onboardingAreas of them
+const (
+	onboardingDays  = 90
+	onboardingAreas = 5
+)
+
+// startPoint is a place in the project worth reading first, and why
+type startPoint struct {
+	Path   string
+	Reason string
+}
+
+// RenderOnboarding renders the onboarding document: everything a new team
+// member, or an agent starting with an empty context window, needs on the
+// first day in one file. Unlike the context files it isn't written by sync.
+func (g *Generator) RenderOnboarding() string {
+	data := struct {
+		contextData
+		Project   string
+		StartHere []startPoint
+	}{
+		contextData: g.contextData("all"),
+		Project:     g.projectName(),
+		StartHere:   g.startHere(),
+	}
+	return g.render(OnboardingTemplate, data)
+}
+
+// projectName names the project after its directory
+func (g *Generator) projectName() string {
+	if abs, err := filepath.Abs(g.rootPath); err == nil && filepath.Base(abs) != string(filepath.Separator) {
+		return filepath.Base(abs)
+	}
+	return "this project"
+}
+
+// startHere returns the entry point, then the busiest areas of the last
+// onboardingDays days, or the key folders when there is no history to go by
+func (g *Generator) startHere() []startPoint {
+	var points []startPoint
+	seen := map[string]bool{}
+	add := func(p, reason string) {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			points = append(points, startPoint{p, reason})
+		}
+	}
+	add(g.analysis.Structure.EntryPoint, "entry point")
+
+	for _, p := range g.busiestAreas() {
+		add(p.Path, p.Reason)
+	}
+	if len(points) <= 1 {
+		for _, w := range g.analysis.Packages.Workspace {
+			add(w.Path+"/", "workspace package "+w.Name)
+		}
+		if src := g.analysis.Structure.SrcDir; src != "" {
+			add(path.Clean(src)+"/", "source root")
+		}
+		for _, f := range g.analysis.Structure.Folders {
+			add(f+"/", "key folder")
+		}
+	}
+	return points
+}
+
+// busiestAreas returns the areas (see suggest.Area) with the most commits
+// in the last onboardingDays days, busiest first. Git reads the project
+// from disk, so there are none unless Root is a directory there.
+func (g *Generator) busiestAreas() []startPoint {
+	if info, err := os.Stat(g.rootPath); err != nil || !info.IsDir() {
+		return nil
+	}
+	commits, err := git.LogFiles(g.rootPath, fmt.Sprintf("%d.days.ago", onboardingDays))
+	if err != nil {
+		g.log.Debug("no history for onboarding", "error", err)
+		return nil
+	}
+	counts := map[string]int{}
+	for _, files := range commits {
+		touched := map[string]bool{}
+		for _, f := range files {
+			if area := suggest.Area(f); area != "" {
+				touched[area] = true
+			}
+		}
+		for area := range touched {
+			counts[area]++
+		}
+	}
+
+	areas := make([]string, 0, len(counts))
+	for area := range counts {
+		areas = append(areas, area)
+	}
+	sort.Slice(areas, func(i, j int) bool {
+		if counts[areas[i]] != counts[areas[j]] {
+			return counts[areas[i]] > counts[areas[j]]
+		}
+		return areas[i] < areas[j]
+	})
+	if len(areas) > onboardingAreas {
+		areas = areas[:onboardingAreas]
+	}
+	points := make([]startPoint, 0, len(areas))
+	for _, area := range areas {
+		points = append(points, startPoint{area + "/", fmt.Sprintf("%d commit(s) in the last %d days", counts[area], onboardingDays)})
+	}
+	return points
+}
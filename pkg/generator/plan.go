@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// Actions a Change can take
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionRemove = "remove"
+)
+
+// Change is what GenerateAll would do to one context file
+type Change struct {
+	File   string `json:"file"`
+	Action string `json:"action"`
+	// Sections are the ## headings whose content would be added, changed
+	// or removed; empty for files created or removed
+	Sections []string `json:"sections"`
+}
+
+// Plan returns the changes GenerateAll would make to the context files,
+// in Stale's order, without writing anything
+func (g *Generator) Plan() []Change {
+	preview := g.Preview()
+	changes := []Change{}
+	for _, f := range g.Stale() {
+		next, generated := preview[f]
+		current, err := fs.ReadFile(g.files, f)
+		switch {
+		case !generated:
+			changes = append(changes, Change{f, ActionRemove, []string{}})
+		case err != nil:
+			changes = append(changes, Change{f, ActionCreate, []string{}})
+		default:
+			changes = append(changes, Change{f, ActionUpdate, changedSections(withoutDate(string(current)), withoutDate(next))})
+		}
+	}
+	return changes
+}
+
+// topOfFile names what comes before a file's first ## heading
+const topOfFile = "(top of file)"
+
+// changedSections returns the headings of the ## sections that differ
+// between old and new, in new's order followed by the removed ones
+func changedSections(old, new string) []string {
+	before, oldOrder := sections(old)
+	after, order := sections(new)
+	changed := []string{}
+	for _, h := range order {
+		if b, ok := before[h]; !ok || b != after[h] {
+			changed = append(changed, h)
+		}
+	}
+	for _, h := range oldOrder {
+		if _, ok := after[h]; !ok {
+			changed = append(changed, h)
+		}
+	}
+	return changed
+}
+
+// sections splits content at its ## headings, deeper ones staying in
+// their section, and returns each section's text by heading, with the
+// headings in order
+func sections(content string) (map[string]string, []string) {
+	byHeading := map[string]string{}
+	order := []string{}
+	heading := topOfFile
+	var body strings.Builder
+	flush := func() {
+		if _, seen := byHeading[heading]; !seen {
+			order = append(order, heading)
+		}
+		byHeading[heading] += body.String()
+		body.Reset()
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			flush()
+			heading = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			continue
+		}
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+	flush()
+	return byHeading, order
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/pkg/plugin"
+)
+
+// GeneratePluginFiles writes the extra files returned by the generate hook
+// of the plugins configured in config.yaml, and returns their names.
+// Plugins only run when the project root is a directory on disk.
+func (g *Generator) GeneratePluginFiles(ctx context.Context) ([]string, error) {
+	cfg, err := config.LoadFS(g.files)
+	if err != nil || len(cfg.Plugins) == 0 {
+		return nil, nil
+	}
+	if info, err := os.Stat(g.rootPath); err != nil || !info.IsDir() {
+		return nil, nil
+	}
+	plugins, err := plugin.ResolveAll(cfg.Plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, p := range plugins {
+		resp, err := p.Call(ctx, plugin.HookGenerate, g.rootPath, g.analysis)
+		if err != nil {
+			return written, err
+		}
+		for _, f := range resp.Files {
+			name := path.Clean(filepath.ToSlash(f.Path))
+			if err := g.files.MkdirAll(path.Dir(name), 0755); err != nil {
+				return written, fmt.Errorf("failed to write %s for plugin %s: %w", name, p.Name, err)
+			}
+			if err := g.write(name, g.redact(f.Content)); err != nil {
+				return written, fmt.Errorf("failed to write %s for plugin %s: %w", name, p.Name, err)
+			}
+			written = append(written, name)
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/suggest"
+)
+
+// otherArea groups commits that only changed root files or hidden
+// directories
+const otherArea = "other"
+
+// changeGroup is the recent commits that mostly changed one area
+type changeGroup struct {
+	Area     string
+	Subjects []string
+}
+
+// recentCommits returns how many commits the context files summarize:
+// Options.RecentChanges, else the config's recentChanges
+func (g *Generator) recentCommits() int {
+	if g.recent != 0 {
+		return max(g.recent, 0)
+	}
+	cfg, err := config.LoadFS(g.files)
+	if err != nil {
+		return 0
+	}
+	return cfg.RecentChanges.Commits()
+}
+
+// recentChanges summarizes the last commits by area (see suggest.Area),
+// the area with the newest commit first. Git reads the project from disk,
+// so there is nothing to summarize unless Root is a directory there.
+func (g *Generator) recentChanges() []changeGroup {
+	n := g.recentCommits()
+	if n == 0 {
+		return nil
+	}
+	if info, err := os.Stat(g.rootPath); err != nil || !info.IsDir() {
+		return nil
+	}
+	commits, err := git.RecentCommits(g.rootPath, n)
+	if err != nil {
+		g.log.Debug("no recent changes", "error", err)
+		return nil
+	}
+
+	var groups []changeGroup
+	index := map[string]int{}
+	for _, c := range commits {
+		area := mainArea(c.Files)
+		i, ok := index[area]
+		if !ok {
+			i = len(groups)
+			index[area] = i
+			groups = append(groups, changeGroup{Area: area})
+		}
+		if !slices.Contains(groups[i].Subjects, c.Subject) {
+			groups[i].Subjects = append(groups[i].Subjects, c.Subject)
+		}
+	}
+	return groups
+}
+
+// mainArea returns the area most of files are in, the first
+// alphabetically on a tie. Root files and hidden directories, such as a
+// lockfile or the context files, don't count unless that's all there is.
+func mainArea(files []string) string {
+	counts := map[string]int{}
+	for _, f := range files {
+		if area := suggest.Area(f); area != "" {
+			counts[area]++
+		}
+	}
+	if len(counts) == 0 {
+		return otherArea
+	}
+	areas := make([]string, 0, len(counts))
+	for area := range counts {
+		areas = append(areas, area)
+	}
+	sort.Slice(areas, func(i, j int) bool {
+		if counts[areas[i]] != counts[areas[j]] {
+			return counts[areas[i]] > counts[areas[j]]
+		}
+		return areas[i] < areas[j]
+	})
+	return areas[0]
+}
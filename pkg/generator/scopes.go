@@ -0,0 +1,273 @@
+package generator
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// ScopedRulesDir is where Cursor reads rules that apply to some files only
+const ScopedRulesDir = ".cursor/rules"
+
+// InstructionsDir is where GitHub Copilot reads instructions that apply
+// to some files only. It is also the output that generates them.
+const InstructionsDir = ".github/instructions"
+
+// scopedRulesPrefix marks the rule files ContextPilot owns in
+// ScopedRulesDir and InstructionsDir, so that hand-written ones are never
+// pruned
+const scopedRulesPrefix = "contextpilot-"
+
+// scopedTarget is how one tool reads scoped rules
+type scopedTarget struct {
+	// output is the output that generates them
+	output   string
+	dir      string
+	suffix   string
+	template string
+}
+
+var (
+	cursorScopes  = scopedTarget{".cursorrules", ScopedRulesDir, ".mdc", CursorScopeTemplate}
+	copilotScopes = scopedTarget{InstructionsDir, InstructionsDir, ".instructions.md", CopilotScopeTemplate}
+)
+
+// file returns the file s is written to for the tool
+func (t scopedTarget) file(s Scope) string {
+	return path.Join(t.dir, scopedRulesPrefix+s.Name+t.suffix)
+}
+
+// minScopeFiles is how many files a language needs for rules of its own
+const minScopeFiles = 3
+
+// Scope is a set of rules that only applies to files matching its globs,
+// e.g. the Go conventions for internal/**/*.go in a repo that also has a
+// TypeScript frontend
+type Scope struct {
+	// Name names the rule file, e.g. "go" or "apps-web"
+	Name string `json:"name"`
+	// Title is what the rules are about, e.g. "Go" or "apps/web"
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Globs       []string `json:"globs"`
+	// Lines are the conventions that apply, e.g. "**Linter:** ESLint"
+	Lines []string `json:"lines,omitempty"`
+	// Commands are the test, lint, format and type check commands
+	Commands string `json:"commands,omitempty"`
+}
+
+// File returns the Cursor rule file the scope is written to
+func (s Scope) File() string {
+	return cursorScopes.file(s)
+}
+
+// InstructionsFile returns the Copilot instructions file the scope is
+// written to
+func (s Scope) InstructionsFile() string {
+	return copilotScopes.file(s)
+}
+
+// languageFamilies groups languages whose files share conventions; the
+// others are a family of their own
+var languageFamilies = map[string]string{
+	"JavaScript":       "JavaScript/TypeScript",
+	"TypeScript":       "JavaScript/TypeScript",
+	"JavaScript (JSX)": "JavaScript/TypeScript",
+	"TypeScript (TSX)": "JavaScript/TypeScript",
+	"Vue":              "JavaScript/TypeScript",
+	"Svelte":           "JavaScript/TypeScript",
+	"C":                "C/C++",
+	"C++":              "C/C++",
+	"C/C++ Header":     "C/C++",
+}
+
+// familyNames are the rule file names of families that can't simply be
+// lowercased
+var familyNames = map[string]string{
+	"JavaScript/TypeScript": "typescript",
+	"C/C++":                 "cpp",
+	"C#":                    "csharp",
+}
+
+// familyNaming is each family's naming convention
+var familyNaming = map[string]string{
+	"Go":                    "camelCase/PascalCase, exported names capitalized",
+	"Python":                "snake_case",
+	"JavaScript/TypeScript": "camelCase",
+}
+
+// toolFamilies names the family of the Go and Python tools the analyzer
+// detects; the others are JavaScript tools
+var toolFamilies = map[string]string{
+	"go test":       "Go",
+	"go vet":        "Go",
+	"golangci-lint": "Go",
+	"gofmt":         "Go",
+	"gofumpt":       "Go",
+	"pytest":        "Python",
+	"unittest":      "Python",
+	"tox":           "Python",
+	"Ruff":          "Python",
+	"flake8":        "Python",
+	"Black":         "Python",
+	"mypy":          "Python",
+}
+
+// managerFamilies names the family whose commands each package manager
+// runs
+var managerFamilies = map[string]string{
+	"go":         "Go",
+	"pip":        "Python",
+	"poetry/pip": "Python",
+}
+
+// familyOf returns the family of a language
+func familyOf(lang string) string {
+	if f, ok := languageFamilies[lang]; ok {
+		return f
+	}
+	return lang
+}
+
+// toolFamily returns the family of a detected tool or package manager
+func toolFamily(families map[string]string, tool string) string {
+	if f, ok := families[tool]; ok {
+		return f
+	}
+	return "JavaScript/TypeScript"
+}
+
+// Scopes splits the project's rules by where they apply: one scope per
+// language family with at least minScopeFiles files, globbed to the
+// top-level folders holding them, and one per monorepo package with its
+// own frameworks or ORMs. A project with a single stack has nothing to
+// split, and gets no scopes.
+func Scopes(analysis *analyzer.Analysis) []Scope {
+	scopes := append(familyScopes(analysis), packageScopes(analysis)...)
+	if len(scopes) < 2 {
+		return nil
+	}
+	return scopes
+}
+
+// familyScopes returns a scope per language family, most files first
+func familyScopes(analysis *analyzer.Analysis) []Scope {
+	type family struct {
+		name  string
+		files int
+		globs []string
+	}
+	byName := map[string]*family{}
+	var families []*family
+	for _, lang := range analysis.Languages {
+		name := familyOf(lang.Name)
+		f := byName[name]
+		if f == nil {
+			f = &family{name: name}
+			byName[name] = f
+			families = append(families, f)
+		}
+		f.files += lang.FileCount
+		f.globs = append(f.globs, languageGlobs(lang)...)
+	}
+	sort.SliceStable(families, func(i, j int) bool {
+		return families[i].files > families[j].files
+	})
+
+	p := analysis.Patterns
+	var scopes []Scope
+	for _, f := range families {
+		if f.files < minScopeFiles {
+			continue
+		}
+		sort.Strings(f.globs)
+		s := Scope{
+			Name:        familyNames[f.name],
+			Title:       f.name,
+			Description: f.name + " conventions",
+			Globs:       f.globs,
+		}
+		if s.Name == "" {
+			s.Name = strings.ToLower(f.name)
+		}
+		add := func(label, value string) {
+			if value != "" {
+				s.Lines = append(s.Lines, "**"+label+":** "+value)
+			}
+		}
+		tool := func(label, value string) {
+			if value != "" && toolFamily(toolFamilies, value) == f.name {
+				add(label, value)
+			}
+		}
+		add("Naming", familyNaming[f.name])
+		if f.name == "JavaScript/TypeScript" {
+			var root []string
+			for _, fw := range frameworks(analysis) {
+				if fw.Where == "" {
+					root = append(root, strings.TrimSpace(fw.Name+" "+fw.Version))
+				}
+			}
+			add("Frameworks", strings.Join(root, ", "))
+			root = nil
+			for _, o := range orms(analysis) {
+				if o.Where == "" && !contains(root, o.Name) {
+					root = append(root, o.Name)
+				}
+			}
+			add("Database/ORM", strings.Join(root, ", "))
+			add("Styling", p.Styling)
+			add("State Management", p.StateManagement)
+		}
+		tool("Testing", p.TestFramework)
+		tool("Test Runner", p.TestRunner)
+		tool("Linter", p.Linter)
+		tool("Formatter", p.Formatter)
+		tool("Type Checker", p.TypeChecker)
+		if analysis.Packages.Manager != "" && toolFamily(managerFamilies, analysis.Packages.Manager) == f.name {
+			s.Commands = commandLines(checkCommands(analysis))
+		}
+		scopes = append(scopes, s)
+	}
+	return scopes
+}
+
+// languageGlobs matches lang's files in the folders holding them, or
+// anywhere when some are at the root
+func languageGlobs(lang analyzer.Language) []string {
+	if len(lang.Dirs) == 0 || contains(lang.Dirs, ".") {
+		return []string{"**/*" + lang.Extension}
+	}
+	globs := make([]string, len(lang.Dirs))
+	for i, dir := range lang.Dirs {
+		globs[i] = dir + "/**/*" + lang.Extension
+	}
+	return globs
+}
+
+// packageScopes returns a scope per monorepo package with frameworks or
+// ORMs of its own
+func packageScopes(analysis *analyzer.Analysis) []Scope {
+	var scopes []Scope
+	for _, row := range stackTable(analysis) {
+		if row.Package == "(root)" {
+			continue
+		}
+		s := Scope{
+			Name:        strings.ReplaceAll(row.Package, "/", "-"),
+			Title:       row.Package,
+			Description: "Stack of the " + row.Package + " package",
+			Globs:       []string{row.Package + "/**"},
+		}
+		if row.Frameworks != "-" {
+			s.Lines = append(s.Lines, "**Frameworks:** "+row.Frameworks)
+		}
+		if row.ORMs != "-" {
+			s.Lines = append(s.Lines, "**Database/ORM:** "+row.ORMs)
+		}
+		scopes = append(scopes, s)
+	}
+	return scopes
+}
@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// frameworks returns every detected framework, main one first
+func frameworks(analysis *analyzer.Analysis) []analyzer.Framework {
+	if len(analysis.Frameworks) == 0 && analysis.Framework != nil {
+		return []analyzer.Framework{*analysis.Framework}
+	}
+	return analysis.Frameworks
+}
+
+// otherFrameworks returns the frameworks detected besides the main one
+func otherFrameworks(analysis *analyzer.Analysis) []analyzer.Framework {
+	if all := frameworks(analysis); len(all) > 1 {
+		return all[1:]
+	}
+	return nil
+}
+
+// frameworksList names the frameworks with their versions and packages,
+// e.g. "Next.js ^14.2.0 (apps/web), Express ^4.19.0 (apps/api)"
+func frameworksList(analysis *analyzer.Analysis) string {
+	var names []string
+	for _, f := range frameworks(analysis) {
+		name := f.Name
+		if f.Version != "" {
+			name += " " + f.Version
+		}
+		if f.Where != "" {
+			name += " (" + f.Where + ")"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// orms returns every detected ORM, main one first
+func orms(analysis *analyzer.Analysis) []analyzer.ORM {
+	if len(analysis.Patterns.ORMs) == 0 && analysis.Patterns.ORM != "" {
+		return []analyzer.ORM{{Name: analysis.Patterns.ORM}}
+	}
+	return analysis.Patterns.ORMs
+}
+
+// ormsList names the ORMs once each, e.g. "Prisma, Drizzle"
+func ormsList(analysis *analyzer.Analysis) string {
+	var names []string
+	for _, o := range orms(analysis) {
+		if !contains(names, o.Name) {
+			names = append(names, o.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// stackRow is one package's line in the stack table
+type stackRow struct {
+	Package    string
+	Frameworks string
+	ORMs       string
+}
+
+// stackTable returns the frameworks and ORMs per package, the project
+// root first, or nil when they are all in one package
+func stackTable(analysis *analyzer.Analysis) []stackRow {
+	rows := map[string]*stackRow{}
+	row := func(where string) *stackRow {
+		if rows[where] == nil {
+			rows[where] = &stackRow{Package: where}
+			if where == "" {
+				rows[where].Package = "(root)"
+			}
+		}
+		return rows[where]
+	}
+	add := func(list *string, name string) {
+		if *list != "" {
+			*list += ", "
+		}
+		*list += name
+	}
+	for _, f := range frameworks(analysis) {
+		name := f.Name
+		if f.Version != "" {
+			name += " " + f.Version
+		}
+		add(&row(f.Where).Frameworks, name)
+	}
+	for _, o := range orms(analysis) {
+		add(&row(o.Where).ORMs, o.Name)
+	}
+	if len(rows) < 2 {
+		return nil
+	}
+
+	wheres := make([]string, 0, len(rows))
+	for where := range rows {
+		wheres = append(wheres, where)
+	}
+	sort.Strings(wheres)
+	table := make([]stackRow, 0, len(wheres))
+	for _, where := range wheres {
+		r := *rows[where]
+		if r.Frameworks == "" {
+			r.Frameworks = "-"
+		}
+		if r.ORMs == "" {
+			r.ORMs = "-"
+		}
+		table = append(table, r)
+	}
+	return table
+}
+
+// scopesList renders the private npm scopes as `@acme/*`, `@corp/*`
+func scopesList(analysis *analyzer.Analysis) string {
+	scopes := make([]string, len(analysis.Packages.PrivateScopes))
+	for i, scope := range analysis.Packages.PrivateScopes {
+		scopes[i] = "`" + scope + "/*`"
+	}
+	return strings.Join(scopes, ", ")
+}
+
+// contains reports whether list has s
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
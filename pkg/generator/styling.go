@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/pkg/analyzer"
+)
+
+// stylingNotes tells an assistant how styles are written here, one line
+// per styling approach, and not to bring in another one: the mistake is
+// easy to make and hard to undo once components depend on it
+func stylingNotes(a *analyzer.Analysis) []string {
+	styles := a.Patterns.Styles
+	if len(styles) == 0 {
+		return nil
+	}
+	var notes []string
+	for _, s := range styles {
+		if note := styleNote(s); note != "" {
+			notes = append(notes, note)
+		}
+	}
+	names := make([]string, len(styles))
+	for i, s := range styles {
+		names[i] = s.String()
+	}
+	return append(notes, "Don't add another styling approach (a CSS-in-JS library, preprocessor or utility framework) next to "+joinAnd(names))
+}
+
+// styleNote says how styles are written with s, or "" when its name says
+// it all
+func styleNote(s analyzer.StyleSystem) string {
+	switch s.Name {
+	case "Tailwind CSS":
+		config := ""
+		for _, e := range s.Evidence {
+			if strings.Contains(e, "tailwind.config.") {
+				config = e
+				break
+			}
+		}
+		switch {
+		case s.Version == "4" && config == "":
+			return "Tailwind CSS v4 is configured in CSS (`@import \"tailwindcss\"`, `@theme`), not in a tailwind.config.js"
+		case s.Version == "4":
+			return "Tailwind CSS v4: theme tokens go in CSS with `@theme`; " + config + " is loaded with `@config`"
+		case s.Version != "" && config != "":
+			return "Tailwind CSS v" + s.Version + ": the theme and plugins are in " + config + "; v4 syntax such as `@theme` doesn't apply"
+		case s.Version != "":
+			return "Tailwind CSS v" + s.Version + ": v4 syntax such as `@theme` doesn't apply"
+		}
+	case "CSS Modules":
+		files := "*.module.css"
+		if len(s.Evidence) > 0 {
+			// "12 *.module.scss files"
+			if f := strings.Fields(s.Evidence[0]); len(f) > 1 && f[1] != "*.module.*" {
+				files = f[1]
+			}
+		}
+		return "Component styles go in a `" + files + "` file next to the component, imported as an object (`styles.button`)"
+	case "Sass":
+		return "Stylesheets are Sass (`.scss`), not plain CSS"
+	case "Less":
+		return "Stylesheets are Less (`.less`), not plain CSS"
+	case "vanilla-extract":
+		return "Styles are written in TypeScript in `*.css.ts` files with vanilla-extract and compiled to static CSS"
+	case "Styled Components":
+		return "Components are styled with `styled` from styled-components"
+	case "Emotion":
+		return "Components are styled with Emotion's `css` prop or `styled`"
+	}
+	return ""
+}
+
+// joinAnd joins names as "a, b and c"
+func joinAnd(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// The templates generated files are rendered from. Each can be overridden,
+// by the first of:
+//
+//  1. .contextpilot/templates/<name> in the project (TemplateDir)
+//  2. the same in the projects above it, nearest first, e.g. the
+//     monorepo root above a package with a config.yaml of its own
+//  3. templates/<name> next to the user config, e.g.
+//     ~/.config/contextpilot/templates/CLAUDE.md.tmpl
+//  4. the built-in template compiled into the binary
+//
+// Templates use text/template with the analysis as data; see the built-in
+// ones (contextpilot templates --eject) for the fields available.
+const (
+	CursorTemplate        = "cursorrules.tmpl"
+	CursorScopeTemplate   = "cursor-scope.mdc.tmpl"
+	ClaudeTemplate        = "CLAUDE.md.tmpl"
+	GeminiTemplate        = "GEMINI.md.tmpl"
+	StyleguideTemplate    = "styleguide.md.tmpl"
+	AgentsTemplate        = "AGENTS.md.tmpl"
+	PackageAgentsTemplate = "package-AGENTS.md.tmpl"
+	CopilotTemplate       = "copilot-instructions.md.tmpl"
+	CopilotScopeTemplate  = "copilot-scope.instructions.md.tmpl"
+	ConfigTemplate        = "config.yaml.tmpl"
+	PackageConfigTemplate = "package-config.yaml.tmpl"
+	OnboardingTemplate    = "ONBOARDING.md.tmpl"
+)
+
+// Templates lists every template name
+var Templates = []string{CursorTemplate, CursorScopeTemplate, ClaudeTemplate, GeminiTemplate, StyleguideTemplate, AgentsTemplate, PackageAgentsTemplate, CopilotTemplate, CopilotScopeTemplate, ConfigTemplate, PackageConfigTemplate, OnboardingTemplate}
+
+// TemplateDir is where a project overrides built-in templates
+const TemplateDir = ".contextpilot/templates"
+
+// Template sources, as returned by Template
+const (
+	SourceProject = "project"
+	SourceParent  = "parent"
+	SourceUser    = "user"
+	SourceBuiltin = "built-in"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// BuiltinTemplate returns the template compiled into the binary
+func BuiltinTemplate(name string) (string, error) {
+	data, err := builtinTemplates.ReadFile(path.Join("templates", name))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// UserTemplateDir returns the directory user-wide template overrides are
+// read from
+func UserTemplateDir() (string, error) {
+	p, err := config.UserPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(p), "templates"), nil
+}
+
+// Template returns the text of the template name as the override chain
+// resolves it, and which source it came from
+func (g *Generator) Template(name string) (string, string, error) {
+	data, err := fs.ReadFile(g.files, path.Join(TemplateDir, name))
+	if err == nil {
+		return string(data), SourceProject, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", "", err
+	}
+
+	if root := fsys.Dir(g.files); root != "" {
+		for dir := config.Parent(root); dir != ""; dir = config.Parent(dir) {
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(TemplateDir), name))
+			if err == nil {
+				return string(data), SourceParent, nil
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return "", "", err
+			}
+		}
+	}
+
+	if dir, err := UserTemplateDir(); err == nil {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return string(data), SourceUser, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", "", err
+		}
+	}
+
+	text, err := BuiltinTemplate(name)
+	return text, SourceBuiltin, err
+}
@@ -0,0 +1,231 @@
+// Package plugin runs ContextPilot plugins: executables that add private
+// framework detectors and extra output files without forking ContextPilot.
+//
+// A plugin is a program named contextpilot-plugin-<name> on PATH (or an
+// absolute path) listed under plugins in .contextpilot/config.yaml. For
+// each hook ContextPilot starts it in the project directory, writes one
+// JSON Request to its stdin and reads one JSON Response from its stdout.
+// A non-zero exit fails the hook, with the plugin's stderr as the message.
+// Plugins answer hooks they don't implement with {}.
+//
+// Hooks:
+//   - describe: report Name, Version and the Hooks implemented
+//   - detect: given the analysis so far, report a framework, tooling and
+//     conventions in Detect
+//   - generate: given the final analysis, return Files to write
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// APIVersion is the protocol version sent with every request
+const APIVersion = 1
+
+// Prefix is what plugin executables are named with
+const Prefix = "contextpilot-plugin-"
+
+// Hooks
+const (
+	HookDescribe = "describe"
+	HookDetect   = "detect"
+	HookGenerate = "generate"
+)
+
+// Timeout bounds a single hook call
+var Timeout = 30 * time.Second
+
+// Request is what a plugin reads from stdin
+type Request struct {
+	APIVersion int    `json:"apiVersion"`
+	Hook       string `json:"hook"`
+	// Root is the project directory, which is also the working directory
+	Root string `json:"root"`
+	// Analysis is the project analysis, as 'contextpilot analyze --json'
+	// prints it, for detect and generate
+	Analysis json.RawMessage `json:"analysis,omitempty"`
+}
+
+// Response is what a plugin writes to stdout
+type Response struct {
+	// describe
+	Name    string   `json:"name,omitempty"`
+	Version string   `json:"version,omitempty"`
+	Hooks   []string `json:"hooks,omitempty"`
+
+	// detect
+	Detect *Detection `json:"detect,omitempty"`
+
+	// generate
+	Files []File `json:"files,omitempty"`
+}
+
+// Detection is what a detect hook found. Set fields replace what
+// ContextPilot detected; conventions are added to its own.
+type Detection struct {
+	Framework       *Framework `json:"framework,omitempty"`
+	TestFramework   string     `json:"testFramework,omitempty"`
+	Linter          string     `json:"linter,omitempty"`
+	Formatter       string     `json:"formatter,omitempty"`
+	ORM             string     `json:"orm,omitempty"`
+	StateManagement string     `json:"stateManagement,omitempty"`
+	Styling         string     `json:"styling,omitempty"`
+	// Conventions are short rules for AI tools, e.g. "Register handlers
+	// in routes/"
+	Conventions []string `json:"conventions,omitempty"`
+}
+
+// Framework names a framework and optionally its version
+type Framework struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// File is an extra output file, relative to the project root
+type File struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// Plugin is a resolved plugin executable
+type Plugin struct {
+	// Name is the name it was configured with
+	Name string `json:"name"`
+	// Path is the executable run for it
+	Path string `json:"path"`
+}
+
+// Resolve finds the executable for a configured plugin: a bare name is
+// looked up on PATH as contextpilot-plugin-<name>, an absolute path is
+// used as is. Relative paths are refused, so a cloned repository can't
+// make ContextPilot run code it ships.
+func Resolve(name string) (Plugin, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return Plugin{}, errors.New("empty plugin name")
+	case filepath.IsAbs(name):
+		if _, err := os.Stat(name); err != nil {
+			return Plugin{}, fmt.Errorf("plugin %s: %w", name, err)
+		}
+		return Plugin{Name: name, Path: name}, nil
+	case strings.ContainsAny(name, `/\`):
+		return Plugin{}, fmt.Errorf("plugin %s: use a name on PATH or an absolute path, not a path in the project", name)
+	}
+	p, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return Plugin{}, fmt.Errorf("plugin %s: %s%s not found on PATH", name, Prefix, name)
+	}
+	return Plugin{Name: name, Path: p}, nil
+}
+
+// ResolveAll resolves every configured plugin, failing on the first one
+// that can't be found
+func ResolveAll(names []string) ([]Plugin, error) {
+	var plugins []Plugin
+	for _, name := range names {
+		p, err := Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, nil
+}
+
+// Discover returns the names of the plugins installed on PATH, sorted
+func Discover() []string {
+	seen := map[string]bool{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := strings.CutPrefix(e.Name(), Prefix)
+			if !ok || e.IsDir() {
+				continue
+			}
+			name = strings.TrimSuffix(name, filepath.Ext(name))
+			if name != "" {
+				seen[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Call runs hook in root, passing analysis (nil for describe)
+func (p Plugin) Call(ctx context.Context, hook, root string, analysis interface{}) (*Response, error) {
+	req := Request{APIVersion: APIVersion, Hook: hook, Root: root}
+	if analysis != nil {
+		data, err := json.Marshal(analysis)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode analysis: %w", err)
+		}
+		req.Analysis = data
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Dir = root
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("plugin %s: %s timed out after %s", p.Name, hook, Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %s: %s failed: %s", p.Name, hook, msg)
+		}
+		return nil, fmt.Errorf("plugin %s: %s failed: %w", p.Name, hook, err)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &resp); err != nil {
+		return nil, fmt.Errorf("plugin %s: invalid %s response: %w", p.Name, hook, err)
+	}
+	if err := resp.validate(); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.Name, err)
+	}
+	return &resp, nil
+}
+
+// validate refuses output files outside the project or in places
+// ContextPilot and git own
+func (r *Response) validate() error {
+	for _, f := range r.Files {
+		name := path.Clean(filepath.ToSlash(f.Path))
+		if f.Path == "" || path.IsAbs(name) || name == "." || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("file %q is outside the project", f.Path)
+		}
+		if top, _, _ := strings.Cut(name, "/"); top == ".git" || top == ".contextpilot" {
+			return fmt.Errorf("file %q is in %s/, which plugins can't write", f.Path, top)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/gitignore"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// fileBackend stores each session as a JSON file in dir, the ones set
+// aside in dir/archive, and the history as one JSON line per version in
+// dir/history.jsonl. It is the default storage.
+type fileBackend struct {
+	files fsys.WriteFS
+	dir   string
+}
+
+func newFileBackend(files fsys.WriteFS, dir string) *fileBackend {
+	return &fileBackend{files: files, dir: dir}
+}
+
+// sessionFile returns the file name of a branch session. The default
+// session keeps the original <branch>.json layout; named sessions use
+// <branch>~<name>.json alongside it. Git branch names can't contain "~"
+// and sanitizeBranch replaces it, so no two sessions share a file.
+func sessionFile(branch, name string) string {
+	filename := sanitizeBranch(branch)
+	if nameKey(name) != "" {
+		filename += "~" + sanitizeBranch(name)
+	}
+	return filename + ".json"
+}
+
+// legacyFile is the <branch>--<name>.json file named sessions were kept
+// in before, which branch a--b's default session could also claim
+func legacyFile(branch, name string) string {
+	if nameKey(name) == "" {
+		return ""
+	}
+	return sanitizeBranch(branch) + "--" + sanitizeBranch(name) + ".json"
+}
+
+func (b *fileBackend) sessionPath(branch, name string) string {
+	return path.Join(b.dir, sessionFile(branch, name))
+}
+
+// legacyPath returns the legacyFile of a named session when it still holds
+// that session, else ""
+func (b *fileBackend) legacyPath(branch, name string) string {
+	file := legacyFile(branch, name)
+	if file == "" {
+		return ""
+	}
+	p := path.Join(b.dir, file)
+	data, err := fs.ReadFile(b.files, p)
+	if err != nil {
+		return ""
+	}
+	var s Session
+	if json.Unmarshal(data, &s) != nil || s.Branch != branch || nameKey(s.Name) != nameKey(name) {
+		return ""
+	}
+	return p
+}
+
+// findPath returns the file the session is stored in: sessionPath, or
+// its legacyPath until it is saved again
+func (b *fileBackend) findPath(branch, name string) string {
+	p := b.sessionPath(branch, name)
+	if !fsys.Exists(b.files, p) {
+		if legacy := b.legacyPath(branch, name); legacy != "" {
+			return legacy
+		}
+	}
+	return p
+}
+
+func (b *fileBackend) historyPath() string {
+	return path.Join(b.dir, "history.jsonl")
+}
+
+// ensureDir creates the sessions directory, kept out of git
+func (b *fileBackend) ensureDir() error {
+	if err := b.files.MkdirAll(b.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+	if err := gitignore.IgnoreDirFS(b.files, b.dir); err != nil {
+		return fmt.Errorf("failed to keep sessions out of git: %w", err)
+	}
+	return nil
+}
+
+func (b *fileBackend) load(branch, name string) (*Session, error) {
+	data, err := fs.ReadFile(b.files, b.findPath(branch, name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil // No session for this branch
+		}
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	return &s, nil
+}
+
+func (b *fileBackend) list() ([]Session, error) {
+	sessions, err := b.readDir(b.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+	return sessions, nil
+}
+
+func (b *fileBackend) put(s *Session) error {
+	if err := b.ensureDir(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	legacy := b.legacyPath(s.Branch, s.Name)
+	if err := b.files.WriteFile(b.sessionPath(s.Branch, s.Name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if legacy != "" {
+		if err := b.files.Remove(legacy); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *fileBackend) remove(branch, name string) error {
+	for _, p := range []string{b.sessionPath(branch, name), b.legacyPath(branch, name)} {
+		if p == "" {
+			continue
+		}
+		if err := b.files.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// archive moves the session file into archive/. A session archived
+// earlier under the same file name is kept by suffixing the new file with
+// its save time.
+func (b *fileBackend) archive(s *Session) error {
+	archiveDir := path.Join(b.dir, "archive")
+	if err := b.files.MkdirAll(archiveDir, 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	src := b.findPath(s.Branch, s.Name)
+	dst := path.Join(archiveDir, path.Base(src))
+	if fsys.Exists(b.files, dst) {
+		dst = strings.TrimSuffix(dst, ".json") + "-" + s.UpdatedAt.Format("20060102-150405") + ".json"
+	}
+	return b.files.Rename(src, dst)
+}
+
+func (b *fileBackend) archived() ([]Session, error) {
+	sessions, err := b.readDir(path.Join(b.dir, "archive"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session archive: %w", err)
+	}
+	return sessions, nil
+}
+
+// readDir reads the session files in dir, skipping any it can't parse
+func (b *fileBackend) readDir(dir string) ([]Session, error) {
+	entries, err := fs.ReadDir(b.files, dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []Session{}, nil
+		}
+		return nil, err
+	}
+
+	sessions := []Session{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") || e.Name() == "history.json" {
+			continue
+		}
+		data, err := fs.ReadFile(b.files, path.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var s Session
+		if err := json.Unmarshal(data, &s); err != nil {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+func (b *fileBackend) history() ([]Session, error) {
+	f, err := b.files.Open(b.historyPath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []Session{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	history := []Session{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var s Session
+		if err := json.Unmarshal(line, &s); err != nil {
+			continue // Skip a torn or corrupt line rather than losing everything
+		}
+		history = append(history, s)
+	}
+	return history, scanner.Err()
+}
+
+// appendHistory adds one line to history.jsonl without rewriting the file
+func (b *fileBackend) appendHistory(s *Session) error {
+	if err := b.ensureDir(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := fsys.AppendFile(b.files, b.historyPath(), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// writeHistory atomically replaces history.jsonl with entries
+func (b *fileBackend) writeHistory(entries []Session) error {
+	var buf bytes.Buffer
+	for _, s := range entries {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return fsys.WriteFileAtomic(b.files, b.historyPath(), buf.Bytes(), 0644)
+}
@@ -0,0 +1,130 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jitin-nhz/contextpilot/internal/api"
+)
+
+// Formats lists the prompt formats supported by Render
+var Formats = []string{"markdown", "plain", "json", "xml"}
+
+// Render returns the resume prompt for s in the given format. project is an
+// optional block of project context placed before the session.
+func (m *Manager) Render(s *Session, format, project string) (string, error) {
+	switch format {
+	case "", "markdown":
+		prompt := m.GeneratePrompt(s)
+		if project != "" {
+			prompt = project + "\n" + prompt
+		}
+		return prompt, nil
+	case "plain":
+		prompt := m.GeneratePrompt(s)
+		if project != "" {
+			prompt = project + "\n" + prompt
+		}
+		return stripMarkdown(prompt), nil
+	case "json":
+		return renderJSON(s, project)
+	case "xml":
+		return renderXML(s, project), nil
+	}
+	return "", fmt.Errorf("unknown format %q (use %s)", format, strings.Join(Formats, ", "))
+}
+
+func renderJSON(s *Session, project string) (string, error) {
+	out := struct {
+		APIVersion     int      `json:"apiVersion"`
+		ProjectContext string   `json:"projectContext,omitempty"`
+		Session        *Session `json:"session"`
+	}{api.Selected(), project, s}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return "", fmt.Errorf("failed to marshal session: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// renderXML wraps the session in <context> tags, which some models follow
+// more reliably than markdown headings
+func renderXML(s *Session, project string) string {
+	var b bytes.Buffer
+	b.WriteString("<context>\n")
+	if project != "" {
+		b.WriteString("<project>\n")
+		b.WriteString(xmlEscape(strings.TrimSpace(project)))
+		b.WriteString("\n</project>\n")
+	}
+
+	fmt.Fprintf(&b, "<session branch=\"%s\"", xmlEscape(s.Branch))
+	if !s.IsDefault() {
+		fmt.Fprintf(&b, " name=\"%s\"", xmlEscape(s.Name))
+	}
+	fmt.Fprintf(&b, " saved=\"%s\">\n", s.UpdatedAt.Format("2006-01-02 15:04"))
+
+	tag := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "<%s>%s</%s>\n", name, xmlEscape(value), name)
+		}
+	}
+	list := func(name, item string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "<%s>\n", name)
+		for _, v := range values {
+			fmt.Fprintf(&b, "  <%s>%s</%s>\n", item, xmlEscape(v), item)
+		}
+		fmt.Fprintf(&b, "</%s>\n", name)
+	}
+
+	tag("task", s.Task)
+	tag("goal", s.Goal)
+	tag("issue", s.Issue)
+	tag("pr", s.PR)
+	list("approaches", "approach", s.Approaches)
+	list("decisions", "decision", s.Decisions)
+	tag("state", s.State)
+	list("completed", "step", s.Completed)
+	list("next-steps", "step", s.NextSteps)
+	tag("notes", s.Notes)
+	list("uncommitted-changes", "file", s.ChangedFiles)
+
+	b.WriteString("</session>\n</context>\n")
+	return b.String()
+}
+
+// xmlEscaper escapes markup characters but keeps newlines readable, unlike
+// xml.EscapeText
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func xmlEscape(s string) string {
+	return xmlEscaper.Replace(s)
+}
+
+// stripMarkdown turns a markdown prompt into plain text for tools that
+// render markup literally
+func stripMarkdown(s string) string {
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line == "---" {
+			continue
+		}
+		line = strings.TrimPrefix(line, "## ")
+		line = strings.ReplaceAll(line, "**", "")
+		if strings.HasPrefix(line, "*") && strings.HasSuffix(line, "*") && len(line) > 1 {
+			line = strings.Trim(line, "*")
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
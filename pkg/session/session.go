@@ -0,0 +1,761 @@
+// Package session saves and restores work sessions per git branch under
+// .contextpilot/sessions, or in .contextpilot/contextpilot.db when
+// config.yaml sets storage: sqlite. It is part of ContextPilot's public Go
+// API.
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/config"
+	"github.com/jitin-nhz/contextpilot/internal/git"
+	"github.com/jitin-nhz/contextpilot/internal/lock"
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+// DefaultName is the name of the unnamed session kept for each branch
+const DefaultName = "default"
+
+// Session represents a work session context
+type Session struct {
+	ID           string    `json:"id"`
+	Branch       string    `json:"branch"`
+	Name         string    `json:"name,omitempty"`
+	Task         string    `json:"task"`
+	Goal         string    `json:"goal,omitempty"`
+	Approaches   []string  `json:"approaches,omitempty"`
+	Decisions    []string  `json:"decisions,omitempty"`
+	State        string    `json:"state,omitempty"`
+	NextSteps    []string  `json:"nextSteps,omitempty"`
+	Completed    []string  `json:"completed,omitempty"`
+	Notes        string    `json:"notes,omitempty"`
+	Issue        string    `json:"issue,omitempty"`
+	PR           string    `json:"pr,omitempty"`
+	Commit       string    `json:"commit,omitempty"`
+	ChangedFiles []string  `json:"changedFiles,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// backend persists sessions and their history in a particular store
+type backend interface {
+	// load returns the session of branch and name, or nil if there is none
+	load(branch, name string) (*Session, error)
+	// list returns the session of every branch and name, in no order
+	list() ([]Session, error)
+	// put stores s as the session of its branch and name
+	put(s *Session) error
+	// remove deletes the session of branch and name, if there is one
+	remove(branch, name string) error
+	// archive sets the session of s's branch and name aside
+	archive(s *Session) error
+	// archived returns the sessions set aside by archive
+	archived() ([]Session, error)
+	// history returns every version saved, oldest first
+	history() ([]Session, error)
+	// appendHistory adds s to the end of the history
+	appendHistory(s *Session) error
+	// writeHistory replaces the history with entries
+	writeHistory(entries []Session) error
+}
+
+// Manager handles session operations
+type Manager struct {
+	rootPath    string
+	files       fsys.WriteFS
+	sessionsDir string
+	branch      string
+	backend     backend
+}
+
+// Options configures a Manager
+type Options struct {
+	// Root is the project directory; git runs there to find the branch
+	Root string
+	// FS holds the sessions instead of the directory at Root when set
+	FS fsys.WriteFS
+	// Branch pins the branch sessions are saved for; empty means the
+	// checked-out branch
+	Branch string
+}
+
+// New creates a new session Manager
+func New(rootPath string) *Manager {
+	return NewWithOptions(Options{Root: rootPath})
+}
+
+// NewWithOptions creates a session Manager configured by opts
+func NewWithOptions(opts Options) *Manager {
+	m := &Manager{
+		rootPath:    opts.Root,
+		files:       opts.FS,
+		sessionsDir: ".contextpilot/sessions",
+		branch:      opts.Branch,
+	}
+	if m.files == nil {
+		m.files = fsys.OS(opts.Root)
+	}
+	m.backend = newFileBackend(m.files, m.sessionsDir)
+	if cfg, err := config.LoadFS(m.files); err == nil && cfg.Storage == config.StorageSQLite {
+		m.backend = newSQLiteBackend(opts.Root)
+	}
+	return m
+}
+
+// Save creates or updates a session
+func (m *Manager) Save(s *Session) error {
+	// Generate ID if new
+	if s.ID == "" {
+		s.ID = fmt.Sprintf("%d", time.Now().UnixNano())
+		s.CreatedAt = time.Now()
+	}
+	s.UpdatedAt = time.Now()
+
+	// Get current branch if not set
+	if s.Branch == "" {
+		s.Branch = m.getCurrentBranch()
+	}
+
+	if s.Issue == "" {
+		s.Issue = DetectIssue(s.Branch)
+	}
+
+	unlock, err := lock.Acquire(m.files, lock.Sessions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.backend.put(s); err != nil {
+		return err
+	}
+
+	// Also save to history
+	return m.appendHistory(s)
+}
+
+// Load returns the default session for the current branch
+func (m *Manager) Load() (*Session, error) {
+	return m.LoadNamed("")
+}
+
+// LoadNamed returns the named session for the current branch.
+// An empty name (or "default") loads the default session.
+func (m *Manager) LoadNamed(name string) (*Session, error) {
+	return m.backend.load(m.getCurrentBranch(), name)
+}
+
+// List returns all sessions saved for the current branch, default first
+func (m *Manager) List() ([]Session, error) {
+	all, err := m.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	branch := m.getCurrentBranch()
+	sessions := []Session{}
+	for _, s := range all {
+		if s.Branch == branch {
+			sessions = append(sessions, s)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].IsDefault() != sessions[j].IsDefault() {
+			return sessions[i].IsDefault()
+		}
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+
+	return sessions, nil
+}
+
+// ListAll returns the saved sessions of every branch, most recent first
+func (m *Manager) ListAll() ([]Session, error) {
+	sessions, err := m.backend.list()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+
+	return sessions, nil
+}
+
+// Search finds sessions on any branch whose text matches query
+// (case-insensitive). Both current session files and history are scanned,
+// so tasks that were later overwritten can still be found; each task of a
+// session appears once, in its most recent version.
+func (m *Manager) Search(query string) ([]Session, error) {
+	current, err := m.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	history, err := m.readHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]Session)
+	for _, s := range append(history, current...) {
+		key := s.ID + "\x00" + s.Task
+		if s.ID == "" {
+			key = s.Branch + "/" + s.Name + "\x00" + s.Task
+		}
+		if prev, ok := latest[key]; ok && prev.UpdatedAt.After(s.UpdatedAt) {
+			continue
+		}
+		latest[key] = s
+	}
+
+	query = strings.ToLower(query)
+	matches := []Session{}
+	for _, s := range latest {
+		if s.matches(query) {
+			matches = append(matches, s)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].UpdatedAt.After(matches[j].UpdatedAt)
+	})
+
+	return matches, nil
+}
+
+var (
+	// trackerKeyPattern matches Jira/Linear keys such as PROJ-123
+	trackerKeyPattern = regexp.MustCompile(`(?:^|[/_-])([A-Z][A-Z0-9]+-[0-9]+)(?:$|[/_-])`)
+	// issueNumberPattern matches GitHub-style numbers such as 123-fix or issue-123
+	issueNumberPattern = regexp.MustCompile(`(?:^|/)(?:issue-|issues-|gh-)?([0-9]+)(?:$|[/_-])`)
+)
+
+// DetectIssue extracts an issue reference from a branch name, e.g.
+// feat/PROJ-123-foo gives PROJ-123 and fix/42-typo gives #42
+func DetectIssue(branch string) string {
+	if m := trackerKeyPattern.FindStringSubmatch(branch); m != nil {
+		return m[1]
+	}
+	if m := issueNumberPattern.FindStringSubmatch(branch); m != nil {
+		return "#" + m[1]
+	}
+	return ""
+}
+
+// AddUnique appends items to list, skipping blanks and ones already present
+func AddUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		found := false
+		for _, existing := range list {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// CompleteStep checks off the nth (1-based) next step, moving it to Completed
+func (s *Session) CompleteStep(n int) (string, error) {
+	if n < 1 || n > len(s.NextSteps) {
+		return "", fmt.Errorf("no next step #%d (session has %d)", n, len(s.NextSteps))
+	}
+	step := s.NextSteps[n-1]
+	s.NextSteps = append(s.NextSteps[:n-1], s.NextSteps[n:]...)
+	s.Completed = append(s.Completed, step)
+	return step, nil
+}
+
+func (s *Session) matches(query string) bool {
+	fields := []string{s.Branch, s.Name, s.Task, s.Goal, s.State, s.Notes, s.Issue, s.PR}
+	fields = append(fields, s.Approaches...)
+	fields = append(fields, s.Decisions...)
+	fields = append(fields, s.NextSteps...)
+	fields = append(fields, s.Completed...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDefault reports whether s is the unnamed session for its branch
+func (s *Session) IsDefault() bool {
+	return s.Name == "" || s.Name == DefaultName
+}
+
+// nameKey is how storage identifies a session name: empty for the
+// default one, however it was spelled
+func nameKey(name string) string {
+	if name == DefaultName {
+		return ""
+	}
+	return name
+}
+
+// DisplayName returns the session name, or "default" for the unnamed session
+func (s *Session) DisplayName() string {
+	if s.IsDefault() {
+		return DefaultName
+	}
+	return s.Name
+}
+
+// GeneratePrompt creates a prompt to paste into AI tools
+func (m *Manager) GeneratePrompt(s *Session) string {
+	if s == nil {
+		return ""
+	}
+
+	prompt := "## Session Context\n\n"
+	if !s.IsDefault() {
+		prompt += fmt.Sprintf("**Session:** %s\n", s.Name)
+	}
+	prompt += fmt.Sprintf("**Task:** %s\n", s.Task)
+
+	if s.Goal != "" {
+		prompt += fmt.Sprintf("**Goal:** %s\n", s.Goal)
+	}
+	if s.Issue != "" {
+		prompt += fmt.Sprintf("**Issue:** %s\n", s.Issue)
+	}
+	if s.PR != "" {
+		prompt += fmt.Sprintf("**PR:** %s\n", s.PR)
+	}
+
+	if len(s.Approaches) > 0 {
+		prompt += "\n**Approaches Tried:**\n"
+		for _, a := range s.Approaches {
+			prompt += fmt.Sprintf("- %s\n", a)
+		}
+	}
+
+	if len(s.Decisions) > 0 {
+		prompt += "\n**Decisions Made:**\n"
+		for _, d := range s.Decisions {
+			prompt += fmt.Sprintf("- %s\n", d)
+		}
+	}
+
+	if s.State != "" {
+		prompt += fmt.Sprintf("\n**Current State:** %s\n", s.State)
+	}
+
+	if len(s.NextSteps) > 0 || len(s.Completed) > 0 {
+		prompt += "\n**Next Steps:**\n"
+		for _, n := range s.Completed {
+			prompt += fmt.Sprintf("- [x] %s\n", n)
+		}
+		for _, n := range s.NextSteps {
+			prompt += fmt.Sprintf("- [ ] %s\n", n)
+		}
+	}
+
+	if s.Notes != "" {
+		prompt += fmt.Sprintf("\n**Notes:** %s\n", s.Notes)
+	}
+
+	if len(s.ChangedFiles) > 0 {
+		prompt += "\n**Uncommitted Changes:**\n"
+		for i, f := range s.ChangedFiles {
+			if i == 10 {
+				prompt += fmt.Sprintf("- ... and %d more\n", len(s.ChangedFiles)-10)
+				break
+			}
+			prompt += fmt.Sprintf("- %s\n", f)
+		}
+	}
+
+	prompt += fmt.Sprintf("\n---\n*Session saved: %s*\n", s.UpdatedAt.Format("2006-01-02 15:04"))
+
+	return prompt
+}
+
+// Snapshot records the current git state (HEAD commit and uncommitted
+// files) on s and saves it
+func (m *Manager) Snapshot(s *Session) error {
+	s.Commit, s.ChangedFiles = m.GitState()
+	return m.Save(s)
+}
+
+// GitState returns the HEAD commit and the uncommitted files, as
+// Snapshot records them
+func (m *Manager) GitState() (commit string, changed []string) {
+	for _, f := range git.ChangedFiles(m.rootPath) {
+		// Our own files churn on every save
+		if !strings.HasPrefix(f, ".contextpilot/") {
+			changed = append(changed, f)
+		}
+	}
+	return git.Head(m.rootPath), changed
+}
+
+// RootPath returns the project root the manager operates on
+func (m *Manager) RootPath() string {
+	return m.rootPath
+}
+
+// CurrentBranch returns the branch sessions are currently scoped to
+func (m *Manager) CurrentBranch() string {
+	return m.getCurrentBranch()
+}
+
+// GetHistory returns session history for current branch
+func (m *Manager) GetHistory(limit int) ([]Session, error) {
+	history, err := m.readHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter by current branch
+	branch := m.getCurrentBranch()
+	var filtered []Session
+	for _, s := range history {
+		if s.Branch == branch {
+			filtered = append(filtered, s)
+		}
+	}
+
+	// Limit results
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[len(filtered)-limit:]
+	}
+
+	return filtered, nil
+}
+
+// History returns every saved version of the sessions of every branch,
+// within the retention policy, oldest first
+func (m *Manager) History() ([]Session, error) {
+	return m.readHistory()
+}
+
+// Clear removes the default session for the current branch
+func (m *Manager) Clear() error {
+	return m.ClearNamed("")
+}
+
+// ClearNamed removes the named session for the current branch
+func (m *Manager) ClearNamed(name string) error {
+	branch := m.getCurrentBranch()
+
+	unlock, err := lock.Acquire(m.files, lock.Sessions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.backend.remove(branch, name)
+}
+
+// FileName is the name of the file s is stored in with the default
+// storage, which also identifies it in a sessions remote
+func (m *Manager) FileName(s Session) string {
+	return sessionFile(s.Branch, s.Name)
+}
+
+// Import writes s as it is, keeping its ID and timestamps, replacing any
+// session saved for the same branch and name. Unlike Save it doesn't add a
+// history entry.
+func (m *Manager) Import(s *Session) error {
+	unlock, err := lock.Acquire(m.files, lock.Sessions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return m.backend.put(s)
+}
+
+// Reasons a session is stale
+const (
+	StaleDeleted = "deleted"
+	StaleMerged  = "merged"
+)
+
+// StaleSession is a session whose branch was deleted or merged
+type StaleSession struct {
+	Session
+	Reason string
+}
+
+// Stale returns sessions whose branch no longer exists in git, or has been
+// merged into base (skipped if base is empty). The current branch is never
+// considered stale, even before its first commit.
+func (m *Manager) Stale(base string) ([]StaleSession, error) {
+	if !git.IsRepo(m.rootPath) {
+		return nil, fmt.Errorf("not a git repository")
+	}
+
+	all, err := m.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	merged := map[string]bool{}
+	if base != "" {
+		for _, b := range git.MergedBranches(m.rootPath, base) {
+			merged[b] = true
+		}
+	}
+
+	current := m.getCurrentBranch()
+	stale := []StaleSession{}
+	for _, s := range all {
+		switch {
+		case s.Branch == current:
+		case merged[s.Branch]:
+			stale = append(stale, StaleSession{s, StaleMerged})
+		case !git.Resolves(m.rootPath, s.Branch):
+			stale = append(stale, StaleSession{s, StaleDeleted})
+		}
+	}
+	return stale, nil
+}
+
+// Archive sets a session aside, e.g. into .contextpilot/sessions/archive/,
+// where it no longer counts as the session of its branch but still counts
+// for Completed
+func (m *Manager) Archive(s *Session) error {
+	unlock, err := lock.Acquire(m.files, lock.Sessions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return m.backend.archive(s)
+}
+
+// Retire folds a session into history and removes its file, so it stays
+// searchable without cluttering the sessions directory
+func (m *Manager) Retire(s *Session) error {
+	unlock, err := lock.Acquire(m.files, lock.Sessions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.appendHistory(s); err != nil {
+		return err
+	}
+	return m.backend.remove(s.Branch, s.Name)
+}
+
+// Completed returns the sessions whose work has landed: their branch was
+// merged into HEAD or deleted, and they were saved after since. Live,
+// archived and retired sessions all count; each appears once, in its most
+// recent version, oldest first.
+func (m *Manager) Completed(since time.Time) ([]Session, error) {
+	if !git.IsRepo(m.rootPath) {
+		return nil, fmt.Errorf("not a git repository")
+	}
+
+	live, err := m.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	archived, err := m.backend.archived()
+	if err != nil {
+		return nil, err
+	}
+	history, err := m.readHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := map[string]Session{}
+	for _, s := range append(append(history, archived...), live...) {
+		key := s.ID
+		if key == "" {
+			key = s.Branch + "/" + s.Name
+		}
+		if prev, ok := latest[key]; ok && prev.UpdatedAt.After(s.UpdatedAt) {
+			continue
+		}
+		latest[key] = s
+	}
+
+	merged := map[string]bool{}
+	for _, b := range git.MergedBranches(m.rootPath, "HEAD") {
+		merged[b] = true
+	}
+	current := m.getCurrentBranch()
+	done := map[string]bool{}
+	completed := []Session{}
+	for _, s := range latest {
+		if s.UpdatedAt.Before(since) || s.Branch == current {
+			continue
+		}
+		finished, ok := done[s.Branch]
+		if !ok {
+			finished = merged[s.Branch] || !git.Resolves(m.rootPath, s.Branch)
+			done[s.Branch] = finished
+		}
+		if finished {
+			completed = append(completed, s)
+		}
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].UpdatedAt.Before(completed[j].UpdatedAt)
+	})
+	return completed, nil
+}
+
+// Prune compacts the history, dropping entries older than maxAge and
+// keeping at most maxEntries of the newest ones. Zero disables a limit.
+// It returns how many entries were removed.
+func (m *Manager) Prune(maxEntries int, maxAge time.Duration, dryRun bool) (int, error) {
+	unlock, err := lock.Acquire(m.files, lock.Sessions)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if err := m.migrateHistory(); err != nil {
+		return 0, err
+	}
+	all, err := m.backend.history()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := applyRetention(all, maxEntries, maxAge)
+	removed := len(all) - len(kept)
+	if removed == 0 || dryRun {
+		return removed, nil
+	}
+
+	return removed, m.backend.writeHistory(kept)
+}
+
+// Retention returns the history limits configured in config.yaml
+func (m *Manager) Retention() (int, time.Duration) {
+	cfg, err := config.LoadFS(m.files)
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	return cfg.Retention()
+}
+
+// readHistory returns history entries within the configured retention
+func (m *Manager) readHistory() ([]Session, error) {
+	if err := m.MigrateHistory(); err != nil {
+		return nil, err
+	}
+	history, err := m.backend.history()
+	if err != nil {
+		return nil, err
+	}
+	maxEntries, maxAge := m.Retention()
+	return applyRetention(history, maxEntries, maxAge), nil
+}
+
+// appendHistory adds s to the history, after converting a legacy
+// history.json
+func (m *Manager) appendHistory(s *Session) error {
+	if err := m.migrateHistory(); err != nil {
+		return err
+	}
+	return m.backend.appendHistory(s)
+}
+
+// HasLegacyHistory reports whether a history.json from before
+// history.jsonl is waiting to be migrated
+func (m *Manager) HasLegacyHistory() bool {
+	return fsys.Exists(m.files, path.Join(m.sessionsDir, "history.json"))
+}
+
+// MigrateHistory moves a legacy history.json array into the history
+func (m *Manager) MigrateHistory() error {
+	if !m.HasLegacyHistory() {
+		return nil
+	}
+	unlock, err := lock.Acquire(m.files, lock.Sessions)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return m.migrateHistory()
+}
+
+// migrateHistory is MigrateHistory for callers holding the sessions lock
+func (m *Manager) migrateHistory() error {
+	legacy := path.Join(m.sessionsDir, "history.json")
+	data, err := fs.ReadFile(m.files, legacy)
+	if err != nil {
+		return nil // Nothing to migrate
+	}
+
+	var history []Session
+	if err := json.Unmarshal(data, &history); err != nil {
+		return fmt.Errorf("failed to parse legacy history.json: %w", err)
+	}
+
+	existing, err := m.backend.history()
+	if err != nil {
+		return err
+	}
+	if err := m.backend.writeHistory(append(history, existing...)); err != nil {
+		return err
+	}
+	return m.files.Remove(legacy)
+}
+
+// applyRetention keeps entries newer than maxAge, capped to the newest
+// maxEntries. Entries are assumed to be in append (chronological) order.
+func applyRetention(history []Session, maxEntries int, maxAge time.Duration) []Session {
+	kept := history
+	if maxAge > 0 {
+		cutoff := time.Now().Add(-maxAge)
+		kept = make([]Session, 0, len(history))
+		for _, s := range history {
+			if s.UpdatedAt.After(cutoff) {
+				kept = append(kept, s)
+			}
+		}
+	}
+	if maxEntries > 0 && len(kept) > maxEntries {
+		kept = kept[len(kept)-maxEntries:]
+	}
+	return kept
+}
+
+func (m *Manager) getCurrentBranch() string {
+	if m.branch != "" {
+		return m.branch
+	}
+	if branch, err := git.CurrentBranch(m.rootPath); err == nil && branch != "" {
+		return branch
+	}
+	return "main"
+}
+
+func sanitizeBranch(branch string) string {
+	// Replace / with _ for filenames, and ~, which separates session names
+	result := ""
+	for _, c := range branch {
+		if c == '/' || c == '\\' || c == '~' {
+			result += "_"
+		} else {
+			result += string(c)
+		}
+	}
+	return result
+}
@@ -0,0 +1,146 @@
+package session
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jitin-nhz/contextpilot/pkg/fsys"
+)
+
+func TestSaveLoadMem(t *testing.T) {
+	files := fsys.NewMem(nil)
+	m := NewWithOptions(Options{Root: "shop", FS: files, Branch: "feat/PAY-7-refunds"})
+
+	if err := m.Save(&Session{Task: "Refund flow", NextSteps: []string{"Verify webhook"}}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := m.Save(&Session{Name: "spike", Task: "Try Stripe"}); err != nil {
+		t.Fatalf("Save spike: %v", err)
+	}
+
+	s, err := m.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if s.Task != "Refund flow" || s.Branch != "feat/PAY-7-refunds" || s.Issue != "PAY-7" {
+		t.Errorf("Load = %q on %q for %q, want Refund flow on feat/PAY-7-refunds for PAY-7", s.Task, s.Branch, s.Issue)
+	}
+
+	sessions, err := m.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(sessions) != 2 || !sessions[0].IsDefault() || sessions[1].Name != "spike" {
+		t.Errorf("List = %+v, want the default session then spike", sessions)
+	}
+
+	want := []string{
+		".contextpilot/sessions/feat_PAY-7-refunds~spike.json",
+		".contextpilot/sessions/feat_PAY-7-refunds.json",
+		".contextpilot/sessions/history.jsonl",
+	}
+	for _, name := range want {
+		if !slices.Contains(files.Files(), name) {
+			t.Errorf("%s not written; files are %v", name, files.Files())
+		}
+	}
+}
+
+func TestHistoryAndClearMem(t *testing.T) {
+	files := fsys.NewMem(nil)
+	m := NewWithOptions(Options{Root: "shop", FS: files, Branch: "main"})
+
+	s := &Session{Task: "Refund flow", State: "started"}
+	if err := m.Save(s); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	s.State = "webhook verified"
+	if err := m.Save(s); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	history, err := m.History()
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 2 || history[0].State != "started" || history[1].State != "webhook verified" {
+		t.Errorf("History = %+v, want both saves oldest first", history)
+	}
+
+	if err := m.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if s, err := m.Load(); err != nil || s != nil {
+		t.Errorf("Load after Clear = %+v, %v; want no session", s, err)
+	}
+	if history, _ := m.History(); len(history) != 2 {
+		t.Errorf("Clear dropped history: %+v", history)
+	}
+}
+
+func TestSearchMem(t *testing.T) {
+	m := NewWithOptions(Options{Root: "shop", FS: fsys.NewMem(nil), Branch: "main"})
+	for _, task := range []string{"Refund flow", "Fix typo"} {
+		if err := m.Save(&Session{Name: task, Task: task}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	found, err := m.Search("REFUND")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(found) != 1 || found[0].Task != "Refund flow" {
+		t.Errorf("Search = %+v, want only Refund flow", found)
+	}
+}
+
+func TestSessionFilesDontCollide(t *testing.T) {
+	files := fsys.NewMem(nil)
+	onBranch := NewWithOptions(Options{Root: "shop", FS: files, Branch: "a--b"})
+	named := NewWithOptions(Options{Root: "shop", FS: files, Branch: "a"})
+
+	if err := onBranch.Save(&Session{Task: "Default of a--b"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := named.Save(&Session{Name: "b", Task: "Session b of a"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if s, err := onBranch.Load(); err != nil || s == nil || s.Task != "Default of a--b" {
+		t.Errorf("Load on a--b = %+v, %v; want its default session", s, err)
+	}
+	if s, err := named.LoadNamed("b"); err != nil || s == nil || s.Task != "Session b of a" {
+		t.Errorf("LoadNamed(b) on a = %+v, %v; want session b", s, err)
+	}
+}
+
+func TestLegacySessionFile(t *testing.T) {
+	files := fsys.NewMem(map[string]string{
+		".contextpilot/sessions/main--spike.json": `{"id":"1","branch":"main","name":"spike","task":"Try Stripe"}`,
+		// a--b's default session, in the file session b of a used to have
+		".contextpilot/sessions/a--b.json": `{"id":"2","branch":"a--b","task":"Default of a--b"}`,
+	})
+	m := NewWithOptions(Options{Root: "shop", FS: files, Branch: "main"})
+
+	s, err := m.LoadNamed("spike")
+	if err != nil || s == nil || s.Task != "Try Stripe" {
+		t.Fatalf("LoadNamed(spike) = %+v, %v; want the session in main--spike.json", s, err)
+	}
+	if s, _ := NewWithOptions(Options{Root: "shop", FS: files, Branch: "a"}).LoadNamed("b"); s != nil {
+		t.Errorf("LoadNamed(b) on a = %+v, want none", s)
+	}
+
+	// saving moves it to its new file
+	s.State = "webhooks next"
+	if err := m.Save(s); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got := files.Files(); slices.Contains(got, ".contextpilot/sessions/main--spike.json") ||
+		!slices.Contains(got, ".contextpilot/sessions/main~spike.json") {
+		t.Errorf("files after Save = %v, want main--spike.json moved to main~spike.json", got)
+	}
+	if sessions, _ := m.List(); len(sessions) != 1 || sessions[0].State != "webhooks next" {
+		t.Errorf("List = %+v, want only the saved session", sessions)
+	}
+}
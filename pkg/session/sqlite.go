@@ -0,0 +1,182 @@
+package session
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jitin-nhz/contextpilot/internal/db"
+)
+
+// sqliteBackend stores sessions, the archive and the history in tables of
+// .contextpilot/contextpilot.db (see internal/db), for config.yaml's
+// storage: sqlite. The database is opened on first use.
+type sqliteBackend struct {
+	root string
+}
+
+func newSQLiteBackend(root string) *sqliteBackend {
+	return &sqliteBackend{root: root}
+}
+
+func (b *sqliteBackend) load(branch, name string) (*Session, error) {
+	conn, err := db.Open(b.root)
+	if err != nil {
+		return nil, err
+	}
+	var data string
+	err = conn.QueryRow(`SELECT data FROM sessions WHERE branch = ? AND name = ?`, branch, nameKey(name)).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // No session for this branch
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	var s Session
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	return &s, nil
+}
+
+func (b *sqliteBackend) list() ([]Session, error) {
+	return b.query(`SELECT data FROM sessions`)
+}
+
+func (b *sqliteBackend) put(s *Session) error {
+	conn, err := db.Open(b.root)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	_, err = conn.Exec(`INSERT INTO sessions (branch, name, updated_at, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (branch, name) DO UPDATE SET updated_at = excluded.updated_at, data = excluded.data`,
+		s.Branch, nameKey(s.Name), timestamp(s.UpdatedAt), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+func (b *sqliteBackend) remove(branch, name string) error {
+	conn, err := db.Open(b.root)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Exec(`DELETE FROM sessions WHERE branch = ? AND name = ?`, branch, nameKey(name))
+	return err
+}
+
+// archive moves the stored session, like the file backend moves its file
+func (b *sqliteBackend) archive(s *Session) error {
+	conn, err := db.Open(b.root)
+	if err != nil {
+		return err
+	}
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`INSERT INTO session_archive (branch, data)
+		SELECT branch, data FROM sessions WHERE branch = ? AND name = ?`, s.Branch, nameKey(s.Name)); err != nil {
+		return fmt.Errorf("failed to archive session: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM sessions WHERE branch = ? AND name = ?`, s.Branch, nameKey(s.Name)); err != nil {
+		return fmt.Errorf("failed to archive session: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (b *sqliteBackend) archived() ([]Session, error) {
+	return b.query(`SELECT data FROM session_archive ORDER BY seq`)
+}
+
+func (b *sqliteBackend) history() ([]Session, error) {
+	return b.query(`SELECT data FROM session_history ORDER BY seq`)
+}
+
+func (b *sqliteBackend) appendHistory(s *Session) error {
+	conn, err := db.Open(b.root)
+	if err != nil {
+		return err
+	}
+	if err := insertHistory(conn, s); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+func (b *sqliteBackend) writeHistory(entries []Session) error {
+	conn, err := db.Open(b.root)
+	if err != nil {
+		return err
+	}
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM session_history`); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	for i := range entries {
+		if err := insertHistory(tx, &entries[i]); err != nil {
+			return fmt.Errorf("failed to write history: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// execer is a database or a transaction
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func insertHistory(conn execer, s *Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Exec(`INSERT INTO session_history (branch, updated_at, data) VALUES (?, ?, ?)`,
+		s.Branch, timestamp(s.UpdatedAt), string(data))
+	return err
+}
+
+// query returns the sessions in the data column of query's rows, skipping
+// any it can't parse
+func (b *sqliteBackend) query(query string) ([]Session, error) {
+	conn, err := db.Open(b.root)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []Session{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read sessions: %w", err)
+		}
+		var s Session
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// timestamp formats t for a column that sorts in time order
+func timestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
@@ -0,0 +1,56 @@
+// Command sign signs a release's checksums.txt for 'contextpilot
+// self-update'. The base64 Ed25519 private key comes from
+// CONTEXTPILOT_SIGNING_KEY, and the base64 signature is written next to
+// the file as checksums.txt.sig.
+//
+//	go run ./scripts/sign dist/checksums.txt
+//	go run ./scripts/sign -keygen
+//
+// -keygen prints a new key pair: keep the private key in the release
+// workflow's secrets and build with the public key (SIGNING_PUBLIC_KEY in
+// the Makefile).
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	keygen := flag.Bool("keygen", false, "Print a new key pair")
+	flag.Parse()
+
+	if *keygen {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("public:  %s\nprivate: %s\n", base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv))
+		return
+	}
+	if flag.NArg() != 1 {
+		fail(fmt.Errorf("usage: sign <checksums.txt> | sign -keygen"))
+	}
+
+	key, err := base64.StdEncoding.DecodeString(os.Getenv("CONTEXTPILOT_SIGNING_KEY"))
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		fail(fmt.Errorf("CONTEXTPILOT_SIGNING_KEY must be a base64 Ed25519 private key"))
+	}
+	data, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		fail(err)
+	}
+	sig := ed25519.Sign(ed25519.PrivateKey(key), data)
+	if err := os.WriteFile(flag.Arg(0)+".sig", []byte(base64.StdEncoding.EncodeToString(sig)+"\n"), 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "sign:", err)
+	os.Exit(1)
+}